@@ -0,0 +1,327 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package actioncache implements the local cache of the outputs of the actions that are run by
+// sbox. The actions are keyed by their command line and the digests of their declared inputs.
+//
+// The cache directory contains the action entries in ac/, which list the outputs of the actions,
+// and the contents of the outputs in cas/, keyed by their digests. Entries and outputs are
+// replaced atomically so that parallel actions can share the cache, and their modification times
+// are updated when they are used so that Trim can evict the least recently used ones.
+package actioncache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache is a local action cache in a directory.
+type Cache struct {
+	dir string
+}
+
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// entry is the action entry of the cache, the outputs of an action.
+type entry struct {
+	Outputs []output `json:"outputs"`
+}
+
+type output struct {
+	Path       string `json:"path"`
+	Digest     string `json:"digest"`
+	Executable bool   `json:"executable,omitempty"`
+}
+
+// Stats are the lookups of the cache since the last call of TakeStats.
+type Stats struct {
+	Hits   int
+	Misses int
+}
+
+// Key returns the key of an action with the given command line, outputs and inputs. Inputs can be
+// files or directories, the contents of directories are hashed recursively.
+func Key(command string, outputs, inputs []string) (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "command %q\n", command)
+	for _, output := range outputs {
+		fmt.Fprintf(h, "output %q\n", output)
+	}
+
+	inputs = append([]string(nil), inputs...)
+	sort.Strings(inputs)
+	for i, input := range inputs {
+		if i > 0 && inputs[i-1] == input {
+			continue
+		}
+		err := filepath.Walk(input, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			digest, executable, err := digestFile(path)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(h, "input %q %s %t\n", path, digest, executable)
+			return nil
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to hash input: %s", err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func digestFile(path string) (digest string, executable bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return "", false, err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", false, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), fi.Mode()&0111 != 0, nil
+}
+
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, "ac", key[:2], key)
+}
+
+func (c *Cache) blobPath(digest string) string {
+	return filepath.Join(c.dir, "cas", digest[:2], digest)
+}
+
+func (c *Cache) statsPath() string {
+	return filepath.Join(c.dir, "stats")
+}
+
+// Lookup restores the outputs of the action with the key into dir, and returns whether the action
+// was in the cache.
+func (c *Cache) Lookup(key, dir string) (bool, error) {
+	hit, err := c.lookup(key, dir)
+	c.recordLookup(hit)
+	return hit, err
+}
+
+func (c *Cache) lookup(key, dir string) (bool, error) {
+	data, err := ioutil.ReadFile(c.entryPath(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false, fmt.Errorf("corrupt action cache entry %s: %s", key, err)
+	}
+
+	// The outputs may have been evicted without the entry.
+	for _, o := range e.Outputs {
+		if _, err := os.Stat(c.blobPath(o.Digest)); os.IsNotExist(err) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+	}
+
+	now := time.Now()
+	for _, o := range e.Outputs {
+		mode := os.FileMode(0666)
+		if o.Executable {
+			mode = 0777
+		}
+		if err := copyFile(c.blobPath(o.Digest), filepath.Join(dir, o.Path), mode); err != nil {
+			return false, err
+		}
+		os.Chtimes(c.blobPath(o.Digest), now, now)
+	}
+	os.Chtimes(c.entryPath(key), now, now)
+	return true, nil
+}
+
+// Store adds the outputs in dir of the action with the key to the cache.
+func (c *Cache) Store(key, dir string, outputs []string) error {
+	var e entry
+	for _, path := range outputs {
+		file := filepath.Join(dir, path)
+		digest, executable, err := digestFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(c.blobPath(digest)); os.IsNotExist(err) {
+			err := writeAtomically(c.blobPath(digest), func(tmp string) error {
+				return copyFile(file, tmp, 0666)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		e.Outputs = append(e.Outputs, output{Path: path, Digest: digest, Executable: executable})
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return writeAtomically(c.entryPath(key), func(tmp string) error {
+		return ioutil.WriteFile(tmp, data, 0666)
+	})
+}
+
+// writeAtomically writes a file with the write function through a temporary file in the same
+// directory, so that other processes never see a partially written file.
+func writeAtomically(path string, write func(tmp string) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	f.Close()
+	defer os.Remove(tmp)
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func copyFile(from, to string, mode os.FileMode) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(to), 0777); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// recordLookup appends the result of a lookup to the stats file. Appends of single lines are
+// atomic, so the parallel actions don't need to lock the file.
+func (c *Cache) recordLookup(hit bool) {
+	line := "miss\n"
+	if hit {
+		line = "hit\n"
+	}
+	if err := os.MkdirAll(c.dir, 0777); err != nil {
+		return
+	}
+	f, err := os.OpenFile(c.statsPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.WriteString(line)
+}
+
+// TakeStats returns the lookups since the last call of TakeStats.
+func (c *Cache) TakeStats() (Stats, error) {
+	var stats Stats
+	taken := c.statsPath() + ".taken"
+	if err := os.Rename(c.statsPath(), taken); os.IsNotExist(err) {
+		return stats, nil
+	} else if err != nil {
+		return stats, err
+	}
+	defer os.Remove(taken)
+
+	data, err := ioutil.ReadFile(taken)
+	if err != nil {
+		return stats, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		switch line {
+		case "hit":
+			stats.Hits++
+		case "miss":
+			stats.Misses++
+		}
+	}
+	return stats, nil
+}
+
+// Trim evicts the least recently used entries and outputs until the cache is at most maxSize
+// bytes, and returns the size of the cache and the size of the evicted files.
+func (c *Cache) Trim(maxSize int64) (size, evicted int64, err error) {
+	type file struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []file
+	for _, dir := range []string{"ac", "cas"} {
+		err := filepath.Walk(filepath.Join(c.dir, dir), func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			if info.Mode().IsRegular() {
+				files = append(files, file{path, info.Size(), info.ModTime()})
+				size += info.Size()
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if size <= maxSize {
+			break
+		}
+		if err := os.Remove(f.path); err != nil && !os.IsNotExist(err) {
+			return size, evicted, err
+		}
+		size -= f.size
+		evicted += f.size
+	}
+	return size, evicted, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actioncache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, []byte(contents), 0666); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actioncache_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	inDir := filepath.Join(dir, "in_dir")
+	writeFile(t, in, "a")
+	writeFile(t, filepath.Join(inDir, "b"), "b")
+
+	key := func(command string, inputs ...string) string {
+		t.Helper()
+		k, err := Key(command, []string{"out"}, inputs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	base := key("cp in out", in, inDir)
+	if k := key("cp in out", inDir, in, in); k != base {
+		t.Errorf("key depends on the order of the inputs")
+	}
+	if k := key("cp -f in out", in, inDir); k == base {
+		t.Errorf("key doesn't depend on the command")
+	}
+	writeFile(t, in, "c")
+	if k := key("cp in out", in, inDir); k == base {
+		t.Errorf("key doesn't depend on the contents of the inputs")
+	}
+	writeFile(t, in, "a")
+	writeFile(t, filepath.Join(inDir, "b"), "d")
+	if k := key("cp in out", in, inDir); k == base {
+		t.Errorf("key doesn't depend on the contents of the input directories")
+	}
+
+	if _, err := Key("cp in out", nil, []string{filepath.Join(dir, "missing")}); err == nil {
+		t.Errorf("expected an error for a missing input")
+	}
+}
+
+func TestCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actioncache_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := New(filepath.Join(dir, "cache"))
+	sandbox := filepath.Join(dir, "sandbox")
+	restored := filepath.Join(dir, "restored")
+
+	if hit, err := cache.Lookup("0123", restored); err != nil || hit {
+		t.Fatalf("want miss, got hit %v, error %v", hit, err)
+	}
+
+	writeFile(t, filepath.Join(sandbox, "out"), "out")
+	writeFile(t, filepath.Join(sandbox, "gen/out.h"), "header")
+	if err := os.Chmod(filepath.Join(sandbox, "out"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Store("0123", sandbox, []string{"out", "gen/out.h"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if hit, err := cache.Lookup("0123", restored); err != nil || !hit {
+		t.Fatalf("want hit, got hit %v, error %v", hit, err)
+	}
+	for path, want := range map[string]string{"out": "out", "gen/out.h": "header"} {
+		data, err := ioutil.ReadFile(filepath.Join(restored, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != want {
+			t.Errorf("want %s to be restored as %q, got %q", path, want, data)
+		}
+	}
+	if fi, err := os.Stat(filepath.Join(restored, "out")); err != nil || fi.Mode()&0100 == 0 {
+		t.Errorf("want out to be restored as executable, got %v, error %v", fi.Mode(), err)
+	}
+
+	stats, err := cache.TakeStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Stats{Hits: 1, Misses: 1}); stats != want {
+		t.Errorf("want stats %+v, got %+v", want, stats)
+	}
+	if stats, _ := cache.TakeStats(); stats != (Stats{}) {
+		t.Errorf("want stats to be reset, got %+v", stats)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actioncache_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cache := New(filepath.Join(dir, "cache"))
+	sandbox := filepath.Join(dir, "sandbox")
+	writeFile(t, filepath.Join(sandbox, "old"), "old output")
+	writeFile(t, filepath.Join(sandbox, "new"), "new output")
+	if err := cache.Store("00old", sandbox, []string{"old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Store("00new", sandbox, []string{"new"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Make the old action the least recently used one.
+	past := time.Now().Add(-time.Hour)
+	digest, _, err := digestFile(filepath.Join(sandbox, "old"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{cache.entryPath("00old"), cache.blobPath(digest)} {
+		if err := os.Chtimes(path, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size, _, err := cache.Trim(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, evicted, err := cache.Trim(size - 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evicted == 0 {
+		t.Errorf("want evicted files, got none with size %d", size)
+	}
+
+	restored := filepath.Join(dir, "restored")
+	if hit, _ := cache.Lookup("00old", restored); hit {
+		t.Errorf("want the least recently used action to be evicted")
+	}
+	if hit, _ := cache.Lookup("00new", restored); !hit {
+		t.Errorf("want the most recently used action to be kept")
+	}
+}
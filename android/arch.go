@@ -31,10 +31,11 @@ const COMMON_VARIANT = "common"
 var (
 	archTypeList []ArchType
 
-	Arm    = newArch("arm", "lib32")
-	Arm64  = newArch("arm64", "lib64")
-	X86    = newArch("x86", "lib32")
-	X86_64 = newArch("x86_64", "lib64")
+	Arm     = newArch("arm", "lib32")
+	Arm64   = newArch("arm64", "lib64")
+	Riscv64 = newArch("riscv64", "lib64")
+	X86     = newArch("x86", "lib32")
+	X86_64  = newArch("x86_64", "lib64")
 
 	Common = ArchType{
 		Name: COMMON_VARIANT,
@@ -42,10 +43,11 @@ var (
 )
 
 var archTypeMap = map[string]ArchType{
-	"arm":    Arm,
-	"arm64":  Arm64,
-	"x86":    X86,
-	"x86_64": X86_64,
+	"arm":     Arm,
+	"arm64":   Arm64,
+	"riscv64": Riscv64,
+	"x86":     X86,
+	"x86_64":  X86_64,
 }
 
 /*
@@ -60,6 +62,9 @@ module {
         arm64: {
             // Host or device variants with arm64 architecture
         },
+        riscv64: {
+            // Host or device variants with riscv64 architecture
+        },
         x86: {
             // Host or device variants with x86 architecture
         },
@@ -135,6 +140,11 @@ var archVariants = map[ArchType][]string{
 		"exynos-m1",
 		"exynos-m2",
 	},
+	Riscv64: {
+		"rv64gc",
+		"sifive-u54",
+		"sifive-u74",
+	},
 	X86: {
 		"amberlake",
 		"atom",
@@ -581,7 +591,7 @@ var (
 		LinuxBionic: []ArchType{X86_64},
 		Darwin:      []ArchType{X86_64},
 		Windows:     []ArchType{X86, X86_64},
-		Android:     []ArchType{Arm, Arm64, X86, X86_64},
+		Android:     []ArchType{Arm, Arm64, Riscv64, X86, X86_64},
 		Fuchsia:     []ArchType{Arm64, X86_64},
 	}
 )
@@ -1627,6 +1637,9 @@ func getMegaDeviceConfig() []archConfig {
 		{"arm64", "armv8-2a", "cortex-a75", []string{"arm64-v8a"}},
 		{"arm64", "armv8-2a", "cortex-a76", []string{"arm64-v8a"}},
 		{"arm64", "armv8-2a", "kryo385", []string{"arm64-v8a"}},
+		{"riscv64", "rv64gc", "", []string{"riscv64"}},
+		{"riscv64", "rv64gc", "sifive-u54", []string{"riscv64"}},
+		{"riscv64", "rv64gc", "sifive-u74", []string{"riscv64"}},
 		{"x86", "", "", []string{"x86"}},
 		{"x86", "atom", "", []string{"x86"}},
 		{"x86", "haswell", "", []string{"x86"}},
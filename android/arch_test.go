@@ -335,6 +335,18 @@ func TestArchMutator(t *testing.T) {
 			bazVariants: nil,
 			quxVariants: buildOS32Variants,
 		},
+		{
+			name: "riscv64",
+			config: func(config Config) {
+				config.Targets[Android] = []Target{
+					{Android, Arch{ArchType: Riscv64, ArchVariant: "rv64gc", Abi: []string{"riscv64"}}, NativeBridgeDisabled, "", ""},
+				}
+			},
+			fooVariants: []string{"android_riscv64_rv64gc"},
+			barVariants: append(buildOSVariants, "android_riscv64_rv64gc"),
+			bazVariants: nil,
+			quxVariants: buildOS32Variants,
+		},
 	}
 
 	enabledVariants := func(ctx *TestContext, name string) []string {
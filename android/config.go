@@ -108,6 +108,9 @@ type config struct {
 	captureBuild      bool // true for tests, saves build parameters for each module
 	ignoreEnvironment bool // true for tests, returns empty from all Getenv calls
 
+	recordModuleGraph   bool // saves the dependencies and outputs of each module for WriteModuleGraph
+	recordModuleActions bool // saves the outputs of each module for WriteModuleActions
+
 	stopBefore bootstrap.StopBefore
 
 	fs         pathtools.FileSystem
@@ -473,6 +476,22 @@ func (c *config) SetStopBefore(stopBefore bootstrap.StopBefore) {
 
 var _ bootstrap.ConfigStopBefore = (*config)(nil)
 
+// SetRecordModuleGraph makes the modules save their dependencies and outputs while generating
+// their build actions, so that WriteModuleGraph can include them.
+func (c *config) SetRecordModuleGraph() {
+	c.recordModuleGraph = true
+}
+
+// SetRecordModuleActions makes the modules save their outputs while generating their build
+// actions, so that WriteModuleActions can map them back to the modules.
+func (c *config) SetRecordModuleActions() {
+	c.recordModuleActions = true
+}
+
+func (c *config) recordModuleOutputs() bool {
+	return c.recordModuleGraph || c.recordModuleActions
+}
+
 func (c *config) BlueprintToolLocation() string {
 	return filepath.Join(c.buildDir, "host", c.PrebuiltOS(), "bin")
 }
@@ -830,6 +849,13 @@ func (c *config) RunErrorProne() bool {
 	return c.IsEnvTrue("RUN_ERROR_PRONE")
 }
 
+// SboxCache returns true if the outputs of the actions run by sbox are stored in the local action
+// cache in shared.SboxCacheDirForOutDir, and restored from it when the actions run again with the
+// same inputs.
+func (c *config) SboxCache() bool {
+	return c.IsEnvTrue("SOONG_SBOX_CACHE")
+}
+
 func (c *config) XrefCorpusName() string {
 	return c.Getenv("XREF_CORPUS")
 }
@@ -971,6 +997,20 @@ func (c *deviceConfig) ProductVndkVersion() string {
 	return String(c.config.productVariables.ProductVndkVersion)
 }
 
+// RamdiskSnapshotVersion returns BOARD_RAMDISK_SNAPSHOT_VERSION: "current" to capture a
+// ramdisk snapshot, the version of the ramdisk snapshot to build ramdisk modules against, or
+// an empty string if ramdisk snapshots aren't used.
+func (c *deviceConfig) RamdiskSnapshotVersion() string {
+	return String(c.config.productVariables.RamdiskSnapshotVersion)
+}
+
+// RecoverySnapshotVersion returns BOARD_RECOVERY_SNAPSHOT_VERSION: "current" to capture a
+// recovery snapshot, the version of the recovery snapshot to build recovery modules against, or
+// an empty string if recovery snapshots aren't used.
+func (c *deviceConfig) RecoverySnapshotVersion() string {
+	return String(c.config.productVariables.RecoverySnapshotVersion)
+}
+
 func (c *deviceConfig) ExtraVndkVersions() []string {
 	return c.config.productVariables.ExtraVndkVersions
 }
@@ -793,6 +793,10 @@ type ModuleBase struct {
 	initRcPaths         Paths
 	vintfFragmentsPaths Paths
 
+	// Set when the config records the module graph, see Config.SetRecordModuleGraph
+	moduleGraphDeps    []moduleGraphDep
+	moduleGraphOutputs []string
+
 	prefer32 func(ctx BaseModuleContext, base *ModuleBase, class OsClass) bool
 }
 
@@ -1415,6 +1419,10 @@ func (m *ModuleBase) GenerateBuildActions(blueprintCtx blueprint.ModuleContext)
 		ctx.GetMissingDependencies()
 	}
 
+	if ctx.config.recordModuleOutputs() {
+		ctx.recordModuleGraph()
+	}
+
 	if m == ctx.FinalModule().(Module).base() {
 		m.generateModuleTarget(ctx)
 		if ctx.Failed() {
@@ -1564,6 +1572,8 @@ type moduleContext struct {
 	buildParams []BuildParams
 	ruleParams  map[blueprint.Rule]blueprint.RuleParams
 	variables   map[string]string
+
+	moduleGraphOutputs []string
 }
 
 func (m *moduleContext) ninjaError(params BuildParams, err error) (PackageContext, BuildParams) {
@@ -1676,6 +1686,10 @@ func (m *moduleContext) Build(pctx PackageContext, params BuildParams) {
 		m.buildParams = append(m.buildParams, params)
 	}
 
+	if m.config.recordModuleOutputs() {
+		m.recordModuleGraphOutputs(params)
+	}
+
 	m.bp.Build(pctx.PackageContext, convertBuildParams(params))
 }
 
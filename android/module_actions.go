@@ -0,0 +1,78 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package android
+
+// This file contains the module actions table of soong_build, which maps the outputs of the build
+// actions to the modules that created them, so that soong_ui can attribute a failed action to its
+// module.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/blueprint"
+)
+
+type ModuleActions struct {
+	Modules []ModuleActionsModule `json:"modules"`
+
+	// The index in Modules of the module that created each output.
+	Outputs map[string]int `json:"outputs"`
+}
+
+// A ModuleActionsModule is a variant of a module which created build actions.
+type ModuleActionsModule struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Variant string `json:"variant"`
+	// The Blueprints file that defines the module.
+	Blueprint string `json:"blueprint"`
+}
+
+// moduleActions returns the module actions table of the context. The outputs of the modules are
+// only known if the config recorded them while generating the build actions, see
+// Config.SetRecordModuleActions.
+func moduleActions(ctx *Context) ModuleActions {
+	actions := ModuleActions{Outputs: make(map[string]int)}
+	var modules []blueprint.Module
+	ctx.VisitAllModules(func(module blueprint.Module) {
+		if m, ok := module.(Module); ok && len(m.base().moduleGraphOutputs) > 0 {
+			modules = append(modules, module)
+		}
+	})
+
+	for i, module := range modules {
+		actions.Modules = append(actions.Modules, ModuleActionsModule{
+			Name:      ctx.ModuleName(module),
+			Type:      ctx.ModuleType(module),
+			Variant:   ctx.ModuleSubDir(module),
+			Blueprint: ctx.BlueprintFile(module),
+		})
+		for _, output := range module.(Module).base().moduleGraphOutputs {
+			actions.Outputs[output] = i
+		}
+	}
+	return actions
+}
+
+// WriteModuleActions writes the module actions table of the context as JSON to the given file.
+func WriteModuleActions(ctx *Context, file string) error {
+	data, err := json.Marshal(moduleActions(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to marshal the module actions: %s", err)
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0666)
+}
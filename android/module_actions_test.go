@@ -0,0 +1,77 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package android
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestModuleActions(t *testing.T) {
+	bp := `
+		test {
+			name: "foo",
+			out: "foo.out",
+		}
+
+		test {
+			name: "bar",
+		}
+	`
+
+	config := TestArchConfig(buildDir, nil, bp, nil)
+	config.SetRecordModuleActions()
+
+	ctx := NewTestArchContext()
+	ctx.RegisterModuleType("test", moduleGraphTestModuleFactory)
+	ctx.Register(config)
+
+	_, errs := ctx.ParseFileList(".", []string{"Android.bp"})
+	FailIfErrored(t, errs)
+	_, errs = ctx.PrepareBuildActions(config)
+	FailIfErrored(t, errs)
+
+	actionsFile := filepath.Join(buildDir, "module_actions.json")
+	if err := WriteModuleActions(ctx.Context, actionsFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(actionsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions ModuleActions
+	if err := json.Unmarshal(data, &actions); err != nil {
+		t.Fatal(err)
+	}
+
+	out := ctx.ModuleForTests("foo", "android_common").Output("foo.out").Output.String()
+	i, ok := actions.Outputs[out]
+	if !ok {
+		t.Fatalf("output %q missing from the module actions: %s", out, data)
+	}
+	expected := ModuleActionsModule{Name: "foo", Type: "test", Variant: "android_common", Blueprint: "Android.bp"}
+	if actions.Modules[i] != expected {
+		t.Errorf("expected %q to be created by %#v, got %#v", out, expected, actions.Modules[i])
+	}
+
+	// Modules without build actions aren't in the table.
+	for _, m := range actions.Modules {
+		if m.Name == "bar" {
+			t.Errorf("unexpected module bar without build actions in the module actions")
+		}
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package android
+
+// This file contains the module graph export of soong_build (--module_graph_file), a JSON
+// description of every module variant with its resolved properties, its dependencies and the
+// files it produces, for tools which answer questions about the build graph without parsing the
+// ninja files.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+
+	"github.com/google/blueprint"
+	"github.com/google/blueprint/proptools"
+)
+
+type ModuleGraph struct {
+	Modules []ModuleGraphModule `json:"modules"`
+}
+
+// A ModuleGraphModule is a variant of a module in the module graph.
+type ModuleGraphModule struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// The directory of the Blueprints file that defines the module.
+	Dir     string `json:"dir"`
+	Variant string `json:"variant"`
+
+	// The variation of the module for each mutator that split it.
+	Variations map[string]string `json:"variations,omitempty"`
+
+	// The properties set on the module, after defaults, arch-specific, product variable and
+	// soong_config properties were applied.
+	Properties map[string]interface{} `json:"properties,omitempty"`
+
+	Deps []ModuleGraphDep `json:"deps,omitempty"`
+
+	// The files created by the build rules of the module, and the files it installs.
+	Outputs  []string `json:"outputs,omitempty"`
+	Installs []string `json:"installs,omitempty"`
+}
+
+// A ModuleGraphDep is a dependency edge from a module variant to another.
+type ModuleGraphDep struct {
+	Name    string `json:"name"`
+	Variant string `json:"variant"`
+	Tag     string `json:"tag,omitempty"`
+}
+
+type moduleGraphDep struct {
+	module blueprint.Module
+	tag    blueprint.DependencyTag
+}
+
+// recordModuleGraph saves the outputs of the module, and its dependencies with their dependency
+// tags, which are only available from a module context, if the whole module graph is recorded.
+func (m *moduleContext) recordModuleGraph() {
+	base := m.module.base()
+	base.moduleGraphDeps = nil
+	if m.config.recordModuleGraph {
+		m.VisitDirectDepsBlueprint(func(dep blueprint.Module) {
+			base.moduleGraphDeps = append(base.moduleGraphDeps, moduleGraphDep{dep, m.OtherModuleDependencyTag(dep)})
+		})
+	}
+	base.moduleGraphOutputs = m.moduleGraphOutputs
+}
+
+// recordModuleGraphOutputs saves the outputs of a build statement of the module.
+func (m *moduleContext) recordModuleGraphOutputs(params BuildParams) {
+	var outputs WritablePaths
+	if params.Output != nil {
+		outputs = append(outputs, params.Output)
+	}
+	outputs = append(outputs, params.Outputs...)
+	if params.ImplicitOutput != nil {
+		outputs = append(outputs, params.ImplicitOutput)
+	}
+	outputs = append(outputs, params.ImplicitOutputs...)
+	for _, output := range outputs {
+		m.moduleGraphOutputs = append(m.moduleGraphOutputs, output.String())
+	}
+}
+
+// dependencyTagString returns a description of a dependency tag, made of its type and, for tags
+// with fields, their values.
+func dependencyTagString(tag blueprint.DependencyTag) string {
+	if tag == nil {
+		return ""
+	}
+	if s, ok := tag.(fmt.Stringer); ok {
+		return s.String()
+	}
+	v := reflect.Indirect(reflect.ValueOf(tag))
+	if v.Kind() == reflect.Struct && v.NumField() > 0 {
+		return fmt.Sprintf("%T%+v", tag, v.Interface())
+	}
+	return fmt.Sprintf("%T", tag)
+}
+
+// moduleGraphProperties returns the properties set on a module, keyed by property name.
+func moduleGraphProperties(module Module) map[string]interface{} {
+	ret := map[string]interface{}{}
+	for _, properties := range module.GetProperties() {
+		moduleGraphStructProperties(reflect.ValueOf(properties).Elem(), ret)
+	}
+	return ret
+}
+
+func moduleGraphStructProperties(structValue reflect.Value, ret map[string]interface{}) {
+	structType := structValue.Type()
+	for i := 0; i < structValue.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath != "" || proptools.HasTag(field, "blueprint", "mutated") {
+			continue
+		}
+		// The arch, multilib and target properties (interfaces) and the product variable
+		// properties hold the conditional values, which have already been applied to the other
+		// properties.
+		if field.Type.Kind() == reflect.Interface || field.Name == "Product_variables" {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			moduleGraphStructProperties(structValue.Field(i), ret)
+			continue
+		}
+		if value, ok := moduleGraphValue(structValue.Field(i)); ok {
+			ret[proptools.PropertyNameForField(field.Name)] = value
+		}
+	}
+}
+
+// moduleGraphValue returns the JSON representation of a property value, or false if the
+// property isn't set. Pointer properties are set even if they point to a zero value.
+func moduleGraphValue(value reflect.Value) (interface{}, bool) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return nil, false
+		}
+		if value.Elem().Kind() == reflect.Struct {
+			return moduleGraphValue(value.Elem())
+		}
+		return value.Elem().Interface(), true
+	case reflect.Bool:
+		return value.Bool(), value.Bool()
+	case reflect.String:
+		return value.String(), value.String() != ""
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), value.Int() != 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint(), value.Uint() != 0
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return nil, false
+		}
+		var ret []interface{}
+		for i := 0; i < value.Len(); i++ {
+			if v, ok := moduleGraphValue(value.Index(i)); ok {
+				ret = append(ret, v)
+			} else {
+				ret = append(ret, reflect.Zero(value.Type().Elem()).Interface())
+			}
+		}
+		return ret, true
+	case reflect.Struct:
+		ret := map[string]interface{}{}
+		moduleGraphStructProperties(value, ret)
+		return ret, len(ret) > 0
+	default:
+		return nil, false
+	}
+}
+
+// moduleGraph returns the module graph of the context. The dependency tags and the outputs of the
+// modules are only known if the config recorded them while generating the build actions, see
+// Config.SetRecordModuleGraph.
+func moduleGraph(ctx *Context) ModuleGraph {
+	var graph ModuleGraph
+	ctx.VisitAllModules(func(module blueprint.Module) {
+		m := ModuleGraphModule{
+			Name:    ctx.ModuleName(module),
+			Type:    ctx.ModuleType(module),
+			Dir:     filepath.Dir(ctx.BlueprintFile(module)),
+			Variant: ctx.ModuleSubDir(module),
+		}
+
+		aModule, ok := module.(Module)
+		if !ok {
+			ctx.VisitDirectDeps(module, func(dep blueprint.Module) {
+				m.Deps = append(m.Deps, ModuleGraphDep{Name: ctx.ModuleName(dep), Variant: ctx.ModuleSubDir(dep)})
+			})
+			graph.Modules = append(graph.Modules, m)
+			return
+		}
+
+		base := aModule.base()
+		for i, mutator := range base.commonProperties.DebugMutators {
+			if variation := base.commonProperties.DebugVariations[i]; variation != "" {
+				if m.Variations == nil {
+					m.Variations = make(map[string]string)
+				}
+				m.Variations[mutator] = variation
+			}
+		}
+		m.Properties = moduleGraphProperties(aModule)
+		for _, dep := range base.moduleGraphDeps {
+			m.Deps = append(m.Deps, ModuleGraphDep{
+				Name:    ctx.ModuleName(dep.module),
+				Variant: ctx.ModuleSubDir(dep.module),
+				Tag:     dependencyTagString(dep.tag),
+			})
+		}
+		m.Outputs = base.moduleGraphOutputs
+		for _, install := range base.installFiles {
+			m.Installs = append(m.Installs, install.String())
+		}
+		graph.Modules = append(graph.Modules, m)
+	})
+	return graph
+}
+
+// WriteModuleGraph writes the module graph of the context as JSON to the given file.
+func WriteModuleGraph(ctx *Context, file string) error {
+	data, err := json.MarshalIndent(moduleGraph(ctx), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal the module graph: %s", err)
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0666)
+}
@@ -0,0 +1,196 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package android
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/blueprint"
+)
+
+type moduleGraphTestDepTag struct {
+	blueprint.BaseDependencyTag
+	name string
+}
+
+type moduleGraphTestProperties struct {
+	Deps   []string
+	Out    *string
+	Flags  []string `android:"arch_variant"`
+	Static *bool
+	Nested struct {
+		Value *string
+	}
+}
+
+type moduleGraphTestModule struct {
+	ModuleBase
+	DefaultableModuleBase
+	props moduleGraphTestProperties
+}
+
+func moduleGraphTestModuleFactory() Module {
+	m := &moduleGraphTestModule{}
+	m.AddProperties(&m.props)
+	InitAndroidArchModule(m, DeviceSupported, MultilibCommon)
+	InitDefaultableModule(m)
+	return m
+}
+
+func (m *moduleGraphTestModule) DepsMutator(ctx BottomUpMutatorContext) {
+	ctx.AddDependency(ctx.Module(), moduleGraphTestDepTag{name: "dep"}, m.props.Deps...)
+}
+
+func (m *moduleGraphTestModule) GenerateAndroidBuildActions(ctx ModuleContext) {
+	if m.props.Out != nil {
+		out := PathForModuleOut(ctx, *m.props.Out)
+		ctx.Build(pctx, BuildParams{
+			Rule:   Touch,
+			Output: out,
+		})
+		ctx.InstallFile(PathForModuleInstall(ctx, "bin"), *m.props.Out, out)
+	}
+}
+
+func TestModuleGraph(t *testing.T) {
+	bp := `
+		defaults {
+			name: "foo_defaults",
+			flags: ["-defaults"],
+		}
+
+		test {
+			name: "foo",
+			defaults: ["foo_defaults"],
+			deps: ["bar"],
+			out: "foo.out",
+			flags: ["-foo"],
+			static: false,
+			nested: {
+				value: "nested",
+			},
+			target: {
+				android: {
+					flags: ["-android"],
+				},
+			},
+		}
+
+		test {
+			name: "bar",
+		}
+	`
+
+	config := TestArchConfig(buildDir, nil, bp, nil)
+	config.SetRecordModuleGraph()
+
+	ctx := NewTestArchContext()
+	ctx.RegisterModuleType("test", moduleGraphTestModuleFactory)
+	ctx.RegisterModuleType("defaults", moduleGraphTestDefaultsFactory)
+	ctx.PreArchMutators(RegisterDefaultsPreArchMutators)
+	ctx.Register(config)
+
+	_, errs := ctx.ParseFileList(".", []string{"Android.bp"})
+	FailIfErrored(t, errs)
+	_, errs = ctx.PrepareBuildActions(config)
+	FailIfErrored(t, errs)
+
+	graphFile := filepath.Join(buildDir, "module_graph.json")
+	if err := WriteModuleGraph(ctx.Context, graphFile); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(graphFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var graph ModuleGraph
+	if err := json.Unmarshal(data, &graph); err != nil {
+		t.Fatal(err)
+	}
+
+	var foo *ModuleGraphModule
+	for i, m := range graph.Modules {
+		if m.Name == "foo" {
+			foo = &graph.Modules[i]
+		}
+	}
+	if foo == nil {
+		t.Fatalf("module foo missing from the module graph: %s", data)
+	}
+
+	if foo.Type != "test" || foo.Dir != "." || foo.Variant != "android_common" {
+		t.Errorf("unexpected type, dir or variant of foo: %q, %q, %q", foo.Type, foo.Dir, foo.Variant)
+	}
+	if foo.Variations["os"] != "android" {
+		t.Errorf("expected os variation \"android\", got %#v", foo.Variations)
+	}
+
+	expectedProperties := map[string]interface{}{
+		"name":     "foo",
+		"defaults": []interface{}{"foo_defaults"},
+		"deps":     []interface{}{"bar"},
+		"out":      "foo.out",
+		"flags":    []interface{}{"-defaults", "-foo", "-android"},
+		"static":   false,
+		"nested":   map[string]interface{}{"value": "nested"},
+	}
+	for name, expected := range expectedProperties {
+		if actual := foo.Properties[name]; !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expected property %q to be %#v, got %#v", name, expected, actual)
+		}
+	}
+	if _, ok := foo.Properties["target"]; ok {
+		t.Errorf("unexpected target property, it should be applied to the other properties")
+	}
+
+	expectedDeps := []ModuleGraphDep{{
+		Name:    "bar",
+		Variant: "android_common",
+		Tag:     "android.moduleGraphTestDepTag{BaseDependencyTag:{} name:dep}",
+	}}
+	var deps []ModuleGraphDep
+	for _, dep := range foo.Deps {
+		if dep.Name == "bar" {
+			deps = append(deps, dep)
+		}
+	}
+	if !reflect.DeepEqual(deps, expectedDeps) {
+		t.Errorf("expected deps of foo on bar to be %#v, got %#v", expectedDeps, deps)
+	}
+
+	if len(foo.Outputs) != 1 || !strings.HasSuffix(foo.Outputs[0], "foo/android_common/foo.out") {
+		t.Errorf("expected foo to output foo.out, got %q", foo.Outputs)
+	}
+	if len(foo.Installs) != 1 || !strings.HasSuffix(foo.Installs[0], "system/bin/foo.out") {
+		t.Errorf("expected foo to install system/bin/foo.out, got %q", foo.Installs)
+	}
+}
+
+type moduleGraphTestDefaults struct {
+	ModuleBase
+	DefaultsModuleBase
+}
+
+func moduleGraphTestDefaultsFactory() Module {
+	m := &moduleGraphTestDefaults{}
+	m.AddProperties(&moduleGraphTestProperties{})
+	InitDefaultsModule(m)
+	return m
+}
@@ -33,8 +33,10 @@ type RuleBuilder struct {
 	temporariesSet map[WritablePath]bool
 	restat         bool
 	sbox           bool
+	sandboxInputs  bool
 	highmem        bool
 	remoteable     RemoteRuleSupports
+	rbeWrapper     RuleBuilderRemoteWrapper
 	sboxOutDir     WritablePath
 	missingDeps    []string
 }
@@ -96,17 +98,41 @@ func (r *RuleBuilder) HighMem() *RuleBuilder {
 	return r
 }
 
-// Remoteable marks the rule as supporting remote execution.
+// Remoteable marks the rule as supporting remote execution. The commands must already be wrapped
+// for remote execution, see Rewrapper to have RuleBuilder wrap them.
 func (r *RuleBuilder) Remoteable(supports RemoteRuleSupports) *RuleBuilder {
 	r.remoteable = supports
 	return r
 }
 
+// RuleBuilderRemoteWrapper wraps the commands of a RuleBuilder for remote execution with RBE. It is
+// implemented by *remoteexec.REParams.
+type RuleBuilderRemoteWrapper interface {
+	// WrapCommand returns command wrapped with the remote execution wrapper. inputs and outputs are
+	// the inputs and outputs of the rule, and rspFile lists more inputs if it is not empty.
+	WrapCommand(config Config, inputs []string, rspFile string, outputs []string, command string) string
+}
+
+// Rewrapper marks the rule as supporting remote execution with RBE, and wraps the commands with the
+// remote execution wrapper when USE_RBE is set. The inputs, tools, rsp file inputs, outputs and
+// depfiles of the commands are passed to the wrapper, so that they don't need to be duplicated in
+// its params.
+//
+// Rewrapper is not compatible with Sbox()
+func (r *RuleBuilder) Rewrapper(wrapper RuleBuilderRemoteWrapper) *RuleBuilder {
+	if r.sbox {
+		panic("Rewrapper() is not compatible with Sbox()")
+	}
+	r.remoteable.RBE = true
+	r.rbeWrapper = wrapper
+	return r
+}
+
 // Sbox marks the rule as needing to be wrapped by sbox. The WritablePath should point to the output
 // directory that sbox will wipe. It should not be written to by any other rule. sbox will ensure
 // that all outputs have been written, and will discard any output files that were not specified.
 //
-// Sbox is not compatible with Restat()
+// Sbox is not compatible with Restat() or Rewrapper()
 func (r *RuleBuilder) Sbox(outputDir WritablePath) *RuleBuilder {
 	if r.sbox {
 		panic("Sbox() may not be called more than once")
@@ -117,11 +143,27 @@ func (r *RuleBuilder) Sbox(outputDir WritablePath) *RuleBuilder {
 	if r.restat {
 		panic("Sbox() is not compatible with Restat()")
 	}
+	if r.rbeWrapper != nil {
+		panic("Sbox() is not compatible with Rewrapper()")
+	}
 	r.sbox = true
 	r.sboxOutDir = outputDir
 	return r
 }
 
+// SandboxInputs marks the rule as running in the hermetic sandbox of sbox, which only contains the
+// inputs, tools and rsp file inputs of the commands. The rule fails if the commands try to access
+// any other file in the source or output tree. The hermetic sandbox is only supported on Linux.
+//
+// SandboxInputs must be called after Sbox()
+func (r *RuleBuilder) SandboxInputs() *RuleBuilder {
+	if !r.sbox {
+		panic("SandboxInputs() must be called after Sbox()")
+	}
+	r.sandboxInputs = true
+	return r
+}
+
 // Install associates an output of the rule with an install location, which can be retrieved later using
 // RuleBuilder.Installs.
 func (r *RuleBuilder) Install(from Path, to string) {
@@ -423,12 +465,34 @@ func (r *RuleBuilder) Build(pctx PackageContext, ctx BuilderContext, name string
 			sboxCmd.Flag("--depfile-out").Text(depFile.String())
 		}
 
+		if r.sandboxInputs {
+			sboxCmd.Flag("--hermetic")
+		}
+
+		// Rules with depfiles aren't cached, the depfiles list inputs that aren't declared.
+		useCache := ctx.Config().SboxCache() && depFile == nil
+		if useCache {
+			sboxCmd.FlagWithArg("--cache-dir ", shared.SboxCacheDirForOutDir(PathForOutput(ctx).String()))
+		}
+
+		// The hermetic sandbox and the action cache need the inputs of the commands.
+		if r.sandboxInputs || useCache {
+			sboxCmd.FlagForEachArg("--input ", append(r.Inputs(), tools...).Strings())
+			if r.RspFileInputs() != nil {
+				sboxCmd.FlagWithArg("--input-list ", "$out.rsp")
+			}
+		}
+
 		sboxCmd.Flags(sboxOutputs)
 
 		commandString = sboxCmd.buf.String()
 		tools = append(tools, sboxCmd.tools...)
 	}
 
+	if ctx.Config().UseRBE() && r.rbeWrapper != nil {
+		commandString = r.rewrapperCommand(ctx, tools, commandString)
+	}
+
 	// Ninja doesn't like multiple outputs when depfiles are enabled, move all but the first output to
 	// ImplicitOutputs.  RuleBuilder only uses "$out" for the rsp file location, so the distinction between Outputs and
 	// ImplicitOutputs doesn't matter.
@@ -473,6 +537,25 @@ func (r *RuleBuilder) Build(pctx PackageContext, ctx BuilderContext, name string
 	})
 }
 
+// rewrapperCommand wraps the commands of the rule with the remote execution wrapper of Rewrapper,
+// using the inputs and outputs of the rule.
+func (r *RuleBuilder) rewrapperCommand(ctx PathContext, tools Paths, commandString string) string {
+	inputs := append(r.Inputs().Strings(), tools.Strings()...)
+	var rspFile string
+	if r.RspFileInputs() != nil {
+		rspFile = "$out.rsp"
+		inputs = append(inputs, rspFile)
+	}
+	outputs := append(r.Outputs().Strings(), r.DepFiles().Strings()...)
+
+	// The wrapper executes a single program, run the commands with bash.
+	commandString = proptools.ShellEscape(commandString)
+	if !strings.HasPrefix(commandString, `'`) {
+		commandString = `'` + commandString + `'`
+	}
+	return r.rbeWrapper.WrapCommand(ctx.Config(), inputs, rspFile, outputs, "bash -c "+commandString)
+}
+
 // RuleBuilderCommand is a builder for a command in a command line.  It can be mutated by its methods to add to the
 // command and track dependencies.  The methods mutate the RuleBuilderCommand in place, as well as return the
 // RuleBuilderCommand, so they can be used chained or unchained.  All methods that add text implicitly add a single
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/blueprint"
+	"github.com/google/blueprint/proptools"
 
 	"android/soong/shared"
 )
@@ -410,8 +411,10 @@ type testRuleBuilderModule struct {
 	properties struct {
 		Src string
 
-		Restat bool
-		Sbox   bool
+		Restat         bool
+		Sbox           bool
+		Sandbox_inputs bool
+		Rewrapper      bool
 	}
 }
 
@@ -421,7 +424,8 @@ func (t *testRuleBuilderModule) GenerateAndroidBuildActions(ctx ModuleContext) {
 	outDep := PathForModuleOut(ctx, ctx.ModuleName()+".d")
 	outDir := PathForModuleOut(ctx)
 
-	testRuleBuilder_Build(ctx, in, out, outDep, outDir, t.properties.Restat, t.properties.Sbox)
+	testRuleBuilder_Build(ctx, in, out, outDep, outDir, t.properties.Restat, t.properties.Sbox,
+		t.properties.Sandbox_inputs, t.properties.Rewrapper)
 }
 
 type testRuleBuilderSingleton struct{}
@@ -435,16 +439,25 @@ func (t *testRuleBuilderSingleton) GenerateBuildActions(ctx SingletonContext) {
 	out := PathForOutput(ctx, "baz")
 	outDep := PathForOutput(ctx, "baz.d")
 	outDir := PathForOutput(ctx)
-	testRuleBuilder_Build(ctx, in, out, outDep, outDir, true, false)
+	testRuleBuilder_Build(ctx, in, out, outDep, outDir, true, false, false, false)
 }
 
-func testRuleBuilder_Build(ctx BuilderContext, in Path, out, outDep, outDir WritablePath, restat, sbox bool) {
+func testRuleBuilder_Build(ctx BuilderContext, in Path, out, outDep, outDir WritablePath, restat, sbox,
+	sandboxInputs, rewrapper bool) {
 	rule := NewRuleBuilder()
 
 	if sbox {
 		rule.Sbox(outDir)
 	}
 
+	if sandboxInputs {
+		rule.SandboxInputs()
+	}
+
+	if rewrapper {
+		rule.Rewrapper(testRuleBuilderRemoteWrapper{})
+	}
+
 	rule.Command().Tool(PathForSource(ctx, "cp")).Input(in).Output(out).ImplicitDepFile(outDep)
 
 	if restat {
@@ -471,6 +484,12 @@ func TestRuleBuilder_Build(t *testing.T) {
 			src: "bar",
 			sbox: true,
 		}
+		rule_builder_test {
+			name: "foo_sandbox_inputs",
+			src: "bar",
+			sbox: true,
+			sandbox_inputs: true,
+		}
 	`
 
 	config := TestConfig(buildDir, nil, bp, fs)
@@ -538,6 +557,20 @@ func TestRuleBuilder_Build(t *testing.T) {
 		check(t, ctx.ModuleForTests("foo_sbox", "").Rule("rule"),
 			cmd, outFile, depFile, false, []string{sbox})
 	})
+	t.Run("sandbox_inputs", func(t *testing.T) {
+		outDir := filepath.Join(buildDir, ".intermediates", "foo_sandbox_inputs")
+		outFile := filepath.Join(outDir, "foo_sandbox_inputs")
+		depFile := filepath.Join(outDir, "foo_sandbox_inputs.d")
+		sbox := filepath.Join(buildDir, "host", config.PrebuiltOS(), "bin/sbox")
+		sandboxPath := shared.TempDirForOutDir(buildDir)
+
+		cmd := sbox + ` -c 'cp bar __SBOX_OUT_DIR__/foo_sandbox_inputs' --sandbox-path ` + sandboxPath +
+			" --output-root " + outDir + " --depfile-out " + depFile +
+			" --hermetic --input bar --input cp __SBOX_OUT_DIR__/foo_sandbox_inputs"
+
+		check(t, ctx.ModuleForTests("foo_sandbox_inputs", "").Rule("rule"),
+			cmd, outFile, depFile, false, []string{sbox})
+	})
 	t.Run("singleton", func(t *testing.T) {
 		outFile := filepath.Join(buildDir, "baz")
 		check(t, ctx.SingletonForTests("rule_builder_test").Rule("rule"),
@@ -545,6 +578,80 @@ func TestRuleBuilder_Build(t *testing.T) {
 	})
 }
 
+type testRuleBuilderRemoteWrapper struct{}
+
+func (testRuleBuilderRemoteWrapper) WrapCommand(config Config, inputs []string, rspFile string, outputs []string, command string) string {
+	ret := "rewrapper --inputs=" + strings.Join(inputs, ",")
+	if rspFile != "" {
+		ret += " --input_list_paths=" + rspFile
+	}
+	return ret + " --output_files=" + strings.Join(outputs, ",") + " -- " + command
+}
+
+func TestRuleBuilder_Rewrapper(t *testing.T) {
+	fs := map[string][]byte{
+		"bar": nil,
+		"cp":  nil,
+	}
+
+	bp := `
+		rule_builder_test {
+			name: "foo",
+			src: "bar",
+		}
+		rule_builder_test {
+			name: "foo_rewrapper",
+			src: "bar",
+			rewrapper: true,
+		}
+	`
+
+	run := func(t *testing.T, useRBE bool) *TestContext {
+		t.Helper()
+		config := TestConfig(buildDir, nil, bp, fs)
+		config.TestProductVariables.UseRBE = proptools.BoolPtr(useRBE)
+		ctx := NewTestContext()
+		ctx.RegisterModuleType("rule_builder_test", testRuleBuilderFactory)
+		ctx.Register(config)
+
+		_, errs := ctx.ParseFileList(".", []string{"Android.bp"})
+		FailIfErrored(t, errs)
+		_, errs = ctx.PrepareBuildActions(config)
+		FailIfErrored(t, errs)
+		return ctx
+	}
+
+	outFile := filepath.Join(buildDir, ".intermediates", "foo_rewrapper", "foo_rewrapper")
+
+	t.Run("rbe", func(t *testing.T) {
+		ctx := run(t, true)
+
+		params := ctx.ModuleForTests("foo_rewrapper", "").Rule("rule")
+		want := "rewrapper --inputs=bar,cp --output_files=" + outFile + "," + outFile + ".d" +
+			" -- bash -c 'cp bar " + outFile + "'"
+		if g := params.RuleParams.Command; g != want {
+			t.Errorf("\nwant RuleParams.Command = %q\n                      got %q", want, g)
+		}
+		if params.RuleParams.Pool != remotePool {
+			t.Errorf("want RuleParams.Pool = %v, got %v", remotePool, params.RuleParams.Pool)
+		}
+
+		// Rules that don't call Rewrapper aren't wrapped.
+		if g, w := ctx.ModuleForTests("foo", "").Rule("rule").RuleParams.Command, "cp bar "; !strings.HasPrefix(g, w) {
+			t.Errorf("want RuleParams.Command to start with %q, got %q", w, g)
+		}
+	})
+
+	t.Run("local", func(t *testing.T) {
+		ctx := run(t, false)
+
+		params := ctx.ModuleForTests("foo_rewrapper", "").Rule("rule")
+		if g, w := params.RuleParams.Command, "cp bar "+outFile; g != w {
+			t.Errorf("\nwant RuleParams.Command = %q\n                      got %q", w, g)
+		}
+	})
+}
+
 func Test_ninjaEscapeExceptForSpans(t *testing.T) {
 	type args struct {
 		s     string
@@ -335,6 +335,9 @@ type productVariables struct {
 
 	ProductVndkVersion *string `json:",omitempty"`
 
+	RamdiskSnapshotVersion  *string `json:",omitempty"`
+	RecoverySnapshotVersion *string `json:",omitempty"`
+
 	TargetFSConfigGen []string `json:",omitempty"`
 
 	MissingUsesLibraries []string `json:",omitempty"`
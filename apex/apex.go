@@ -31,6 +31,7 @@ import (
 	prebuilt_etc "android/soong/etc"
 	"android/soong/java"
 	"android/soong/python"
+	"android/soong/rust"
 	"android/soong/sh"
 )
 
@@ -909,6 +910,9 @@ type ApexNativeDependencies struct {
 	// List of native libraries
 	Native_shared_libs []string
 
+	// List of rust dylibs
+	Rust_dyn_libs []string
+
 	// List of JNI libraries
 	Jni_libs []string
 
@@ -1348,6 +1352,10 @@ func addDependenciesForNativeModules(ctx android.BottomUpMutatorContext,
 	binVariations := target.Variations()
 	libVariations := append(target.Variations(),
 		blueprint.Variation{Mutator: "link", Variation: "shared"})
+	// rust libraries are not split by the "link" mutator; their shared form is the dylib
+	// variant.
+	rustLibVariations := append(target.Variations(),
+		blueprint.Variation{Mutator: "rust_libraries", Variation: "dylib"})
 	testVariations := append(target.Variations(),
 		blueprint.Variation{Mutator: "test_per_src", Variation: ""}) // "" is the all-tests variant
 
@@ -1357,12 +1365,16 @@ func addDependenciesForNativeModules(ctx android.BottomUpMutatorContext,
 		libVariations = append(libVariations,
 			blueprint.Variation{Mutator: "image", Variation: imageVariation},
 			blueprint.Variation{Mutator: "version", Variation: ""}) // "" is the non-stub variant
+		rustLibVariations = append(rustLibVariations,
+			blueprint.Variation{Mutator: "image", Variation: imageVariation})
 		testVariations = append(testVariations,
 			blueprint.Variation{Mutator: "image", Variation: imageVariation})
 	}
 
 	ctx.AddFarVariationDependencies(libVariations, sharedLibTag, nativeModules.Native_shared_libs...)
 
+	ctx.AddFarVariationDependencies(rustLibVariations, sharedLibTag, nativeModules.Rust_dyn_libs...)
+
 	ctx.AddFarVariationDependencies(libVariations, jniLibTag, nativeModules.Jni_libs...)
 
 	ctx.AddFarVariationDependencies(binVariations, executableTag, nativeModules.Binaries...)
@@ -1406,6 +1418,7 @@ func (a *apexBundle) DepsMutator(ctx android.BottomUpMutatorContext) {
 		addDependenciesForNativeModules(ctx,
 			ApexNativeDependencies{
 				Native_shared_libs: a.properties.Native_shared_libs,
+				Rust_dyn_libs:      a.properties.Rust_dyn_libs,
 				Tests:              a.properties.Tests,
 				Jni_libs:           a.properties.Jni_libs,
 				Binaries:           nil,
@@ -1425,6 +1438,7 @@ func (a *apexBundle) DepsMutator(ctx android.BottomUpMutatorContext) {
 			addDependenciesForNativeModules(ctx,
 				ApexNativeDependencies{
 					Native_shared_libs: nil,
+					Rust_dyn_libs:      nil,
 					Tests:              nil,
 					Jni_libs:           nil,
 					Binaries:           a.properties.Binaries,
@@ -1702,6 +1716,35 @@ func apexFileForExecutable(ctx android.BaseModuleContext, cc *cc.Module) apexFil
 	return af
 }
 
+func apexFileForRustExecutable(ctx android.BaseModuleContext, rustm *rust.Module) apexFile {
+	dirInApex := "bin"
+	if rustm.Target().NativeBridge == android.NativeBridgeEnabled {
+		dirInApex = filepath.Join(dirInApex, rustm.Target().NativeBridgeRelativePath)
+	}
+	dirInApex = filepath.Join(dirInApex, rustm.RelativeInstallPath())
+	fileToCopy := rustm.OutputFile().Path()
+	androidMkModuleName := rustm.BaseModuleName() + rustm.Properties.SubName
+	return newApexFile(ctx, fileToCopy, androidMkModuleName, dirInApex, nativeExecutable, rustm)
+}
+
+func apexFileForRustLibrary(ctx android.BaseModuleContext, rustm *rust.Module) apexFile {
+	// Decide the APEX-local directory by the multilib of the library
+	var dirInApex string
+	switch rustm.Arch().ArchType.Multilib {
+	case "lib32":
+		dirInApex = "lib"
+	case "lib64":
+		dirInApex = "lib64"
+	}
+	if rustm.Target().NativeBridge == android.NativeBridgeEnabled {
+		dirInApex = filepath.Join(dirInApex, rustm.Target().NativeBridgeRelativePath)
+	}
+	dirInApex = filepath.Join(dirInApex, rustm.RelativeInstallPath())
+	fileToCopy := rustm.OutputFile().Path()
+	androidMkModuleName := rustm.BaseModuleName() + rustm.Properties.SubName
+	return newApexFile(ctx, fileToCopy, androidMkModuleName, dirInApex, nativeSharedLib, rustm)
+}
+
 func apexFileForPyBinary(ctx android.BaseModuleContext, py *python.Module) apexFile {
 	dirInApex := "bin"
 	fileToCopy := py.HostToolPath().Path()
@@ -2089,17 +2132,25 @@ func (a *apexBundle) GenerateAndroidBuildActions(ctx android.ModuleContext) {
 						provideNativeLibs = append(provideNativeLibs, fi.Stem())
 					}
 					return true // track transitive dependencies
+				} else if r, ok := child.(*rust.Module); ok {
+					fi := apexFileForRustLibrary(ctx, r)
+					fi.isJniLib = isJniLib
+					filesInfo = append(filesInfo, fi)
+					return true // track transitive dependencies
 				} else {
 					propertyName := "native_shared_libs"
 					if isJniLib {
 						propertyName = "jni_libs"
 					}
-					ctx.PropertyErrorf(propertyName, "%q is not a cc_library or cc_library_shared module", depName)
+					ctx.PropertyErrorf(propertyName, "%q is not a cc_library, cc_library_shared, rust_library or rust_ffi_shared module", depName)
 				}
 			case executableTag:
 				if cc, ok := child.(*cc.Module); ok {
 					filesInfo = append(filesInfo, apexFileForExecutable(ctx, cc))
 					return true // track transitive dependencies
+				} else if r, ok := child.(*rust.Module); ok {
+					filesInfo = append(filesInfo, apexFileForRustExecutable(ctx, r))
+					return true // track transitive dependencies
 				} else if sh, ok := child.(*sh.ShBinary); ok {
 					filesInfo = append(filesInfo, apexFileForShBinary(ctx, sh))
 				} else if py, ok := child.(*python.Module); ok && py.HostToolPath().Valid() {
@@ -2107,7 +2158,7 @@ func (a *apexBundle) GenerateAndroidBuildActions(ctx android.ModuleContext) {
 				} else if gb, ok := child.(bootstrap.GoBinaryTool); ok && a.Host() {
 					filesInfo = append(filesInfo, apexFileForGoBinary(ctx, depName, gb))
 				} else {
-					ctx.PropertyErrorf("binaries", "%q is neither cc_binary, (embedded) py_binary, (host) blueprint_go_binary, (host) bootstrap_go_binary, nor sh_binary", depName)
+					ctx.PropertyErrorf("binaries", "%q is neither cc_binary, rust_binary, (embedded) py_binary, (host) blueprint_go_binary, (host) bootstrap_go_binary, nor sh_binary", depName)
 				}
 			case javaLibTag:
 				switch child.(type) {
@@ -2250,7 +2301,23 @@ func (a *apexBundle) GenerateAndroidBuildActions(ctx android.ModuleContext) {
 						}
 						filesInfo = append(filesInfo, af)
 						return true // track transitive dependencies
+					} else if r, ok := child.(*rust.Module); ok {
+						af := apexFileForRustLibrary(ctx, r)
+						af.transitiveDep = true
+						filesInfo = append(filesInfo, af)
+						return true // track transitive dependencies
 					}
+				} else if rust.IsDylibDepTag(depTag) {
+					if r, ok := child.(*rust.Module); ok {
+						af := apexFileForRustLibrary(ctx, r)
+						af.transitiveDep = true
+						filesInfo = append(filesInfo, af)
+						return true // track transitive dependencies
+					}
+				} else if rust.IsRlibDepTag(depTag) {
+					// Rlibs are statically linked, but their shared and dylib dependencies
+					// still need to be in the APEX.
+					return true // track transitive dependencies
 				} else if cc.IsTestPerSrcDepTag(depTag) {
 					if cc, ok := child.(*cc.Module); ok {
 						af := apexFileForExecutable(ctx, cc)
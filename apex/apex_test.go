@@ -32,6 +32,7 @@ import (
 	"android/soong/dexpreopt"
 	prebuilt_etc "android/soong/etc"
 	"android/soong/java"
+	"android/soong/rust"
 	"android/soong/sh"
 )
 
@@ -245,6 +246,7 @@ func testApexContext(_ *testing.T, bp string, handlers ...testCustomizer) (*andr
 	ctx.PostDepsMutators(android.RegisterVisibilityRuleEnforcer)
 
 	cc.RegisterRequiredBuildComponentsForTest(ctx)
+	rust.RegisterRequiredBuildComponentsForTest(ctx)
 
 	ctx.RegisterModuleType("cc_test", cc.TestFactory)
 	ctx.RegisterModuleType("vndk_prebuilt_shared", cc.VndkPrebuiltSharedFactory)
@@ -992,6 +994,157 @@ func TestApexWithRuntimeLibsDependency(t *testing.T) {
 
 }
 
+// The rust standard libraries and liblog, which rust modules in an APEX link against.
+const rustStdLibsForApexTest = `
+		cc_library {
+			name: "liblog",
+			no_libcrt: true,
+			nocrt: true,
+			system_shared_libs: [],
+			stubs: {
+				versions: ["29"],
+			},
+		}
+
+		rust_library {
+			name: "libstd",
+			crate_name: "std",
+			srcs: ["mylib.rs"],
+			no_stdlibs: true,
+			native_coverage: false,
+			apex_available: ["myapex"],
+			min_sdk_version: "29",
+		}
+
+		rust_library {
+			name: "libtest",
+			crate_name: "test",
+			srcs: ["mylib.rs"],
+			no_stdlibs: true,
+			native_coverage: false,
+			apex_available: ["myapex"],
+			min_sdk_version: "29",
+		}
+`
+
+func TestApexWithRustModules(t *testing.T) {
+	ctx, _ := testApex(t, `
+		apex {
+			name: "myapex",
+			key: "myapex.key",
+			native_shared_libs: ["libfoo_ffi"],
+			rust_dyn_libs: ["libbaz_rust"],
+			binaries: ["foo_rust"],
+		}
+
+		apex_key {
+			name: "myapex.key",
+			public_key: "testkey.avbpubkey",
+			private_key: "testkey.pem",
+		}
+
+		rust_binary {
+			name: "foo_rust",
+			srcs: ["mylib.rs"],
+			rustlibs: ["libbar_rust"],
+			apex_available: ["myapex"],
+		}
+
+		rust_library_dylib {
+			name: "libbar_rust",
+			crate_name: "bar_rust",
+			srcs: ["mylib.rs"],
+			apex_available: ["myapex"],
+		}
+
+		rust_library_dylib {
+			name: "libbaz_rust",
+			crate_name: "baz_rust",
+			srcs: ["mylib.rs"],
+			apex_available: ["myapex"],
+		}
+
+		rust_ffi_shared {
+			name: "libfoo_ffi",
+			crate_name: "foo_ffi",
+			srcs: ["mylib.rs"],
+			apex_available: ["myapex"],
+		}
+	`+rustStdLibsForApexTest, withFiles(map[string][]byte{
+		"mylib.rs": nil,
+	}))
+
+	apexRule := ctx.ModuleForTests("myapex", "android_common_myapex_image").Rule("apexRule")
+	copyCmds := apexRule.Args["copy_commands"]
+
+	// Ensure that the rust binary, the rust libraries and their rust dylib deps are copied into apex
+	ensureContains(t, copyCmds, "image.apex/bin/foo_rust")
+	ensureContains(t, copyCmds, "image.apex/lib64/libfoo_ffi.so")
+	ensureContains(t, copyCmds, "image.apex/lib64/libbaz_rust.dylib.so")
+	ensureContains(t, copyCmds, "image.apex/lib64/libbar_rust.dylib.so")
+	ensureContains(t, copyCmds, "image.apex/lib64/libstd.dylib.so")
+
+	// Ensure that liblog, which provides stubs, is not included
+	ensureNotContains(t, copyCmds, "image.apex/lib64/liblog.so")
+
+	// Ensure that apex variants are created for the rust modules
+	ensureListContains(t, ctx.ModuleVariantsForTests("foo_rust"), "android_arm64_armv8-a_apex10000")
+	ensureListContains(t, ctx.ModuleVariantsForTests("libbar_rust"), "android_arm64_armv8-a_dylib_apex10000")
+}
+
+func TestApexMinSdkVersion_ErrorIfIncompatibleRustVersion(t *testing.T) {
+	testApexError(t, `module "libfoo_rust".*: should support min_sdk_version\(29\)`, `
+		apex {
+			name: "myapex",
+			key: "myapex.key",
+			rust_dyn_libs: ["libfoo_rust"],
+			min_sdk_version: "29",
+		}
+
+		apex_key {
+			name: "myapex.key",
+			public_key: "testkey.avbpubkey",
+			private_key: "testkey.pem",
+		}
+
+		rust_library_dylib {
+			name: "libfoo_rust",
+			crate_name: "foo_rust",
+			srcs: ["mylib.rs"],
+			apex_available: ["myapex"],
+			min_sdk_version: "30",
+		}
+	`+rustStdLibsForApexTest, withFiles(map[string][]byte{
+		"mylib.rs": nil,
+	}))
+}
+
+func TestApexAvailable_RustDirectDep(t *testing.T) {
+	// libfoo_rust is not available to myapex, but only to otherapex
+	testApexError(t, `requires "libfoo_rust" that is not available for the APEX`, `
+		apex {
+			name: "myapex",
+			key: "myapex.key",
+			rust_dyn_libs: ["libfoo_rust"],
+		}
+
+		apex_key {
+			name: "myapex.key",
+			public_key: "testkey.avbpubkey",
+			private_key: "testkey.pem",
+		}
+
+		rust_library_dylib {
+			name: "libfoo_rust",
+			crate_name: "foo_rust",
+			srcs: ["mylib.rs"],
+			apex_available: ["otherapex"],
+		}
+	`+rustStdLibsForApexTest, withFiles(map[string][]byte{
+		"mylib.rs": nil,
+	}))
+}
+
 func TestRuntimeApexShouldInstallHwasanIfLibcDependsOnIt(t *testing.T) {
 	ctx, _ := testApex(t, "", func(fs map[string][]byte, config android.Config) {
 		bp := `
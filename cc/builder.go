@@ -1026,6 +1026,9 @@ func TransformArchiveRepack(ctx android.ModuleContext, inputFile android.Path,
 }
 
 func gccCmd(toolchain config.Toolchain, cmd string) string {
+	if toolchain.GccRoot() == "" {
+		return config.LLVMBinutilsCmd(cmd)
+	}
 	return filepath.Join(toolchain.GccRoot(), "bin", toolchain.GccTriple()+"-"+cmd)
 }
 
@@ -1518,6 +1518,286 @@ func TestVendorSnapshotExcludeWithVendorAvailable(t *testing.T) {
 	})
 }
 
+func TestRecoverySnapshotCapture(t *testing.T) {
+	bp := `
+	cc_library {
+		name: "librecovery",
+		recovery: true,
+		nocrt: true,
+	}
+
+	cc_library {
+		name: "librecovery_available",
+		recovery_available: true,
+		nocrt: true,
+	}
+
+	cc_library {
+		name: "libsystem",
+		nocrt: true,
+	}
+
+	cc_library_headers {
+		name: "librecovery_headers",
+		recovery_available: true,
+		nocrt: true,
+	}
+
+	cc_binary {
+		name: "recovery_bin",
+		recovery: true,
+		nocrt: true,
+	}
+
+	cc_object {
+		name: "obj",
+		recovery_available: true,
+	}
+`
+	config := TestConfig(buildDir, android.Android, nil, bp, nil)
+	config.TestProductVariables.RecoverySnapshotVersion = StringPtr("current")
+	ctx := testCcWithConfig(t, config)
+
+	// Check Recovery snapshot output.
+
+	snapshotDir := "recovery-snapshot"
+	snapshotVariantPath := filepath.Join(buildDir, snapshotDir, "arm64")
+	snapshotSingleton := ctx.SingletonForTests("recovery-snapshot")
+
+	archDir := "arch-arm64-armv8-a"
+
+	// For libraries, all recovery: true and recovery_available modules are captured.
+	sharedVariant := "android_recovery_arm64_armv8-a_shared"
+	sharedDir := filepath.Join(snapshotVariantPath, archDir, "shared")
+	checkSnapshot(t, ctx, snapshotSingleton, "librecovery", "librecovery.so", sharedDir, sharedVariant)
+	checkSnapshot(t, ctx, snapshotSingleton, "librecovery_available", "librecovery_available.so", sharedDir, sharedVariant)
+
+	staticVariant := "android_recovery_arm64_armv8-a_static"
+	staticDir := filepath.Join(snapshotVariantPath, archDir, "static")
+	checkSnapshot(t, ctx, snapshotSingleton, "librecovery", "librecovery.a", staticDir, staticVariant)
+	checkSnapshot(t, ctx, snapshotSingleton, "librecovery_available", "librecovery_available.a", staticDir, staticVariant)
+
+	// Modules without recovery variants aren't captured.
+	checkSnapshotExclude(t, ctx, snapshotSingleton, "libsystem", "libsystem.so", sharedDir, "android_arm64_armv8-a_shared")
+
+	binaryDir := filepath.Join(snapshotVariantPath, archDir, "binary")
+	checkSnapshot(t, ctx, snapshotSingleton, "recovery_bin", "recovery_bin", binaryDir, "android_recovery_arm64_armv8-a")
+
+	objectDir := filepath.Join(snapshotVariantPath, archDir, "object")
+	checkSnapshot(t, ctx, snapshotSingleton, "obj", "obj.o", objectDir, "android_recovery_arm64_armv8-a")
+
+	for _, jsonFile := range []string{
+		filepath.Join(sharedDir, "librecovery.so.json"),
+		filepath.Join(sharedDir, "librecovery_available.so.json"),
+		filepath.Join(staticDir, "librecovery.a.json"),
+		filepath.Join(staticDir, "librecovery_available.a.json"),
+		filepath.Join(snapshotVariantPath, archDir, "header", "librecovery_headers.json"),
+		filepath.Join(binaryDir, "recovery_bin.json"),
+		filepath.Join(objectDir, "obj.o.json"),
+	} {
+		// verify all json files exist
+		if snapshotSingleton.MaybeOutput(jsonFile).Rule == nil {
+			t.Errorf("%q expected but not found", jsonFile)
+		}
+	}
+
+	// The vendor snapshot isn't captured along with the recovery snapshot.
+	if len(ctx.SingletonForTests("vendor-snapshot").AllOutputs()) != 0 {
+		t.Errorf("vendor snapshot must not be captured without BOARD_VNDK_VERSION=current")
+	}
+}
+
+func TestRecoverySnapshotUse(t *testing.T) {
+	frameworkBp := `
+	cc_library {
+		name: "librecovery_available",
+		recovery_available: true,
+		nocrt: true,
+		no_libcrt: true,
+		stl: "none",
+		system_shared_libs: [],
+		compile_multilib: "64",
+	}
+
+	cc_binary {
+		name: "bin",
+		recovery: true,
+		nocrt: true,
+		no_libcrt: true,
+		stl: "none",
+		system_shared_libs: [],
+		compile_multilib: "64",
+	}
+
+	cc_library_shared {
+		name: "libclient",
+		recovery: true,
+		nocrt: true,
+		no_libcrt: true,
+		stl: "none",
+		system_shared_libs: [],
+		shared_libs: ["librecovery_available"],
+		compile_multilib: "64",
+	}
+`
+
+	snapshotBp := `
+	recovery_snapshot_shared {
+		name: "librecovery_available",
+		version: "BOARD",
+		target_arch: "arm64",
+		recovery: true,
+		arch: {
+			arm64: {
+				src: "librecovery_available.so",
+			},
+		},
+	}
+
+	recovery_snapshot_binary {
+		name: "bin",
+		version: "BOARD",
+		target_arch: "arm64",
+		recovery: true,
+		arch: {
+			arm64: {
+				src: "bin",
+			},
+		},
+	}
+
+	recovery_snapshot_binary {
+		name: "bin",
+		version: "OTHER",
+		target_arch: "arm64",
+		recovery: true,
+		arch: {
+			arm64: {
+				src: "bin",
+			},
+		},
+	}
+`
+	depsBp := GatherRequiredDepsForTest(android.Android)
+
+	mockFS := map[string][]byte{
+		"deps/Android.bp":                   []byte(depsBp),
+		"framework/Android.bp":              []byte(frameworkBp),
+		"snapshot/Android.bp":               []byte(snapshotBp),
+		"snapshot/librecovery_available.so": nil,
+		"snapshot/bin":                      nil,
+	}
+
+	config := TestConfig(buildDir, android.Android, nil, "", mockFS)
+	config.TestProductVariables.RecoverySnapshotVersion = StringPtr("BOARD")
+	ctx := CreateTestContext()
+	ctx.Register(config)
+
+	_, errs := ctx.ParseFileList(".", []string{"deps/Android.bp", "framework/Android.bp", "snapshot/Android.bp"})
+	android.FailIfErrored(t, errs)
+	_, errs = ctx.PrepareBuildActions(config)
+	android.FailIfErrored(t, errs)
+
+	sharedVariant := "android_recovery_arm64_armv8-a_shared"
+	binaryVariant := "android_recovery_arm64_armv8-a"
+
+	// libclient uses librecovery_available.recovery_shared.BOARD.arm64
+	libclient := ctx.ModuleForTests("libclient", sharedVariant)
+	libclientFlags := libclient.Rule("ld").Args["libFlags"]
+	outputPaths := getOutputPaths(ctx, sharedVariant, []string{"librecovery_available.recovery_shared.BOARD.arm64"})
+	if !strings.Contains(libclientFlags, outputPaths[0].String()) {
+		t.Errorf("libflags for libclient must contain %#v, but was %#v", outputPaths[0], libclientFlags)
+	}
+
+	// Make knows the snapshot by the name of the recovery variant of the source module.
+	sharedLibs := libclient.Module().(*Module).Properties.AndroidMkSharedLibs
+	if !inList("librecovery_available.recovery", sharedLibs) {
+		t.Errorf("libclient must depend on librecovery_available.recovery in Make, but was %#v", sharedLibs)
+	}
+
+	// librecovery_available.so is installed by librecovery_available.recovery_shared.BOARD.arm64
+	ctx.ModuleForTests("librecovery_available.recovery_shared.BOARD.arm64", sharedVariant).Output("librecovery_available.so")
+
+	// The recovery variant of librecovery_available is replaced by the snapshot.
+	librecovery := ctx.ModuleForTests("librecovery_available", sharedVariant).Module().(*Module)
+	if !librecovery.Properties.HideFromMake {
+		t.Errorf("the recovery variant of librecovery_available must be hidden from Make")
+	}
+
+	// bin is installed by bin.recovery_binary.BOARD.arm64
+	ctx.ModuleForTests("bin.recovery_binary.BOARD.arm64", binaryVariant).Output("bin")
+	if ctx.ModuleForTests("bin", binaryVariant).Module().Enabled() {
+		t.Errorf("bin must be disabled in favor of its recovery snapshot")
+	}
+
+	// Snapshots of other versions are disabled.
+	if ctx.ModuleForTests("bin.recovery_binary.OTHER.arm64", binaryVariant).Module().Enabled() {
+		t.Errorf("bin.recovery_binary.OTHER.arm64 must be disabled")
+	}
+}
+
+func TestRamdiskSnapshotCapture(t *testing.T) {
+	bp := `
+	cc_library {
+		name: "libramdisk",
+		ramdisk: true,
+		nocrt: true,
+	}
+
+	cc_library {
+		name: "libramdisk_available",
+		ramdisk_available: true,
+		nocrt: true,
+	}
+
+	cc_library {
+		name: "libsystem",
+		nocrt: true,
+	}
+
+	cc_binary {
+		name: "ramdisk_bin",
+		ramdisk: true,
+		nocrt: true,
+	}
+`
+	config := TestConfig(buildDir, android.Android, nil, bp, nil)
+	config.TestProductVariables.RamdiskSnapshotVersion = StringPtr("current")
+	ctx := testCcWithConfig(t, config)
+
+	// Check Ramdisk snapshot output.
+
+	snapshotDir := "ramdisk-snapshot"
+	snapshotVariantPath := filepath.Join(buildDir, snapshotDir, "arm64")
+	snapshotSingleton := ctx.SingletonForTests("ramdisk-snapshot")
+
+	archDir := "arch-arm64-armv8-a"
+
+	// For libraries, all ramdisk: true and ramdisk_available modules are captured.
+	sharedVariant := "android_ramdisk_arm64_armv8-a_shared"
+	sharedDir := filepath.Join(snapshotVariantPath, archDir, "shared")
+	checkSnapshot(t, ctx, snapshotSingleton, "libramdisk", "libramdisk.so", sharedDir, sharedVariant)
+	checkSnapshot(t, ctx, snapshotSingleton, "libramdisk_available", "libramdisk_available.so", sharedDir, sharedVariant)
+
+	staticVariant := "android_ramdisk_arm64_armv8-a_static"
+	staticDir := filepath.Join(snapshotVariantPath, archDir, "static")
+	checkSnapshot(t, ctx, snapshotSingleton, "libramdisk", "libramdisk.a", staticDir, staticVariant)
+	checkSnapshot(t, ctx, snapshotSingleton, "libramdisk_available", "libramdisk_available.a", staticDir, staticVariant)
+
+	// Modules without ramdisk variants aren't captured.
+	checkSnapshotExclude(t, ctx, snapshotSingleton, "libsystem", "libsystem.so", sharedDir, "android_arm64_armv8-a_shared")
+
+	binaryDir := filepath.Join(snapshotVariantPath, archDir, "binary")
+	checkSnapshot(t, ctx, snapshotSingleton, "ramdisk_bin", "ramdisk_bin", binaryDir, "android_ramdisk_arm64_armv8-a")
+
+	// Neither the vendor nor the recovery snapshot is captured along with the ramdisk snapshot.
+	for _, singleton := range []string{"vendor-snapshot", "recovery-snapshot"} {
+		if len(ctx.SingletonForTests(singleton).AllOutputs()) != 0 {
+			t.Errorf("%s must not be captured along with the ramdisk snapshot", singleton)
+		}
+	}
+}
+
 func TestDoubleLoadableDepError(t *testing.T) {
 	// Check whether an error is emitted when a LLNDK depends on a non-double_loadable VNDK lib.
 	testCcError(t, "module \".*\" variant \".*\": link.* \".*\" which is not LL-NDK, VNDK-SP, .*double_loadable", `
@@ -194,6 +194,16 @@ func (toolchainArm64) LibclangRuntimeLibraryArch() string {
 	return "aarch64"
 }
 
+func (toolchainArm64) SupportedSanitizers() []string {
+	// HWASan requires the top-byte-ignore hardware feature, and SCS is only
+	// implemented on AArch64.
+	return append([]string{"hwaddress", "shadow-call-stack"}, lp64Sanitizers...)
+}
+
+func (toolchainArm64) CfiIncludePathsEnabled() bool {
+	return true
+}
+
 func arm64ToolchainFactory(arch android.Arch) Toolchain {
 	switch arch.ArchVariant {
 	case "armv8-a":
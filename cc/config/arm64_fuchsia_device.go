@@ -90,6 +90,14 @@ func (t *toolchainFuchsiaArm64) ToolchainClangCflags() string {
 	return "-march=armv8-a"
 }
 
+func (t *toolchainFuchsiaArm64) SupportedSanitizers() []string {
+	return append([]string{"hwaddress", "shadow-call-stack"}, fuchsiaSanitizers...)
+}
+
+func (t *toolchainFuchsiaArm64) CfiIncludePathsEnabled() bool {
+	return true
+}
+
 var toolchainArm64FuchsiaSingleton Toolchain = &toolchainFuchsiaArm64{}
 
 func arm64FuchsiaToolchainFactory(arch android.Arch) Toolchain {
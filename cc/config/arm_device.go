@@ -316,6 +316,24 @@ func (toolchainArm) LibclangRuntimeLibraryArch() string {
 	return "arm"
 }
 
+func (toolchainArm) SupportedSanitizers() []string {
+	// CFI is disabled for arm32 until b/35157333 is fixed.
+	return []string{"address", "fuzzer", "integer_overflow", "scudo", "undefined"}
+}
+
+func (toolchainArm) SanitizerInstructionSet(sanitizer string) string {
+	switch sanitizer {
+	case "address":
+		// Frame pointer based unwinder in ASan requires ARM frame setup.
+		return "arm"
+	case "cfi":
+		// __cfi_check needs to be built as Thumb (see the code in linker_cfi.cpp). LLVM is not set up
+		// to do this on a function basis, so force Thumb on the entire module.
+		return "thumb"
+	}
+	return ""
+}
+
 func armToolchainFactory(arch android.Arch) Toolchain {
 	var fixCortexA8 string
 	toolchainClangCflags := make([]string, 2, 3)
@@ -0,0 +1,174 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"android/soong/android"
+)
+
+var (
+	riscv64Cflags = []string{
+		// Help catch common 32/64-bit errors.
+		"-Werror=implicit-function-declaration",
+		// Bionic uses the TLS slots directly, emulated TLS is never wanted.
+		"-fno-emulated-tls",
+	}
+
+	riscv64ArchVariantCflags = map[string][]string{
+		"rv64gc": []string{
+			"-march=rv64gc",
+			"-mabi=lp64d",
+		},
+	}
+
+	riscv64Ldflags = []string{
+		"-Wl,--hash-style=gnu",
+		"-Wl,-z,separate-code",
+	}
+
+	riscv64Lldflags = append(ClangFilterUnknownLldflags(riscv64Ldflags),
+		"-Wl,-z,max-page-size=4096")
+
+	riscv64Cppflags = []string{}
+
+	riscv64ClangCpuVariantCflags = map[string][]string{
+		"sifive-u54": []string{
+			"-mcpu=sifive-u54",
+		},
+		"sifive-u74": []string{
+			"-mcpu=sifive-u74",
+		},
+	}
+)
+
+func init() {
+	pctx.StaticVariable("Riscv64Ldflags", strings.Join(riscv64Ldflags, " "))
+	pctx.StaticVariable("Riscv64Lldflags", strings.Join(riscv64Lldflags, " "))
+
+	pctx.StaticVariable("Riscv64ClangCflags", strings.Join(ClangFilterUnknownCflags(riscv64Cflags), " "))
+	pctx.StaticVariable("Riscv64ClangLdflags", strings.Join(ClangFilterUnknownCflags(riscv64Ldflags), " "))
+	pctx.StaticVariable("Riscv64ClangLldflags", strings.Join(ClangFilterUnknownCflags(riscv64Lldflags), " "))
+	pctx.StaticVariable("Riscv64ClangCppflags", strings.Join(ClangFilterUnknownCflags(riscv64Cppflags), " "))
+
+	pctx.StaticVariable("Riscv64ClangRv64gcCflags", strings.Join(riscv64ArchVariantCflags["rv64gc"], " "))
+
+	pctx.StaticVariable("Riscv64ClangSifiveU54Cflags",
+		strings.Join(riscv64ClangCpuVariantCflags["sifive-u54"], " "))
+
+	pctx.StaticVariable("Riscv64ClangSifiveU74Cflags",
+		strings.Join(riscv64ClangCpuVariantCflags["sifive-u74"], " "))
+}
+
+var (
+	riscv64ClangArchVariantCflagsVar = map[string]string{
+		"":       "${config.Riscv64ClangRv64gcCflags}",
+		"rv64gc": "${config.Riscv64ClangRv64gcCflags}",
+	}
+
+	riscv64ClangCpuVariantCflagsVar = map[string]string{
+		"":           "",
+		"sifive-u54": "${config.Riscv64ClangSifiveU54Cflags}",
+		"sifive-u74": "${config.Riscv64ClangSifiveU74Cflags}",
+	}
+)
+
+type toolchainRiscv64 struct {
+	toolchain64Bit
+
+	toolchainClangCflags string
+}
+
+func (t *toolchainRiscv64) Name() string {
+	return "riscv64"
+}
+
+// There is no GCC toolchain for riscv64, it only uses clang and the LLVM binutils.
+func (t *toolchainRiscv64) GccRoot() string {
+	return ""
+}
+
+func (t *toolchainRiscv64) GccTriple() string {
+	return "riscv64-linux-android"
+}
+
+func (t *toolchainRiscv64) GccVersion() string {
+	return ""
+}
+
+func (t *toolchainRiscv64) ToolPath() string {
+	return "${config.ClangBin}"
+}
+
+func (t *toolchainRiscv64) IncludeFlags() string {
+	return ""
+}
+
+func (t *toolchainRiscv64) ClangTriple() string {
+	return t.GccTriple()
+}
+
+func (t *toolchainRiscv64) ClangCflags() string {
+	return "${config.Riscv64ClangCflags}"
+}
+
+func (t *toolchainRiscv64) ClangCppflags() string {
+	return "${config.Riscv64ClangCppflags}"
+}
+
+func (t *toolchainRiscv64) ClangLdflags() string {
+	return "${config.Riscv64Ldflags}"
+}
+
+func (t *toolchainRiscv64) ClangLldflags() string {
+	return "${config.Riscv64Lldflags}"
+}
+
+func (t *toolchainRiscv64) ToolchainClangCflags() string {
+	return t.toolchainClangCflags
+}
+
+func (toolchainRiscv64) LibclangRuntimeLibraryArch() string {
+	return "riscv64"
+}
+
+func (toolchainRiscv64) SupportedSanitizers() []string {
+	// The riscv64 sanitizer runtimes do not yet provide cfi, scudo, tsan or
+	// safe-stack support.
+	return []string{"address", "fuzzer", "integer_overflow", "undefined"}
+}
+
+func riscv64ToolchainFactory(arch android.Arch) Toolchain {
+	switch arch.ArchVariant {
+	case "", "rv64gc":
+		// Nothing extra for rv64gc
+	default:
+		panic(fmt.Sprintf("Unknown RISC-V architecture version: %q", arch.ArchVariant))
+	}
+
+	toolchainClangCflags := []string{riscv64ClangArchVariantCflagsVar[arch.ArchVariant]}
+	toolchainClangCflags = append(toolchainClangCflags,
+		variantOrDefault(riscv64ClangCpuVariantCflagsVar, arch.CpuVariant))
+
+	return &toolchainRiscv64{
+		toolchainClangCflags: strings.Join(toolchainClangCflags, " "),
+	}
+}
+
+func init() {
+	registerToolchainFactory(android.Android, android.Riscv64, riscv64ToolchainFactory)
+}
@@ -43,6 +43,8 @@ func FindToolchain(os android.OsType, arch android.Arch) Toolchain {
 type Toolchain interface {
 	Name() string
 
+	// GccRoot returns the root of the GCC toolchain that provides the binutils, or "" if the
+	// toolchain only uses clang and the LLVM binutils
 	GccRoot() string
 	GccTriple() string
 	// GccVersion should return a real value, not a ninja reference
@@ -76,9 +78,55 @@ type Toolchain interface {
 
 	AvailableLibraries() []string
 
+	// SupportedSanitizers returns the names of the sanitizers, as spelled in
+	// SANITIZE_TARGET, that can be used with this toolchain.  Every toolchain
+	// must list them explicitly so that a new architecture does not silently
+	// inherit sanitizers it cannot build.
+	SupportedSanitizers() []string
+
+	// CfiIncludePathsEnabled returns true if CFI is enabled by default for the modules in
+	// CFI_INCLUDE_PATHS.
+	CfiIncludePathsEnabled() bool
+
+	// SanitizerInstructionSet returns the instruction set that a module must be built with when
+	// the named sanitizer is enabled, or "" if any instruction set works.
+	SanitizerInstructionSet(sanitizer string) string
+
 	Bionic() bool
 }
 
+var (
+	// Sanitizers available on every Linux and Android toolchain.
+	commonSanitizers = []string{
+		"address",
+		"cfi",
+		"fuzzer",
+		"integer_overflow",
+		"scudo",
+		"undefined",
+	}
+
+	// Sanitizers that additionally require a 64-bit address space.
+	lp64Sanitizers = append([]string{
+		"safe-stack",
+		"thread",
+	}, commonSanitizers...)
+
+	// Sanitizers available on Fuchsia, which has no UBSan runtime.
+	fuchsiaSanitizers = []string{
+		"address",
+		"fuzzer",
+		"safe-stack",
+		"scudo",
+		"thread",
+	}
+)
+
+// SanitizerSupported returns true if the named sanitizer can be used with the toolchain.
+func SanitizerSupported(t Toolchain, sanitizer string) bool {
+	return inList(sanitizer, t.SupportedSanitizers())
+}
+
 type toolchainBase struct {
 }
 
@@ -138,6 +186,14 @@ func (toolchainBase) AvailableLibraries() []string {
 	return []string{}
 }
 
+func (toolchainBase) CfiIncludePathsEnabled() bool {
+	return false
+}
+
+func (toolchainBase) SanitizerInstructionSet(sanitizer string) string {
+	return ""
+}
+
 func (toolchainBase) Bionic() bool {
 	return true
 }
@@ -238,4 +294,13 @@ func ToolPath(t Toolchain) string {
 	return filepath.Join(t.GccRoot(), t.GccTriple(), "bin")
 }
 
+// LLVMBinutilsCmd returns the LLVM replacement of a binutils command for the toolchains without
+// a GccRoot, or the prefix of the commands if cmd is empty.
+func LLVMBinutilsCmd(cmd string) string {
+	if cmd == "ld" {
+		return "${config.ClangBin}/ld.lld"
+	}
+	return "${config.ClangBin}/llvm-" + cmd
+}
+
 var inList = android.InList
@@ -0,0 +1,91 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"testing"
+
+	"android/soong/android"
+)
+
+func TestSanitizerSupported(t *testing.T) {
+	testCases := []struct {
+		name      string
+		os        android.OsType
+		arch      android.Arch
+		sanitizer string
+		expected  bool
+	}{
+		{"arm64 hwaddress", android.Android, android.Arch{ArchType: android.Arm64, ArchVariant: "armv8-a"}, "hwaddress", true},
+		{"arm64 scs", android.Android, android.Arch{ArchType: android.Arm64, ArchVariant: "armv8-a"}, "shadow-call-stack", true},
+		{"arm cfi", android.Android, android.Arch{ArchType: android.Arm, ArchVariant: "armv7-a-neon"}, "cfi", false},
+		{"arm thread", android.Android, android.Arch{ArchType: android.Arm, ArchVariant: "armv7-a-neon"}, "thread", false},
+		{"riscv64 address", android.Android, android.Arch{ArchType: android.Riscv64, ArchVariant: "rv64gc"}, "address", true},
+		{"riscv64 hwaddress", android.Android, android.Arch{ArchType: android.Riscv64, ArchVariant: "rv64gc"}, "hwaddress", false},
+		{"riscv64 cfi", android.Android, android.Arch{ArchType: android.Riscv64, ArchVariant: "rv64gc"}, "cfi", false},
+		{"x86_64 thread", android.Android, android.Arch{ArchType: android.X86_64}, "thread", true},
+		{"x86_64 hwaddress", android.Android, android.Arch{ArchType: android.X86_64}, "hwaddress", false},
+		{"darwin undefined", android.Darwin, android.Arch{ArchType: android.X86_64}, "undefined", false},
+		{"windows address", android.Windows, android.Arch{ArchType: android.X86_64}, "address", false},
+		{"fuchsia address", android.Fuchsia, android.Arch{ArchType: android.X86_64}, "address", true},
+		{"fuchsia fuzzer", android.Fuchsia, android.Arch{ArchType: android.X86_64}, "fuzzer", true},
+		{"fuchsia cfi", android.Fuchsia, android.Arch{ArchType: android.X86_64}, "cfi", false},
+		{"fuchsia arm64 hwaddress", android.Fuchsia, android.Arch{ArchType: android.Arm64}, "hwaddress", true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			toolchain := FindToolchain(testCase.os, testCase.arch)
+			if g, w := SanitizerSupported(toolchain, testCase.sanitizer), testCase.expected; g != w {
+				t.Errorf("expected SanitizerSupported(%q) to be %v, got %v", testCase.sanitizer, w, g)
+			}
+		})
+	}
+}
+
+func TestSanitizerToolchainData(t *testing.T) {
+	arm := FindToolchain(android.Android, android.Arch{ArchType: android.Arm, ArchVariant: "armv7-a-neon"})
+	arm64 := FindToolchain(android.Android, android.Arch{ArchType: android.Arm64, ArchVariant: "armv8-a"})
+	x86_64 := FindToolchain(android.Android, android.Arch{ArchType: android.X86_64})
+
+	if !arm64.CfiIncludePathsEnabled() {
+		t.Errorf("expected CFI_INCLUDE_PATHS to apply to arm64")
+	}
+	if x86_64.CfiIncludePathsEnabled() {
+		t.Errorf("expected CFI_INCLUDE_PATHS not to apply to x86_64")
+	}
+	if g, w := arm.SanitizerInstructionSet("address"), "arm"; g != w {
+		t.Errorf("expected arm address instruction set %q, got %q", w, g)
+	}
+	if g, w := arm.SanitizerInstructionSet("cfi"), "thumb"; g != w {
+		t.Errorf("expected arm cfi instruction set %q, got %q", w, g)
+	}
+	if g := arm64.SanitizerInstructionSet("address"); g != "" {
+		t.Errorf("expected no arm64 address instruction set, got %q", g)
+	}
+}
+
+func TestClangOnlyToolchain(t *testing.T) {
+	toolchain := FindToolchain(android.Android, android.Arch{ArchType: android.Riscv64, ArchVariant: "rv64gc"})
+	if g := toolchain.GccRoot(); g != "" {
+		t.Errorf("expected riscv64 to have no GccRoot, got %q", g)
+	}
+	if g, w := ToolPath(toolchain), "${config.ClangBin}"; g != w {
+		t.Errorf("expected ToolPath to be %q, got %q", w, g)
+	}
+	if g, w := LLVMBinutilsCmd("objcopy"), "${config.ClangBin}/llvm-objcopy"; g != w {
+		t.Errorf("expected LLVMBinutilsCmd(\"objcopy\") to be %q, got %q", w, g)
+	}
+}
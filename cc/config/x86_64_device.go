@@ -183,6 +183,10 @@ func (toolchainX86_64) LibclangRuntimeLibraryArch() string {
 	return "x86_64"
 }
 
+func (toolchainX86_64) SupportedSanitizers() []string {
+	return lp64Sanitizers
+}
+
 func x86_64ToolchainFactory(arch android.Arch) Toolchain {
 	toolchainClangCflags := []string{
 		"${config.X86_64ToolchainCflags}",
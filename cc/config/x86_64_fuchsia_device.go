@@ -87,6 +87,10 @@ func (t *toolchainFuchsiaX8664) Bionic() bool {
 	return false
 }
 
+func (t *toolchainFuchsiaX8664) SupportedSanitizers() []string {
+	return fuchsiaSanitizers
+}
+
 func (t *toolchainFuchsiaX8664) YasmFlags() string {
 	return "-f elf64 -m amd64"
 }
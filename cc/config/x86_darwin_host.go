@@ -240,6 +240,12 @@ func (t *toolchainDarwin) AvailableLibraries() []string {
 	return darwinAvailableLibraries
 }
 
+func (t *toolchainDarwin) SupportedSanitizers() []string {
+	// The UBSan runtime, which CFI and integer_overflow also rely on, is
+	// only available for Linux.
+	return []string{"address", "fuzzer", "safe-stack", "scudo", "thread"}
+}
+
 func (t *toolchainDarwin) Bionic() bool {
 	return false
 }
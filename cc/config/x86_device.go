@@ -194,6 +194,10 @@ func (toolchainX86) LibclangRuntimeLibraryArch() string {
 	return "i686"
 }
 
+func (toolchainX86) SupportedSanitizers() []string {
+	return commonSanitizers
+}
+
 func x86ToolchainFactory(arch android.Arch) Toolchain {
 	toolchainClangCflags := []string{
 		"${config.X86ToolchainCflags}",
@@ -137,6 +137,10 @@ func (t *toolchainLinuxBionic) Bionic() bool {
 	return true
 }
 
+func (toolchainLinuxBionic) SupportedSanitizers() []string {
+	return lp64Sanitizers
+}
+
 func (toolchainLinuxBionic) LibclangRuntimeLibraryArch() string {
 	return "x86_64"
 }
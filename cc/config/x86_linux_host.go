@@ -241,6 +241,14 @@ func (toolchainLinuxX8664) LibclangRuntimeLibraryArch() string {
 	return "x86_64"
 }
 
+func (toolchainLinuxX86) SupportedSanitizers() []string {
+	return commonSanitizers
+}
+
+func (toolchainLinuxX8664) SupportedSanitizers() []string {
+	return lp64Sanitizers
+}
+
 func (t *toolchainLinux) AvailableLibraries() []string {
 	return linuxAvailableLibraries
 }
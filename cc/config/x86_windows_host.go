@@ -259,6 +259,10 @@ func (t *toolchainWindows) AvailableLibraries() []string {
 	return windowsAvailableLibraries
 }
 
+func (t *toolchainWindows) SupportedSanitizers() []string {
+	return nil
+}
+
 func (t *toolchainWindows) Bionic() bool {
 	return false
 }
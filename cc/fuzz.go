@@ -16,6 +16,7 @@ package cc
 
 import (
 	"encoding/json"
+	"fmt"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -64,7 +65,7 @@ type FuzzProperties struct {
 
 func init() {
 	android.RegisterModuleType("cc_fuzz", FuzzFactory)
-	android.RegisterSingletonType("cc_fuzz_packaging", fuzzPackagingFactory)
+	android.RegisterSingletonType("cc_fuzz_packaging", FuzzPackagingFactory)
 }
 
 // cc_fuzz creates a host/device fuzzer binary. Host binaries can be found at
@@ -79,23 +80,69 @@ func NewFuzzInstaller() *baseInstaller {
 	return NewBaseInstaller("fuzz", "fuzz", InstallInData)
 }
 
+// FuzzPackagedModule holds the files that are installed and packaged alongside a fuzz
+// target. It is shared between cc_fuzz and the fuzz modules of other languages.
+type FuzzPackagedModule struct {
+	FuzzProperties        FuzzProperties
+	Dictionary            android.Path
+	Corpus                android.Paths
+	CorpusIntermediateDir android.Path
+	Config                android.Path
+	Data                  android.Paths
+	DataIntermediateDir   android.Path
+}
+
+// FuzzModule is implemented by modules whose fuzz target variants are packaged by the
+// cc_fuzz_packaging singleton.
+type FuzzModule interface {
+	LinkableInterface
+
+	// IsFuzzModule returns true if this module variant is a fuzz target.
+	IsFuzzModule() bool
+	FuzzPackagedModule() FuzzPackagedModule
+	UnstrippedOutputFile() android.Path
+	InstallPrevented() bool
+}
+
+// SharedFuzzDependency is implemented by non-cc modules, such as rust dylibs, whose shared
+// variants must be installed and packaged with the fuzz targets that depend on them.
+type SharedFuzzDependency interface {
+	IsSharedFuzzDependency() bool
+}
+
+type unstrippedOutputFileProducer interface {
+	UnstrippedOutputFile() android.Path
+}
+
 type fuzzBinary struct {
 	*binaryDecorator
 	*baseCompiler
 
-	Properties            FuzzProperties
-	dictionary            android.Path
-	corpus                android.Paths
-	corpusIntermediateDir android.Path
-	config                android.Path
-	data                  android.Paths
-	dataIntermediateDir   android.Path
-	installedSharedDeps   []string
+	fuzzPackagedModule  FuzzPackagedModule
+	installedSharedDeps []string
+}
+
+var _ FuzzModule = (*Module)(nil)
+
+func (c *Module) IsFuzzModule() bool {
+	_, ok := c.compiler.(*fuzzBinary)
+	return ok
+}
+
+func (c *Module) FuzzPackagedModule() FuzzPackagedModule {
+	if fuzz, ok := c.compiler.(*fuzzBinary); ok {
+		return fuzz.fuzzPackagedModule
+	}
+	panic(fmt.Errorf("FuzzPackagedModule called on non-fuzz module: %q", c.BaseModuleName()))
+}
+
+func (c *Module) InstallPrevented() bool {
+	return c.Properties.PreventInstall
 }
 
 func (fuzz *fuzzBinary) linkerProps() []interface{} {
 	props := fuzz.binaryDecorator.linkerProps()
-	props = append(props, &fuzz.Properties)
+	props = append(props, &fuzz.fuzzPackagedModule.FuzzProperties)
 	return props
 }
 
@@ -148,8 +195,8 @@ func collectAllSharedDependencies(ctx android.SingletonContext, module android.M
 		}
 		seen[module.Name()] = true
 
-		ccModule := module.(*Module)
-		sharedLibraries = append(sharedLibraries, ccModule.UnstrippedOutputFile())
+		sharedLibraries = append(sharedLibraries,
+			module.(unstrippedOutputFileProducer).UnstrippedOutputFile())
 		ctx.VisitDirectDeps(module, func(dep android.Module) {
 			if isValidSharedDependency(dep) && !seen[dep.Name()] {
 				fringe = append(fringe, dep)
@@ -169,6 +216,11 @@ func isValidSharedDependency(dependency android.Module) bool {
 	// TODO(b/144090547): We should be parsing these modules using
 	// ModuleDependencyTag instead of the current brute-force checking.
 
+	// Shared libraries of other languages (e.g. rust dylibs) decide for themselves.
+	if dep, ok := dependency.(SharedFuzzDependency); ok && dep.IsSharedFuzzDependency() {
+		return true
+	}
+
 	if linkable, ok := dependency.(LinkableInterface); !ok || // Discard non-linkables.
 		!linkable.CcLibraryInterface() || !linkable.Shared() || // Discard static libs.
 		linkable.UseVndk() || // Discard vendor linked libraries.
@@ -213,84 +265,124 @@ func (fuzz *fuzzBinary) install(ctx ModuleContext, file android.Path) {
 		"fuzz", ctx.Target().Arch.ArchType.String(), ctx.ModuleName())
 	fuzz.binaryDecorator.baseInstaller.install(ctx, file)
 
-	fuzz.corpus = android.PathsForModuleSrc(ctx, fuzz.Properties.Corpus)
+	fuzz.fuzzPackagedModule.CollectFuzzFiles(ctx)
+
+	fuzz.installedSharedDeps = InstalledFuzzSharedDeps(ctx)
+}
+
+// InstalledFuzzSharedDeps returns the install locations of the shared libraries a fuzz
+// target depends on, including their symbols on device.
+func InstalledFuzzSharedDeps(ctx android.ModuleContext) []string {
+	// Grab the list of required shared libraries.
+	seen := make(map[string]bool)
+	var sharedLibraries android.Paths
+	ctx.WalkDeps(func(child, parent android.Module) bool {
+		if seen[child.Name()] {
+			return false
+		}
+		seen[child.Name()] = true
+
+		if isValidSharedDependency(child) {
+			sharedLibraries = append(sharedLibraries, child.(unstrippedOutputFileProducer).UnstrippedOutputFile())
+			return true
+		}
+		return false
+	})
+
+	var installedSharedDeps []string
+	for _, lib := range sharedLibraries {
+		installedSharedDeps = append(installedSharedDeps,
+			sharedLibraryInstallLocation(
+				lib, ctx.Host(), ctx.Arch().ArchType.String()))
+
+		// Also add the dependency on the shared library symbols dir.
+		if !ctx.Host() {
+			installedSharedDeps = append(installedSharedDeps,
+				sharedLibrarySymbolsInstallLocation(lib, ctx.Arch().ArchType.String()))
+		}
+	}
+	return installedSharedDeps
+}
+
+// CollectFuzzFiles copies the corpus and data files of a fuzz target into its intermediates
+// directory and writes out its fuzz config, ready for installation and packaging.
+func (fuzz *FuzzPackagedModule) CollectFuzzFiles(ctx android.ModuleContext) {
+	fuzz.Corpus = android.PathsForModuleSrc(ctx, fuzz.FuzzProperties.Corpus)
 	builder := android.NewRuleBuilder()
 	intermediateDir := android.PathForModuleOut(ctx, "corpus")
-	for _, entry := range fuzz.corpus {
+	for _, entry := range fuzz.Corpus {
 		builder.Command().Text("cp").
 			Input(entry).
 			Output(intermediateDir.Join(ctx, entry.Base()))
 	}
 	builder.Build(pctx, ctx, "copy_corpus", "copy corpus")
-	fuzz.corpusIntermediateDir = intermediateDir
+	fuzz.CorpusIntermediateDir = intermediateDir
 
-	fuzz.data = android.PathsForModuleSrc(ctx, fuzz.Properties.Data)
+	fuzz.Data = android.PathsForModuleSrc(ctx, fuzz.FuzzProperties.Data)
 	builder = android.NewRuleBuilder()
 	intermediateDir = android.PathForModuleOut(ctx, "data")
-	for _, entry := range fuzz.data {
+	for _, entry := range fuzz.Data {
 		builder.Command().Text("cp").
 			Input(entry).
 			Output(intermediateDir.Join(ctx, entry.Rel()))
 	}
 	builder.Build(pctx, ctx, "copy_data", "copy data")
-	fuzz.dataIntermediateDir = intermediateDir
+	fuzz.DataIntermediateDir = intermediateDir
 
-	if fuzz.Properties.Dictionary != nil {
-		fuzz.dictionary = android.PathForModuleSrc(ctx, *fuzz.Properties.Dictionary)
-		if fuzz.dictionary.Ext() != ".dict" {
+	if fuzz.FuzzProperties.Dictionary != nil {
+		fuzz.Dictionary = android.PathForModuleSrc(ctx, *fuzz.FuzzProperties.Dictionary)
+		if fuzz.Dictionary.Ext() != ".dict" {
 			ctx.PropertyErrorf("dictionary",
 				"Fuzzer dictionary %q does not have '.dict' extension",
-				fuzz.dictionary.String())
+				fuzz.Dictionary.String())
 		}
 	}
 
-	if fuzz.Properties.Fuzz_config != nil {
+	if fuzz.FuzzProperties.Fuzz_config != nil {
 		configPath := android.PathForModuleOut(ctx, "config").Join(ctx, "config.json")
 		ctx.Build(pctx, android.BuildParams{
 			Rule:        android.WriteFile,
 			Description: "fuzzer infrastructure configuration",
 			Output:      configPath,
 			Args: map[string]string{
-				"content": fuzz.Properties.Fuzz_config.String(),
+				"content": fuzz.FuzzProperties.Fuzz_config.String(),
 			},
 		})
-		fuzz.config = configPath
+		fuzz.Config = configPath
 	}
+}
 
-	// Grab the list of required shared libraries.
-	seen := make(map[string]bool)
-	var sharedLibraries android.Paths
-	ctx.WalkDeps(func(child, parent android.Module) bool {
-		if seen[child.Name()] {
-			return false
-		}
-		seen[child.Name()] = true
+// FuzzFilesForMake returns the LOCAL_TEST_DATA entries that install the corpus, data,
+// dictionary and config of a fuzz target next to it.
+func (fuzz *FuzzPackagedModule) FuzzFilesForMake() []string {
+	var fuzzFiles []string
+	for _, d := range fuzz.Corpus {
+		fuzzFiles = append(fuzzFiles,
+			filepath.Dir(fuzz.CorpusIntermediateDir.String())+":corpus/"+d.Base())
+	}
 
-		if isValidSharedDependency(child) {
-			sharedLibraries = append(sharedLibraries, child.(*Module).UnstrippedOutputFile())
-			return true
-		}
-		return false
-	})
+	for _, d := range fuzz.Data {
+		fuzzFiles = append(fuzzFiles,
+			filepath.Dir(fuzz.DataIntermediateDir.String())+":data/"+d.Rel())
+	}
 
-	for _, lib := range sharedLibraries {
-		fuzz.installedSharedDeps = append(fuzz.installedSharedDeps,
-			sharedLibraryInstallLocation(
-				lib, ctx.Host(), ctx.Arch().ArchType.String()))
+	if fuzz.Dictionary != nil {
+		fuzzFiles = append(fuzzFiles,
+			filepath.Dir(fuzz.Dictionary.String())+":"+fuzz.Dictionary.Base())
+	}
 
-		// Also add the dependency on the shared library symbols dir.
-		if !ctx.Host() {
-			fuzz.installedSharedDeps = append(fuzz.installedSharedDeps,
-				sharedLibrarySymbolsInstallLocation(lib, ctx.Arch().ArchType.String()))
-		}
+	if fuzz.Config != nil {
+		fuzzFiles = append(fuzzFiles,
+			filepath.Dir(fuzz.Config.String())+":config.json")
 	}
+	return fuzzFiles
 }
 
 func NewFuzz(hod android.HostOrDeviceSupported) *Module {
 	module, binary := NewBinary(hod)
 
 	binary.baseInstaller = NewFuzzInstaller()
-	module.sanitize.SetSanitizer(fuzzer, true)
+	module.sanitize.SetSanitizer(Fuzzer, true)
 
 	fuzz := &fuzzBinary{
 		binaryDecorator: binary,
@@ -328,7 +420,7 @@ type fuzzPackager struct {
 	fuzzTargets             map[string]bool
 }
 
-func fuzzPackagingFactory() android.Singleton {
+func FuzzPackagingFactory() android.Singleton {
 	return &fuzzPackager{}
 }
 
@@ -358,35 +450,32 @@ func (s *fuzzPackager) GenerateBuildActions(ctx android.SingletonContext) {
 	s.fuzzTargets = make(map[string]bool)
 
 	ctx.VisitAllModules(func(module android.Module) {
-		// Discard non-fuzz targets.
-		ccModule, ok := module.(*Module)
-		if !ok {
-			return
-		}
-
-		fuzzModule, ok := ccModule.compiler.(*fuzzBinary)
-		if !ok {
+		// Discard non-fuzz targets. Fuzz targets of other languages (e.g. rust_fuzz)
+		// implement FuzzModule as well and are packaged in the same zips.
+		fuzzModule, ok := module.(FuzzModule)
+		if !ok || !fuzzModule.IsFuzzModule() {
 			return
 		}
+		fuzzPackagedModule := fuzzModule.FuzzPackagedModule()
 
 		// Discard ramdisk + recovery modules, they're duplicates of
 		// fuzz targets we're going to package anyway.
-		if !ccModule.Enabled() || ccModule.Properties.PreventInstall ||
-			ccModule.InRamdisk() || ccModule.InRecovery() {
+		if !module.Enabled() || fuzzModule.InstallPrevented() ||
+			fuzzModule.InRamdisk() || fuzzModule.InRecovery() {
 			return
 		}
 
 		// Discard modules that are in an unavailable namespace.
-		if !ccModule.ExportedToMake() {
+		if !module.ExportedToMake() {
 			return
 		}
 
 		hostOrTargetString := "target"
-		if ccModule.Host() {
+		if fuzzModule.Host() {
 			hostOrTargetString = "host"
 		}
 
-		archString := ccModule.Arch().ArchType.String()
+		archString := module.Target().Arch.ArchType.String()
 		archDir := android.PathForIntermediates(ctx, "fuzz", hostOrTargetString, archString)
 		archOs := archOs{hostOrTarget: hostOrTargetString, arch: archString, dir: archDir.String()}
 
@@ -397,21 +486,21 @@ func (s *fuzzPackager) GenerateBuildActions(ctx android.SingletonContext) {
 		builder := android.NewRuleBuilder()
 
 		// Package the corpora into a zipfile.
-		if fuzzModule.corpus != nil {
+		if fuzzPackagedModule.Corpus != nil {
 			corpusZip := archDir.Join(ctx, module.Name()+"_seed_corpus.zip")
 			command := builder.Command().BuiltTool(ctx, "soong_zip").
 				Flag("-j").
 				FlagWithOutput("-o ", corpusZip)
-			command.FlagWithRspFileInputList("-r ", fuzzModule.corpus)
+			command.FlagWithRspFileInputList("-r ", fuzzPackagedModule.Corpus)
 			files = append(files, fileToZip{corpusZip, ""})
 		}
 
 		// Package the data into a zipfile.
-		if fuzzModule.data != nil {
+		if fuzzPackagedModule.Data != nil {
 			dataZip := archDir.Join(ctx, module.Name()+"_data.zip")
 			command := builder.Command().BuiltTool(ctx, "soong_zip").
 				FlagWithOutput("-o ", dataZip)
-			for _, f := range fuzzModule.data {
+			for _, f := range fuzzPackagedModule.Data {
 				intermediateDir := strings.TrimSuffix(f.String(), f.Rel())
 				command.FlagWithArg("-C ", intermediateDir)
 				command.FlagWithInput("-f ", f)
@@ -428,7 +517,7 @@ func (s *fuzzPackager) GenerateBuildActions(ctx android.SingletonContext) {
 			// install it to the output directory. Setup the install destination here,
 			// which will be used by $(copy-many-files) in the Make backend.
 			installDestination := sharedLibraryInstallLocation(
-				library, ccModule.Host(), archString)
+				library, fuzzModule.Host(), archString)
 			if sharedLibraryInstalled[installDestination] {
 				continue
 			}
@@ -445,7 +534,7 @@ func (s *fuzzPackager) GenerateBuildActions(ctx android.SingletonContext) {
 			// dir. Symbolized DSO's are always installed to the device when fuzzing, but
 			// we want symbolization tools (like `stack`) to be able to find the symbols
 			// in $ANDROID_PRODUCT_OUT/symbols automagically.
-			if !ccModule.Host() {
+			if !fuzzModule.Host() {
 				symbolsInstallDestination := sharedLibrarySymbolsInstallLocation(library, archString)
 				symbolsInstallDestination = strings.ReplaceAll(symbolsInstallDestination, "$", "$$")
 				s.sharedLibInstallStrings = append(s.sharedLibInstallStrings,
@@ -454,16 +543,16 @@ func (s *fuzzPackager) GenerateBuildActions(ctx android.SingletonContext) {
 		}
 
 		// The executable.
-		files = append(files, fileToZip{ccModule.UnstrippedOutputFile(), ""})
+		files = append(files, fileToZip{fuzzModule.UnstrippedOutputFile(), ""})
 
 		// The dictionary.
-		if fuzzModule.dictionary != nil {
-			files = append(files, fileToZip{fuzzModule.dictionary, ""})
+		if fuzzPackagedModule.Dictionary != nil {
+			files = append(files, fileToZip{fuzzPackagedModule.Dictionary, ""})
 		}
 
 		// Additional fuzz config.
-		if fuzzModule.config != nil {
-			files = append(files, fileToZip{fuzzModule.config, ""})
+		if fuzzPackagedModule.Config != nil {
+			files = append(files, fileToZip{fuzzPackagedModule.Config, ""})
 		}
 
 		fuzzZip := archDir.Join(ctx, module.Name()+".zip")
@@ -484,8 +573,8 @@ func (s *fuzzPackager) GenerateBuildActions(ctx android.SingletonContext) {
 
 		// Don't add modules to 'make haiku' that are set to not be exported to the
 		// fuzzing infrastructure.
-		if config := fuzzModule.Properties.Fuzz_config; config != nil {
-			if ccModule.Host() && !BoolDefault(config.Fuzz_on_haiku_host, true) {
+		if config := fuzzPackagedModule.FuzzProperties.Fuzz_config; config != nil {
+			if fuzzModule.Host() && !BoolDefault(config.Fuzz_on_haiku_host, true) {
 				return
 			} else if !BoolDefault(config.Fuzz_on_haiku_device, true) {
 				return
@@ -180,7 +180,13 @@ func (m *Module) ImageMutatorBegin(mctx android.BaseModuleContext) {
 		productVndkVersion = platformVndkVersion
 	}
 
-	if boardVndkVersion == "" {
+	if ramdiskImage.isSnapshotPrebuilt(m) {
+		// Ramdisk snapshot prebuilts only replace ramdisk variants.
+		ramdiskVariantNeeded = true
+	} else if recoveryImage.isSnapshotPrebuilt(m) {
+		// Recovery snapshot prebuilts only replace recovery variants.
+		recoveryVariantNeeded = true
+	} else if boardVndkVersion == "" {
 		// If the device isn't compiling against the VNDK, we always
 		// use the core mode.
 		coreVariantNeeded = true
@@ -205,7 +211,7 @@ func (m *Module) ImageMutatorBegin(mctx android.BaseModuleContext) {
 			platformVndkVersion,
 			productVndkVersion,
 		)
-	} else if m.isSnapshotPrebuilt() {
+	} else if vendorImage.isSnapshotPrebuilt(m) {
 		// Make vendor variants only for the versions in BOARD_VNDK_VERSION and
 		// PRODUCT_EXTRA_VNDK_VERSIONS.
 		if snapshot, ok := m.linker.(interface {
@@ -223,7 +229,7 @@ func (m *Module) ImageMutatorBegin(mctx android.BaseModuleContext) {
 		// We assume that modules under proprietary paths are compatible for
 		// BOARD_VNDK_VERSION. The other modules are regarded as AOSP, or
 		// PLATFORM_VNDK_VERSION.
-		if isVendorProprietaryModule(mctx) {
+		if IsVendorProprietaryModule(mctx) {
 			vendorVariants = append(vendorVariants, boardVndkVersion)
 		} else {
 			vendorVariants = append(vendorVariants, platformVndkVersion)
@@ -249,7 +255,7 @@ func (m *Module) ImageMutatorBegin(mctx android.BaseModuleContext) {
 				platformVndkVersion,
 				boardVndkVersion,
 			)
-		} else if isVendorProprietaryModule(mctx) {
+		} else if IsVendorProprietaryModule(mctx) {
 			vendorVariants = append(vendorVariants, boardVndkVersion)
 		} else {
 			vendorVariants = append(vendorVariants, platformVndkVersion)
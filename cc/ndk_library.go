@@ -120,10 +120,11 @@ func normalizeNdkApiLevel(ctx android.BaseModuleContext, apiLevel string,
 
 	minVersion := ctx.Config().MinSupportedSdkVersion()
 	firstArchVersions := map[android.ArchType]int{
-		android.Arm:    minVersion,
-		android.Arm64:  21,
-		android.X86:    minVersion,
-		android.X86_64: 21,
+		android.Arm:     minVersion,
+		android.Arm64:   21,
+		android.Riscv64: android.FutureApiLevel,
+		android.X86:     minVersion,
+		android.X86_64:  21,
 	}
 
 	firstArchVersion, ok := firstArchVersions[arch.ArchType]
@@ -0,0 +1,248 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cc
+
+// This file contains the ramdisk snapshot, which captures the ramdisk variants of the
+// modules in AOSP so that the ramdisk image can be built against an older platform, the same
+// way the vendor snapshot does for the vendor image. BOARD_RAMDISK_SNAPSHOT_VERSION selects
+// the snapshot: "current" captures it, and any other version restores the ramdisk_snapshot_*
+// prebuilts of that version in place of the source modules.
+
+import (
+	"android/soong/android"
+)
+
+const (
+	ramdiskSnapshotHeaderSuffix = ".ramdisk_header."
+	ramdiskSnapshotSharedSuffix = ".ramdisk_shared."
+	ramdiskSnapshotStaticSuffix = ".ramdisk_static."
+	ramdiskSnapshotBinarySuffix = ".ramdisk_binary."
+	ramdiskSnapshotObjectSuffix = ".ramdisk_object."
+)
+
+var (
+	ramdiskSuffixModulesKey      = android.NewOnceKey("ramdiskSuffixModules")
+	ramdiskSnapshotHeaderLibsKey = android.NewOnceKey("ramdiskSnapshotHeaderLibs")
+	ramdiskSnapshotStaticLibsKey = android.NewOnceKey("ramdiskSnapshotStaticLibs")
+	ramdiskSnapshotSharedLibsKey = android.NewOnceKey("ramdiskSnapshotSharedLibs")
+	ramdiskSnapshotBinariesKey   = android.NewOnceKey("ramdiskSnapshotBinaries")
+	ramdiskSnapshotObjectsKey    = android.NewOnceKey("ramdiskSnapshotObjects")
+)
+
+// ramdisk snapshot maps hold names of ramdisk snapshot modules per arch
+func ramdiskSuffixModules(config android.Config) map[string]bool {
+	return config.Once(ramdiskSuffixModulesKey, func() interface{} {
+		return make(map[string]bool)
+	}).(map[string]bool)
+}
+
+func ramdiskSnapshotHeaderLibs(config android.Config) *SnapshotMap {
+	return config.Once(ramdiskSnapshotHeaderLibsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func ramdiskSnapshotSharedLibs(config android.Config) *SnapshotMap {
+	return config.Once(ramdiskSnapshotSharedLibsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func ramdiskSnapshotStaticLibs(config android.Config) *SnapshotMap {
+	return config.Once(ramdiskSnapshotStaticLibsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func ramdiskSnapshotBinaries(config android.Config) *SnapshotMap {
+	return config.Once(ramdiskSnapshotBinariesKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func ramdiskSnapshotObjects(config android.Config) *SnapshotMap {
+	return config.Once(ramdiskSnapshotObjectsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+type ramdiskSnapshotImage struct{}
+
+var ramdiskImage snapshotImage = ramdiskSnapshotImage{}
+
+func (ramdiskSnapshotImage) imageName() string {
+	return "ramdisk"
+}
+
+func (ramdiskSnapshotImage) snapshotVersion(config android.DeviceConfig) string {
+	return config.RamdiskSnapshotVersion()
+}
+
+func (ramdiskSnapshotImage) usesSnapshot(m *Module, config android.DeviceConfig) bool {
+	return m.InRamdisk()
+}
+
+func (ramdiskSnapshotImage) isSnapshotModule(m *Module, inProprietaryPath bool) bool {
+	return isRamdiskSnapshotModule(m, inProprietaryPath)
+}
+
+func (ramdiskSnapshotImage) isSnapshotPrebuilt(m *Module) bool {
+	return m.isRamdiskSnapshotPrebuilt()
+}
+
+func (ramdiskSnapshotImage) isSnapshotLibrary(l SnapshotLibrary, inProprietaryPath bool) bool {
+	// TODO: capture the ramdisk variants of modules which aren't cc modules.
+	return false
+}
+
+func (ramdiskSnapshotImage) replaceableBySnapshot(m *Module, config android.Config) bool {
+	return true
+}
+
+func (ramdiskSnapshotImage) needsNameSuffix(m *Module) bool {
+	// ramdisk suffix should be added to snapshots if the source module is ramdisk_available
+	// rather than "ramdisk: true".
+	return m.InRamdisk() && !m.OnlyInRamdisk()
+}
+
+func (ramdiskSnapshotImage) moduleNameSuffix() string {
+	return ramdiskSuffix
+}
+
+func (ramdiskSnapshotImage) suffixModules(config android.Config) map[string]bool {
+	return ramdiskSuffixModules(config)
+}
+
+func (ramdiskSnapshotImage) headerLibs(config android.Config) *SnapshotMap {
+	return ramdiskSnapshotHeaderLibs(config)
+}
+
+func (ramdiskSnapshotImage) sharedLibs(config android.Config) *SnapshotMap {
+	return ramdiskSnapshotSharedLibs(config)
+}
+
+func (ramdiskSnapshotImage) staticLibs(config android.Config) *SnapshotMap {
+	return ramdiskSnapshotStaticLibs(config)
+}
+
+func (ramdiskSnapshotImage) binaries(config android.Config) *SnapshotMap {
+	return ramdiskSnapshotBinaries(config)
+}
+
+func (ramdiskSnapshotImage) objects(config android.Config) *SnapshotMap {
+	return ramdiskSnapshotObjects(config)
+}
+
+func RamdiskSnapshotSharedFactory() android.Module {
+	module, prebuilt := snapshotLibrary(ramdiskImage, ramdiskSnapshotSharedSuffix)
+	prebuilt.libraryDecorator.BuildOnlyShared()
+	return module.Init()
+}
+
+func RamdiskSnapshotStaticFactory() android.Module {
+	module, prebuilt := snapshotLibrary(ramdiskImage, ramdiskSnapshotStaticSuffix)
+	prebuilt.libraryDecorator.BuildOnlyStatic()
+	return module.Init()
+}
+
+func RamdiskSnapshotHeaderFactory() android.Module {
+	module, prebuilt := snapshotLibrary(ramdiskImage, ramdiskSnapshotHeaderSuffix)
+	prebuilt.libraryDecorator.HeaderOnly()
+	return module.Init()
+}
+
+func RamdiskSnapshotBinaryFactory() android.Module {
+	return snapshotBinary(ramdiskImage, ramdiskSnapshotBinarySuffix)
+}
+
+func RamdiskSnapshotObjectFactory() android.Module {
+	return snapshotObject(ramdiskImage, ramdiskSnapshotObjectSuffix)
+}
+
+func init() {
+	android.RegisterSingletonType("ramdisk-snapshot", RamdiskSnapshotSingleton)
+	android.RegisterModuleType("ramdisk_snapshot_shared", RamdiskSnapshotSharedFactory)
+	android.RegisterModuleType("ramdisk_snapshot_static", RamdiskSnapshotStaticFactory)
+	android.RegisterModuleType("ramdisk_snapshot_header", RamdiskSnapshotHeaderFactory)
+	android.RegisterModuleType("ramdisk_snapshot_binary", RamdiskSnapshotBinaryFactory)
+	android.RegisterModuleType("ramdisk_snapshot_object", RamdiskSnapshotObjectFactory)
+}
+
+func RamdiskSnapshotSingleton() android.Singleton {
+	return &snapshotSingleton{
+		image:   ramdiskImage,
+		makeVar: "SOONG_RAMDISK_SNAPSHOT_ZIP",
+	}
+}
+
+// Determine if a module is going to be included in ramdisk snapshot or not.
+//
+// Targets of ramdisk snapshot are "ramdisk: true" or "ramdisk_available: true" modules in
+// AOSP. Proprietary modules are built from source along with the ramdisk image, so they
+// aren't captured.
+func isRamdiskSnapshotModule(m *Module, inProprietaryPath bool) bool {
+	if !m.Enabled() || m.Properties.HideFromMake {
+		return false
+	}
+	if inProprietaryPath {
+		return false
+	}
+	if m.Target().Os.Class != android.Device {
+		return false
+	}
+	if m.Target().NativeBridge == android.NativeBridgeEnabled {
+		return false
+	}
+	// the module must be installed in the ramdisk
+	if !m.IsForPlatform() || m.isSnapshotPrebuilt() || !m.InRamdisk() {
+		return false
+	}
+	// skip kernel_headers which always depend on the device
+	if _, ok := m.linker.(*kernelHeadersDecorator); ok {
+		return false
+	}
+	// TODO: add support for sanitized variants
+	if m.sanitize != nil {
+		for _, t := range []SanitizerType{cfi, scs, Hwasan} {
+			if m.sanitize.isSanitizerEnabled(t) {
+				return false
+			}
+		}
+	}
+
+	// Libraries
+	if l, ok := m.linker.(snapshotLibraryInterface); ok {
+		if l.static() || l.shared() {
+			return m.outputFile.Valid()
+		}
+		return true
+	}
+
+	// Binaries and Objects
+	if m.binary() || m.object() {
+		return m.outputFile.Valid()
+	}
+
+	return false
+}
+
+// gathers all snapshot modules for ramdisk, and disable unnecessary snapshots
+func RamdiskSnapshotMutator(ctx android.BottomUpMutatorContext) {
+	snapshotMutator(ctx, ramdiskImage)
+}
+
+// Disables source modules which have ramdisk snapshots
+func RamdiskSnapshotSourceMutator(ctx android.BottomUpMutatorContext) {
+	snapshotSourceMutator(ctx, ramdiskImage)
+}
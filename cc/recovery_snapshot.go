@@ -0,0 +1,248 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cc
+
+// This file contains the recovery snapshot, which captures the recovery variants of the
+// modules in AOSP so that the recovery image can be built against an older platform, the same
+// way the vendor snapshot does for the vendor image. BOARD_RECOVERY_SNAPSHOT_VERSION selects
+// the snapshot: "current" captures it, and any other version restores the recovery_snapshot_*
+// prebuilts of that version in place of the source modules.
+
+import (
+	"android/soong/android"
+)
+
+const (
+	recoverySnapshotHeaderSuffix = ".recovery_header."
+	recoverySnapshotSharedSuffix = ".recovery_shared."
+	recoverySnapshotStaticSuffix = ".recovery_static."
+	recoverySnapshotBinarySuffix = ".recovery_binary."
+	recoverySnapshotObjectSuffix = ".recovery_object."
+)
+
+var (
+	recoverySuffixModulesKey      = android.NewOnceKey("recoverySuffixModules")
+	recoverySnapshotHeaderLibsKey = android.NewOnceKey("recoverySnapshotHeaderLibs")
+	recoverySnapshotStaticLibsKey = android.NewOnceKey("recoverySnapshotStaticLibs")
+	recoverySnapshotSharedLibsKey = android.NewOnceKey("recoverySnapshotSharedLibs")
+	recoverySnapshotBinariesKey   = android.NewOnceKey("recoverySnapshotBinaries")
+	recoverySnapshotObjectsKey    = android.NewOnceKey("recoverySnapshotObjects")
+)
+
+// recovery snapshot maps hold names of recovery snapshot modules per arch
+func recoverySuffixModules(config android.Config) map[string]bool {
+	return config.Once(recoverySuffixModulesKey, func() interface{} {
+		return make(map[string]bool)
+	}).(map[string]bool)
+}
+
+func recoverySnapshotHeaderLibs(config android.Config) *SnapshotMap {
+	return config.Once(recoverySnapshotHeaderLibsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func recoverySnapshotSharedLibs(config android.Config) *SnapshotMap {
+	return config.Once(recoverySnapshotSharedLibsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func recoverySnapshotStaticLibs(config android.Config) *SnapshotMap {
+	return config.Once(recoverySnapshotStaticLibsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func recoverySnapshotBinaries(config android.Config) *SnapshotMap {
+	return config.Once(recoverySnapshotBinariesKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+func recoverySnapshotObjects(config android.Config) *SnapshotMap {
+	return config.Once(recoverySnapshotObjectsKey, func() interface{} {
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+type recoverySnapshotImage struct{}
+
+var recoveryImage snapshotImage = recoverySnapshotImage{}
+
+func (recoverySnapshotImage) imageName() string {
+	return "recovery"
+}
+
+func (recoverySnapshotImage) snapshotVersion(config android.DeviceConfig) string {
+	return config.RecoverySnapshotVersion()
+}
+
+func (recoverySnapshotImage) usesSnapshot(m *Module, config android.DeviceConfig) bool {
+	return m.InRecovery()
+}
+
+func (recoverySnapshotImage) isSnapshotModule(m *Module, inProprietaryPath bool) bool {
+	return isRecoverySnapshotModule(m, inProprietaryPath)
+}
+
+func (recoverySnapshotImage) isSnapshotPrebuilt(m *Module) bool {
+	return m.isRecoverySnapshotPrebuilt()
+}
+
+func (recoverySnapshotImage) isSnapshotLibrary(l SnapshotLibrary, inProprietaryPath bool) bool {
+	// TODO: capture the recovery variants of modules which aren't cc modules.
+	return false
+}
+
+func (recoverySnapshotImage) replaceableBySnapshot(m *Module, config android.Config) bool {
+	return true
+}
+
+func (recoverySnapshotImage) needsNameSuffix(m *Module) bool {
+	// recovery suffix should be added to snapshots if the source module is recovery_available
+	// rather than "recovery: true".
+	return m.InRecovery() && !m.OnlyInRecovery()
+}
+
+func (recoverySnapshotImage) moduleNameSuffix() string {
+	return recoverySuffix
+}
+
+func (recoverySnapshotImage) suffixModules(config android.Config) map[string]bool {
+	return recoverySuffixModules(config)
+}
+
+func (recoverySnapshotImage) headerLibs(config android.Config) *SnapshotMap {
+	return recoverySnapshotHeaderLibs(config)
+}
+
+func (recoverySnapshotImage) sharedLibs(config android.Config) *SnapshotMap {
+	return recoverySnapshotSharedLibs(config)
+}
+
+func (recoverySnapshotImage) staticLibs(config android.Config) *SnapshotMap {
+	return recoverySnapshotStaticLibs(config)
+}
+
+func (recoverySnapshotImage) binaries(config android.Config) *SnapshotMap {
+	return recoverySnapshotBinaries(config)
+}
+
+func (recoverySnapshotImage) objects(config android.Config) *SnapshotMap {
+	return recoverySnapshotObjects(config)
+}
+
+func RecoverySnapshotSharedFactory() android.Module {
+	module, prebuilt := snapshotLibrary(recoveryImage, recoverySnapshotSharedSuffix)
+	prebuilt.libraryDecorator.BuildOnlyShared()
+	return module.Init()
+}
+
+func RecoverySnapshotStaticFactory() android.Module {
+	module, prebuilt := snapshotLibrary(recoveryImage, recoverySnapshotStaticSuffix)
+	prebuilt.libraryDecorator.BuildOnlyStatic()
+	return module.Init()
+}
+
+func RecoverySnapshotHeaderFactory() android.Module {
+	module, prebuilt := snapshotLibrary(recoveryImage, recoverySnapshotHeaderSuffix)
+	prebuilt.libraryDecorator.HeaderOnly()
+	return module.Init()
+}
+
+func RecoverySnapshotBinaryFactory() android.Module {
+	return snapshotBinary(recoveryImage, recoverySnapshotBinarySuffix)
+}
+
+func RecoverySnapshotObjectFactory() android.Module {
+	return snapshotObject(recoveryImage, recoverySnapshotObjectSuffix)
+}
+
+func init() {
+	android.RegisterSingletonType("recovery-snapshot", RecoverySnapshotSingleton)
+	android.RegisterModuleType("recovery_snapshot_shared", RecoverySnapshotSharedFactory)
+	android.RegisterModuleType("recovery_snapshot_static", RecoverySnapshotStaticFactory)
+	android.RegisterModuleType("recovery_snapshot_header", RecoverySnapshotHeaderFactory)
+	android.RegisterModuleType("recovery_snapshot_binary", RecoverySnapshotBinaryFactory)
+	android.RegisterModuleType("recovery_snapshot_object", RecoverySnapshotObjectFactory)
+}
+
+func RecoverySnapshotSingleton() android.Singleton {
+	return &snapshotSingleton{
+		image:   recoveryImage,
+		makeVar: "SOONG_RECOVERY_SNAPSHOT_ZIP",
+	}
+}
+
+// Determine if a module is going to be included in recovery snapshot or not.
+//
+// Targets of recovery snapshot are "recovery: true" or "recovery_available: true" modules in
+// AOSP. Proprietary modules are built from source along with the recovery image, so they
+// aren't captured.
+func isRecoverySnapshotModule(m *Module, inProprietaryPath bool) bool {
+	if !m.Enabled() || m.Properties.HideFromMake {
+		return false
+	}
+	if inProprietaryPath {
+		return false
+	}
+	if m.Target().Os.Class != android.Device {
+		return false
+	}
+	if m.Target().NativeBridge == android.NativeBridgeEnabled {
+		return false
+	}
+	// the module must be installed in /recovery
+	if !m.IsForPlatform() || m.isSnapshotPrebuilt() || !m.InRecovery() {
+		return false
+	}
+	// skip kernel_headers which always depend on the device
+	if _, ok := m.linker.(*kernelHeadersDecorator); ok {
+		return false
+	}
+	// TODO: add support for sanitized variants
+	if m.sanitize != nil {
+		for _, t := range []SanitizerType{cfi, scs, Hwasan} {
+			if m.sanitize.isSanitizerEnabled(t) {
+				return false
+			}
+		}
+	}
+
+	// Libraries
+	if l, ok := m.linker.(snapshotLibraryInterface); ok {
+		if l.static() || l.shared() {
+			return m.outputFile.Valid()
+		}
+		return true
+	}
+
+	// Binaries and Objects
+	if m.binary() || m.object() {
+		return m.outputFile.Valid()
+	}
+
+	return false
+}
+
+// gathers all snapshot modules for recovery, and disable unnecessary snapshots
+func RecoverySnapshotMutator(ctx android.BottomUpMutatorContext) {
+	snapshotMutator(ctx, recoveryImage)
+}
+
+// Disables source modules which have recovery snapshots
+func RecoverySnapshotSourceMutator(ctx android.BottomUpMutatorContext) {
+	snapshotSourceMutator(ctx, recoveryImage)
+}
@@ -67,7 +67,7 @@ var (
 		"export_memory_stats=0", "max_malloc_fill_size=0"}
 )
 
-type sanitizerType int
+type SanitizerType int
 
 func boolPtr(v bool) *bool {
 	if v {
@@ -78,21 +78,21 @@ func boolPtr(v bool) *bool {
 }
 
 const (
-	asan sanitizerType = iota + 1
-	hwasan
+	Asan SanitizerType = iota + 1
+	Hwasan
 	tsan
 	intOverflow
 	cfi
 	scs
-	fuzzer
+	Fuzzer
 )
 
 // Name of the sanitizer variation for this sanitizer type
-func (t sanitizerType) variationName() string {
+func (t SanitizerType) variationName() string {
 	switch t {
-	case asan:
+	case Asan:
 		return "asan"
-	case hwasan:
+	case Hwasan:
 		return "hwasan"
 	case tsan:
 		return "tsan"
@@ -102,19 +102,19 @@ func (t sanitizerType) variationName() string {
 		return "cfi"
 	case scs:
 		return "scs"
-	case fuzzer:
+	case Fuzzer:
 		return "fuzzer"
 	default:
-		panic(fmt.Errorf("unknown sanitizerType %d", t))
+		panic(fmt.Errorf("unknown SanitizerType %d", t))
 	}
 }
 
 // This is the sanitizer names in SANITIZE_[TARGET|HOST]
-func (t sanitizerType) name() string {
+func (t SanitizerType) name() string {
 	switch t {
-	case asan:
+	case Asan:
 		return "address"
-	case hwasan:
+	case Hwasan:
 		return "hwaddress"
 	case tsan:
 		return "thread"
@@ -124,15 +124,15 @@ func (t sanitizerType) name() string {
 		return "cfi"
 	case scs:
 		return "shadow-call-stack"
-	case fuzzer:
+	case Fuzzer:
 		return "fuzzer"
 	default:
-		panic(fmt.Errorf("unknown sanitizerType %d", t))
+		panic(fmt.Errorf("unknown SanitizerType %d", t))
 	}
 }
 
-func (t sanitizerType) incompatibleWithCfi() bool {
-	return t == asan || t == fuzzer || t == hwasan
+func (t SanitizerType) incompatibleWithCfi() bool {
+	return t == Asan || t == Fuzzer || t == Hwasan
 }
 
 type SanitizeProperties struct {
@@ -309,8 +309,8 @@ func (sanitize *sanitize) begin(ctx BaseModuleContext) {
 		}
 	}
 
-	// Enable CFI for all components in the include paths (for Aarch64 only)
-	if s.Cfi == nil && ctx.Config().CFIEnabledForPath(ctx.ModuleDir()) && ctx.Arch().ArchType == android.Arm64 {
+	// Enable CFI for all components in the include paths, on the toolchains that default to it
+	if s.Cfi == nil && ctx.Config().CFIEnabledForPath(ctx.ModuleDir()) && ctx.toolchain().CfiIncludePathsEnabled() {
 		s.Cfi = boolPtr(true)
 		if inList("cfi", ctx.Config().SanitizeDeviceDiag()) {
 			s.Diag.Cfi = boolPtr(true)
@@ -323,21 +323,8 @@ func (sanitize *sanitize) begin(ctx BaseModuleContext) {
 		s.Diag.Cfi = boolPtr(false)
 	}
 
-	// Also disable CFI for arm32 until b/35157333 is fixed.
-	if ctx.Arch().ArchType == android.Arm {
-		s.Cfi = boolPtr(false)
-		s.Diag.Cfi = boolPtr(false)
-	}
-
-	// HWASan requires AArch64 hardware feature (top-byte-ignore).
-	if ctx.Arch().ArchType != android.Arm64 {
-		s.Hwaddress = nil
-	}
-
-	// SCS is only implemented on AArch64.
-	if ctx.Arch().ArchType != android.Arm64 {
-		s.Scs = nil
-	}
+	// Disable any sanitizer that the toolchain for this variant does not support.
+	sanitize.disableUnsupportedSanitizers(ctx.toolchain())
 
 	// Also disable CFI if ASAN is enabled.
 	if Bool(s.Address) || Bool(s.Hwaddress) {
@@ -345,16 +332,6 @@ func (sanitize *sanitize) begin(ctx BaseModuleContext) {
 		s.Diag.Cfi = boolPtr(false)
 	}
 
-	// Disable sanitizers that depend on the UBSan runtime for windows/darwin builds.
-	if !ctx.Os().Linux() {
-		s.Cfi = boolPtr(false)
-		s.Diag.Cfi = boolPtr(false)
-		s.Misc_undefined = nil
-		s.Undefined = nil
-		s.All_undefined = nil
-		s.Integer_overflow = nil
-	}
-
 	// Also disable CFI for VNDK variants of components
 	if ctx.isVndk() && ctx.useVndk() {
 		if ctx.static() {
@@ -384,13 +361,6 @@ func (sanitize *sanitize) begin(ctx BaseModuleContext) {
 		s.Undefined = nil
 	}
 
-	if !ctx.toolchain().Is64Bit() {
-		// TSAN and SafeStack are not supported on 32-bit architectures
-		s.Thread = nil
-		s.Safestack = nil
-		// TODO(ccross): error for compile_multilib = "32"?
-	}
-
 	if ctx.Os() != android.Windows && (Bool(s.All_undefined) || Bool(s.Undefined) || Bool(s.Address) || Bool(s.Thread) ||
 		Bool(s.Fuzzer) || Bool(s.Safestack) || Bool(s.Cfi) || Bool(s.Integer_overflow) || len(s.Misc_undefined) > 0 ||
 		Bool(s.Scudo) || Bool(s.Hwaddress) || Bool(s.Scs)) {
@@ -414,6 +384,52 @@ func (sanitize *sanitize) begin(ctx BaseModuleContext) {
 	}
 }
 
+// disableUnsupportedSanitizers clears the properties of every sanitizer that is not listed in
+// the toolchain's SupportedSanitizers.
+func (sanitize *sanitize) disableUnsupportedSanitizers(toolchain config.Toolchain) {
+	s := &sanitize.Properties.Sanitize
+	supported := func(name string) bool {
+		return config.SanitizerSupported(toolchain, name)
+	}
+
+	if !supported("address") {
+		s.Address = nil
+	}
+	if !supported("hwaddress") {
+		s.Hwaddress = nil
+	}
+	if !supported("thread") {
+		s.Thread = nil
+	}
+	if !supported("fuzzer") {
+		s.Fuzzer = nil
+	}
+	if !supported("safe-stack") {
+		s.Safestack = nil
+	}
+	if !supported("cfi") {
+		s.Cfi = boolPtr(false)
+		s.Diag.Cfi = boolPtr(false)
+	}
+	if !supported("integer_overflow") {
+		s.Integer_overflow = nil
+		s.Diag.Integer_overflow = nil
+	}
+	if !supported("scudo") {
+		s.Scudo = nil
+	}
+	if !supported("shadow-call-stack") {
+		s.Scs = nil
+	}
+	if !supported("undefined") {
+		s.Misc_undefined = nil
+		s.Undefined = nil
+		s.All_undefined = nil
+		s.Diag.Undefined = nil
+		s.Diag.Misc_undefined = nil
+	}
+}
+
 func (sanitize *sanitize) deps(ctx BaseModuleContext, deps Deps) Deps {
 	if !sanitize.Properties.SanitizerEnabled { // || c.static() {
 		return deps
@@ -459,10 +475,8 @@ func (sanitize *sanitize) flags(ctx ModuleContext, flags Flags) Flags {
 	}
 
 	if Bool(sanitize.Properties.Sanitize.Address) {
-		if ctx.Arch().ArchType == android.Arm {
-			// Frame pointer based unwinder in ASan requires ARM frame setup.
-			// TODO: put in flags?
-			flags.RequiredInstructionSet = "arm"
+		if set := ctx.toolchain().SanitizerInstructionSet("address"); set != "" {
+			flags.RequiredInstructionSet = set
 		}
 		flags.Local.CFlags = append(flags.Local.CFlags, asanCflags...)
 		flags.Local.LdFlags = append(flags.Local.LdFlags, asanLdflags...)
@@ -528,10 +542,8 @@ func (sanitize *sanitize) flags(ctx ModuleContext, flags Flags) Flags {
 	}
 
 	if Bool(sanitize.Properties.Sanitize.Cfi) {
-		if ctx.Arch().ArchType == android.Arm {
-			// __cfi_check needs to be built as Thumb (see the code in linker_cfi.cpp). LLVM is not set up
-			// to do this on a function basis, so force Thumb on the entire module.
-			flags.RequiredInstructionSet = "thumb"
+		if set := ctx.toolchain().SanitizerInstructionSet("cfi"); set != "" {
+			flags.RequiredInstructionSet = set
 		}
 
 		flags.Local.CFlags = append(flags.Local.CFlags, cfiCflags...)
@@ -637,11 +649,11 @@ func (sanitize *sanitize) inSanitizerDir() bool {
 	return sanitize.Properties.InSanitizerDir
 }
 
-func (sanitize *sanitize) getSanitizerBoolPtr(t sanitizerType) *bool {
+func (sanitize *sanitize) getSanitizerBoolPtr(t SanitizerType) *bool {
 	switch t {
-	case asan:
+	case Asan:
 		return sanitize.Properties.Sanitize.Address
-	case hwasan:
+	case Hwasan:
 		return sanitize.Properties.Sanitize.Hwaddress
 	case tsan:
 		return sanitize.Properties.Sanitize.Thread
@@ -651,34 +663,34 @@ func (sanitize *sanitize) getSanitizerBoolPtr(t sanitizerType) *bool {
 		return sanitize.Properties.Sanitize.Cfi
 	case scs:
 		return sanitize.Properties.Sanitize.Scs
-	case fuzzer:
+	case Fuzzer:
 		return sanitize.Properties.Sanitize.Fuzzer
 	default:
-		panic(fmt.Errorf("unknown sanitizerType %d", t))
+		panic(fmt.Errorf("unknown SanitizerType %d", t))
 	}
 }
 
 func (sanitize *sanitize) isUnsanitizedVariant() bool {
-	return !sanitize.isSanitizerEnabled(asan) &&
-		!sanitize.isSanitizerEnabled(hwasan) &&
+	return !sanitize.isSanitizerEnabled(Asan) &&
+		!sanitize.isSanitizerEnabled(Hwasan) &&
 		!sanitize.isSanitizerEnabled(tsan) &&
 		!sanitize.isSanitizerEnabled(cfi) &&
 		!sanitize.isSanitizerEnabled(scs) &&
-		!sanitize.isSanitizerEnabled(fuzzer)
+		!sanitize.isSanitizerEnabled(Fuzzer)
 }
 
 func (sanitize *sanitize) isVariantOnProductionDevice() bool {
-	return !sanitize.isSanitizerEnabled(asan) &&
-		!sanitize.isSanitizerEnabled(hwasan) &&
+	return !sanitize.isSanitizerEnabled(Asan) &&
+		!sanitize.isSanitizerEnabled(Hwasan) &&
 		!sanitize.isSanitizerEnabled(tsan) &&
-		!sanitize.isSanitizerEnabled(fuzzer)
+		!sanitize.isSanitizerEnabled(Fuzzer)
 }
 
-func (sanitize *sanitize) SetSanitizer(t sanitizerType, b bool) {
+func (sanitize *sanitize) SetSanitizer(t SanitizerType, b bool) {
 	switch t {
-	case asan:
+	case Asan:
 		sanitize.Properties.Sanitize.Address = boolPtr(b)
-	case hwasan:
+	case Hwasan:
 		sanitize.Properties.Sanitize.Hwaddress = boolPtr(b)
 	case tsan:
 		sanitize.Properties.Sanitize.Thread = boolPtr(b)
@@ -688,10 +700,10 @@ func (sanitize *sanitize) SetSanitizer(t sanitizerType, b bool) {
 		sanitize.Properties.Sanitize.Cfi = boolPtr(b)
 	case scs:
 		sanitize.Properties.Sanitize.Scs = boolPtr(b)
-	case fuzzer:
+	case Fuzzer:
 		sanitize.Properties.Sanitize.Fuzzer = boolPtr(b)
 	default:
-		panic(fmt.Errorf("unknown sanitizerType %d", t))
+		panic(fmt.Errorf("unknown SanitizerType %d", t))
 	}
 	if b {
 		sanitize.Properties.SanitizerEnabled = true
@@ -700,7 +712,7 @@ func (sanitize *sanitize) SetSanitizer(t sanitizerType, b bool) {
 
 // Check if the sanitizer is explicitly disabled (as opposed to nil by
 // virtue of not being set).
-func (sanitize *sanitize) isSanitizerExplicitlyDisabled(t sanitizerType) bool {
+func (sanitize *sanitize) isSanitizerExplicitlyDisabled(t SanitizerType) bool {
 	if sanitize == nil {
 		return false
 	}
@@ -714,7 +726,7 @@ func (sanitize *sanitize) isSanitizerExplicitlyDisabled(t sanitizerType) bool {
 // indirectly (via a mutator) sets the bool ptr to true, and you can't
 // distinguish between the cases. It isn't needed though - both cases can be
 // treated identically.
-func (sanitize *sanitize) isSanitizerEnabled(t sanitizerType) bool {
+func (sanitize *sanitize) isSanitizerEnabled(t SanitizerType) bool {
 	if sanitize == nil {
 		return false
 	}
@@ -729,16 +741,25 @@ func isSanitizableDependencyTag(tag blueprint.DependencyTag) bool {
 		return t == reuseObjTag || t == objDepTag
 	case libraryDependencyTag:
 		return true
+	case SanitizableDependencyTag:
+		return t.SanitizableDependencyTag()
 	default:
 		return false
 	}
 }
 
+// SanitizableDependencyTag is implemented by dependency tags of non-cc modules that take
+// part in the sanitizer mutators, such as rust modules, to tell whether sanitizer
+// requirements propagate across the dependency.
+type SanitizableDependencyTag interface {
+	SanitizableDependencyTag() bool
+}
+
 // Determines if the current module is a static library going to be captured
 // as vendor snapshot. Such modules must create both cfi and non-cfi variants,
 // except for ones which explicitly disable cfi.
 func needsCfiForVendorSnapshot(mctx android.TopDownMutatorContext) bool {
-	if isVendorProprietaryModule(mctx) {
+	if IsVendorProprietaryModule(mctx) {
 		return false
 	}
 
@@ -762,7 +783,7 @@ func needsCfiForVendorSnapshot(mctx android.TopDownMutatorContext) bool {
 }
 
 // Propagate sanitizer requirements down from binaries
-func sanitizerDepsMutator(t sanitizerType) func(android.TopDownMutatorContext) {
+func sanitizerDepsMutator(t SanitizerType) func(android.TopDownMutatorContext) {
 	return func(mctx android.TopDownMutatorContext) {
 		if c, ok := mctx.Module().(*Module); ok {
 			enabled := c.sanitize.isSanitizerEnabled(t)
@@ -778,17 +799,18 @@ func sanitizerDepsMutator(t sanitizerType) func(android.TopDownMutatorContext) {
 					if !isSanitizableDependencyTag(mctx.OtherModuleDependencyTag(child)) {
 						return false
 					}
-					if d, ok := child.(*Module); ok && d.sanitize != nil &&
-						!Bool(d.sanitize.Properties.Sanitize.Never) &&
-						!d.sanitize.isSanitizerExplicitlyDisabled(t) {
-						if t == cfi || t == hwasan || t == scs {
-							if d.static() {
-								d.sanitize.Properties.SanitizeDep = true
-							}
-						} else {
-							d.sanitize.Properties.SanitizeDep = true
-						}
+					markSanitizableDep(child, t)
+					return true
+				})
+			}
+		} else if c, ok := mctx.Module().(PlatformSanitizeable); ok {
+			// Non-cc modules (e.g. rust) propagate their sanitizers the same way.
+			if c.SanitizerSupported(t) && c.IsSanitizerEnabled(t) {
+				mctx.WalkDeps(func(child, parent android.Module) bool {
+					if !isSanitizableDependencyTag(mctx.OtherModuleDependencyTag(child)) {
+						return false
 					}
+					markSanitizableDep(child, t)
 					return true
 				})
 			}
@@ -798,12 +820,41 @@ func sanitizerDepsMutator(t sanitizerType) func(android.TopDownMutatorContext) {
 			mctx.VisitDirectDeps(func(child android.Module) {
 				if c, ok := child.(*Module); ok && c.sanitize.isSanitizerEnabled(t) {
 					sanitizeable.EnableSanitizer(t.name())
+				} else if c, ok := child.(PlatformSanitizeable); ok &&
+					c.SanitizerSupported(t) && c.IsSanitizerEnabled(t) {
+					sanitizeable.EnableSanitizer(t.name())
 				}
 			})
 		}
 	}
 }
 
+// markSanitizableDep requests a sanitized variant of a dependency of a module that is
+// enabled for sanitizer t. Dependencies are either cc modules or other modules
+// implementing PlatformSanitizeable.
+func markSanitizableDep(child android.Module, t SanitizerType) {
+	if d, ok := child.(*Module); ok && d.sanitize != nil &&
+		!Bool(d.sanitize.Properties.Sanitize.Never) &&
+		!d.sanitize.isSanitizerExplicitlyDisabled(t) {
+		if t == cfi || t == Hwasan || t == scs {
+			if d.static() {
+				d.sanitize.Properties.SanitizeDep = true
+			}
+		} else {
+			d.sanitize.Properties.SanitizeDep = true
+		}
+	} else if d, ok := child.(PlatformSanitizeable); ok && d.SanitizerSupported(t) &&
+		!d.SanitizeNever() && !d.IsSanitizerExplicitlyDisabled(t) {
+		if t == cfi || t == Hwasan || t == scs {
+			if d.StaticallyLinked() {
+				d.SetSanitizeDep(true)
+			}
+		} else {
+			d.SetSanitizeDep(true)
+		}
+	}
+}
+
 // Propagate the ubsan minimal runtime dependency when there are integer overflow sanitized static dependencies.
 func sanitizerRuntimeDepsMutator(mctx android.TopDownMutatorContext) {
 	if c, ok := mctx.Module().(*Module); ok && c.sanitize != nil {
@@ -986,7 +1037,7 @@ func sanitizerRuntimeMutator(mctx android.BottomUpMutatorContext) {
 
 		if runtimeLibrary != "" && (toolchain.Bionic() || c.sanitize.Properties.UbsanRuntimeDep) {
 			// UBSan is supported on non-bionic linux host builds as well
-			if isLlndkLibrary(runtimeLibrary, mctx.Config()) && !c.static() && c.UseVndk() {
+			if IsLlndkLibrary(runtimeLibrary, mctx.Config()) && !c.static() && c.UseVndk() {
 				runtimeLibrary = runtimeLibrary + llndkLibrarySuffix
 			}
 
@@ -1003,7 +1054,7 @@ func sanitizerRuntimeMutator(mctx android.BottomUpMutatorContext) {
 				if c.VndkVersion() == mctx.DeviceConfig().VndkVersion() {
 					snapshots := vendorSnapshotStaticLibs(mctx.Config())
 					for idx, dep := range deps {
-						if lib, ok := snapshots.get(dep, mctx.Arch().ArchType); ok {
+						if lib, ok := snapshots.Get(dep, mctx.Arch().ArchType); ok {
 							deps[idx] = lib
 						}
 					}
@@ -1021,7 +1072,7 @@ func sanitizerRuntimeMutator(mctx android.BottomUpMutatorContext) {
 				// If we're using snapshots and in vendor, redirect to snapshot whenever possible
 				if c.VndkVersion() == mctx.DeviceConfig().VndkVersion() {
 					snapshots := vendorSnapshotSharedLibs(mctx.Config())
-					if lib, ok := snapshots.get(runtimeLibrary, mctx.Arch().ArchType); ok {
+					if lib, ok := snapshots.Get(runtimeLibrary, mctx.Arch().ArchType); ok {
 						runtimeLibrary = lib
 					}
 				}
@@ -1049,8 +1100,33 @@ type Sanitizeable interface {
 	AddSanitizerDependencies(ctx android.BottomUpMutatorContext, sanitizerName string)
 }
 
+// PlatformSanitizeable is implemented by non-cc modules, such as rust modules, that can be
+// built with the platform sanitizers and take part in the cc sanitizer mutators.
+type PlatformSanitizeable interface {
+	LinkableInterface
+
+	// SanitizerSupported returns true if the module can be built with sanitizer t at all.
+	SanitizerSupported(t SanitizerType) bool
+	IsSanitizerEnabled(t SanitizerType) bool
+	IsSanitizerExplicitlyDisabled(t SanitizerType) bool
+	SanitizeNever() bool
+	SetSanitizer(t SanitizerType, b bool)
+
+	// SanitizeDep is set by sanitizerDepsMutator when a dependent requires a sanitized variant.
+	SanitizeDep() bool
+	SetSanitizeDep(b bool)
+	SetInSanitizerDir()
+
+	// StaticallyLinked returns true if the module is linked into its dependents, such as a
+	// static library or a rust rlib.
+	StaticallyLinked() bool
+	IsDependencyRoot() bool
+	PreventInstall()
+	HideFromMake()
+}
+
 // Create sanitized variants for modules that need them
-func sanitizerMutator(t sanitizerType) func(android.BottomUpMutatorContext) {
+func sanitizerMutator(t SanitizerType) func(android.BottomUpMutatorContext) {
 	return func(mctx android.BottomUpMutatorContext) {
 		if c, ok := mctx.Module().(*Module); ok && c.sanitize != nil {
 			if c.isDependencyRoot() && c.sanitize.isSanitizerEnabled(t) {
@@ -1058,7 +1134,7 @@ func sanitizerMutator(t sanitizerType) func(android.BottomUpMutatorContext) {
 				modules[0].(*Module).sanitize.SetSanitizer(t, true)
 			} else if c.sanitize.isSanitizerEnabled(t) || c.sanitize.Properties.SanitizeDep {
 				isSanitizerEnabled := c.sanitize.isSanitizerEnabled(t)
-				if c.static() || c.header() || t == asan || t == fuzzer {
+				if c.static() || c.header() || t == Asan || t == Fuzzer {
 					// Static and header libs are split into non-sanitized and sanitized variants.
 					// Shared libs are not split. However, for asan and fuzzer, we split even for shared
 					// libs because a library sanitized for asan/fuzzer can't be linked from a library
@@ -1088,7 +1164,7 @@ func sanitizerMutator(t sanitizerType) func(android.BottomUpMutatorContext) {
 					// For cfi/scs/hwasan, we can export both sanitized and un-sanitized variants
 					// to Make, because the sanitized version has a different suffix in name.
 					// For other types of sanitizers, suppress the variation that is disabled.
-					if t != cfi && t != scs && t != hwasan {
+					if t != cfi && t != scs && t != Hwasan {
 						if isSanitizerEnabled {
 							modules[0].(*Module).Properties.PreventInstall = true
 							modules[0].(*Module).Properties.HideFromMake = true
@@ -1102,7 +1178,7 @@ func sanitizerMutator(t sanitizerType) func(android.BottomUpMutatorContext) {
 					if c.static() && c.ExportedToMake() {
 						if t == cfi {
 							cfiStaticLibs(mctx.Config()).add(c, c.Name())
-						} else if t == hwasan {
+						} else if t == Hwasan {
 							hwasanStaticLibs(mctx.Config()).add(c, c.Name())
 						}
 					}
@@ -1113,7 +1189,7 @@ func sanitizerMutator(t sanitizerType) func(android.BottomUpMutatorContext) {
 					modules[0].(*Module).sanitize.Properties.SanitizeDep = false
 
 					// locate the asan libraries under /data/asan
-					if mctx.Device() && t == asan && isSanitizerEnabled {
+					if mctx.Device() && t == Asan && isSanitizerEnabled {
 						modules[0].(*Module).sanitize.Properties.InSanitizerDir = true
 					}
 
@@ -1125,6 +1201,44 @@ func sanitizerMutator(t sanitizerType) func(android.BottomUpMutatorContext) {
 				}
 			}
 			c.sanitize.Properties.SanitizeDep = false
+		} else if c, ok := mctx.Module().(PlatformSanitizeable); ok && c.SanitizerSupported(t) {
+			// Non-cc modules (e.g. rust) fall here
+			isSanitizerEnabled := c.IsSanitizerEnabled(t)
+			if c.IsDependencyRoot() && isSanitizerEnabled {
+				modules := mctx.CreateVariations(t.variationName())
+				modules[0].(PlatformSanitizeable).SetSanitizer(t, true)
+			} else if isSanitizerEnabled || c.SanitizeDep() {
+				if c.StaticallyLinked() || t == Asan || t == Fuzzer {
+					// Split the same way as cc static libraries, see above.
+					defaultVariation := t.variationName()
+					mctx.SetDefaultDependencyVariation(&defaultVariation)
+					modules := mctx.CreateVariations("", t.variationName())
+					modules[0].(PlatformSanitizeable).SetSanitizer(t, false)
+					modules[1].(PlatformSanitizeable).SetSanitizer(t, true)
+					modules[0].(PlatformSanitizeable).SetSanitizeDep(false)
+					modules[1].(PlatformSanitizeable).SetSanitizeDep(false)
+
+					if t != Hwasan {
+						if isSanitizerEnabled {
+							modules[0].(PlatformSanitizeable).PreventInstall()
+							modules[0].(PlatformSanitizeable).HideFromMake()
+						} else {
+							modules[1].(PlatformSanitizeable).PreventInstall()
+							modules[1].(PlatformSanitizeable).HideFromMake()
+						}
+					}
+				} else {
+					modules := mctx.CreateVariations(t.variationName())
+					modules[0].(PlatformSanitizeable).SetSanitizer(t, true)
+					modules[0].(PlatformSanitizeable).SetSanitizeDep(false)
+
+					// locate the asan libraries under /data/asan
+					if mctx.Device() && t == Asan && isSanitizerEnabled {
+						modules[0].(PlatformSanitizeable).SetInSanitizerDir()
+					}
+				}
+			}
+			c.SetSanitizeDep(false)
 		} else if sanitizeable, ok := mctx.Module().(Sanitizeable); ok && sanitizeable.IsSanitizerEnabled(mctx, t.name()) {
 			// APEX modules fall here
 			sanitizeable.AddSanitizerDependencies(mctx, t.name())
@@ -1156,10 +1270,10 @@ type sanitizerStaticLibsMap struct {
 	// e.g. libs[vendor]["arm"] contains arm modules installed to vendor
 	libsMap       map[imageVariantType]map[string][]string
 	libsMapLock   sync.Mutex
-	sanitizerType sanitizerType
+	sanitizerType SanitizerType
 }
 
-func newSanitizerStaticLibsMap(t sanitizerType) *sanitizerStaticLibsMap {
+func newSanitizerStaticLibsMap(t SanitizerType) *sanitizerStaticLibsMap {
 	return &sanitizerStaticLibsMap{
 		sanitizerType: t,
 		libsMap:       make(map[imageVariantType]map[string][]string),
@@ -1217,7 +1331,7 @@ var hwasanStaticLibsKey = android.NewOnceKey("hwasanStaticLibs")
 
 func hwasanStaticLibs(config android.Config) *sanitizerStaticLibsMap {
 	return config.Once(hwasanStaticLibsKey, func() interface{} {
-		return newSanitizerStaticLibsMap(hwasan)
+		return newSanitizerStaticLibsMap(Hwasan)
 	}).(*sanitizerStaticLibsMap)
 }
 
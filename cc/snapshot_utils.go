@@ -14,6 +14,9 @@
 package cc
 
 import (
+	"path/filepath"
+	"strings"
+
 	"android/soong/android"
 )
 
@@ -31,12 +34,81 @@ type snapshotLibraryInterface interface {
 var _ snapshotLibraryInterface = (*prebuiltLibraryLinker)(nil)
 var _ snapshotLibraryInterface = (*libraryDecorator)(nil)
 
-type snapshotMap struct {
+// SnapshotLibrary is implemented by libraries which aren't cc modules, e.g. rust libraries,
+// whose vendor variants are captured in the vendor snapshot.
+type SnapshotLibrary interface {
+	android.Module
+
+	// Returns true if this variant should be captured in the vendor snapshot.
+	IsVendorSnapshotLibrary(inVendorProprietaryPath bool) bool
+
+	// Returns the type of the library, which is also the snapshot directory it is captured
+	// to, e.g. "static" or "rlib".
+	SnapshotLibType() string
+
+	// Returns the library file to be captured.
+	SnapshotOutputFile() android.Path
+
+	// Returns the exported include directories and the headers in them.
+	SnapshotExportedDirs() android.Paths
+	SnapshotHeaders() android.Paths
+
+	// Returns the crate name and the rlib dependencies of rust libraries.
+	SnapshotCrateName() string
+	SnapshotRlibs() []string
+}
+
+// snapshotImage is a partition image, e.g. vendor or recovery, whose modules can be captured in
+// a snapshot and later be restored from it as prebuilts.
+type snapshotImage interface {
+	// Returns the name of the image, which prefixes the snapshot directory and zip file.
+	imageName() string
+
+	// Returns the snapshot version selected by the device: "current" to capture a snapshot,
+	// the version of the snapshot prebuilts to use, or an empty string.
+	snapshotVersion(config android.DeviceConfig) string
+
+	// Returns true if the variant is built for the image with the snapshot version selected
+	// by the device, so that its dependencies are replaced with snapshot prebuilts.
+	usesSnapshot(m *Module, config android.DeviceConfig) bool
+
+	// Returns true if the variant should be captured in the snapshot.
+	isSnapshotModule(m *Module, inProprietaryPath bool) bool
+
+	// Returns true if the module is a snapshot prebuilt restored from the snapshot of this image.
+	isSnapshotPrebuilt(m *Module) bool
+
+	// Returns true if the variant is a library which isn't a cc module and which should be
+	// captured in the snapshot.
+	isSnapshotLibrary(l SnapshotLibrary, inProprietaryPath bool) bool
+
+	// Returns true if the source module can be replaced with a snapshot prebuilt.
+	replaceableBySnapshot(m *Module, config android.Config) bool
+
+	// Returns true if the source module of the variant also has a core variant, in which case
+	// Make knows the image variant and its snapshot by the name plus moduleNameSuffix. The
+	// names of such modules are recorded in suffixModules.
+	needsNameSuffix(m *Module) bool
+	moduleNameSuffix() string
+	suffixModules(config android.Config) map[string]bool
+
+	// Returns the maps from the names of source modules to the snapshot prebuilts replacing
+	// them, per module type.
+	headerLibs(config android.Config) *SnapshotMap
+	sharedLibs(config android.Config) *SnapshotMap
+	staticLibs(config android.Config) *SnapshotMap
+	binaries(config android.Config) *SnapshotMap
+	objects(config android.Config) *SnapshotMap
+}
+
+// SnapshotMap maps module names to the names of the snapshot prebuilts replacing them, per
+// architecture.
+type SnapshotMap struct {
 	snapshots map[string]string
 }
 
-func newSnapshotMap() *snapshotMap {
-	return &snapshotMap{
+func NewSnapshotMap() *SnapshotMap {
+	return &SnapshotMap{
 		snapshots: make(map[string]string),
 	}
 }
@@ -46,23 +118,87 @@ func snapshotMapKey(name string, arch android.ArchType) string {
 }
 
 // Adds a snapshot name for given module name and architecture.
-// e.g. add("libbase", X86, "libbase.vndk.29.x86")
-func (s *snapshotMap) add(name string, arch android.ArchType, snapshot string) {
+// e.g. Add("libbase", X86, "libbase.vndk.29.x86")
+func (s *SnapshotMap) Add(name string, arch android.ArchType, snapshot string) {
 	s.snapshots[snapshotMapKey(name, arch)] = snapshot
 }
 
 // Returns snapshot name for given module name and architecture, if found.
-// e.g. get("libcutils", X86) => "libcutils.vndk.29.x86", true
-func (s *snapshotMap) get(name string, arch android.ArchType) (snapshot string, found bool) {
+// e.g. Get("libcutils", X86) => "libcutils.vndk.29.x86", true
+func (s *SnapshotMap) Get(name string, arch android.ArchType) (snapshot string, found bool) {
 	snapshot, found = s.snapshots[snapshotMapKey(name, arch)]
 	return snapshot, found
 }
 
+// GlobHeadersForSnapshot globs the header files under the given exported include directories.
+// Intermediate directories (which contain generated headers) are filtered out, since generated
+// headers can't be globbed and must be collected separately.
+func GlobHeadersForSnapshot(ctx android.ModuleContext, paths android.Paths) android.Paths {
+	ret := android.Paths{}
+
+	for _, path := range paths {
+		dir := path.String()
+		// Skip if dir is for generated headers
+		if strings.HasPrefix(dir, android.PathForOutput(ctx).String()) {
+			continue
+		}
+		// libeigen wrongly exports the root directory "external/eigen". But only two
+		// subdirectories "Eigen" and "unsupported" contain exported header files. Even worse
+		// some of them have no extension. So we need special treatment for libeigen in order
+		// to glob correctly.
+		if dir == "external/eigen" {
+			// Only these two directories contains exported headers.
+			for _, subdir := range []string{"Eigen", "unsupported/Eigen"} {
+				glob, err := ctx.GlobWithDeps("external/eigen/"+subdir+"/**/*", nil)
+				if err != nil {
+					ctx.ModuleErrorf("glob failed: %#v", err)
+					return nil
+				}
+				for _, header := range glob {
+					if strings.HasSuffix(header, "/") {
+						continue
+					}
+					ext := filepath.Ext(header)
+					if ext != "" && ext != ".h" {
+						continue
+					}
+					ret = append(ret, android.PathForSource(ctx, header))
+				}
+			}
+			continue
+		}
+		exts := headerExts
+		// Glob all files under this special directory, because of C++ headers.
+		if strings.HasPrefix(dir, "external/libcxx/include") {
+			exts = []string{""}
+		}
+		for _, ext := range exts {
+			glob, err := ctx.GlobWithDeps(dir+"/**/*"+ext, nil)
+			if err != nil {
+				ctx.ModuleErrorf("glob failed: %#v", err)
+				return nil
+			}
+			for _, header := range glob {
+				if strings.HasSuffix(header, "/") {
+					continue
+				}
+				ret = append(ret, android.PathForSource(ctx, header))
+			}
+		}
+	}
+
+	return ret
+}
+
 func isSnapshotAware(ctx android.ModuleContext, m *Module) bool {
 	if _, _, ok := isVndkSnapshotLibrary(ctx.DeviceConfig(), m); ok {
 		return ctx.Config().VndkSnapshotBuildArtifacts()
 	} else if isVendorSnapshotModule(m, isVendorProprietaryPath(ctx.ModuleDir())) {
 		return true
+	} else if isRecoverySnapshotModule(m, isVendorProprietaryPath(ctx.ModuleDir())) {
+		return true
+	} else if isRamdiskSnapshotModule(m, isVendorProprietaryPath(ctx.ModuleDir())) {
+		return true
 	}
 	return false
 }
@@ -34,7 +34,7 @@ const (
 )
 
 var (
-	vendorSnapshotsLock         sync.Mutex
+	snapshotsLock               sync.Mutex
 	vendorSuffixModulesKey      = android.NewOnceKey("vendorSuffixModules")
 	vendorSnapshotHeaderLibsKey = android.NewOnceKey("vendorSnapshotHeaderLibs")
 	vendorSnapshotStaticLibsKey = android.NewOnceKey("vendorSnapshotStaticLibs")
@@ -50,34 +50,109 @@ func vendorSuffixModules(config android.Config) map[string]bool {
 	}).(map[string]bool)
 }
 
-func vendorSnapshotHeaderLibs(config android.Config) *snapshotMap {
+func vendorSnapshotHeaderLibs(config android.Config) *SnapshotMap {
 	return config.Once(vendorSnapshotHeaderLibsKey, func() interface{} {
-		return newSnapshotMap()
-	}).(*snapshotMap)
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
 }
 
-func vendorSnapshotSharedLibs(config android.Config) *snapshotMap {
+func vendorSnapshotSharedLibs(config android.Config) *SnapshotMap {
 	return config.Once(vendorSnapshotSharedLibsKey, func() interface{} {
-		return newSnapshotMap()
-	}).(*snapshotMap)
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
 }
 
-func vendorSnapshotStaticLibs(config android.Config) *snapshotMap {
+func vendorSnapshotStaticLibs(config android.Config) *SnapshotMap {
 	return config.Once(vendorSnapshotStaticLibsKey, func() interface{} {
-		return newSnapshotMap()
-	}).(*snapshotMap)
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
 }
 
-func vendorSnapshotBinaries(config android.Config) *snapshotMap {
+func vendorSnapshotBinaries(config android.Config) *SnapshotMap {
 	return config.Once(vendorSnapshotBinariesKey, func() interface{} {
-		return newSnapshotMap()
-	}).(*snapshotMap)
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
 }
 
-func vendorSnapshotObjects(config android.Config) *snapshotMap {
+func vendorSnapshotObjects(config android.Config) *SnapshotMap {
 	return config.Once(vendorSnapshotObjectsKey, func() interface{} {
-		return newSnapshotMap()
-	}).(*snapshotMap)
+		return NewSnapshotMap()
+	}).(*SnapshotMap)
+}
+
+type vendorSnapshotImage struct{}
+
+var vendorImage snapshotImage = vendorSnapshotImage{}
+
+func (vendorSnapshotImage) imageName() string {
+	return "vendor"
+}
+
+func (vendorSnapshotImage) snapshotVersion(config android.DeviceConfig) string {
+	return config.VndkVersion()
+}
+
+func (vendorSnapshotImage) usesSnapshot(m *Module, config android.DeviceConfig) bool {
+	// only modules with BOARD_VNDK_VERSION uses snapshot.
+	return m.VndkVersion() == config.VndkVersion()
+}
+
+func (vendorSnapshotImage) isSnapshotModule(m *Module, inProprietaryPath bool) bool {
+	return isVendorSnapshotModule(m, inProprietaryPath)
+}
+
+func (vendorSnapshotImage) isSnapshotPrebuilt(m *Module) bool {
+	// VNDK snapshot prebuilts belong to the vendor image as well.
+	return m.isSnapshotPrebuilt() && !m.isRamdiskSnapshotPrebuilt() && !m.isRecoverySnapshotPrebuilt()
+}
+
+func (vendorSnapshotImage) isSnapshotLibrary(l SnapshotLibrary, inProprietaryPath bool) bool {
+	return l.IsVendorSnapshotLibrary(inProprietaryPath)
+}
+
+func (vendorSnapshotImage) replaceableBySnapshot(m *Module, config android.Config) bool {
+	// LL-NDK libraries are replaced by their stubs instead.
+	return !m.isLlndk(config)
+}
+
+func (vendorSnapshotImage) needsNameSuffix(m *Module) bool {
+	// vendor suffix should be added to snapshots if the source module isn't vendor: true.
+	if m.SocSpecific() {
+		return false
+	}
+	// But we can't just check SocSpecific() since we already passed the image mutator.
+	// Check ramdisk and recovery to see if we are real "vendor: true" module.
+	ramdisk_available := m.InRamdisk() && !m.OnlyInRamdisk()
+	recovery_available := m.InRecovery() && !m.OnlyInRecovery()
+	return !ramdisk_available && !recovery_available
+}
+
+func (vendorSnapshotImage) moduleNameSuffix() string {
+	return vendorSuffix
+}
+
+func (vendorSnapshotImage) suffixModules(config android.Config) map[string]bool {
+	return vendorSuffixModules(config)
+}
+
+func (vendorSnapshotImage) headerLibs(config android.Config) *SnapshotMap {
+	return vendorSnapshotHeaderLibs(config)
+}
+
+func (vendorSnapshotImage) sharedLibs(config android.Config) *SnapshotMap {
+	return vendorSnapshotSharedLibs(config)
+}
+
+func (vendorSnapshotImage) staticLibs(config android.Config) *SnapshotMap {
+	return vendorSnapshotStaticLibs(config)
+}
+
+func (vendorSnapshotImage) binaries(config android.Config) *SnapshotMap {
+	return vendorSnapshotBinaries(config)
+}
+
+func (vendorSnapshotImage) objects(config android.Config) *SnapshotMap {
+	return vendorSnapshotObjects(config)
 }
 
 type vendorSnapshotBaseProperties struct {
@@ -88,10 +163,12 @@ type vendorSnapshotBaseProperties struct {
 	Target_arch string
 }
 
-// vendorSnapshotModuleBase provides common basic functions for all snapshot modules.
+// vendorSnapshotModuleBase provides common basic functions for all snapshot modules, of both
+// the vendor, ramdisk and recovery snapshots.
 type vendorSnapshotModuleBase struct {
 	baseProperties vendorSnapshotBaseProperties
 	moduleSuffix   string
+	image          snapshotImage
 }
 
 func (p *vendorSnapshotModuleBase) Name(name string) string {
@@ -119,18 +196,32 @@ func (p *vendorSnapshotModuleBase) isSnapshotPrebuilt() bool {
 	return true
 }
 
-// Call this after creating a snapshot module with module suffix
+func (p *vendorSnapshotModuleBase) getImage() snapshotImage {
+	return p.image
+}
+
+// Returns the suffix of the name Make knows the snapshot module by, which is the image suffix
+// if the source module also has a core variant.
+func (p *vendorSnapshotModuleBase) makeNameSuffix(config android.Config, baseName string) string {
+	if p.image.suffixModules(config)[baseName] {
+		return p.image.moduleNameSuffix()
+	}
+	return ""
+}
+
+// Call this after creating a snapshot module with the image it restores and module suffix
 // such as vendorSnapshotSharedSuffix
-func (p *vendorSnapshotModuleBase) init(m *Module, suffix string) {
+func (p *vendorSnapshotModuleBase) init(m *Module, image snapshotImage, suffix string) {
 	p.moduleSuffix = suffix
+	p.image = image
 	m.AddProperties(&p.baseProperties)
 	android.AddLoadHook(m, func(ctx android.LoadHookContext) {
-		vendorSnapshotLoadHook(ctx, p)
+		snapshotLoadHook(ctx, p)
 	})
 }
 
-func vendorSnapshotLoadHook(ctx android.LoadHookContext, p *vendorSnapshotModuleBase) {
-	if p.version() != ctx.DeviceConfig().VndkVersion() {
+func snapshotLoadHook(ctx android.LoadHookContext, p *vendorSnapshotModuleBase) {
+	if p.version() != p.image.snapshotVersion(ctx.DeviceConfig()) {
 		ctx.Module().Disable()
 		return
 	}
@@ -157,8 +248,8 @@ type vendorSnapshotLibraryProperties struct {
 }
 
 type snapshotSanitizer interface {
-	isSanitizerEnabled(t sanitizerType) bool
-	setSanitizerVariation(t sanitizerType, enabled bool)
+	isSanitizerEnabled(t SanitizerType) bool
+	setSanitizerVariation(t SanitizerType, enabled bool)
 }
 
 type vendorSnapshotLibraryDecorator struct {
@@ -171,7 +262,7 @@ type vendorSnapshotLibraryDecorator struct {
 		// Library flags for cfi variant.
 		Cfi vendorSnapshotLibraryProperties `android:"arch_variant"`
 	}
-	androidMkVendorSuffix bool
+	androidMkSuffix string
 }
 
 func (p *vendorSnapshotLibraryDecorator) linkerFlags(ctx ModuleContext, flags Flags) Flags {
@@ -193,7 +284,7 @@ func (p *vendorSnapshotLibraryDecorator) matchesWithDevice(config android.Device
 func (p *vendorSnapshotLibraryDecorator) link(ctx ModuleContext,
 	flags Flags, deps PathDeps, objs Objects) android.Path {
 	m := ctx.Module().(*Module)
-	p.androidMkVendorSuffix = vendorSuffixModules(ctx.Config())[m.BaseModuleName()]
+	p.androidMkSuffix = p.makeNameSuffix(ctx.Config(), m.BaseModuleName())
 
 	if p.header() {
 		return p.libraryDecorator.link(ctx, flags, deps, objs)
@@ -238,7 +329,7 @@ func (p *vendorSnapshotLibraryDecorator) nativeCoverage() bool {
 	return false
 }
 
-func (p *vendorSnapshotLibraryDecorator) isSanitizerEnabled(t sanitizerType) bool {
+func (p *vendorSnapshotLibraryDecorator) isSanitizerEnabled(t SanitizerType) bool {
 	switch t {
 	case cfi:
 		return p.sanitizerProperties.Cfi.Src != nil
@@ -247,7 +338,7 @@ func (p *vendorSnapshotLibraryDecorator) isSanitizerEnabled(t sanitizerType) boo
 	}
 }
 
-func (p *vendorSnapshotLibraryDecorator) setSanitizerVariation(t sanitizerType, enabled bool) {
+func (p *vendorSnapshotLibraryDecorator) setSanitizerVariation(t SanitizerType, enabled bool) {
 	if !enabled {
 		return
 	}
@@ -259,7 +350,7 @@ func (p *vendorSnapshotLibraryDecorator) setSanitizerVariation(t sanitizerType,
 	}
 }
 
-func vendorSnapshotLibrary(suffix string) (*Module, *vendorSnapshotLibraryDecorator) {
+func snapshotLibrary(image snapshotImage, suffix string) (*Module, *vendorSnapshotLibraryDecorator) {
 	module, library := NewLibrary(android.DeviceSupported)
 
 	module.stl = nil
@@ -282,7 +373,7 @@ func vendorSnapshotLibrary(suffix string) (*Module, *vendorSnapshotLibraryDecora
 	module.linker = prebuilt
 	module.installer = prebuilt
 
-	prebuilt.init(module, suffix)
+	prebuilt.init(module, image, suffix)
 	module.AddProperties(
 		&prebuilt.properties,
 		&prebuilt.sanitizerProperties,
@@ -292,19 +383,19 @@ func vendorSnapshotLibrary(suffix string) (*Module, *vendorSnapshotLibraryDecora
 }
 
 func VendorSnapshotSharedFactory() android.Module {
-	module, prebuilt := vendorSnapshotLibrary(vendorSnapshotSharedSuffix)
+	module, prebuilt := snapshotLibrary(vendorImage, vendorSnapshotSharedSuffix)
 	prebuilt.libraryDecorator.BuildOnlyShared()
 	return module.Init()
 }
 
 func VendorSnapshotStaticFactory() android.Module {
-	module, prebuilt := vendorSnapshotLibrary(vendorSnapshotStaticSuffix)
+	module, prebuilt := snapshotLibrary(vendorImage, vendorSnapshotStaticSuffix)
 	prebuilt.libraryDecorator.BuildOnlyStatic()
 	return module.Init()
 }
 
 func VendorSnapshotHeaderFactory() android.Module {
-	module, prebuilt := vendorSnapshotLibrary(vendorSnapshotHeaderSuffix)
+	module, prebuilt := snapshotLibrary(vendorImage, vendorSnapshotHeaderSuffix)
 	prebuilt.libraryDecorator.HeaderOnly()
 	return module.Init()
 }
@@ -319,8 +410,8 @@ type vendorSnapshotBinaryProperties struct {
 type vendorSnapshotBinaryDecorator struct {
 	vendorSnapshotModuleBase
 	*binaryDecorator
-	properties            vendorSnapshotBinaryProperties
-	androidMkVendorSuffix bool
+	properties      vendorSnapshotBinaryProperties
+	androidMkSuffix string
 }
 
 func (p *vendorSnapshotBinaryDecorator) matchesWithDevice(config android.DeviceConfig) bool {
@@ -350,7 +441,7 @@ func (p *vendorSnapshotBinaryDecorator) link(ctx ModuleContext,
 	}
 
 	m := ctx.Module().(*Module)
-	p.androidMkVendorSuffix = vendorSuffixModules(ctx.Config())[m.BaseModuleName()]
+	p.androidMkSuffix = p.makeNameSuffix(ctx.Config(), m.BaseModuleName())
 
 	// use cpExecutable to make it executable
 	outputFile := android.PathForModuleOut(ctx, binName)
@@ -369,6 +460,10 @@ func (p *vendorSnapshotBinaryDecorator) nativeCoverage() bool {
 }
 
 func VendorSnapshotBinaryFactory() android.Module {
+	return snapshotBinary(vendorImage, vendorSnapshotBinarySuffix)
+}
+
+func snapshotBinary(image snapshotImage, suffix string) android.Module {
 	module, binary := NewBinary(android.DeviceSupported)
 	binary.baseLinker.Properties.No_libcrt = BoolPtr(true)
 	binary.baseLinker.Properties.Nocrt = BoolPtr(true)
@@ -387,7 +482,7 @@ func VendorSnapshotBinaryFactory() android.Module {
 	module.stl = nil
 	module.linker = prebuilt
 
-	prebuilt.init(module, vendorSnapshotBinarySuffix)
+	prebuilt.init(module, image, suffix)
 	module.AddProperties(&prebuilt.properties)
 	return module.Init()
 }
@@ -400,8 +495,8 @@ type vendorSnapshotObjectProperties struct {
 type vendorSnapshotObjectLinker struct {
 	vendorSnapshotModuleBase
 	objectLinker
-	properties            vendorSnapshotObjectProperties
-	androidMkVendorSuffix bool
+	properties      vendorSnapshotObjectProperties
+	androidMkSuffix string
 }
 
 func (p *vendorSnapshotObjectLinker) matchesWithDevice(config android.DeviceConfig) bool {
@@ -421,7 +516,7 @@ func (p *vendorSnapshotObjectLinker) link(ctx ModuleContext,
 	}
 
 	m := ctx.Module().(*Module)
-	p.androidMkVendorSuffix = vendorSuffixModules(ctx.Config())[m.BaseModuleName()]
+	p.androidMkSuffix = p.makeNameSuffix(ctx.Config(), m.BaseModuleName())
 
 	return android.PathForModuleSrc(ctx, *p.properties.Src)
 }
@@ -431,6 +526,10 @@ func (p *vendorSnapshotObjectLinker) nativeCoverage() bool {
 }
 
 func VendorSnapshotObjectFactory() android.Module {
+	return snapshotObject(vendorImage, vendorSnapshotObjectSuffix)
+}
+
+func snapshotObject(image snapshotImage, suffix string) android.Module {
 	module := newObject()
 
 	prebuilt := &vendorSnapshotObjectLinker{
@@ -440,7 +539,7 @@ func VendorSnapshotObjectFactory() android.Module {
 	}
 	module.linker = prebuilt
 
-	prebuilt.init(module, vendorSnapshotObjectSuffix)
+	prebuilt.init(module, image, suffix)
 	module.AddProperties(&prebuilt.properties)
 	return module.Init()
 }
@@ -455,11 +554,21 @@ func init() {
 }
 
 func VendorSnapshotSingleton() android.Singleton {
-	return &vendorSnapshotSingleton{}
+	return &snapshotSingleton{
+		image:   vendorImage,
+		makeVar: "SOONG_VENDOR_SNAPSHOT_ZIP",
+	}
 }
 
-type vendorSnapshotSingleton struct {
-	vendorSnapshotZipFile android.OptionalPath
+// snapshotSingleton captures the modules of an image, along with their headers, notices and
+// config files, and zips them into a snapshot.
+type snapshotSingleton struct {
+	image snapshotImage
+
+	// The Make variable the path of the snapshot zip file is exported to.
+	makeVar string
+
+	snapshotZipFile android.OptionalPath
 }
 
 var (
@@ -508,7 +617,7 @@ func isVendorProprietaryPath(dir string) bool {
 	return false
 }
 
-func isVendorProprietaryModule(ctx android.BaseModuleContext) bool {
+func IsVendorProprietaryModule(ctx android.BaseModuleContext) bool {
 
 	// Any module in a vendor proprietary path is a vendor proprietary
 	// module.
@@ -578,7 +687,7 @@ func isVendorSnapshotModule(m *Module, inVendorProprietaryPath bool) bool {
 		if m.sanitize != nil {
 			// scs and hwasan export both sanitized and unsanitized variants for static and header
 			// Always use unsanitized variants of them.
-			for _, t := range []sanitizerType{scs, hwasan} {
+			for _, t := range []SanitizerType{scs, Hwasan} {
 				if !l.shared() && m.sanitize.isSanitizerEnabled(t) {
 					return false
 				}
@@ -611,16 +720,17 @@ func isVendorSnapshotModule(m *Module, inVendorProprietaryPath bool) bool {
 	return false
 }
 
-func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonContext) {
-	// BOARD_VNDK_VERSION must be set to 'current' in order to generate a vendor snapshot.
-	if ctx.DeviceConfig().VndkVersion() != "current" {
+func (c *snapshotSingleton) GenerateBuildActions(ctx android.SingletonContext) {
+	// The snapshot version (e.g. BOARD_VNDK_VERSION for the vendor snapshot) must be set to
+	// 'current' in order to generate a snapshot.
+	if c.image.snapshotVersion(ctx.DeviceConfig()) != "current" {
 		return
 	}
 
 	var snapshotOutputs android.Paths
 
 	/*
-		Vendor snapshot zipped artifacts directory structure:
+		Snapshot zipped artifacts directory structure:
 		{SNAPSHOT_ARCH}/
 			arch-{TARGET_ARCH}-{TARGET_ARCH_VARIANT}/
 				shared/
@@ -633,6 +743,8 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 					(executable binaries)
 				object/
 					(.o object files)
+				rlib/
+					(.rlib rust libraries, vendor snapshot only)
 			arch-{TARGET_2ND_ARCH}-{TARGET_2ND_ARCH_VARIANT}/
 				shared/
 					(.so shared libraries)
@@ -644,6 +756,8 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 					(executable binaries)
 				object/
 					(.o object files)
+				rlib/
+					(.rlib rust libraries, vendor snapshot only)
 			NOTICE_FILES/
 				(notice files, e.g. libbase.txt)
 			configs/
@@ -652,7 +766,7 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 				(header files of same directory structure with source tree)
 	*/
 
-	snapshotDir := "vendor-snapshot"
+	snapshotDir := c.image.imageName() + "-snapshot"
 	snapshotArchDir := filepath.Join(snapshotDir, ctx.DeviceConfig().DeviceArch())
 
 	includeDir := filepath.Join(snapshotArchDir, "include")
@@ -664,11 +778,16 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 
 	var headers android.Paths
 
-	installSnapshot := func(m *Module) android.Paths {
+	targetArchDir := func(m android.Module) string {
 		targetArch := "arch-" + m.Target().Arch.ArchType.String()
 		if m.Target().Arch.ArchVariant != "" {
 			targetArch += "-" + m.Target().Arch.ArchVariant
 		}
+		return targetArch
+	}
+
+	installSnapshot := func(m *Module) android.Paths {
+		targetArch := targetArchDir(m)
 
 		var ret android.Paths
 
@@ -798,10 +917,46 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 			ret = append(ret, copyFile(ctx, objPath, snapshotObjOut))
 			propOut = snapshotObjOut + ".json"
 		} else {
-			ctx.Errorf("unknown module %q in vendor snapshot", m.String())
+			ctx.Errorf("unknown module %q in %s snapshot", m.String(), c.image.imageName())
+			return nil
+		}
+
+		j, err := json.Marshal(prop)
+		if err != nil {
+			ctx.Errorf("json marshal to %q failed: %#v", propOut, err)
 			return nil
 		}
+		ret = append(ret, writeStringToFile(ctx, string(j), propOut))
+
+		return ret
+	}
+
+	// Libraries which aren't cc modules, e.g. rust static libraries and rlibs.
+	installSnapshotLibrary := func(l SnapshotLibrary) android.Paths {
+		targetArch := targetArchDir(l)
+
+		var ret android.Paths
+
+		prop := struct {
+			ModuleName   string   `json:",omitempty"`
+			CrateName    string   `json:",omitempty"`
+			ExportedDirs []string `json:",omitempty"`
+			Rlibs        []string `json:",omitempty"`
+		}{}
+
+		prop.ModuleName = ctx.ModuleName(l)
+		prop.CrateName = l.SnapshotCrateName()
+		for _, dir := range l.SnapshotExportedDirs() {
+			prop.ExportedDirs = append(prop.ExportedDirs, filepath.Join("include", dir.String()))
+		}
+		prop.Rlibs = l.SnapshotRlibs()
 
+		libPath := l.SnapshotOutputFile()
+		stem := libPath.Base()
+		snapshotLibOut := filepath.Join(snapshotArchDir, targetArch, l.SnapshotLibType(), stem)
+		ret = append(ret, copyFile(ctx, libPath, snapshotLibOut))
+
+		propOut := snapshotLibOut + ".json"
 		j, err := json.Marshal(prop)
 		if err != nil {
 			ctx.Errorf("json marshal to %q failed: %#v", propOut, err)
@@ -812,7 +967,30 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 		return ret
 	}
 
+	installNotices := func(m android.Module) {
+		if len(m.NoticeFiles()) > 0 {
+			noticeName := ctx.ModuleName(m) + ".txt"
+			noticeOut := filepath.Join(noticeDir, noticeName)
+			// skip already copied notice file
+			if !installedNotices[noticeOut] {
+				installedNotices[noticeOut] = true
+				snapshotOutputs = append(snapshotOutputs, combineNotices(
+					ctx, m.NoticeFiles(), noticeOut))
+			}
+		}
+	}
+
 	ctx.VisitAllModules(func(module android.Module) {
+		if l, ok := module.(SnapshotLibrary); ok {
+			if !c.image.isSnapshotLibrary(l, isVendorProprietaryPath(ctx.ModuleDir(module))) {
+				return
+			}
+			snapshotOutputs = append(snapshotOutputs, installSnapshotLibrary(l)...)
+			headers = append(headers, l.SnapshotHeaders()...)
+			installNotices(l)
+			return
+		}
+
 		m, ok := module.(*Module)
 		if !ok {
 			return
@@ -821,7 +999,7 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 		moduleDir := ctx.ModuleDir(module)
 		inVendorProprietaryPath := isVendorProprietaryPath(moduleDir)
 
-		if m.ExcludeFromVendorSnapshot() {
+		if c.image == vendorImage && m.ExcludeFromVendorSnapshot() {
 			if inVendorProprietaryPath {
 				// Error: exclude_from_vendor_snapshot applies
 				// to framework-path modules only.
@@ -837,7 +1015,7 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 			}
 		}
 
-		if !isVendorSnapshotModule(m, inVendorProprietaryPath) {
+		if !c.image.isSnapshotModule(m, inVendorProprietaryPath) {
 			return
 		}
 
@@ -846,16 +1024,7 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 			headers = append(headers, l.snapshotHeaders()...)
 		}
 
-		if len(m.NoticeFiles()) > 0 {
-			noticeName := ctx.ModuleName(m) + ".txt"
-			noticeOut := filepath.Join(noticeDir, noticeName)
-			// skip already copied notice file
-			if !installedNotices[noticeOut] {
-				installedNotices[noticeOut] = true
-				snapshotOutputs = append(snapshotOutputs, combineNotices(
-					ctx, m.NoticeFiles(), noticeOut))
-			}
-		}
+		installNotices(m)
 	})
 
 	// install all headers after removing duplicates
@@ -869,11 +1038,11 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 		return snapshotOutputs[i].String() < snapshotOutputs[j].String()
 	})
 
-	zipPath := android.PathForOutput(ctx, snapshotDir, "vendor-"+ctx.Config().DeviceName()+".zip")
+	zipPath := android.PathForOutput(ctx, snapshotDir, c.image.imageName()+"-"+ctx.Config().DeviceName()+".zip")
 	zipRule := android.NewRuleBuilder()
 
 	// filenames in rspfile from FlagWithRspFileInputList might be single-quoted. Remove it with tr
-	snapshotOutputList := android.PathForOutput(ctx, snapshotDir, "vendor-"+ctx.Config().DeviceName()+"_list")
+	snapshotOutputList := android.PathForOutput(ctx, snapshotDir, c.image.imageName()+"-"+ctx.Config().DeviceName()+"_list")
 	zipRule.Command().
 		Text("tr").
 		FlagWithArg("-d ", "\\'").
@@ -888,13 +1057,13 @@ func (c *vendorSnapshotSingleton) GenerateBuildActions(ctx android.SingletonCont
 		FlagWithArg("-C ", android.PathForOutput(ctx, snapshotDir).String()).
 		FlagWithInput("-l ", snapshotOutputList)
 
-	zipRule.Build(pctx, ctx, zipPath.String(), "vendor snapshot "+zipPath.String())
+	zipRule.Build(pctx, ctx, zipPath.String(), c.image.imageName()+" snapshot "+zipPath.String())
 	zipRule.DeleteTemporaryFiles()
-	c.vendorSnapshotZipFile = android.OptionalPathForPath(zipPath)
+	c.snapshotZipFile = android.OptionalPathForPath(zipPath)
 }
 
-func (c *vendorSnapshotSingleton) MakeVars(ctx android.MakeVarsContext) {
-	ctx.Strict("SOONG_VENDOR_SNAPSHOT_ZIP", c.vendorSnapshotZipFile.String())
+func (c *snapshotSingleton) MakeVars(ctx android.MakeVarsContext) {
+	ctx.Strict(c.makeVar, c.snapshotZipFile.String())
 }
 
 type snapshotInterface interface {
@@ -909,18 +1078,23 @@ var _ snapshotInterface = (*vendorSnapshotObjectLinker)(nil)
 // gathers all snapshot modules for vendor, and disable unnecessary snapshots
 // TODO(b/145966707): remove mutator and utilize android.Prebuilt to override source modules
 func VendorSnapshotMutator(ctx android.BottomUpMutatorContext) {
-	vndkVersion := ctx.DeviceConfig().VndkVersion()
+	snapshotMutator(ctx, vendorImage)
+}
+
+func snapshotMutator(ctx android.BottomUpMutatorContext, image snapshotImage) {
+	snapshotVersion := image.snapshotVersion(ctx.DeviceConfig())
 	// don't need snapshot if current
-	if vndkVersion == "current" || vndkVersion == "" {
+	if snapshotVersion == "current" || snapshotVersion == "" {
 		return
 	}
 
 	module, ok := ctx.Module().(*Module)
-	if !ok || !module.Enabled() || module.VndkVersion() != vndkVersion {
+	if !ok || !module.Enabled() || !image.usesSnapshot(module, ctx.DeviceConfig()) {
 		return
 	}
 
-	if !module.isSnapshotPrebuilt() {
+	// skip source modules and snapshot prebuilts of other images
+	if !image.isSnapshotPrebuilt(module) {
 		return
 	}
 
@@ -934,39 +1108,43 @@ func VendorSnapshotMutator(ctx android.BottomUpMutatorContext) {
 		return
 	}
 
-	var snapshotMap *snapshotMap
+	var snapshotMap *SnapshotMap
 
 	if lib, ok := module.linker.(libraryInterface); ok {
 		if lib.static() {
-			snapshotMap = vendorSnapshotStaticLibs(ctx.Config())
+			snapshotMap = image.staticLibs(ctx.Config())
 		} else if lib.shared() {
-			snapshotMap = vendorSnapshotSharedLibs(ctx.Config())
+			snapshotMap = image.sharedLibs(ctx.Config())
 		} else {
 			// header
-			snapshotMap = vendorSnapshotHeaderLibs(ctx.Config())
+			snapshotMap = image.headerLibs(ctx.Config())
 		}
 	} else if _, ok := module.linker.(*vendorSnapshotBinaryDecorator); ok {
-		snapshotMap = vendorSnapshotBinaries(ctx.Config())
+		snapshotMap = image.binaries(ctx.Config())
 	} else if _, ok := module.linker.(*vendorSnapshotObjectLinker); ok {
-		snapshotMap = vendorSnapshotObjects(ctx.Config())
+		snapshotMap = image.objects(ctx.Config())
 	} else {
 		return
 	}
 
-	vendorSnapshotsLock.Lock()
-	defer vendorSnapshotsLock.Unlock()
-	snapshotMap.add(module.BaseModuleName(), ctx.Arch().ArchType, ctx.ModuleName())
+	snapshotsLock.Lock()
+	defer snapshotsLock.Unlock()
+	snapshotMap.Add(module.BaseModuleName(), ctx.Arch().ArchType, ctx.ModuleName())
 }
 
 // Disables source modules which have snapshots
 func VendorSnapshotSourceMutator(ctx android.BottomUpMutatorContext) {
+	snapshotSourceMutator(ctx, vendorImage)
+}
+
+func snapshotSourceMutator(ctx android.BottomUpMutatorContext, image snapshotImage) {
 	if !ctx.Device() {
 		return
 	}
 
-	vndkVersion := ctx.DeviceConfig().VndkVersion()
+	snapshotVersion := image.snapshotVersion(ctx.DeviceConfig())
 	// don't need snapshot if current
-	if vndkVersion == "current" || vndkVersion == "" {
+	if snapshotVersion == "current" || snapshotVersion == "" {
 		return
 	}
 
@@ -975,51 +1153,43 @@ func VendorSnapshotSourceMutator(ctx android.BottomUpMutatorContext) {
 		return
 	}
 
-	// vendor suffix should be added to snapshots if the source module isn't vendor: true.
-	if !module.SocSpecific() {
-		// But we can't just check SocSpecific() since we already passed the image mutator.
-		// Check ramdisk and recovery to see if we are real "vendor: true" module.
-		ramdisk_available := module.InRamdisk() && !module.OnlyInRamdisk()
-		recovery_available := module.InRecovery() && !module.OnlyInRecovery()
-
-		if !ramdisk_available && !recovery_available {
-			vendorSnapshotsLock.Lock()
-			defer vendorSnapshotsLock.Unlock()
+	if image.needsNameSuffix(module) {
+		snapshotsLock.Lock()
+		defer snapshotsLock.Unlock()
 
-			vendorSuffixModules(ctx.Config())[ctx.ModuleName()] = true
-		}
+		image.suffixModules(ctx.Config())[ctx.ModuleName()] = true
 	}
 
-	if module.isSnapshotPrebuilt() || module.VndkVersion() != ctx.DeviceConfig().VndkVersion() {
-		// only non-snapshot modules with BOARD_VNDK_VERSION
+	if module.isSnapshotPrebuilt() || !image.usesSnapshot(module, ctx.DeviceConfig()) {
+		// only non-snapshot modules with the snapshot version
 		return
 	}
 
-	// .. and also filter out llndk library
-	if module.isLlndk(ctx.Config()) {
+	// .. and also filter out modules which are replaced otherwise, e.g. llndk library
+	if !image.replaceableBySnapshot(module, ctx.Config()) {
 		return
 	}
 
-	var snapshotMap *snapshotMap
+	var snapshotMap *SnapshotMap
 
 	if lib, ok := module.linker.(libraryInterface); ok {
 		if lib.static() {
-			snapshotMap = vendorSnapshotStaticLibs(ctx.Config())
+			snapshotMap = image.staticLibs(ctx.Config())
 		} else if lib.shared() {
-			snapshotMap = vendorSnapshotSharedLibs(ctx.Config())
+			snapshotMap = image.sharedLibs(ctx.Config())
 		} else {
 			// header
-			snapshotMap = vendorSnapshotHeaderLibs(ctx.Config())
+			snapshotMap = image.headerLibs(ctx.Config())
 		}
 	} else if module.binary() {
-		snapshotMap = vendorSnapshotBinaries(ctx.Config())
+		snapshotMap = image.binaries(ctx.Config())
 	} else if module.object() {
-		snapshotMap = vendorSnapshotObjects(ctx.Config())
+		snapshotMap = image.objects(ctx.Config())
 	} else {
 		return
 	}
 
-	if _, ok := snapshotMap.get(ctx.ModuleName(), ctx.Arch().ArchType); !ok {
+	if _, ok := snapshotMap.Get(ctx.ModuleName(), ctx.Arch().ArchType); !ok {
 		// Corresponding snapshot doesn't exist
 		return
 	}
@@ -1033,3 +1203,40 @@ func VendorSnapshotSourceMutator(ctx android.BottomUpMutatorContext) {
 		module.Disable()
 	}
 }
+
+// RewriteSnapshotLib returns the name of the snapshot prebuilt in snapshotMap that replaces lib
+// for a vendor module with the given VNDK version, or lib itself if there is none.
+func RewriteSnapshotLib(ctx android.BaseModuleContext, vndkVersion string, lib string, snapshotMap *SnapshotMap) string {
+	// only modules with BOARD_VNDK_VERSION uses snapshot.
+	if vndkVersion != ctx.DeviceConfig().VndkVersion() {
+		return lib
+	}
+
+	if snapshot, ok := snapshotMap.Get(lib, ctx.Arch().ArchType); ok {
+		return snapshot
+	}
+
+	return lib
+}
+
+// The following rewrite the cc dependencies of vendor variants of modules that aren't cc
+// modules, e.g. rust modules, the same way cc modules rewrite their own.
+
+// RewriteVendorSharedLib returns the LL-NDK stub or the vendor snapshot prebuilt that replaces
+// the shared library lib.
+func RewriteVendorSharedLib(ctx android.BaseModuleContext, vndkVersion string, lib string) string {
+	if IsLlndkLibrary(lib, ctx.Config()) {
+		return lib + llndkLibrarySuffix
+	}
+	return RewriteSnapshotLib(ctx, vndkVersion, lib, vendorSnapshotSharedLibs(ctx.Config()))
+}
+
+// RewriteVendorStaticLib returns the vendor snapshot prebuilt that replaces the static library lib.
+func RewriteVendorStaticLib(ctx android.BaseModuleContext, vndkVersion string, lib string) string {
+	return RewriteSnapshotLib(ctx, vndkVersion, lib, vendorSnapshotStaticLibs(ctx.Config()))
+}
+
+// RewriteVendorObject returns the vendor snapshot prebuilt that replaces the object obj.
+func RewriteVendorObject(ctx android.BaseModuleContext, vndkVersion string, obj string) string {
+	return RewriteSnapshotLib(ctx, vndkVersion, obj, vendorSnapshotObjects(ctx.Config()))
+}
@@ -246,7 +246,7 @@ func vndkSpLibraries(config android.Config) map[string]string {
 	}).(map[string]string)
 }
 
-func isLlndkLibrary(baseModuleName string, config android.Config) bool {
+func IsLlndkLibrary(baseModuleName string, config android.Config) bool {
 	_, ok := llndkLibraries(config)[baseModuleName]
 	return ok
 }
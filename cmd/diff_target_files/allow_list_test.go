@@ -40,7 +40,7 @@ func bytesToZipArtifactFile(name string, data []byte) *ZipArtifactFile {
 		panic(err)
 	}
 
-	return &ZipArtifactFile{r.File[0]}
+	return &ZipArtifactFile{File: r.File[0]}
 }
 
 var f1a = bytesToZipArtifactFile("dir/f1", []byte(`
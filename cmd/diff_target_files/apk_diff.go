@@ -0,0 +1,221 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// The extensions of the zip files whose entries are compared recursively.
+var zipContentExtensions = []string{".apk", ".apex", ".capex", ".jar", ".zip"}
+
+// compareZipContents compares APK, JAR and other zip files by their entries, recursing into the
+// modified entries with the content-aware comparators, and by the ID-value pairs of their APK
+// signing blocks.
+func compareZipContents(name string, a, b []byte, _ []string, opts contentDiffOptions) (*contentDiff, error) {
+	isZip := false
+	for _, ext := range zipContentExtensions {
+		if filepath.Ext(name) == ext {
+			isZip = true
+		}
+	}
+	if !isZip {
+		return nil, nil
+	}
+
+	zipA, err := zip.NewReader(bytes.NewReader(a), int64(len(a)))
+	if err != nil {
+		return nil, err
+	}
+	zipB, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	cd := &contentDiff{Format: "zip"}
+
+	diff := diffTargetFilesLists(sortedZipEntries(zipA), sortedZipEntries(zipB))
+	for _, f := range diff.onlyInA {
+		cd.Changes = append(cd.Changes, contentChange{Kind: "entry", Name: f.Name, Change: changeRemoved,
+			A: fmt.Sprintf("%d bytes", f.UncompressedSize64)})
+	}
+	for _, f := range diff.onlyInB {
+		cd.Changes = append(cd.Changes, contentChange{Kind: "entry", Name: f.Name, Change: changeAdded,
+			B: fmt.Sprintf("%d bytes", f.UncompressedSize64)})
+	}
+	for _, m := range diff.modified {
+		entryA, err := readZipArtifactFile(m[0])
+		if err != nil {
+			return nil, err
+		}
+		entryB, err := readZipArtifactFile(m[1])
+		if err != nil {
+			return nil, err
+		}
+		entryDiff, err := compareFileContents(m[0].Name, entryA, entryB, nil, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error comparing %s: %v", m[0].Name, err)
+		}
+		if entryDiff != nil && len(entryDiff.Changes) == 0 {
+			continue
+		}
+		cd.Changes = append(cd.Changes, contentChange{Kind: "entry", Name: m[0].Name, Change: changeModified,
+			A: fmt.Sprintf("%d bytes", m[0].UncompressedSize64), B: fmt.Sprintf("%d bytes", m[1].UncompressedSize64),
+			Diff: entryDiff})
+	}
+	cd.Changes = append(cd.Changes, compareZipEntryMetadata(zipA, zipB)...)
+	sort.SliceStable(cd.Changes, func(i, j int) bool { return cd.Changes[i].Name < cd.Changes[j].Name })
+
+	blockA := apkSigningBlockPairs(a)
+	blockB := apkSigningBlockPairs(b)
+	for _, id := range sortedPairIDs(blockA, blockB) {
+		valueA, inA := blockA[id]
+		valueB, inB := blockB[id]
+		switch {
+		case !inB:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "signing block", Name: signingBlockPairName(id),
+				Change: changeRemoved, A: fmt.Sprintf("%d bytes", len(valueA))})
+		case !inA:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "signing block", Name: signingBlockPairName(id),
+				Change: changeAdded, B: fmt.Sprintf("%d bytes", len(valueB))})
+		case !bytes.Equal(valueA, valueB):
+			cd.Changes = append(cd.Changes, contentChange{Kind: "signing block", Name: signingBlockPairName(id),
+				Change: changeModified, A: fmt.Sprintf("%d bytes", len(valueA)), B: fmt.Sprintf("%d bytes", len(valueB))})
+		}
+	}
+
+	return cd, nil
+}
+
+// compareZipEntryMetadata reports the entries that are in both zip files but differ in their
+// modification times, compression methods or permissions, which are nondeterministic even when the
+// contents of the entries are the same.
+func compareZipEntryMetadata(zipA, zipB *zip.Reader) []contentChange {
+	entriesB := make(map[string]*zip.File)
+	for _, zf := range zipB.File {
+		entriesB[zf.Name] = zf
+	}
+	describe := func(zf *zip.File) string {
+		return fmt.Sprintf("%s method %d mode %s", zf.Modified.UTC().Format("2006-01-02 15:04:05"), zf.Method, zf.Mode())
+	}
+	var changes []contentChange
+	for _, zfA := range zipA.File {
+		zfB := entriesB[zfA.Name]
+		if zfB == nil || zfA.FileInfo().IsDir() {
+			continue
+		}
+		if metadataA, metadataB := describe(zfA), describe(zfB); metadataA != metadataB {
+			changes = append(changes, contentChange{Kind: "entry metadata", Name: zfA.Name, Change: changeModified,
+				A: metadataA, B: metadataB})
+		}
+	}
+	return changes
+}
+
+func sortedZipEntries(zr *zip.Reader) []*ZipArtifactFile {
+	var files []*ZipArtifactFile
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+		files = append(files, &ZipArtifactFile{File: zf})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files
+}
+
+const (
+	apkSigningBlockMagic = "APK Sig Block 42"
+
+	endOfCentralDirectorySignature = 0x06054b50
+	endOfCentralDirectorySize      = 22
+	maxZipCommentSize              = 0xffff
+)
+
+// The IDs of the well-known ID-value pairs of the APK signing block.
+var signingBlockPairNames = map[uint32]string{
+	0x7109871a: "APK signature scheme v2",
+	0xf05368c0: "APK signature scheme v3",
+	0x1b93ad61: "APK signature scheme v3.1",
+	0x42726577: "verity padding",
+	0x6dff800d: "source stamp",
+}
+
+func signingBlockPairName(id uint32) string {
+	if name, ok := signingBlockPairNames[id]; ok {
+		return fmt.Sprintf("%s (0x%08x)", name, id)
+	}
+	return fmt.Sprintf("0x%08x", id)
+}
+
+// apkSigningBlockPairs returns the ID-value pairs of the APK signing block of a zip file, which is
+// located right before the central directory. It returns nil if the zip file has no signing block.
+func apkSigningBlockPairs(data []byte) map[uint32][]byte {
+	// Find the end of central directory record, which is followed by a comment of up to 64kB.
+	eocd := -1
+	for i := len(data) - endOfCentralDirectorySize; i >= 0 && i >= len(data)-endOfCentralDirectorySize-maxZipCommentSize; i-- {
+		if binary.LittleEndian.Uint32(data[i:]) == endOfCentralDirectorySignature {
+			eocd = i
+			break
+		}
+	}
+	if eocd == -1 {
+		return nil
+	}
+	centralDirectoryOffset := int64(binary.LittleEndian.Uint32(data[eocd+16:]))
+
+	// The signing block ends with its size and the magic, and starts with its size again.
+	footer := centralDirectoryOffset - int64(len(apkSigningBlockMagic)) - 8
+	if footer < 8 || centralDirectoryOffset > int64(len(data)) ||
+		string(data[footer+8:centralDirectoryOffset]) != apkSigningBlockMagic {
+		return nil
+	}
+	blockSize := int64(binary.LittleEndian.Uint64(data[footer:]))
+	start := centralDirectoryOffset - blockSize - 8
+	if start < 0 || blockSize < 24 {
+		return nil
+	}
+
+	pairs := make(map[uint32][]byte)
+	for pos := start + 8; pos+12 <= footer; {
+		pairSize := int64(binary.LittleEndian.Uint64(data[pos:]))
+		if pairSize < 4 || pos+8+pairSize > footer {
+			break
+		}
+		id := binary.LittleEndian.Uint32(data[pos+8:])
+		pairs[id] = data[pos+12 : pos+8+pairSize]
+		pos += 8 + pairSize
+	}
+	return pairs
+}
+
+func sortedPairIDs(a, b map[uint32][]byte) []uint32 {
+	var ids []uint32
+	for id := range a {
+		ids = append(ids, id)
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
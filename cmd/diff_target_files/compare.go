@@ -16,6 +16,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 )
 
@@ -56,6 +57,12 @@ type zipDiff struct {
 
 // String pretty-prints the list of files that differ between two zip files.
 func (d *zipDiff) String() string {
+	return d.format(nil)
+}
+
+// format pretty-prints the list of files that differ between two zip files, with the content
+// differences of the modified files indented under them.
+func (d *zipDiff) format(contentDiffs map[string]*contentDiff) string {
 	buf := &bytes.Buffer{}
 
 	must := func(n int, err error) {
@@ -71,6 +78,9 @@ func (d *zipDiff) String() string {
 		for _, f := range d.modified {
 			must(fmt.Fprintf(buf, "   %v (%v bytes -> %v bytes)\n", f[0].Name, f[0].UncompressedSize64, f[1].UncompressedSize64))
 			sizeChange += int64(f[1].UncompressedSize64) - int64(f[0].UncompressedSize64)
+			if cd := contentDiffs[f[0].Name]; cd != nil {
+				cd.writeChanges(buf, "      ")
+			}
 		}
 	}
 
@@ -97,6 +107,48 @@ func (d *zipDiff) String() string {
 	return buf.String()
 }
 
+type jsonModifiedFile struct {
+	Name         string
+	SizeA, SizeB uint64
+	Diff         *contentDiff `json:",omitempty"`
+}
+
+type jsonFile struct {
+	Name string
+	Size uint64
+}
+
+type jsonZipDiff struct {
+	Modified   []jsonModifiedFile `json:",omitempty"`
+	Removed    []jsonFile         `json:",omitempty"`
+	Added      []jsonFile         `json:",omitempty"`
+	SizeChange int64
+}
+
+// JSON returns the list of files that differ between two zip files and the content differences
+// of the modified files as JSON.
+func (d *zipDiff) JSON(contentDiffs map[string]*contentDiff) ([]byte, error) {
+	var j jsonZipDiff
+	for _, f := range d.modified {
+		j.Modified = append(j.Modified, jsonModifiedFile{
+			Name:  f[0].Name,
+			SizeA: f[0].UncompressedSize64,
+			SizeB: f[1].UncompressedSize64,
+			Diff:  contentDiffs[f[0].Name],
+		})
+		j.SizeChange += int64(f[1].UncompressedSize64) - int64(f[0].UncompressedSize64)
+	}
+	for _, f := range d.onlyInA {
+		j.Removed = append(j.Removed, jsonFile{f.Name, f.UncompressedSize64})
+		j.SizeChange -= int64(f.UncompressedSize64)
+	}
+	for _, f := range d.onlyInB {
+		j.Added = append(j.Added, jsonFile{f.Name, f.UncompressedSize64})
+		j.SizeChange += int64(f.UncompressedSize64)
+	}
+	return json.MarshalIndent(j, "", "  ")
+}
+
 func diffTargetFilesLists(a, b []*ZipArtifactFile) zipDiff {
 	i := 0
 	j := 0
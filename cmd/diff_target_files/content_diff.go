@@ -0,0 +1,237 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// contentDiffOptions configures the content-aware comparison of modified files.
+type contentDiffOptions struct {
+	// ignoreELFSections are patterns of the names of ELF sections that are not compared, for
+	// example ".note.gnu.build-id".
+	ignoreELFSections []string
+}
+
+// contentDiff is the difference between the contents of two versions of a file, as understood by
+// the comparator for the format of the file.
+type contentDiff struct {
+	Format  string
+	Changes []contentChange
+}
+
+// contentChange is a single difference between the contents of two versions of a file, such as a
+// modified ELF section, a removed zip entry or an added property.
+type contentChange struct {
+	// Kind is the kind of the changed part of the file, for example "section" or "property".
+	Kind string
+	// Name is the name of the changed part, or empty if there is only one part of the kind.
+	Name   string
+	Change string
+	// A and B describe the old and the new version of the part if they are short enough to print.
+	A, B string `json:",omitempty"`
+	// Diff is the content difference of a modified zip entry.
+	Diff *contentDiff `json:",omitempty"`
+}
+
+const (
+	changeModified = "modified"
+	changeRemoved  = "removed"
+	changeAdded    = "added"
+)
+
+// contentComparator compares the contents of two versions of a file. It returns nil if the file
+// is not in the format of the comparator.
+type contentComparator func(name string, a, b []byte, ignoreMatchingLines []string,
+	opts contentDiffOptions) (*contentDiff, error)
+
+// compareContents compares the contents of the modified files with the content-aware comparators.
+// Files that have no differences in their contents, for example ELF files that only differ in
+// ignored sections, are removed from the modified files of the zipDiff. The content differences
+// of the remaining modified files are returned by file name.
+func compareContents(diff *zipDiff, allowLists []allowList, opts contentDiffOptions) (map[string]*contentDiff, error) {
+	contentDiffs := make(map[string]*contentDiff)
+	var modified [][2]*ZipArtifactFile
+	for _, m := range diff.modified {
+		var ignoreMatchingLines []string
+		for _, w := range allowLists {
+			if match, err := Match(w.path, m[0].Name); err != nil {
+				return nil, err
+			} else if match {
+				ignoreMatchingLines = w.ignoreMatchingLines
+				break
+			}
+		}
+
+		a, err := readZipArtifactFile(m[0])
+		if err != nil {
+			return nil, err
+		}
+		b, err := readZipArtifactFile(m[1])
+		if err != nil {
+			return nil, err
+		}
+
+		cd, err := compareFileContents(m[0].Name, a, b, ignoreMatchingLines, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error comparing %s: %v", m[0].Name, err)
+		}
+		if cd != nil && len(cd.Changes) == 0 {
+			continue
+		}
+		if cd != nil {
+			contentDiffs[m[0].Name] = cd
+		}
+		modified = append(modified, m)
+	}
+	diff.modified = modified
+	return contentDiffs, nil
+}
+
+// compareFileContents compares two versions of a file with the first comparator that understands
+// its format. It returns nil if none of the comparators does.
+func compareFileContents(name string, a, b []byte, ignoreMatchingLines []string,
+	opts contentDiffOptions) (*contentDiff, error) {
+
+	// The zip comparator recurses into compareFileContents, so the comparators can't be listed in
+	// a package level variable.
+	comparators := []contentComparator{compareELFContents, compareZipContents, comparePropContents}
+	for _, comparator := range comparators {
+		if cd, err := comparator(name, a, b, ignoreMatchingLines, opts); err != nil || cd != nil {
+			return cd, err
+		}
+	}
+	return nil, nil
+}
+
+func readZipArtifactFile(f *ZipArtifactFile) ([]byte, error) {
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(r)
+}
+
+// comparePropContents compares *.prop files by their properties, ignoring the order of the lines,
+// comments and the lines that match ignoreMatchingLines.
+func comparePropContents(name string, a, b []byte, ignoreMatchingLines []string,
+	_ contentDiffOptions) (*contentDiff, error) {
+
+	if !strings.HasSuffix(name, ".prop") && !strings.HasSuffix(name, "/prop.default") {
+		return nil, nil
+	}
+
+	propsA, err := parseProps(a, ignoreMatchingLines)
+	if err != nil {
+		return nil, err
+	}
+	propsB, err := parseProps(b, ignoreMatchingLines)
+	if err != nil {
+		return nil, err
+	}
+
+	cd := &contentDiff{Format: "prop"}
+	for _, key := range sortedKeys(propsA, propsB) {
+		valueA, inA := propsA[key]
+		valueB, inB := propsB[key]
+		switch {
+		case !inB:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "property", Name: key, Change: changeRemoved, A: valueA})
+		case !inA:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "property", Name: key, Change: changeAdded, B: valueB})
+		case valueA != valueB:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "property", Name: key, Change: changeModified,
+				A: valueA, B: valueB})
+		}
+	}
+	return cd, nil
+}
+
+// parseProps returns the properties of a *.prop file. Like the property service, the last value of
+// a property wins.
+func parseProps(data []byte, ignoreMatchingLines []string) (map[string]string, error) {
+	props := make(map[string]string)
+	s := bufio.NewScanner(bytes.NewReader(data))
+outer:
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, m := range ignoreMatchingLines {
+			if match, err := regexp.MatchString(m, line); err != nil {
+				return nil, err
+			} else if match {
+				continue outer
+			}
+		}
+		key, value := line, ""
+		if i := strings.IndexRune(line, '='); i >= 0 {
+			key, value = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		props[key] = value
+	}
+	return props, s.Err()
+}
+
+func sortedKeys(a, b map[string]string) []string {
+	var keys []string
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// String returns the description of the change that is printed under the modified file.
+func (c contentChange) String() string {
+	s := c.Kind
+	if c.Name != "" {
+		s += " " + c.Name
+	}
+	s += ": " + c.Change
+	switch {
+	case c.Change == changeModified && (c.A != "" || c.B != ""):
+		s += fmt.Sprintf(" (%s -> %s)", c.A, c.B)
+	case c.A != "":
+		s += fmt.Sprintf(" (%s)", c.A)
+	case c.B != "":
+		s += fmt.Sprintf(" (%s)", c.B)
+	}
+	return s
+}
+
+// writeChanges prints the changes of a content difference, and the changes of modified zip
+// entries indented under them.
+func (cd *contentDiff) writeChanges(buf *bytes.Buffer, indent string) {
+	for _, c := range cd.Changes {
+		fmt.Fprintf(buf, "%s%s\n", indent, c)
+		if c.Diff != nil {
+			c.Diff.writeChanges(buf, indent+"   ")
+		}
+	}
+}
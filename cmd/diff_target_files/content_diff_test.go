@@ -0,0 +1,275 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestComparePropContents(t *testing.T) {
+	a := []byte("# comment\nro.a=1\nro.b=2\nro.build.date=Mon\nro.c=3\n")
+	b := []byte("ro.c=3\nro.b=20\nro.build.date=Tue\n\nro.d=4\n")
+
+	cd, err := compareFileContents("system/build.prop", a, b, []string{`ro\.build\.date=.*`}, contentDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &contentDiff{
+		Format: "prop",
+		Changes: []contentChange{
+			{Kind: "property", Name: "ro.a", Change: changeRemoved, A: "1"},
+			{Kind: "property", Name: "ro.b", Change: changeModified, A: "2", B: "20"},
+			{Kind: "property", Name: "ro.d", Change: changeAdded, B: "4"},
+		},
+	}
+	if !reflect.DeepEqual(cd, want) {
+		t.Errorf("want %+v, got %+v", want, cd)
+	}
+
+	cd, err = compareFileContents("system/build.prop", []byte("a=1\nb=2\n"), []byte("b=2\n# comment\na=1\n"), nil,
+		contentDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cd.Changes) != 0 {
+		t.Errorf("want no changes for reordered properties, got %+v", cd.Changes)
+	}
+
+	if cd, err := compareFileContents("system/etc/foo.txt", a, b, nil, contentDiffOptions{}); err != nil || cd != nil {
+		t.Errorf("want no comparator for a text file, got %+v, %v", cd, err)
+	}
+}
+
+type testZipEntry struct {
+	name, contents string
+	modified       time.Time
+}
+
+// testZip returns a zip file with the entries and an APK signing block with the ID-value pairs.
+func testZip(t *testing.T, entries []testZipEntry, signingBlockPairs map[uint32]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for _, e := range entries {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: e.name, Method: zip.Deflate, Modified: e.modified})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if signingBlockPairs == nil {
+		return data
+	}
+
+	// Insert the signing block before the central directory.
+	eocd := len(data) - endOfCentralDirectorySize
+	centralDirectoryOffset := binary.LittleEndian.Uint32(data[eocd+16:])
+	var pairs []byte
+	for id, value := range signingBlockPairs {
+		pair := make([]byte, 12+len(value))
+		binary.LittleEndian.PutUint64(pair, uint64(4+len(value)))
+		binary.LittleEndian.PutUint32(pair[8:], id)
+		copy(pair[12:], value)
+		pairs = append(pairs, pair...)
+	}
+	blockSize := make([]byte, 8)
+	binary.LittleEndian.PutUint64(blockSize, uint64(len(pairs)+8+len(apkSigningBlockMagic)))
+	var block []byte
+	block = append(block, blockSize...)
+	block = append(block, pairs...)
+	block = append(block, blockSize...)
+	block = append(block, apkSigningBlockMagic...)
+
+	var ret []byte
+	ret = append(ret, data[:centralDirectoryOffset]...)
+	ret = append(ret, block...)
+	ret = append(ret, data[centralDirectoryOffset:]...)
+	binary.LittleEndian.PutUint32(ret[len(ret)-endOfCentralDirectorySize+16:], centralDirectoryOffset+uint32(len(block)))
+	return ret
+}
+
+func TestCompareZipContents(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	innerA := string(testZip(t, []testZipEntry{{"res/build.prop", "a=1\n", t0}}, nil))
+	innerB := string(testZip(t, []testZipEntry{{"res/build.prop", "a=2\n", t0}}, nil))
+	a := testZip(t, []testZipEntry{
+		{"classes.dex", "dex", t0},
+		{"removed.txt", "removed", t0},
+		{"lib/inner.jar", innerA, t0},
+		{"assets/time.txt", "same", t0},
+	}, map[uint32]string{0x7109871a: "signature a", 0x42726577: "padding"})
+	b := testZip(t, []testZipEntry{
+		{"added.txt", "added", t0},
+		{"assets/time.txt", "same", t1},
+		{"lib/inner.jar", innerB, t0},
+		{"classes.dex", "dex", t0},
+	}, map[uint32]string{0x7109871a: "signature b2", 0x42726577: "padding"})
+
+	cd, err := compareFileContents("system/app/Foo/Foo.apk", a, b, nil, contentDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	cd.writeChanges(buf, "")
+	want := strings.Join([]string{
+		"entry added.txt: added (5 bytes)",
+		"entry metadata assets/time.txt: modified (2020-01-01 00:00:00 method 8 mode -rw-rw-rw- -> 2020-01-02 00:00:00 method 8 mode -rw-rw-rw-)",
+		fmt.Sprintf("entry lib/inner.jar: modified (%d bytes -> %d bytes)", len(innerA), len(innerB)),
+		"   entry res/build.prop: modified (4 bytes -> 4 bytes)",
+		"      property a: modified (1 -> 2)",
+		"entry removed.txt: removed (7 bytes)",
+		"signing block APK signature scheme v2 (0x7109871a): modified (11 bytes -> 12 bytes)",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("want changes:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	// Zip files that only differ in the order of their entries have no content differences.
+	a = testZip(t, []testZipEntry{{"a", "a", t0}, {"b", "b", t0}}, nil)
+	b = testZip(t, []testZipEntry{{"b", "b", t0}, {"a", "a", t0}}, nil)
+	if cd, err := compareFileContents("foo.jar", a, b, nil, contentDiffOptions{}); err != nil || len(cd.Changes) != 0 {
+		t.Errorf("want no changes for reordered entries, got %+v, %v", cd, err)
+	}
+}
+
+func TestCompareELFContents(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := ioutil.ReadFile(exe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := elf.NewFile(bytes.NewReader(a))
+	if err != nil {
+		t.Skipf("test binary is not an ELF file: %v", err)
+	}
+	section := f.Section(".note.go.buildid")
+	if section == nil {
+		t.Skip("test binary has no .note.go.buildid section")
+	}
+
+	// Modify the last byte of the build ID note.
+	b := append([]byte(nil), a...)
+	b[section.Offset+section.Size-1] ^= 0xff
+
+	cd, err := compareFileContents("system/bin/foo", a, b, nil, contentDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []contentChange{{Kind: "section", Name: ".note.go.buildid", Change: changeModified}}
+	if !reflect.DeepEqual(cd.Changes, want) {
+		t.Errorf("want changes %+v, got %+v", want, cd.Changes)
+	}
+
+	cd, err = compareFileContents("system/bin/foo", a, b, nil, contentDiffOptions{
+		ignoreELFSections: []string{".note.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cd.Changes) != 0 {
+		t.Errorf("want no changes when ignoring the note sections, got %+v", cd.Changes)
+	}
+}
+
+func TestCompareContents(t *testing.T) {
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := testZip(t, []testZipEntry{
+		{"system/build.prop", "ro.a=1\nro.build.date=Mon\n", t0},
+		{"system/etc/reordered.prop", "a=1\nb=2\n", t0},
+		{"system/etc/foo.txt", "foo", t0},
+	}, nil)
+	b := testZip(t, []testZipEntry{
+		{"system/build.prop", "ro.a=2\nro.build.date=Tue\n", t0},
+		{"system/etc/reordered.prop", "b=2\na=1\n", t0},
+		{"system/etc/foo.txt", "bar", t0},
+	}, nil)
+	zipA, err := zip.NewReader(bytes.NewReader(a), int64(len(a)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipB, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := diffTargetFilesLists(sortedZipEntries(zipA), sortedZipEntries(zipB))
+	allowLists := []allowList{{path: "**/build.prop", ignoreMatchingLines: []string{`ro\.build\.date=.*`}}}
+	contentDiffs, err := compareContents(&diff, allowLists, contentDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantText := strings.Join([]string{
+		"files modified:",
+		"   system/build.prop (25 bytes -> 25 bytes)",
+		"      property ro.a: modified (1 -> 2)",
+		"   system/etc/foo.txt (3 bytes -> 3 bytes)",
+		"total size change: 0 bytes",
+		"",
+	}, "\n")
+	if got := diff.format(contentDiffs); got != wantText {
+		t.Errorf("want text output:\n%s\ngot:\n%s", wantText, got)
+	}
+
+	data, err := diff.JSON(contentDiffs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got jsonZipDiff
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := jsonZipDiff{
+		Modified: []jsonModifiedFile{
+			{
+				Name:  "system/build.prop",
+				SizeA: 25,
+				SizeB: 25,
+				Diff: &contentDiff{
+					Format:  "prop",
+					Changes: []contentChange{{Kind: "property", Name: "ro.a", Change: changeModified, A: "1", B: "2"}},
+				},
+			},
+			{Name: "system/etc/foo.txt", SizeA: 3, SizeB: 3},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want JSON output %+v, got %+v", want, got)
+	}
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 )
@@ -26,6 +27,12 @@ var (
 	allowListFiles = newMultiString("allowlist_file", "files containing allowlist definitions")
 
 	filters = newMultiString("filter", "filter patterns to apply to files in target-files.zip before comparing")
+
+	compareContentsFlag = flag.Bool("compare_contents", true,
+		"compare modified ELF, APK, JAR and *.prop files by their contents")
+	ignoreELFSections = newMultiString("ignore_elf_section",
+		"patterns of ELF sections to ignore when comparing ELF files, for example .note.gnu.build-id")
+	jsonOutput = flag.String("json_output", "", "write the differences as JSON to this file")
 )
 
 func newMultiString(name, usage string) *multiString {
@@ -43,7 +50,8 @@ func main() {
 	flag.Parse()
 
 	if flag.NArg() != 2 {
-		fmt.Fprintf(os.Stderr, "Error, exactly two arguments are required\n")
+		fmt.Fprintf(os.Stderr, "Error, exactly two arguments are required, each a target-files.zip, "+
+			"an extracted target-files directory or a product output directory\n")
 		os.Exit(1)
 	}
 
@@ -53,16 +61,16 @@ func main() {
 		os.Exit(1)
 	}
 
-	priZip, err := NewLocalZipArtifact(flag.Arg(0))
+	priZip, err := OpenArtifact(flag.Arg(0))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error opening zip file %v: %v\n", flag.Arg(0), err)
+		fmt.Fprintf(os.Stderr, "Error opening %v: %v\n", flag.Arg(0), err)
 		os.Exit(1)
 	}
 	defer priZip.Close()
 
-	refZip, err := NewLocalZipArtifact(flag.Arg(1))
+	refZip, err := OpenArtifact(flag.Arg(1))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error opening zip file %v: %v\n", flag.Arg(1), err)
+		fmt.Fprintf(os.Stderr, "Error opening %v: %v\n", flag.Arg(1), err)
 		os.Exit(1)
 	}
 	defer refZip.Close()
@@ -73,7 +81,30 @@ func main() {
 		os.Exit(1)
 	}
 
-	fmt.Print(diff.String())
+	var contentDiffs map[string]*contentDiff
+	if *compareContentsFlag {
+		contentDiffs, err = compareContents(&diff, allowLists, contentDiffOptions{
+			ignoreELFSections: *ignoreELFSections,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error comparing file contents: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	fmt.Print(diff.format(contentDiffs))
+
+	if *jsonOutput != "" {
+		data, err := diff.JSON(contentDiffs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error generating JSON output: %v\n", err)
+			os.Exit(1)
+		}
+		if err := ioutil.WriteFile(*jsonOutput, data, 0666); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing JSON output: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	if len(diff.modified) > 0 || len(diff.onlyInA) > 0 || len(diff.onlyInB) > 0 {
 		fmt.Fprintln(os.Stderr, "differences found")
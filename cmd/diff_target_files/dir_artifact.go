@@ -0,0 +1,210 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// productOutPartitions maps the directories of the partitions in out/target/product/<device> to
+// the directories of the partitions in target-files.zip.
+var productOutPartitions = map[string]string{
+	"data/":         "DATA/",
+	"odm/":          "ODM/",
+	"oem/":          "OEM/",
+	"product/":      "PRODUCT/",
+	"ramdisk/":      "BOOT/RAMDISK/",
+	"root/":         "ROOT/",
+	"system/":       "SYSTEM/",
+	"system_ext/":   "SYSTEM_EXT/",
+	"system_other/": "SYSTEM_OTHER/",
+	"vendor/":       "VENDOR/",
+}
+
+// localDirArtifact is a handle to a local directory that is compared as if it was a zip file.
+type localDirArtifact struct {
+	files []*ZipArtifactFile
+}
+
+// NewLocalDirArtifact returns a ZipArtifact for a local directory, for example an extracted
+// target-files.zip staging directory.  The names of the files are relative to the directory.
+func NewLocalDirArtifact(dir string) (ZipArtifact, error) {
+	return newLocalDirArtifact(dir, func(name string) (string, bool) { return name, true })
+}
+
+// NewProductOutArtifact returns a ZipArtifact for the partition directories of a product output
+// directory (out/target/product/<device>), with the files renamed to their names in
+// target-files.zip.  The images and the other files in the product output directory are skipped.
+func NewProductOutArtifact(dir string) (ZipArtifact, error) {
+	return newLocalDirArtifact(dir, func(name string) (string, bool) {
+		for productOut, targetFiles := range productOutPartitions {
+			if strings.HasPrefix(name, productOut) {
+				return targetFiles + strings.TrimPrefix(name, productOut), true
+			}
+		}
+		return "", false
+	})
+}
+
+// newLocalDirArtifact returns a ZipArtifact for the files in a local directory, using rename to
+// compute the name of each file from its path relative to the directory, or to skip it.  The CRCs
+// of the files are computed in parallel.
+func newLocalDirArtifact(dir string, rename func(string) (string, bool)) (ZipArtifact, error) {
+	var files []*ZipArtifactFile
+	var paths []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name, ok := rename(filepath.ToSlash(rel))
+		if !ok {
+			return nil
+		}
+		fh := &zip.FileHeader{
+			Name:     name,
+			Modified: info.ModTime(),
+		}
+		fh.SetMode(info.Mode())
+		files = append(files, &ZipArtifactFile{
+			File: &zip.File{FileHeader: *fh},
+			open: func() (io.ReadCloser, error) { return openDirArtifactFile(path) },
+		})
+		paths = append(paths, path)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := computeCRCs(files, paths); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	return &localDirArtifact{files: files}, nil
+}
+
+// openDirArtifactFile opens a file in a local directory.  Like in a zip file, the contents of a
+// symlink are its target.
+func openDirArtifactFile(path string) (io.ReadCloser, error) {
+	fi, err := os.Lstat(path)
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader([]byte(target))), nil
+	}
+	return os.Open(path)
+}
+
+// computeCRCs sets the uncompressed sizes and the CRCs of the files in parallel.
+func computeCRCs(files []*ZipArtifactFile, paths []string) error {
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	ch := make(chan int)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				size, crc, err := crcFile(paths[i])
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					continue
+				}
+				files[i].UncompressedSize64 = size
+				files[i].CRC32 = crc
+			}
+		}()
+	}
+	for i := range files {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+
+	return firstErr
+}
+
+func crcFile(path string) (uint64, uint32, error) {
+	r, err := openDirArtifactFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer r.Close()
+
+	crc := crc32.NewIEEE()
+	size, err := io.Copy(crc, r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint64(size), crc.Sum32(), nil
+}
+
+// Files returns the list of files contained in the local directory artifact.
+func (d *localDirArtifact) Files() ([]*ZipArtifactFile, error) {
+	return d.files, nil
+}
+
+// Close does nothing, the files of a local directory artifact are opened on demand.
+func (d *localDirArtifact) Close() {}
+
+// OpenArtifact returns a ZipArtifact for a local zip file, an extracted target-files.zip staging
+// directory, or a product output directory.  Directories that contain a SYSTEM directory are
+// treated as staging directories.
+func OpenArtifact(name string) (ZipArtifact, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return NewLocalZipArtifact(name)
+	}
+
+	entries, err := ioutil.ReadDir(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name() == "SYSTEM" && entry.IsDir() {
+			return NewLocalDirArtifact(name)
+		}
+	}
+	return NewProductOutArtifact(name)
+}
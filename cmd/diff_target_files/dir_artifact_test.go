@@ -0,0 +1,154 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirArtifacts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diff_target_files_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles := func(root string, files map[string]string) {
+		t.Helper()
+		for name, contents := range files {
+			path := filepath.Join(root, name)
+			if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, []byte(contents), 0666); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// The target-files.zip.
+	targetFiles := filepath.Join(dir, "target_files.zip")
+	f, err := os.Create(targetFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, e := range []struct {
+		name, contents string
+		mode           os.FileMode
+	}{
+		{"BOOT/RAMDISK/init.rc", "on boot", 0644},
+		{"IMAGES/system.img", "image", 0644},
+		{"SYSTEM/bin/sh", "mksh", os.ModeSymlink | 0777},
+		{"SYSTEM/build.prop", "ro.a=1\n", 0644},
+		{"VENDOR/lib/libfoo.so", "foo", 0644},
+	} {
+		fh := &zip.FileHeader{Name: e.name}
+		fh.SetMode(e.mode)
+		w, err := zw.CreateHeader(fh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.contents)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The staging directory of the target-files.zip.
+	staging := filepath.Join(dir, "staging")
+	writeFiles(staging, map[string]string{
+		"BOOT/RAMDISK/init.rc": "on boot",
+		"IMAGES/system.img":    "other image",
+		"SYSTEM/build.prop":    "ro.a=1\n",
+		"VENDOR/lib/libfoo.so": "foo",
+	})
+
+	// The product output directory, with a modified property.
+	productOut := filepath.Join(dir, "product_out")
+	writeFiles(productOut, map[string]string{
+		"ramdisk/init.rc":          "on boot",
+		"system.img":               "image",
+		"obj/PACKAGING/foo":        "foo",
+		"system/build.prop":        "ro.a=2\n",
+		"vendor/lib/libfoo.so":     "foo",
+		"symbols/system/bin/false": "false",
+	})
+
+	for _, link := range []string{"staging/SYSTEM/bin/sh", "product_out/system/bin/sh"} {
+		path := filepath.Join(dir, link)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink("mksh", path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The artifacts stay open for the content comparison.
+	var artifacts []ZipArtifact
+	defer func() {
+		for _, artifact := range artifacts {
+			artifact.Close()
+		}
+	}()
+	compare := func(a, b string) zipDiff {
+		t.Helper()
+		artifactA, err := OpenArtifact(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		artifactB, err := OpenArtifact(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		artifacts = append(artifacts, artifactA, artifactB)
+		diff, err := compareTargetFiles(artifactA, artifactB, targetFilesPattern, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return diff
+	}
+
+	if diff := compare(staging, targetFiles); diff.String() != "" {
+		t.Errorf("want no differences between the staging directory and the target-files.zip, got:\n%s",
+			diff.String())
+	}
+
+	diff := compare(productOut, targetFiles)
+	if len(diff.modified) != 1 || len(diff.onlyInA) != 0 || len(diff.onlyInB) != 0 {
+		t.Fatalf("want only system/build.prop to be modified, got:\n%s", diff.String())
+	}
+	contentDiffs, err := compareContents(&diff, nil, contentDiffOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "files modified:\n" +
+		"   system/build.prop (7 bytes -> 7 bytes)\n" +
+		"      property ro.a: modified (1 -> 2)\n" +
+		"total size change: 0 bytes\n"
+	if got := diff.format(contentDiffs); got != want {
+		t.Errorf("want:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"reflect"
+)
+
+// compareELFContents compares ELF files section by section. The sections whose names match the
+// ignoreELFSections patterns are skipped, and the offsets and addresses of the sections are not
+// compared so that a section that grows is not reported as moving all of the sections after it.
+func compareELFContents(_ string, a, b []byte, _ []string, opts contentDiffOptions) (*contentDiff, error) {
+	if !bytes.HasPrefix(a, []byte(elf.ELFMAG)) || !bytes.HasPrefix(b, []byte(elf.ELFMAG)) {
+		return nil, nil
+	}
+
+	elfA, err := elf.NewFile(bytes.NewReader(a))
+	if err != nil {
+		return nil, err
+	}
+	elfB, err := elf.NewFile(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	cd := &contentDiff{Format: "elf"}
+	if elfA.FileHeader != elfB.FileHeader {
+		cd.Changes = append(cd.Changes, contentChange{Kind: "header", Change: changeModified})
+	}
+	if !reflect.DeepEqual(progHeaders(elfA), progHeaders(elfB)) {
+		cd.Changes = append(cd.Changes, contentChange{Kind: "program headers", Change: changeModified,
+			A: fmt.Sprintf("%d segments", len(elfA.Progs)), B: fmt.Sprintf("%d segments", len(elfB.Progs))})
+	}
+
+	sectionsA, err := elfSections(elfA, opts.ignoreELFSections)
+	if err != nil {
+		return nil, err
+	}
+	sectionsB, err := elfSections(elfB, opts.ignoreELFSections)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range sortedSectionNames(sectionsA, sectionsB) {
+		sa, inA := sectionsA[name]
+		sb, inB := sectionsB[name]
+		switch {
+		case !inB:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "section", Name: name, Change: changeRemoved,
+				A: fmt.Sprintf("%d bytes", sa.Size)})
+		case !inA:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "section", Name: name, Change: changeAdded,
+				B: fmt.Sprintf("%d bytes", sb.Size)})
+		case sa.Type != sb.Type || sa.Flags != sb.Flags:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "section", Name: name, Change: changeModified,
+				A: fmt.Sprintf("%s %s", sa.Type, sa.Flags), B: fmt.Sprintf("%s %s", sb.Type, sb.Flags)})
+		case sa.Size != sb.Size:
+			cd.Changes = append(cd.Changes, contentChange{Kind: "section", Name: name, Change: changeModified,
+				A: fmt.Sprintf("%d bytes", sa.Size), B: fmt.Sprintf("%d bytes", sb.Size)})
+		default:
+			dataA, err := sectionData(sa)
+			if err != nil {
+				return nil, err
+			}
+			dataB, err := sectionData(sb)
+			if err != nil {
+				return nil, err
+			}
+			if !bytes.Equal(dataA, dataB) {
+				cd.Changes = append(cd.Changes, contentChange{Kind: "section", Name: name, Change: changeModified})
+			}
+		}
+	}
+
+	return cd, nil
+}
+
+// progHeaders returns the program headers of an ELF file without the offsets and addresses of
+// the segments, which change when any of the sections in them grows.
+func progHeaders(f *elf.File) []elf.ProgHeader {
+	var ret []elf.ProgHeader
+	for _, p := range f.Progs {
+		ret = append(ret, elf.ProgHeader{Type: p.Type, Flags: p.Flags, Align: p.Align})
+	}
+	return ret
+}
+
+// elfSections returns the sections of an ELF file by name, except for the ignored sections.
+func elfSections(f *elf.File, ignoreSections []string) (map[string]*elf.Section, error) {
+	sections := make(map[string]*elf.Section)
+outer:
+	for i, s := range f.Sections {
+		if s.Type == elf.SHT_NULL || s.Name == "" {
+			continue
+		}
+		for _, pattern := range ignoreSections {
+			if match, err := Match(pattern, s.Name); err != nil {
+				return nil, err
+			} else if match {
+				continue outer
+			}
+		}
+		name := s.Name
+		if _, exists := sections[name]; exists {
+			// Sections with the same name are compared by their indices.
+			name = fmt.Sprintf("%s[%d]", name, i)
+		}
+		sections[name] = s
+	}
+	return sections, nil
+}
+
+func sortedSectionNames(a, b map[string]*elf.Section) []string {
+	names := make(map[string]string)
+	for name := range a {
+		names[name] = ""
+	}
+	for name := range b {
+		names[name] = ""
+	}
+	return sortedKeys(names, nil)
+}
+
+func sectionData(s *elf.Section) ([]byte, error) {
+	if s.Type == elf.SHT_NOBITS {
+		return nil, nil
+	}
+	return s.Data()
+}
@@ -49,7 +49,7 @@ func NewLocalZipArtifact(name string) (ZipArtifact, error) {
 
 	var files []*ZipArtifactFile
 	for _, zf := range zr.File {
-		files = append(files, &ZipArtifactFile{zf})
+		files = append(files, &ZipArtifactFile{File: zf})
 	}
 
 	return &localZipArtifact{
@@ -69,9 +69,20 @@ func (z *localZipArtifact) Close() {
 }
 
 // ZipArtifactFile contains a zip.File handle to the data inside the remote *-target_files-*.zip
-// build artifact.
+// build artifact.  For files that are not in a zip file the zip.File only contains the header,
+// and open returns the contents.
 type ZipArtifactFile struct {
 	*zip.File
+
+	open func() (io.ReadCloser, error)
+}
+
+// Open returns a ReadCloser that provides access to the contents of the file.
+func (zf *ZipArtifactFile) Open() (io.ReadCloser, error) {
+	if zf.open != nil {
+		return zf.open()
+	}
+	return zf.File.Open()
 }
 
 // Extract begins extract a file from inside a ZipArtifact.  It returns an
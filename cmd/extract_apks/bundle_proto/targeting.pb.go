@@ -129,6 +129,7 @@ const (
 	Abi_X86_64                       Abi_AbiAlias = 5
 	Abi_MIPS                         Abi_AbiAlias = 6
 	Abi_MIPS64                       Abi_AbiAlias = 7
+	Abi_RISCV64                      Abi_AbiAlias = 8
 )
 
 var Abi_AbiAlias_name = map[int32]string{
@@ -140,6 +141,7 @@ var Abi_AbiAlias_name = map[int32]string{
 	5: "X86_64",
 	6: "MIPS",
 	7: "MIPS64",
+	8: "RISCV64",
 }
 
 var Abi_AbiAlias_value = map[string]int32{
@@ -151,6 +153,7 @@ var Abi_AbiAlias_value = map[string]int32{
 	"X86_64":                       5,
 	"MIPS":                         6,
 	"MIPS64":                       7,
+	"RISCV64":                      8,
 }
 
 func (x Abi_AbiAlias) String() string {
@@ -1636,99 +1639,100 @@ func init() {
 }
 
 var fileDescriptor_df45b505afdf471e = []byte{
-	// 1504 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x5b, 0x6f, 0xe3, 0xc4,
-	0x17, 0xaf, 0x93, 0xa6, 0x4d, 0x4e, 0x92, 0xd6, 0x3b, 0xe9, 0xbf, 0xff, 0xb0, 0xec, 0x4a, 0x5b,
-	0xef, 0x85, 0xee, 0x0a, 0x05, 0xda, 0xae, 0xba, 0x15, 0x97, 0x22, 0xd7, 0x71, 0x9b, 0x48, 0x4d,
-	0x9a, 0x75, 0xdc, 0x6c, 0x59, 0x90, 0xbc, 0x4e, 0x3c, 0x0d, 0xa6, 0x89, 0x1d, 0x6c, 0xa7, 0xda,
-	0xe5, 0x05, 0x81, 0x90, 0x90, 0x78, 0xe2, 0x8d, 0x77, 0x3e, 0x00, 0x12, 0x4f, 0x08, 0x89, 0x07,
-	0x24, 0x3e, 0x0c, 0x7c, 0x0c, 0x34, 0xbe, 0x24, 0x9e, 0xc4, 0x4e, 0xb3, 0x2c, 0xf0, 0x50, 0xe5,
-	0xcc, 0xf1, 0x39, 0xbf, 0x73, 0x99, 0x73, 0x66, 0xce, 0x14, 0x56, 0x1d, 0xd5, 0xea, 0x62, 0x47,
-	0x37, 0xba, 0xa5, 0x81, 0x65, 0x3a, 0x26, 0x5a, 0x51, 0x0d, 0xcd, 0x32, 0x75, 0xad, 0xd4, 0x1e,
-	0x1a, 0x5a, 0x0f, 0x73, 0x7f, 0x26, 0x81, 0x6d, 0xa9, 0x96, 0xae, 0x1a, 0x8e, 0x1c, 0x88, 0xa2,
-	0x27, 0xf0, 0x3f, 0x5b, 0xbb, 0x50, 0x2e, 0xb1, 0x65, 0xeb, 0xa6, 0xa1, 0x8c, 0x30, 0x8a, 0xcc,
-	0x2d, 0x66, 0x33, 0xbb, 0x7d, 0xbb, 0x44, 0x83, 0x94, 0x9a, 0xda, 0x45, 0xcb, 0x93, 0x1d, 0x61,
-	0x48, 0x05, 0x7b, 0x9a, 0x89, 0x78, 0xc8, 0xab, 0x6d, 0x3d, 0x04, 0x98, 0x70, 0x01, 0x6f, 0x4c,
-	0x02, 0xf2, 0x6d, 0x7d, 0x8c, 0x94, 0x53, 0x43, 0x2b, 0xf4, 0x0c, 0x8a, 0x76, 0xc7, 0xc2, 0xd8,
-	0x50, 0x34, 0x6c, 0xd8, 0xba, 0xf3, 0x22, 0x84, 0x96, 0x74, 0xd1, 0xee, 0x4d, 0xb9, 0xe7, 0xca,
-	0x97, 0x3d, 0xf1, 0x31, 0xee, 0xba, 0x1d, 0xc9, 0x47, 0x8f, 0xa1, 0xd0, 0x1f, 0xf6, 0x1c, 0x5d,
-	0xa1, 0x5d, 0x5d, 0x74, 0xc1, 0x37, 0x26, 0xc1, 0x6b, 0x44, 0x94, 0xf2, 0xf7, 0x5a, 0x7f, 0x92,
-	0x85, 0xbe, 0x62, 0xe0, 0x8e, 0x83, 0x9f, 0x3b, 0x43, 0x0b, 0x2b, 0x1d, 0xb3, 0x3f, 0xb0, 0xb0,
-	0xed, 0x66, 0xf6, 0xdc, 0xb4, 0xfa, 0xaa, 0x13, 0x32, 0x92, 0x72, 0x8d, 0x6c, 0x4d, 0x1a, 0x91,
-	0x3d, 0x5d, 0x61, 0xac, 0x7a, 0xe8, 0x6a, 0x8e, 0x8d, 0x6e, 0x38, 0x57, 0x89, 0x70, 0x7f, 0xa4,
-	0x20, 0xc7, 0x0f, 0x2e, 0x66, 0xec, 0x06, 0xf3, 0xd2, 0xbb, 0xf1, 0x14, 0xd6, 0xbb, 0x96, 0x3a,
-	0xf8, 0x44, 0xef, 0xd8, 0x8a, 0x3a, 0x98, 0xde, 0xd9, 0x3b, 0x93, 0x58, 0x47, 0xbe, 0x34, 0x3f,
-	0x08, 0x61, 0xae, 0x75, 0x23, 0xb8, 0xa8, 0x01, 0xa8, 0xa7, 0x1a, 0xdd, 0xa1, 0xda, 0xc5, 0x53,
-	0x7b, 0x3c, 0xb5, 0x0d, 0xc7, 0xbe, 0x64, 0x68, 0x1b, 0x7a, 0x93, 0xac, 0x99, 0xb5, 0xb3, 0xf8,
-	0x8f, 0xd4, 0x4e, 0x6c, 0xe7, 0xa4, 0x5e, 0xb1, 0x73, 0xe6, 0xae, 0xa0, 0xa5, 0x7f, 0xaf, 0x82,
-	0xe2, 0x3a, 0x63, 0xf9, 0x15, 0x3a, 0xa3, 0x09, 0x05, 0x5b, 0x35, 0x74, 0x47, 0xff, 0x1c, 0x5b,
-	0x21, 0xc8, 0xb4, 0x0b, 0xc9, 0x4d, 0xa5, 0x2b, 0x10, 0x1d, 0x63, 0x22, 0x7b, 0x8a, 0xc7, 0xfd,
-	0x98, 0x80, 0xd5, 0x9a, 0xa9, 0x0d, 0x7b, 0xf8, 0x3f, 0x38, 0xd3, 0x9e, 0x41, 0x51, 0xc3, 0x97,
-	0x7a, 0x07, 0x2b, 0xe7, 0x58, 0x75, 0xf7, 0x27, 0xdc, 0x04, 0xc9, 0xa8, 0xa2, 0x2a, 0xbb, 0xf2,
-	0x87, 0x9e, 0x78, 0xa8, 0xa8, 0xb4, 0x48, 0x3e, 0xb1, 0x30, 0xb4, 0xb1, 0xa5, 0x74, 0xcc, 0xa1,
-	0xe1, 0x58, 0x3a, 0xb6, 0xaf, 0x3e, 0xf2, 0x4e, 0x6d, 0x6c, 0x09, 0x81, 0x78, 0xc8, 0xc2, 0x30,
-	0x92, 0xcf, 0x3d, 0x81, 0xf5, 0x68, 0x0d, 0x74, 0x1b, 0xf2, 0x9e, 0xd9, 0x17, 0x4a, 0xc7, 0xd4,
-	0xb0, 0x5d, 0x64, 0x6e, 0x25, 0x37, 0x33, 0x52, 0xce, 0x67, 0x0a, 0x84, 0x87, 0x8a, 0xb0, 0x8c,
-	0x9f, 0x77, 0x7a, 0x43, 0x0d, 0xbb, 0x6d, 0x9f, 0x96, 0x82, 0x25, 0xf7, 0x7d, 0x02, 0xf2, 0x54,
-	0x0b, 0xa1, 0xc7, 0x90, 0x0f, 0x9a, 0x4f, 0xed, 0xe9, 0xaa, 0xed, 0xe6, 0x7f, 0x65, 0xfb, 0xc1,
-	0xcc, 0xc6, 0x2b, 0xf9, 0xbf, 0x3c, 0xd1, 0xa8, 0x2c, 0x48, 0x39, 0x2d, 0xb4, 0x46, 0x1b, 0x90,
-	0x0d, 0x20, 0xb5, 0x81, 0xee, 0xba, 0x90, 0xaa, 0x2c, 0x48, 0xe0, 0x33, 0xcb, 0x03, 0x9d, 0xfb,
-	0x02, 0x72, 0x61, 0x08, 0xf4, 0x7f, 0x28, 0x94, 0xc5, 0x7a, 0xb3, 0x2a, 0x7f, 0xa8, 0x9c, 0xd6,
-	0x9b, 0x0d, 0x51, 0xa8, 0x1e, 0x56, 0xc5, 0x32, 0xbb, 0x80, 0x32, 0x90, 0xaa, 0x9f, 0x94, 0x1b,
-	0x55, 0x96, 0x41, 0x69, 0x58, 0x3c, 0x26, 0x54, 0x82, 0x50, 0x35, 0x42, 0x25, 0xc9, 0x67, 0xb9,
-	0x45, 0xc8, 0x45, 0xc2, 0xac, 0x10, 0x2a, 0x45, 0x98, 0x67, 0x2e, 0xb9, 0x84, 0x00, 0x96, 0xce,
-	0x3c, 0x7a, 0x19, 0x65, 0x61, 0xf9, 0xcc, 0x5f, 0xa4, 0x0f, 0x56, 0xc7, 0x61, 0x9b, 0x06, 0x36,
-	0xcf, 0x39, 0x0e, 0xa0, 0x6a, 0x38, 0x3b, 0xdb, 0x2d, 0xb5, 0x37, 0xc4, 0x68, 0x0d, 0x52, 0x97,
-	0x84, 0x70, 0xb3, 0x91, 0x92, 0xbc, 0x05, 0xf7, 0x0e, 0xc0, 0xb8, 0x0c, 0xd1, 0x9b, 0x90, 0xec,
-	0xeb, 0x86, 0x5f, 0xaf, 0xd7, 0x27, 0xf3, 0x35, 0x06, 0x93, 0x88, 0x18, 0xf7, 0x0b, 0x03, 0xd9,
-	0xd0, 0x61, 0x8b, 0xea, 0x50, 0xe8, 0xeb, 0x86, 0x62, 0x0e, 0xb0, 0xa1, 0x74, 0x7b, 0x41, 0x1f,
-	0xf8, 0x68, 0x37, 0x27, 0xd1, 0x4e, 0x06, 0xd8, 0x38, 0xea, 0xf9, 0x96, 0x2b, 0x0b, 0x12, 0xdb,
-	0xd7, 0x0d, 0x8a, 0x87, 0x6a, 0x80, 0x08, 0xde, 0xe5, 0xb0, 0x77, 0xa1, 0x1a, 0x23, 0xb8, 0x44,
-	0x34, 0x5c, 0xcb, 0x95, 0xa2, 0xe1, 0x28, 0xde, 0x41, 0x16, 0x32, 0xe4, 0xfe, 0xf0, 0x72, 0xf3,
-	0x2e, 0xe4, 0xa9, 0xaf, 0x24, 0x3d, 0x7d, 0xf5, 0x53, 0xd3, 0x0a, 0xd2, 0xe3, 0x2e, 0x5c, 0xae,
-	0x6e, 0x98, 0x96, 0xb7, 0xe3, 0x92, 0xb7, 0x20, 0xca, 0xb4, 0xa7, 0x2f, 0xa3, 0xfc, 0x73, 0x02,
-	0x8a, 0x71, 0x47, 0x25, 0xfa, 0x18, 0x52, 0xe1, 0x92, 0x3d, 0x9c, 0xf7, 0x8c, 0x8d, 0xfd, 0xe0,
-	0xd6, 0xa2, 0xe4, 0x81, 0x72, 0xbf, 0x32, 0x70, 0x73, 0xa6, 0x20, 0x7a, 0x00, 0xf7, 0x42, 0xc5,
-	0xaa, 0xc8, 0xe2, 0x99, 0x7c, 0x2a, 0x89, 0x8a, 0x70, 0x52, 0x6b, 0x48, 0x62, 0xb3, 0x59, 0x3d,
-	0xa9, 0x2b, 0x87, 0x27, 0x52, 0x8d, 0x97, 0xd9, 0x05, 0x94, 0x87, 0x8c, 0x28, 0x0b, 0x5b, 0x8a,
-	0x74, 0x74, 0xb0, 0xc7, 0x32, 0x28, 0x07, 0xe9, 0x06, 0x7f, 0x2c, 0xca, 0xb2, 0x58, 0x66, 0x13,
-	0x64, 0x25, 0x57, 0x24, 0x51, 0x54, 0xca, 0x02, 0x9b, 0x44, 0xcb, 0x90, 0xe4, 0x65, 0xc1, 0xab,
-	0xe8, 0x63, 0x42, 0xa5, 0x08, 0x55, 0x3e, 0x93, 0xb7, 0xd8, 0x25, 0x42, 0x35, 0x77, 0x64, 0x81,
-	0x5d, 0x26, 0x55, 0xde, 0x68, 0x49, 0xb2, 0xc0, 0xa6, 0x09, 0x93, 0x6f, 0xca, 0x02, 0x9b, 0x21,
-	0x94, 0x28, 0x0b, 0xdb, 0x2c, 0x70, 0xbf, 0x31, 0x90, 0xe4, 0xdb, 0x3a, 0xda, 0xa6, 0x93, 0x14,
-	0x35, 0x4c, 0x90, 0x3f, 0x2a, 0xf4, 0xaf, 0x19, 0x48, 0x07, 0x3c, 0x74, 0x0b, 0x6e, 0x84, 0xa3,
-	0x14, 0x1a, 0xa7, 0x0a, 0x2f, 0x09, 0x95, 0xaa, 0x2c, 0x0a, 0x24, 0x5c, 0x76, 0x81, 0x34, 0x16,
-	0x2f, 0xd5, 0x44, 0xfe, 0x80, 0x74, 0xe9, 0x2a, 0x64, 0xfd, 0x85, 0xd2, 0x7a, 0xc4, 0xb3, 0x09,
-	0x12, 0x39, 0x2f, 0xd5, 0x76, 0x1f, 0x2a, 0xad, 0x3d, 0xde, 0x8b, 0xee, 0x6c, 0x6f, 0x97, 0x5d,
-	0x74, 0x5b, 0x73, 0x6f, 0x57, 0xd9, 0x7d, 0xe8, 0xc5, 0x57, 0xab, 0x36, 0x9a, 0x5e, 0xc3, 0x12,
-	0x6a, 0xf7, 0x21, 0xbb, 0xcc, 0x6d, 0x41, 0x3a, 0xb8, 0xb3, 0xd0, 0x5d, 0x48, 0xaa, 0x6d, 0xdd,
-	0x3d, 0xed, 0xb2, 0xdb, 0x85, 0x88, 0x20, 0x24, 0xf2, 0x9d, 0xbb, 0x84, 0xcc, 0xe8, 0x4e, 0x42,
-	0xfb, 0x74, 0xe8, 0x9b, 0xb1, 0xb7, 0xd7, 0x98, 0xa2, 0xd2, 0x70, 0x1f, 0x56, 0xe8, 0x0f, 0xc4,
-	0xcf, 0xfa, 0x49, 0x5d, 0xf4, 0xf6, 0xb3, 0xf2, 0x84, 0x2f, 0x97, 0xc9, 0x46, 0xb3, 0x0c, 0xf7,
-	0x11, 0xe4, 0xa9, 0x4b, 0x04, 0x6d, 0x40, 0x2e, 0xb8, 0x7e, 0x0c, 0xb5, 0xef, 0x9d, 0x23, 0x19,
-	0x29, 0xeb, 0xf3, 0xea, 0x6a, 0x1f, 0xa3, 0x37, 0x60, 0x35, 0x10, 0x09, 0xb7, 0x6b, 0x4a, 0x5a,
-	0xf1, 0xd9, 0x7e, 0xc3, 0x70, 0xbf, 0x27, 0xa0, 0xc8, 0xdb, 0x36, 0x76, 0xec, 0xb2, 0x6e, 0xe1,
-	0x8e, 0x63, 0x5a, 0xa1, 0x09, 0xa7, 0x14, 0x24, 0xe6, 0xea, 0x51, 0x91, 0x08, 0xa2, 0x23, 0xc8,
-	0x85, 0x27, 0xc4, 0x97, 0x9a, 0x0b, 0xb3, 0xa1, 0xb9, 0x10, 0x99, 0x70, 0x3d, 0x7e, 0x00, 0xf2,
-	0xef, 0xc1, 0xbf, 0x31, 0xf6, 0x14, 0xe3, 0xc6, 0x1e, 0xf4, 0x3e, 0xa4, 0x83, 0x11, 0x32, 0x6e,
-	0xf8, 0x9f, 0x9e, 0x3a, 0x47, 0x2a, 0xdc, 0x0f, 0x09, 0x28, 0xd6, 0x55, 0x47, 0xbf, 0xc4, 0x11,
-	0x59, 0xbc, 0x1b, 0xce, 0x62, 0x6c, 0x79, 0xa1, 0xfd, 0xc8, 0xe4, 0xbd, 0x3e, 0x23, 0x79, 0x74,
-	0xce, 0xce, 0xe7, 0xc8, 0xd9, 0xe6, 0xbc, 0x39, 0x9b, 0x91, 0xaa, 0x47, 0x90, 0x19, 0x8d, 0x61,
-	0x7e, 0xae, 0x5e, 0x8b, 0xad, 0x7e, 0x69, 0x2c, 0xcb, 0xc9, 0x80, 0xf8, 0x01, 0x7e, 0x5e, 0xed,
-	0x53, 0x73, 0xfa, 0x3e, 0x64, 0x46, 0x73, 0xa6, 0x9f, 0xa3, 0x39, 0xa6, 0xcb, 0x74, 0x30, 0x5d,
-	0x72, 0x16, 0xe4, 0xa8, 0x21, 0xf3, 0xfe, 0xf8, 0x76, 0x8d, 0x6d, 0x67, 0x4f, 0x02, 0x3d, 0x82,
-	0x9c, 0xda, 0x73, 0xb0, 0x65, 0xb8, 0x3b, 0x67, 0xfb, 0x13, 0x5c, 0xa4, 0x06, 0x25, 0xc8, 0x7d,
-	0xc9, 0xc0, 0xb5, 0x29, 0x9f, 0x50, 0x89, 0xb6, 0x5c, 0x8c, 0x8b, 0x22, 0x30, 0xff, 0x5e, 0xa4,
-	0xf9, 0x78, 0x35, 0xda, 0x87, 0xef, 0x18, 0x58, 0x8f, 0x7e, 0xb0, 0xa0, 0x1d, 0xda, 0x91, 0x9b,
-	0x33, 0xc7, 0xad, 0xc0, 0x1b, 0x3e, 0xd2, 0x9b, 0x2b, 0x74, 0x69, 0x97, 0x6a, 0x70, 0x6d, 0xaa,
-	0x49, 0xc2, 0xd3, 0x0e, 0x19, 0x26, 0x7d, 0x6b, 0x5c, 0x84, 0xb5, 0xcc, 0x04, 0xdc, 0xb7, 0x0c,
-	0xac, 0x45, 0x1d, 0x15, 0x68, 0x8b, 0x8e, 0x6f, 0x66, 0x8b, 0xf8, 0xf6, 0x3e, 0x88, 0x8c, 0x6e,
-	0xa6, 0x26, 0xed, 0xcc, 0x37, 0x0c, 0x14, 0x22, 0x9e, 0x09, 0xe8, 0x6d, 0xda, 0x97, 0xeb, 0xf1,
-	0x4f, 0x8b, 0xc0, 0x95, 0xfd, 0x48, 0x57, 0x66, 0x29, 0xd2, 0x9e, 0xfc, 0xc4, 0xc0, 0xc6, 0x95,
-	0x47, 0x1d, 0xb9, 0x9f, 0xc2, 0x7e, 0xcd, 0xdf, 0xf8, 0xbe, 0x97, 0xc7, 0x91, 0x5e, 0xce, 0x0f,
-	0x43, 0xfb, 0x2c, 0x02, 0x9a, 0x7e, 0xce, 0xa1, 0xb7, 0x68, 0x1f, 0x67, 0x9c, 0x22, 0xfe, 0x8c,
-	0xdc, 0x86, 0xf5, 0xe8, 0xe7, 0x14, 0xaa, 0x00, 0x6b, 0xe1, 0xcf, 0x86, 0xba, 0x85, 0xb5, 0xe0,
-	0x69, 0x16, 0x37, 0xee, 0x52, 0x08, 0xd2, 0x6a, 0xa0, 0xe6, 0x33, 0x0e, 0x1e, 0x00, 0xea, 0x98,
-	0xfd, 0x09, 0xa5, 0xa7, 0x6b, 0xfe, 0x5a, 0xf1, 0xd6, 0x8a, 0xfb, 0x0f, 0xb6, 0xf6, 0x92, 0xfb,
-	0xb3, 0xf3, 0x57, 0x00, 0x00, 0x00, 0xff, 0xff, 0x47, 0xe0, 0x85, 0xa8, 0x7a, 0x13, 0x00, 0x00,
+	// 1510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0xcd, 0x6f, 0xdb, 0xc6,
+	0x12, 0x37, 0x25, 0xcb, 0x96, 0x46, 0xb2, 0xcd, 0xac, 0xfd, 0xfc, 0xf8, 0xf2, 0x12, 0x20, 0x66,
+	0x3e, 0x9e, 0x13, 0x3c, 0xa8, 0xf5, 0x07, 0x1c, 0xa3, 0x1f, 0x2e, 0x68, 0x8a, 0xb6, 0x04, 0x58,
+	0xb2, 0x42, 0xd1, 0x8a, 0x9b, 0x16, 0x60, 0x28, 0x71, 0xad, 0xb2, 0x96, 0x48, 0x95, 0xa4, 0x8c,
+	0xa4, 0x97, 0xa2, 0xbd, 0x14, 0xe8, 0xa9, 0x3d, 0xf5, 0xde, 0x3f, 0xa0, 0x40, 0x4f, 0x45, 0x81,
+	0xde, 0x7a, 0xee, 0xdf, 0xd1, 0xfe, 0x19, 0xc5, 0xf2, 0x43, 0xe2, 0x4a, 0xa4, 0xac, 0x34, 0x6d,
+	0x0f, 0x86, 0x66, 0x87, 0x33, 0xbf, 0x99, 0x9d, 0x8f, 0xdd, 0x59, 0xc3, 0x8a, 0xab, 0xd9, 0x1d,
+	0xec, 0x1a, 0x66, 0xa7, 0xd8, 0xb7, 0x2d, 0xd7, 0x42, 0xcb, 0x9a, 0xa9, 0xdb, 0x96, 0xa1, 0x17,
+	0x5b, 0x03, 0x53, 0xef, 0x62, 0xfe, 0xf7, 0x34, 0xb0, 0x4d, 0xcd, 0x36, 0x34, 0xd3, 0x55, 0x42,
+	0x51, 0xf4, 0x14, 0xfe, 0xe5, 0xe8, 0x97, 0xea, 0x15, 0xb6, 0x1d, 0xc3, 0x32, 0xd5, 0x21, 0x06,
+	0xc7, 0xdc, 0x61, 0x36, 0xf3, 0xdb, 0x77, 0x8b, 0x34, 0x48, 0xb1, 0xa1, 0x5f, 0x36, 0x7d, 0xd9,
+	0x21, 0x86, 0xbc, 0xea, 0x4c, 0x32, 0x91, 0x00, 0x4b, 0x5a, 0xcb, 0x88, 0x00, 0xa6, 0x3c, 0xc0,
+	0x5b, 0xe3, 0x80, 0x42, 0xcb, 0x18, 0x21, 0x15, 0xb4, 0xc8, 0x0a, 0x3d, 0x07, 0xce, 0x69, 0xdb,
+	0x18, 0x9b, 0xaa, 0x8e, 0x4d, 0xc7, 0x70, 0x5f, 0x46, 0xd0, 0xd2, 0x1e, 0xda, 0x83, 0x09, 0xf7,
+	0x3c, 0xf9, 0x92, 0x2f, 0x3e, 0xc2, 0x5d, 0x77, 0x62, 0xf9, 0xe8, 0x09, 0xac, 0xf6, 0x06, 0x5d,
+	0xd7, 0x50, 0x69, 0x57, 0xe7, 0x3d, 0xf0, 0x8d, 0x71, 0xf0, 0x2a, 0x11, 0xa5, 0xfc, 0xbd, 0xd1,
+	0x1b, 0x67, 0xa1, 0x2f, 0x18, 0xb8, 0xe7, 0xe2, 0x17, 0xee, 0xc0, 0xc6, 0x6a, 0xdb, 0xea, 0xf5,
+	0x6d, 0xec, 0x78, 0x91, 0xbd, 0xb0, 0xec, 0x9e, 0xe6, 0x46, 0x8c, 0x64, 0x3c, 0x23, 0x5b, 0xe3,
+	0x46, 0x14, 0x5f, 0x57, 0x1c, 0xa9, 0x1e, 0x79, 0x9a, 0x23, 0xa3, 0x1b, 0xee, 0x75, 0x22, 0xfc,
+	0x6f, 0x19, 0x28, 0x08, 0xfd, 0xcb, 0x29, 0xd9, 0x60, 0x5e, 0x39, 0x1b, 0xcf, 0x60, 0xbd, 0x63,
+	0x6b, 0xfd, 0x8f, 0x8c, 0xb6, 0xa3, 0x6a, 0xfd, 0xc9, 0xcc, 0xde, 0x1b, 0xc7, 0x3a, 0x0e, 0xa4,
+	0x85, 0x7e, 0x04, 0x73, 0xad, 0x13, 0xc3, 0x45, 0x75, 0x40, 0x5d, 0xcd, 0xec, 0x0c, 0xb4, 0x0e,
+	0x9e, 0xc8, 0xf1, 0x44, 0x1a, 0x4e, 0x02, 0xc9, 0x48, 0x1a, 0xba, 0xe3, 0xac, 0xa9, 0xb5, 0x33,
+	0xff, 0x97, 0xd4, 0x4e, 0x62, 0xe7, 0x64, 0x5e, 0xb3, 0x73, 0x66, 0xae, 0xa0, 0x85, 0xbf, 0xaf,
+	0x82, 0x92, 0x3a, 0x63, 0xf1, 0x35, 0x3a, 0xa3, 0x01, 0xab, 0x8e, 0x66, 0x1a, 0xae, 0xf1, 0x29,
+	0xb6, 0x23, 0x90, 0x59, 0x0f, 0x92, 0x9f, 0x08, 0x57, 0x28, 0x3a, 0xc2, 0x44, 0xce, 0x04, 0x8f,
+	0xff, 0x3e, 0x05, 0x2b, 0x55, 0x4b, 0x1f, 0x74, 0xf1, 0x3f, 0x70, 0xa6, 0x3d, 0x07, 0x4e, 0xc7,
+	0x57, 0x46, 0x1b, 0xab, 0x17, 0x58, 0xf3, 0xf2, 0x13, 0x6d, 0x82, 0x74, 0x5c, 0x51, 0x95, 0x3c,
+	0xf9, 0x23, 0x5f, 0x3c, 0x52, 0x54, 0x7a, 0x2c, 0x9f, 0x58, 0x18, 0x38, 0xd8, 0x56, 0xdb, 0xd6,
+	0xc0, 0x74, 0x6d, 0x03, 0x3b, 0xd7, 0x1f, 0x79, 0x67, 0x0e, 0xb6, 0xc5, 0x50, 0x3c, 0x62, 0x61,
+	0x10, 0xcb, 0xe7, 0x9f, 0xc2, 0x7a, 0xbc, 0x06, 0xba, 0x0b, 0x4b, 0xbe, 0xd9, 0x97, 0x6a, 0xdb,
+	0xd2, 0xb1, 0xc3, 0x31, 0x77, 0xd2, 0x9b, 0x39, 0xb9, 0x10, 0x30, 0x45, 0xc2, 0x43, 0x1c, 0x2c,
+	0xe2, 0x17, 0xed, 0xee, 0x40, 0xc7, 0x5e, 0xdb, 0x67, 0xe5, 0x70, 0xc9, 0x7f, 0x9b, 0x82, 0x25,
+	0xaa, 0x85, 0xd0, 0x13, 0x58, 0x0a, 0x9b, 0x4f, 0xeb, 0x1a, 0x9a, 0xe3, 0xc5, 0x7f, 0x79, 0xfb,
+	0xd1, 0xd4, 0xc6, 0x2b, 0x06, 0xbf, 0x02, 0xd1, 0x28, 0xcf, 0xc9, 0x05, 0x3d, 0xb2, 0x46, 0x1b,
+	0x90, 0x0f, 0x21, 0xf5, 0xbe, 0xe1, 0xb9, 0x90, 0x29, 0xcf, 0xc9, 0x10, 0x30, 0x4b, 0x7d, 0x83,
+	0xff, 0x0c, 0x0a, 0x51, 0x08, 0xf4, 0x6f, 0x58, 0x2d, 0x49, 0xb5, 0x46, 0x45, 0x79, 0x5f, 0x3d,
+	0xab, 0x35, 0xea, 0x92, 0x58, 0x39, 0xaa, 0x48, 0x25, 0x76, 0x0e, 0xe5, 0x20, 0x53, 0x3b, 0x2d,
+	0xd5, 0x2b, 0x2c, 0x83, 0xb2, 0x30, 0x7f, 0x42, 0xa8, 0x14, 0xa1, 0xaa, 0x84, 0x4a, 0x93, 0xcf,
+	0x4a, 0x93, 0x90, 0xf3, 0x84, 0x59, 0x26, 0x54, 0x86, 0x30, 0xcf, 0x3d, 0x72, 0x01, 0x01, 0x2c,
+	0x9c, 0xfb, 0xf4, 0x22, 0xca, 0xc3, 0xe2, 0x79, 0xb0, 0xc8, 0x1e, 0xae, 0x8c, 0xb6, 0x6d, 0x99,
+	0xd8, 0xba, 0xe0, 0x79, 0x80, 0x8a, 0xe9, 0xee, 0x6c, 0x37, 0xb5, 0xee, 0x00, 0xa3, 0x35, 0xc8,
+	0x5c, 0x11, 0xc2, 0x8b, 0x46, 0x46, 0xf6, 0x17, 0xfc, 0x5b, 0x00, 0xa3, 0x32, 0x44, 0xff, 0x87,
+	0x74, 0xcf, 0x30, 0x83, 0x7a, 0xbd, 0x39, 0x1e, 0xaf, 0x11, 0x98, 0x4c, 0xc4, 0xf8, 0x9f, 0x18,
+	0xc8, 0x47, 0x0e, 0x5b, 0x54, 0x83, 0xd5, 0x9e, 0x61, 0xaa, 0x56, 0x1f, 0x9b, 0x6a, 0xa7, 0x1b,
+	0xf6, 0x41, 0x80, 0x76, 0x7b, 0x1c, 0xed, 0xb4, 0x8f, 0xcd, 0xe3, 0x6e, 0x60, 0xb9, 0x3c, 0x27,
+	0xb3, 0x3d, 0xc3, 0xa4, 0x78, 0xa8, 0x0a, 0x88, 0xe0, 0x5d, 0x0d, 0xba, 0x97, 0x9a, 0x39, 0x84,
+	0x4b, 0xc5, 0xc3, 0x35, 0x3d, 0x29, 0x1a, 0x8e, 0xe2, 0x1d, 0xe6, 0x21, 0x47, 0xee, 0x0f, 0x3f,
+	0x36, 0x6f, 0xc3, 0x12, 0xf5, 0x95, 0x84, 0xa7, 0xa7, 0x7d, 0x6c, 0xd9, 0x61, 0x78, 0xbc, 0x85,
+	0xc7, 0x35, 0x4c, 0xcb, 0xf6, 0x33, 0x2e, 0xfb, 0x0b, 0xa2, 0x4c, 0x7b, 0xfa, 0x2a, 0xca, 0x3f,
+	0xa6, 0x80, 0x4b, 0x3a, 0x2a, 0xd1, 0x87, 0x90, 0x89, 0x96, 0xec, 0xd1, 0xac, 0x67, 0x6c, 0xe2,
+	0x07, 0xaf, 0x16, 0x65, 0x1f, 0x94, 0xff, 0x99, 0x81, 0xdb, 0x53, 0x05, 0xd1, 0x23, 0x78, 0x10,
+	0x29, 0x56, 0x55, 0x91, 0xce, 0x95, 0x33, 0x59, 0x52, 0xc5, 0xd3, 0x6a, 0x5d, 0x96, 0x1a, 0x8d,
+	0xca, 0x69, 0x4d, 0x3d, 0x3a, 0x95, 0xab, 0x82, 0xc2, 0xce, 0xa1, 0x25, 0xc8, 0x49, 0x8a, 0xb8,
+	0xa5, 0xca, 0xc7, 0x87, 0xfb, 0x2c, 0x83, 0x0a, 0x90, 0xad, 0x0b, 0x27, 0x92, 0xa2, 0x48, 0x25,
+	0x36, 0x45, 0x56, 0x4a, 0x59, 0x96, 0x24, 0xb5, 0x24, 0xb2, 0x69, 0xb4, 0x08, 0x69, 0x41, 0x11,
+	0xfd, 0x8a, 0x3e, 0x21, 0x54, 0x86, 0x50, 0xa5, 0x73, 0x65, 0x8b, 0x5d, 0x20, 0x54, 0x63, 0x47,
+	0x11, 0xd9, 0x45, 0x52, 0xe5, 0xf5, 0xa6, 0xac, 0x88, 0x6c, 0x96, 0x30, 0x85, 0x86, 0x22, 0xb2,
+	0x39, 0x42, 0x49, 0x8a, 0xb8, 0xcd, 0x02, 0xff, 0x2b, 0x03, 0x69, 0xa1, 0x65, 0xa0, 0x6d, 0x3a,
+	0x48, 0x71, 0xc3, 0x04, 0xf9, 0xa3, 0xb6, 0xfe, 0x0d, 0x03, 0xd9, 0x90, 0x87, 0xee, 0xc0, 0xad,
+	0xe8, 0x2e, 0xc5, 0xfa, 0x99, 0x2a, 0xc8, 0x62, 0xb9, 0xa2, 0x48, 0x22, 0xd9, 0x2e, 0x3b, 0x47,
+	0x1a, 0x4b, 0x90, 0xab, 0x92, 0x70, 0x48, 0xba, 0x74, 0x05, 0xf2, 0xc1, 0x42, 0x6d, 0x3e, 0x16,
+	0xd8, 0x14, 0xd9, 0xb9, 0x20, 0x57, 0xf7, 0x76, 0xd5, 0xe6, 0xbe, 0xe0, 0xef, 0xee, 0x7c, 0x7f,
+	0x8f, 0x9d, 0xf7, 0x5a, 0x73, 0x7f, 0x4f, 0xdd, 0xdb, 0xf5, 0xf7, 0x57, 0xad, 0xd4, 0x1b, 0x7e,
+	0xc3, 0x12, 0x6a, 0x6f, 0xd7, 0x6f, 0x58, 0xb9, 0xd2, 0x10, 0x9b, 0x7b, 0xbb, 0x6c, 0x96, 0xdf,
+	0x82, 0x6c, 0x78, 0x81, 0xa1, 0xfb, 0x90, 0xd6, 0x5a, 0x86, 0x77, 0xf4, 0xe5, 0xb7, 0x57, 0x63,
+	0x76, 0x24, 0x93, 0xef, 0xfc, 0x15, 0xe4, 0x86, 0x17, 0x14, 0x3a, 0xa0, 0xe3, 0xb0, 0x99, 0x78,
+	0x95, 0x8d, 0x28, 0x2a, 0x26, 0x0f, 0x61, 0x99, 0xfe, 0x40, 0x9c, 0xae, 0x9d, 0xd6, 0x24, 0x3f,
+	0xb9, 0xe5, 0xa7, 0x42, 0xa9, 0x44, 0xb2, 0xce, 0x32, 0xfc, 0x07, 0xb0, 0x44, 0xdd, 0x28, 0x68,
+	0x03, 0x0a, 0xe1, 0x5d, 0x64, 0x6a, 0x3d, 0xff, 0x50, 0xc9, 0xc9, 0xf9, 0x80, 0x57, 0xd3, 0x7a,
+	0x18, 0xfd, 0x0f, 0x56, 0x42, 0x91, 0x68, 0xef, 0x66, 0xe4, 0xe5, 0x80, 0x1d, 0x74, 0x0f, 0xff,
+	0x4b, 0x0a, 0x38, 0xc1, 0x71, 0xb0, 0xeb, 0x94, 0x0c, 0x1b, 0xb7, 0x5d, 0xcb, 0x8e, 0x8c, 0x3b,
+	0xc5, 0x30, 0x30, 0xd7, 0xcf, 0x8d, 0x44, 0x10, 0x1d, 0x43, 0x21, 0x3a, 0x2e, 0xbe, 0xd2, 0x90,
+	0x98, 0x8f, 0x0c, 0x89, 0xc8, 0x82, 0x9b, 0xc9, 0xd3, 0x10, 0x97, 0xfe, 0xb3, 0x33, 0x10, 0x97,
+	0x34, 0x03, 0xa1, 0x77, 0x21, 0x1b, 0xce, 0x93, 0xdc, 0xfc, 0xac, 0x23, 0xe8, 0x50, 0x85, 0xff,
+	0x2e, 0x05, 0x5c, 0x4d, 0x73, 0x8d, 0x2b, 0x1c, 0x13, 0xc5, 0xfb, 0xd1, 0x28, 0x26, 0x96, 0x17,
+	0x3a, 0x88, 0x0d, 0xde, 0x7f, 0xa7, 0x04, 0x8f, 0x8e, 0xd9, 0xc5, 0x0c, 0x31, 0xdb, 0x9c, 0x35,
+	0x66, 0x53, 0x42, 0xf5, 0x18, 0x72, 0xc3, 0x99, 0x2c, 0x88, 0xd5, 0x7f, 0x12, 0xab, 0x5f, 0x1e,
+	0xc9, 0xf2, 0x0a, 0x20, 0xa1, 0x8f, 0x5f, 0x54, 0x7a, 0xd4, 0xd0, 0x7e, 0x00, 0xb9, 0xe1, 0xd0,
+	0xc9, 0x31, 0xf1, 0xa1, 0x9f, 0x1c, 0x35, 0xb3, 0xe1, 0xa8, 0xc9, 0xdb, 0x50, 0x88, 0x7e, 0x41,
+	0x0f, 0x47, 0x57, 0x6d, 0x62, 0x3b, 0xfb, 0x12, 0xe8, 0x31, 0x14, 0xb4, 0xae, 0x8b, 0x6d, 0xd3,
+	0xcb, 0x9c, 0xc3, 0xa5, 0x92, 0x35, 0x28, 0x41, 0xfe, 0x73, 0x06, 0x6e, 0x4c, 0xf8, 0x84, 0x8a,
+	0xb4, 0x65, 0x2e, 0x69, 0x17, 0xa1, 0xf9, 0x77, 0x62, 0xcd, 0x27, 0xab, 0xd1, 0x3e, 0x7c, 0xcd,
+	0xc0, 0x7a, 0xfc, 0xeb, 0x05, 0xed, 0xd0, 0x8e, 0xdc, 0x9e, 0x3a, 0x7b, 0x85, 0xde, 0x08, 0xb1,
+	0xde, 0x5c, 0xa3, 0x4b, 0xbb, 0x54, 0x85, 0x1b, 0x13, 0x4d, 0x12, 0x1d, 0x7d, 0xc8, 0x64, 0x19,
+	0x58, 0xe3, 0x63, 0xac, 0xe5, 0xc6, 0xe0, 0xbe, 0x62, 0x60, 0x2d, 0xee, 0xa8, 0x40, 0x5b, 0xf4,
+	0xfe, 0xa6, 0xb6, 0x48, 0x60, 0xef, 0xbd, 0xd8, 0xdd, 0x4d, 0xd5, 0xa4, 0x9d, 0xf9, 0x92, 0x81,
+	0xd5, 0x98, 0x37, 0x03, 0x7a, 0x93, 0xf6, 0xe5, 0x66, 0xf2, 0x3b, 0x23, 0x74, 0xe5, 0x20, 0xd6,
+	0x95, 0x69, 0x8a, 0xb4, 0x27, 0x3f, 0x30, 0xb0, 0x71, 0xed, 0x51, 0x47, 0xee, 0xa7, 0xa8, 0x5f,
+	0xb3, 0x37, 0x7e, 0xe0, 0xe5, 0x49, 0xac, 0x97, 0xb3, 0xc3, 0xd0, 0x3e, 0x4b, 0x80, 0x26, 0xdf,
+	0x76, 0xe8, 0x0d, 0xda, 0xc7, 0x29, 0xa7, 0x48, 0x30, 0x30, 0xb7, 0x60, 0x3d, 0xfe, 0x6d, 0x85,
+	0xca, 0xc0, 0xda, 0xf8, 0x93, 0x81, 0x61, 0x63, 0x3d, 0x7c, 0xa7, 0x25, 0xcd, 0xbe, 0x14, 0x82,
+	0xbc, 0x12, 0xaa, 0x05, 0x8c, 0xc3, 0x47, 0x80, 0xda, 0x56, 0x6f, 0x4c, 0xe9, 0xd9, 0x5a, 0xb0,
+	0x56, 0xfd, 0xb5, 0xea, 0xfd, 0xb7, 0xad, 0xb5, 0xe0, 0xfd, 0xec, 0xfc, 0x31, 0x00, 0xe1, 0xe0,
+	0x96, 0x3f, 0x87, 0x13, 0x00, 0x00,
 }
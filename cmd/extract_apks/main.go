@@ -37,9 +37,14 @@ type TargetConfig struct {
 	sdkVersion int32
 	screenDpi  map[android_bundle_proto.ScreenDensity_DensityAlias]bool
 	// Map holding <ABI alias>:<its sequence number in the flag> info.
-	abis             map[android_bundle_proto.Abi_AbiAlias]int
-	allowPrereleased bool
-	stem             string
+	abis map[android_bundle_proto.Abi_AbiAlias]int
+	// Languages of the device locales. All of the languages match if allLanguages is true.
+	languages    map[string]bool
+	allLanguages bool
+	// Map holding <texture compression format alias>:<its sequence number in the flag> info.
+	textureCompressionFormats map[android_bundle_proto.TextureCompressionFormat_TextureCompressionFormatAlias]int
+	allowPrereleased          bool
+	stem                      string
 }
 
 // An APK set is a zip archive. An entry 'toc.pb' describes its contents.
@@ -145,6 +150,7 @@ func (m apkTargetingMatcher) matches(config TargetConfig) bool {
 			languageTargetingMatcher{m.LanguageTargeting}.matches(config) &&
 			screenDensityTargetingMatcher{m.ScreenDensityTargeting}.matches(config) &&
 			sdkVersionTargetingMatcher{m.SdkVersionTargeting}.matches(config) &&
+			textureCompressionFormatTargetingMatcher{m.TextureCompressionFormatTargeting}.matches(config) &&
 			multiAbiTargetingMatcher{m.MultiAbiTargeting}.matches(config))
 }
 
@@ -152,12 +158,46 @@ type languageTargetingMatcher struct {
 	*android_bundle_proto.LanguageTargeting
 }
 
-func (m languageTargetingMatcher) matches(_ TargetConfig) bool {
-	if m.LanguageTargeting == nil {
+// Like bundletool, select the splits for any of the device languages, and the fallback split that
+// has no values if none of its alternatives is a device language.
+func (m languageTargetingMatcher) matches(config TargetConfig) bool {
+	if m.LanguageTargeting == nil || config.allLanguages {
 		return true
 	}
-	log.Fatal("language based entry selection is not implemented")
-	return false
+	for _, v := range m.GetValue() {
+		if config.languages[normalizeLanguage(v)] {
+			return true
+		}
+	}
+	if len(m.GetValue()) > 0 {
+		return false
+	}
+	for _, a := range m.GetAlternatives() {
+		if config.languages[normalizeLanguage(a)] {
+			return false
+		}
+	}
+	return true
+}
+
+// Obsolete ISO-639 language codes that are still used by Java and Android, and their current codes.
+var obsoleteLanguageCodes = map[string]string{
+	"iw": "he",
+	"in": "id",
+	"ji": "yi",
+}
+
+// normalizeLanguage returns the ISO-639 language code of a locale, such as "en" for "en-US",
+// "en_US" or "en-rUS".
+func normalizeLanguage(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "-_"); i != -1 {
+		language = language[:i]
+	}
+	if current, ok := obsoleteLanguageCodes[language]; ok {
+		return current
+	}
+	return language
 }
 
 type moduleMetadataMatcher struct {
@@ -280,12 +320,44 @@ type textureCompressionFormatTargetingMatcher struct {
 	*android_bundle_proto.TextureCompressionFormatTargeting
 }
 
-func (m textureCompressionFormatTargetingMatcher) matches(_ TargetConfig) bool {
+func (m textureCompressionFormatTargetingMatcher) matches(config TargetConfig) bool {
 	if m.TextureCompressionFormatTargeting == nil {
 		return true
 	}
-	log.Fatal("texture based entry selection is not implemented")
-	return false
+	if _, ok := config.textureCompressionFormats[android_bundle_proto.TextureCompressionFormat_UNSPECIFIED_TEXTURE_COMPRESSION_FORMAT]; ok {
+		return true
+	}
+	// The fallback entry without values is selected if the device supports none of the alternatives.
+	if len(m.GetValue()) == 0 {
+		for _, a := range m.GetAlternatives() {
+			if _, ok := config.textureCompressionFormats[a.Alias]; ok {
+				return false
+			}
+		}
+		return true
+	}
+	// Find the one that appears first in the texture compression formats flag.
+	formatIdx := math.MaxInt32
+	for _, v := range m.GetValue() {
+		if i, ok := config.textureCompressionFormats[v.Alias]; ok {
+			if i < formatIdx {
+				formatIdx = i
+			}
+		}
+	}
+	if formatIdx == math.MaxInt32 {
+		return false
+	}
+	// See if any alternatives appear before the above one.
+	for _, a := range m.GetAlternatives() {
+		if i, ok := config.textureCompressionFormats[a.Alias]; ok {
+			if i < formatIdx {
+				// There is a better alternative. Skip this one.
+				return false
+			}
+		}
+	}
+	return true
 }
 
 type userCountriesTargetingMatcher struct {
@@ -435,8 +507,11 @@ func (apkSet *ApkSet) extractAndCopySingle(selected SelectionResult, outFile *os
 var (
 	outputFile   = flag.String("o", "", "output file containing extracted entries")
 	targetConfig = TargetConfig{
-		screenDpi: map[android_bundle_proto.ScreenDensity_DensityAlias]bool{},
-		abis:      map[android_bundle_proto.Abi_AbiAlias]int{},
+		screenDpi:                 map[android_bundle_proto.ScreenDensity_DensityAlias]bool{},
+		abis:                      map[android_bundle_proto.Abi_AbiAlias]int{},
+		languages:                 map[string]bool{},
+		allLanguages:              true,
+		textureCompressionFormats: map[android_bundle_proto.TextureCompressionFormat_TextureCompressionFormatAlias]int{},
 	}
 	extractSingle = flag.Bool("extract-single", false,
 		"extract a single target and output it uncompressed. only available for standalone apks and apexes.")
@@ -492,10 +567,62 @@ func (s screenDensityFlagValue) Set(densityList string) error {
 	return nil
 }
 
+// Parse locale values
+type localesFlagValue struct {
+	targetConfig *TargetConfig
+}
+
+func (l localesFlagValue) String() string {
+	return "all"
+}
+
+func (l localesFlagValue) Set(localeList string) error {
+	if localeList == "all" {
+		targetConfig.allLanguages = true
+		return nil
+	}
+	targetConfig.allLanguages = false
+	for _, locale := range strings.Split(localeList, ",") {
+		if locale == "" {
+			return fmt.Errorf("bad locale value: %q", localeList)
+		}
+		targetConfig.languages[normalizeLanguage(locale)] = true
+	}
+	return nil
+}
+
+// Parse texture compression format values
+type textureCompressionFormatsFlagValue struct {
+	targetConfig *TargetConfig
+}
+
+func (t textureCompressionFormatsFlagValue) String() string {
+	return "none"
+}
+
+func (t textureCompressionFormatsFlagValue) Set(formatList string) error {
+	if formatList == "none" {
+		return nil
+	}
+	if formatList == "all" {
+		targetConfig.textureCompressionFormats[android_bundle_proto.TextureCompressionFormat_UNSPECIFIED_TEXTURE_COMPRESSION_FORMAT] = 0
+		return nil
+	}
+	for i, format := range strings.Split(formatList, ",") {
+		v, ok := android_bundle_proto.TextureCompressionFormat_TextureCompressionFormatAlias_value[format]
+		if !ok {
+			return fmt.Errorf("bad texture compression format value: %q", format)
+		}
+		targetConfig.textureCompressionFormats[android_bundle_proto.TextureCompressionFormat_TextureCompressionFormatAlias(v)] = i
+	}
+	return nil
+}
+
 func processArgs() {
 	flag.Usage = func() {
 		fmt.Fprintln(os.Stderr, `usage: extract_apks -o <output-file> -sdk-version value -abis value `+
-			`-screen-densities value {-stem value | -extract-single} [-allow-prereleased] `+
+			`-screen-densities value [-locales value] [-texture-compression-formats value] `+
+			`{-stem value | -extract-single} [-allow-prereleased] `+
 			`[-apkcerts <apkcerts output file> -partition <partition>] <APK set>`)
 		flag.PrintDefaults()
 		os.Exit(2)
@@ -505,6 +632,12 @@ func processArgs() {
 		"comma-separated ABIs list of ARMEABI ARMEABI_V7A ARM64_V8A X86 X86_64 MIPS MIPS64")
 	flag.Var(screenDensityFlagValue{&targetConfig}, "screen-densities",
 		"'all' or comma-separated list of screen density names (NODPI LDPI MDPI TVDPI HDPI XHDPI XXHDPI XXXHDPI)")
+	flag.Var(localesFlagValue{&targetConfig}, "locales",
+		"'all' or comma-separated list of device locales (e.g. en-US,fr), only their languages are used")
+	flag.Var(textureCompressionFormatsFlagValue{&targetConfig}, "texture-compression-formats",
+		"'none', 'all' or comma-separated list of supported texture compression formats in order of preference "+
+			"(ETC1_RGB8 PALETTED THREE_DC ATC LATC DXT1 S3TC PVRTC ASTC ETC2). "+
+			"With 'none' only the fallback textures are selected")
 	flag.BoolVar(&targetConfig.allowPrereleased, "allow-prereleased", false,
 		"allow prereleased")
 	flag.StringVar(&targetConfig.stem, "stem", "", "output entries base name in the output zip file")
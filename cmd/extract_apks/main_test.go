@@ -250,6 +250,150 @@ variant {
 				},
 			},
 		},
+		{
+			protoText: `
+variant {
+  targeting {
+    sdk_version_targeting {
+      value { min { value: 29 } } } }
+  apk_set {
+    module_metadata {
+      name: "base" targeting {} delivery_type: INSTALL_TIME }
+    apk_description {
+      targeting {
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-master.apk"
+      split_apk_metadata { is_master_split: true } }
+    apk_description {
+      targeting {
+        language_targeting {
+          value: "de"
+          alternatives: "fr"
+          alternatives: "he" }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-de.apk"
+      split_apk_metadata { split_id: "config.de" } }
+    apk_description {
+      targeting {
+        language_targeting {
+          value: "fr"
+          alternatives: "de"
+          alternatives: "he" }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-fr.apk"
+      split_apk_metadata { split_id: "config.fr" } }
+    apk_description {
+      targeting {
+        language_targeting {
+          value: "he"
+          alternatives: "de"
+          alternatives: "fr" }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-he.apk"
+      split_apk_metadata { split_id: "config.he" } }
+    apk_description {
+      targeting {
+        language_targeting {
+          alternatives: "de"
+          alternatives: "fr"
+          alternatives: "he" }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-other_lang.apk"
+      split_apk_metadata { split_id: "config.other_lang" } }
+    apk_description {
+      targeting {
+        texture_compression_format_targeting {
+          value { alias: ASTC }
+          alternatives { alias: ETC2 } }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-astc.apk"
+      split_apk_metadata { split_id: "config.astc" } }
+    apk_description {
+      targeting {
+        texture_compression_format_targeting {
+          value { alias: ETC2 }
+          alternatives { alias: ASTC } }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-etc2.apk"
+      split_apk_metadata { split_id: "config.etc2" } }
+    apk_description {
+      targeting {
+        texture_compression_format_targeting {
+          alternatives { alias: ASTC }
+          alternatives { alias: ETC2 } }
+        sdk_version_targeting {
+          value { min { value: 21 } } } }
+      path: "splits/base-other_tcf.apk"
+      split_apk_metadata { split_id: "config.other_tcf" } } } }`,
+			configs: []testConfigDesc{
+				{
+					name: "no languages and texture compression formats",
+					targetConfig: TargetConfig{
+						sdkVersion:                29,
+						languages:                 map[string]bool{},
+						textureCompressionFormats: map[bp.TextureCompressionFormat_TextureCompressionFormatAlias]int{},
+					},
+					expected: SelectionResult{
+						"base",
+						[]string{
+							"splits/base-master.apk",
+							"splits/base-other_lang.apk",
+							"splits/base-other_tcf.apk",
+						},
+					},
+				},
+				{
+					name: "some languages and texture compression formats",
+					targetConfig: TargetConfig{
+						sdkVersion: 29,
+						languages:  map[string]bool{"fr": true, "he": true, "en": true},
+						textureCompressionFormats: map[bp.TextureCompressionFormat_TextureCompressionFormatAlias]int{
+							bp.TextureCompressionFormat_ETC2: 0,
+							bp.TextureCompressionFormat_ASTC: 1,
+						},
+					},
+					expected: SelectionResult{
+						"base",
+						[]string{
+							"splits/base-master.apk",
+							"splits/base-fr.apk",
+							"splits/base-he.apk",
+							"splits/base-etc2.apk",
+						},
+					},
+				},
+				{
+					name: "all languages and texture compression formats",
+					targetConfig: TargetConfig{
+						sdkVersion:   29,
+						allLanguages: true,
+						textureCompressionFormats: map[bp.TextureCompressionFormat_TextureCompressionFormatAlias]int{
+							bp.TextureCompressionFormat_UNSPECIFIED_TEXTURE_COMPRESSION_FORMAT: 0,
+						},
+					},
+					expected: SelectionResult{
+						"base",
+						[]string{
+							"splits/base-master.apk",
+							"splits/base-de.apk",
+							"splits/base-fr.apk",
+							"splits/base-he.apk",
+							"splits/base-other_lang.apk",
+							"splits/base-astc.apk",
+							"splits/base-etc2.apk",
+							"splits/base-other_tcf.apk",
+						},
+					},
+				},
+			},
+		},
 	}
 	for _, testCase := range testCases {
 		var toc bp.BuildApksResult
@@ -441,6 +585,22 @@ type testCaseWriteApks struct {
 	expectedApkcerts   []string
 }
 
+func TestNormalizeLanguage(t *testing.T) {
+	for locale, want := range map[string]string{
+		"en":     "en",
+		"en-US":  "en",
+		"en_US":  "en",
+		"fr-rCA": "fr",
+		"iw":     "he",
+		"in-ID":  "id",
+		"FIL":    "fil",
+	} {
+		if got := normalizeLanguage(locale); got != want {
+			t.Errorf("normalizeLanguage(%q): expected %q, got %q", locale, want, got)
+		}
+	}
+}
+
 func TestWriteApks(t *testing.T) {
 	testCases := []testCaseWriteApks{
 		{
@@ -0,0 +1,65 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// fake_rewrapper is a stand-in for the remote execution wrapper that executes the wrapped commands
+// with a local server instead of a remote execution backend. Pointing RBE_WRAPPER at it runs the
+// remote execution rules end to end on a machine without access to the backend, with their inputs
+// and outputs checked like in a remote build:
+//
+//   RBE_WRAPPER=$(pwd)/out/soong/host/linux-x86/bin/fake_rewrapper USE_RBE=true NOSTART_RBE=true m
+//
+// The rules that use the remote_local_fallback exec strategy by default, such as javac, d8 and r8,
+// need their strategy overridden with remote, for example RBE_JAVAC_EXEC_STRATEGY=remote, to fail
+// when they are missing inputs. The blobs of the local server are stored in $OUT_DIR/.fake_rbe.
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"android/soong/remoteexec/executor"
+)
+
+func main() {
+	opts, args, err := executor.ParseArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fake_rewrapper:", err)
+		fmt.Fprintln(os.Stderr, "usage: fake_rewrapper [--flag=value...] -- <command> [<args>...]")
+		os.Exit(2)
+	}
+
+	execRoot := os.Getenv("RBE_exec_root")
+	if execRoot == "" {
+		if execRoot, err = os.Getwd(); err != nil {
+			fmt.Fprintln(os.Stderr, "fake_rewrapper:", err)
+			os.Exit(1)
+		}
+	}
+	outDir := os.Getenv("OUT_DIR")
+	if outDir == "" {
+		outDir = "out"
+	}
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(execRoot, outDir)
+	}
+
+	server := executor.NewLocalServer(filepath.Join(outDir, ".fake_rbe"))
+	code, err := executor.Run(server, opts, args, execRoot, os.Stdout, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fake_rewrapper:", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// metrics_diff compares the soong_metrics files of two builds, or the cuj_metrics.pb files of two
+// runs of the critical user journey tests, and reports the phase times, soong_build counts and
+// action CPU times which regressed. It exits with status 1 if there is any regression.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"android/soong/ui/metrics"
+)
+
+var (
+	verbose = flag.Bool("v", false, "print all the compared metrics, not only those beyond the thresholds")
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: metrics_diff [flags] <base metrics> <metrics>")
+	fmt.Fprintln(os.Stderr, "The metrics files are soong_metrics or cuj_metrics.pb files.")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	thresholds := metrics.DefaultThresholds
+	thresholds.RegisterFlags(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() != 2 {
+		usage()
+	}
+
+	base, err := metrics.ReadMetricsFile(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	current, err := metrics.ReadMetricsFile(flag.Arg(1))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	changes := metrics.DiffCriticalUserJourneys(base, current, thresholds)
+	if len(changes) == 0 {
+		log.Fatalf("%s and %s have no metrics in common", flag.Arg(0), flag.Arg(1))
+	}
+
+	regressions := 0
+	for _, c := range changes {
+		switch {
+		case c.Regression:
+			regressions++
+			fmt.Println("REGRESSION  ", c)
+		case c.Improvement:
+			fmt.Println("improvement ", c)
+		case *verbose:
+			fmt.Println("            ", c)
+		}
+	}
+
+	fmt.Printf("%d metrics compared, %d regressions\n", len(changes), regressions)
+	if regressions > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// tracingSupported is whether --hermetic and --trace-inputs can be used.
+const tracingSupported = false
+
+var errHermeticUnsupported = errors.New("hermetic sandboxes are only supported on Linux")
+
+func runHermetic(command, sandboxDir string, inputs []string) (undeclared []string, err error) {
+	return nil, errHermeticUnsupported
+}
+
+func runTraced(cmd *exec.Cmd) ([]string, error) {
+	return nil, errors.New("tracing commands is only supported on Linux")
+}
+
+func hermeticChild(args []string) {
+	fmt.Fprintln(os.Stderr, "sbox:", errHermeticUnsupported)
+	os.Exit(1)
+}
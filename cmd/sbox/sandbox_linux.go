@@ -0,0 +1,304 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// This file contains the hermetic sandbox of sbox. The command runs in a user and mount namespace
+// whose root only contains the system directories, a private /tmp, the sandbox directory and the
+// declared inputs, mounted at the same paths as outside of the sandbox. Only the sandbox directory
+// and /tmp are writable. The command is traced with ptrace to report every path it tries to access
+// outside of those.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// The system directories that are mounted into the hermetic sandbox.
+var sandboxSystemDirs = []string{"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64", "/etc", "/dev", "/proc", "/sys"}
+
+// The flags of statfs, which aren't in the syscall package.
+const (
+	stNosuid     = 0x2
+	stNodev      = 0x4
+	stNoexec     = 0x8
+	stNoatime    = 0x400
+	stNodiratime = 0x800
+	stRelatime   = 0x1000
+)
+
+// The PATH of the command in the hermetic sandbox, the PATH of the build may point to directories
+// in the source or output tree which aren't mounted into the sandbox.
+const sandboxPath = "/usr/local/bin:/usr/bin:/bin"
+
+// hermeticSpec is passed from sbox to the child that sets up the hermetic sandbox.
+type hermeticSpec struct {
+	// The directory that becomes the root of the sandbox.
+	Root string
+	// The sandbox directory, the only writable directory besides /tmp.
+	SandboxDir string
+	// The working directory of the command.
+	Dir string
+	// The absolute paths that are mounted into the sandbox.
+	Mounts  []string
+	Command string
+}
+
+// runHermetic runs the command with bash in a hermetic sandbox that only contains the system
+// directories, the sandbox directory and the given inputs. It returns the paths that the command
+// tried to access outside of those, and the error of the command.
+func runHermetic(command, sandboxDir string, inputs []string) (undeclared []string, err error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	sandboxDir = absPath(dir, sandboxDir)
+	root, err := ioutil.TempDir(filepath.Dir(sandboxDir), "root")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create sandbox root: %s", err)
+	}
+	defer os.RemoveAll(root)
+
+	spec := hermeticSpec{
+		Root:       root,
+		SandboxDir: sandboxDir,
+		Dir:        dir,
+		Mounts:     []string{sandboxDir},
+		Command:    command,
+	}
+	for _, input := range inputs {
+		spec.Mounts = append(spec.Mounts, absPath(dir, input))
+	}
+	specFile := root + ".json"
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(specFile, data, 0666); err != nil {
+		return nil, err
+	}
+	defer os.Remove(specFile)
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(self, hermeticChildArg, specFile)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Ptrace:     true,
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS,
+		// Keep the user and group of the build in the sandbox so that the outputs are owned by it.
+		UidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}},
+		GidMappings:                []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}},
+		GidMappingsEnableSetgroups: false,
+	}
+
+	accessed, status, err := traceFileAccesses(cmd, root, true)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := append([]string{sandboxDir, "/tmp"}, spec.Mounts...)
+	allowed = append(allowed, sandboxSystemDirs...)
+	for _, path := range accessed {
+		if !isAllowedPath(path, dir, allowed) {
+			undeclared = append(undeclared, path)
+		}
+	}
+	sort.Strings(undeclared)
+	return undeclared, waitStatusError(status)
+}
+
+// hermeticChild is run in the new namespaces with the spec file written by runHermetic. It mounts
+// the sandbox, changes its root to it and executes the command.
+func hermeticChild(args []string) {
+	if err := setupHermeticChild(args); err != nil {
+		fmt.Fprintln(os.Stderr, "sbox: failed to set up hermetic sandbox:", err)
+		os.Exit(1)
+	}
+}
+
+func setupHermeticChild(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected a spec file, got %q", args)
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var spec hermeticSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+
+	// Don't propagate the mounts of the sandbox to the mount namespace of the build.
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("making mounts private: %s", err)
+	}
+
+	for _, dir := range sandboxSystemDirs {
+		if err := mountSystemDir(spec.Root, dir); err != nil {
+			return err
+		}
+	}
+	tmp := filepath.Join(spec.Root, "tmp")
+	if err := os.MkdirAll(tmp, 0777); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", tmp, "tmpfs", 0, ""); err != nil {
+		return fmt.Errorf("mounting /tmp: %s", err)
+	}
+
+	// Mount parent directories before their contents, and skip the paths that are already in a
+	// mounted directory so that the mount points aren't created in the source tree. The sandbox
+	// directory is mounted even if it is in an input directory, as the inputs are read-only.
+	sort.Strings(spec.Mounts)
+	var mountedDirs []string
+	for _, path := range spec.Mounts {
+		writable := path == spec.SandboxDir
+		if inAny(path, mountedDirs) && !writable {
+			continue
+		}
+		isDir, err := bindMount(spec.Root, path, !writable)
+		if err != nil {
+			return err
+		}
+		if isDir {
+			mountedDirs = append(mountedDirs, path)
+		}
+	}
+
+	if err := syscall.Chroot(spec.Root); err != nil {
+		return fmt.Errorf("changing root: %s", err)
+	}
+	if err := os.Chdir(spec.Dir); err != nil {
+		return err
+	}
+
+	// Replace the variables that point to paths outside of the sandbox.
+	env := []string{"PATH=" + sandboxPath, "TMPDIR=/tmp", "PWD=" + spec.Dir}
+	for _, v := range os.Environ() {
+		switch strings.SplitN(v, "=", 2)[0] {
+		case "PATH", "TMPDIR", "PWD", "OLDPWD":
+		default:
+			env = append(env, v)
+		}
+	}
+	return syscall.Exec("/bin/bash", []string{"bash", "-c", spec.Command}, env)
+}
+
+// inAny returns true if the path is one of the directories or in one of them.
+func inAny(path string, dirs []string) bool {
+	for _, dir := range dirs {
+		if path == dir || isAncestor(dir, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// mountSystemDir mounts a system directory into the sandbox, or recreates it if it is a symlink
+// (e.g. /bin -> usr/bin).
+func mountSystemDir(root, dir string) error {
+	fi, err := os.Lstat(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(dir)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, filepath.Join(root, dir))
+	}
+	_, err = bindMount(root, dir, true)
+	return err
+}
+
+// bindMount mounts the file or directory at the same path in the sandbox, read-only if requested.
+// Missing paths are skipped, the command fails the same way it would outside of the sandbox.
+func bindMount(root, path string, readOnly bool) (isDir bool, err error) {
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	target := filepath.Join(root, path)
+	if fi.IsDir() {
+		if err := os.MkdirAll(target, 0777); err != nil {
+			return false, err
+		}
+	} else {
+		if err := os.MkdirAll(filepath.Dir(target), 0777); err != nil {
+			return false, err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0666)
+		if err != nil {
+			return false, err
+		}
+		f.Close()
+	}
+
+	if err := syscall.Mount(path, target, "", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
+		return false, fmt.Errorf("mounting %s: %s", path, err)
+	}
+	if readOnly {
+		if err := remountReadOnly(target); err != nil {
+			return false, fmt.Errorf("mounting %s read-only: %s", path, err)
+		}
+	}
+	return fi.IsDir(), nil
+}
+
+// remountReadOnly makes a bind mount read-only. The bind mount flags are ignored when it is
+// created, so it has to be remounted. The other flags of the mount are kept, as they can't be
+// cleared in a user namespace.
+func remountReadOnly(target string) error {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(target, &st); err != nil {
+		return err
+	}
+	flags := uintptr(syscall.MS_REMOUNT | syscall.MS_BIND | syscall.MS_RDONLY)
+	for _, f := range []struct {
+		statfs int64
+		mount  uintptr
+	}{
+		{stNosuid, syscall.MS_NOSUID},
+		{stNodev, syscall.MS_NODEV},
+		{stNoexec, syscall.MS_NOEXEC},
+		{stNoatime, syscall.MS_NOATIME},
+		{stNodiratime, syscall.MS_NODIRATIME},
+		{stRelatime, syscall.MS_RELATIME},
+	} {
+		if int64(st.Flags)&f.statfs != 0 {
+			flags |= f.mount
+		}
+	}
+	return syscall.Mount("", target, "", flags, "")
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// runHermetic runs the test binary to set up the sandbox.
+	if len(os.Args) > 1 && os.Args[1] == hermeticChildArg {
+		hermeticChild(os.Args[2:])
+	}
+	os.Exit(m.Run())
+}
+
+// skipWithoutUserNamespaces skips the test if unprivileged user namespaces aren't available.
+func skipWithoutUserNamespaces(t *testing.T) {
+	t.Helper()
+	cmd := exec.Command("true")
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags:  syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS,
+		UidMappings: []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}},
+		GidMappings: []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}},
+	}
+	if err := cmd.Run(); err != nil {
+		t.Skipf("user namespaces aren't available: %s", err)
+	}
+}
+
+func TestHermeticUndeclaredInputs(t *testing.T) {
+	skipWithoutUserNamespaces(t)
+
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tempDir, err := ioutil.TempDir("", "sbox_hermetic_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	sandboxDir := filepath.Join(tempDir, "sbox")
+	if err := os.Mkdir(sandboxDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(h bool, c string, i []string) {
+		hermetic, rawCommand, inputs = h, c, i
+	}(hermetic, rawCommand, inputs)
+
+	// Android.bp is declared and sbox.go isn't, both are in the source directory of sbox.
+	hermetic = true
+	rawCommand = "cat Android.bp > " + sandboxDir + "/out && cat sbox.go > /dev/null"
+	inputs = []string{"Android.bp"}
+
+	err = runCommand(sandboxDir, "test")
+	if err == nil {
+		t.Fatal("expected an error for the undeclared input")
+	}
+	expected := "undeclared inputs in hermetic sbox command(test)\n\n" +
+		"the command tried to access 1 paths that aren't inputs or tools:\n" +
+		"  " + filepath.Join(dir, "sbox.go") + "\n" +
+		"add them to the inputs or tools of the rule."
+	if err.Error() != expected {
+		t.Errorf("expected error:\n%s\ngot:\n%s", expected, err.Error())
+	}
+
+	// The declared input was readable and the sandbox directory writable.
+	if out, err := ioutil.ReadFile(filepath.Join(sandboxDir, "out")); err != nil {
+		t.Error(err)
+	} else if len(out) == 0 {
+		t.Error("expected the declared input to be copied to the sandbox directory")
+	}
+}
+
+func TestHermeticReadOnlyInputs(t *testing.T) {
+	skipWithoutUserNamespaces(t)
+
+	tempDir, err := ioutil.TempDir("", "sbox_hermetic_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	sandboxDir := filepath.Join(tempDir, "sbox")
+	if err := os.Mkdir(sandboxDir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	// Android.bp is declared, but can't be modified.
+	_, err = runHermetic("touch Android.bp", sandboxDir, []string{"Android.bp"})
+	if _, ok := err.(exitStatusError); !ok {
+		t.Errorf("expected the command writing to an input to fail, got %v", err)
+	}
+}
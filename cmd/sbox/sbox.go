@@ -16,6 +16,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -25,8 +26,10 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	"android/soong/actioncache"
 	"android/soong/makedeps"
 )
 
@@ -37,8 +40,29 @@ var (
 	keepOutDir    bool
 	depfileOut    string
 	inputHash     string
+	hermetic      bool
+	inputs        stringList
+	inputLists    stringList
+	traceInputs   string
+	traceLabel    string
+	cacheDir      string
 )
 
+// hermeticChildArg is the first argument of the sbox process that sets up the namespaces of a
+// hermetic sandbox, see runHermetic.
+const hermeticChildArg = "--internal-hermetic-child"
+
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
 func init() {
 	flag.StringVar(&sandboxesRoot, "sandbox-path", "",
 		"root of temp directory to put the sandbox into")
@@ -54,6 +78,21 @@ func init() {
 
 	flag.StringVar(&inputHash, "input-hash", "",
 		"This option is ignored. Typical usage is to supply a hash of the list of input names so that the module will be rebuilt if the list (and thus the hash) changes.")
+
+	flag.BoolVar(&hermetic, "hermetic", false,
+		"run the command in a sandbox that only contains the system directories, the sandbox directory and the inputs given with --input and --input-list, and fail if the command accesses any other path")
+	flag.Var(&inputs, "input",
+		"file or directory that is an input of the command in the hermetic sandbox, may be repeated")
+	flag.Var(&inputLists, "input-list",
+		"file containing a whitespace separated list of inputs of the command in the hermetic sandbox, such as an rsp file. The file is an input too. May be repeated")
+
+	flag.StringVar(&traceInputs, "trace-inputs", "",
+		"file path of a JSON report of the files in the source and output trees that the command accessed but that aren't given with --input or --input-list. The command is traced but not restricted")
+	flag.StringVar(&traceLabel, "trace-inputs-label", "",
+		"label of the command in the --trace-inputs report, such as the module that runs it")
+
+	flag.StringVar(&cacheDir, "cache-dir", "",
+		"directory of the local action cache. The outputs are restored from the cache if an action with the same command, outputs and inputs given with --input and --input-list was run before, and stored otherwise")
 }
 
 func usageViolation(violation string) {
@@ -62,7 +101,7 @@ func usageViolation(violation string) {
 	}
 
 	fmt.Fprintf(os.Stderr,
-		"Usage: sbox -c <commandToRun> --sandbox-path <sandboxPath> --output-root <outputRoot> [--depfile-out depFile] [--input-hash hash] <outputFile> [<outputFile>...]\n"+
+		"Usage: sbox -c <commandToRun> --sandbox-path <sandboxPath> --output-root <outputRoot> [--depfile-out depFile] [--input-hash hash] [--hermetic|--trace-inputs report [--trace-inputs-label label]] [--cache-dir cacheDir] [--input input]... [--input-list inputList]... <outputFile> [<outputFile>...]\n"+
 			"\n"+
 			"Deletes <outputRoot>,"+
 			"runs <commandToRun>,"+
@@ -74,6 +113,11 @@ func usageViolation(violation string) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == hermeticChildArg {
+		hermeticChild(os.Args[2:])
+		return
+	}
+
 	flag.Usage = func() {
 		usageViolation("")
 	}
@@ -117,6 +161,20 @@ func run() error {
 	if len(outputRoot) == 0 {
 		usageViolation("--output-root <outputRoot> is required and must be non-empty")
 	}
+	if (hermetic || traceInputs != "") && !tracingSupported {
+		usageViolation("--hermetic and --trace-inputs are only supported on linux/amd64")
+	}
+	if cacheDir != "" && traceInputs != "" {
+		// The report wouldn't be written for the actions that are restored from the cache.
+		usageViolation("--cache-dir can't be used with --trace-inputs")
+	}
+	if cacheDir != "" && depfileOut != "" {
+		// The depfile lists inputs that aren't declared, which aren't part of the action cache key.
+		usageViolation("--cache-dir can't be used with --depfile-out")
+	}
+
+	// the command before the sandbox directory is substituted, which is the same in every run
+	originalCommand := rawCommand
 
 	// the contents of the __SBOX_OUT_FILES__ variable
 	outputsVarEntries := flag.Args()
@@ -201,16 +259,35 @@ func run() error {
 
 	commandDescription := rawCommand
 
-	cmd := exec.Command("bash", "-c", rawCommand)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
+	var cache *actioncache.Cache
+	var cacheKey string
+	cacheHit := false
+	if cacheDir != "" {
+		declaredInputs, err := readInputs()
+		if err != nil {
+			return err
+		}
+		var cacheOutputs []string
+		for _, filePath := range allOutputs {
+			cacheOutputs = append(cacheOutputs, filepath.Join(outputRoot, filePath))
+		}
+		cacheKey, err = actioncache.Key(originalCommand, cacheOutputs, declaredInputs)
+		if err != nil {
+			return err
+		}
+		cache = actioncache.New(cacheDir)
+		cacheHit, err = cache.Lookup(cacheKey, tempDir)
+		if err != nil {
+			// The action can still be run if the cache is broken.
+			fmt.Fprintln(os.Stderr, "sbox: failed to look up the action cache:", err)
+		}
+	}
 
-	if exit, ok := err.(*exec.ExitError); ok && !exit.Success() {
-		return fmt.Errorf("sbox command (%s) failed with err %#v\n", commandDescription, err.Error())
-	} else if err != nil {
-		return err
+	if !cacheHit {
+		err = runCommand(tempDir, commandDescription)
+		if err != nil {
+			return err
+		}
 	}
 
 	// validate that all files are created properly
@@ -258,6 +335,13 @@ func run() error {
 		keepOutDir = true
 		return errors.New(errorMessage)
 	}
+	if cache != nil && !cacheHit {
+		err := cache.Store(cacheKey, tempDir, allOutputs)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "sbox: failed to store the outputs in the action cache:", err)
+		}
+	}
+
 	// the created files match the declared files; now move them
 	for _, filePath := range allOutputs {
 		tempPath := filepath.Join(tempDir, filePath)
@@ -307,3 +391,178 @@ func run() error {
 	// TODO(jeffrygaston) if a process creates more output files than it declares, should there be a warning?
 	return nil
 }
+
+// runCommand runs the command, in the hermetic sandbox or traced if requested.
+func runCommand(tempDir, commandDescription string) error {
+	if hermetic {
+		declaredInputs, err := readInputs()
+		if err != nil {
+			return err
+		}
+		undeclared, err := runHermetic(rawCommand, tempDir, declaredInputs)
+		if len(undeclared) > 0 {
+			return undeclaredPathsError(commandDescription, undeclared)
+		}
+		if _, ok := err.(exitStatusError); ok {
+			return fmt.Errorf("sbox command (%s) failed with err %#v\n", commandDescription, err.Error())
+		} else if err != nil {
+			return err
+		}
+	} else if traceInputs != "" {
+		declaredInputs, err := readInputs()
+		if err != nil {
+			return err
+		}
+		cmd := exec.Command("bash", "-c", rawCommand)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		accessed, err := runTraced(cmd)
+
+		if _, ok := err.(exitStatusError); ok {
+			return fmt.Errorf("sbox command (%s) failed with err %#v\n", commandDescription, err.Error())
+		} else if err != nil {
+			return err
+		}
+
+		err = writeTraceReport(accessed, declaredInputs, tempDir)
+		if err != nil {
+			return err
+		}
+	} else {
+		cmd := exec.Command("bash", "-c", rawCommand)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		err := cmd.Run()
+
+		if exit, ok := err.(*exec.ExitError); ok && !exit.Success() {
+			return fmt.Errorf("sbox command (%s) failed with err %#v\n", commandDescription, err.Error())
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exitStatusError is returned by runHermetic when the command fails.
+type exitStatusError struct {
+	status syscall.WaitStatus
+}
+
+func (e exitStatusError) Error() string {
+	if e.status.Signaled() {
+		return "signal: " + e.status.Signal().String()
+	}
+	return fmt.Sprintf("exit status %d", e.status.ExitStatus())
+}
+
+// waitStatusError returns an exitStatusError if the wait status is of a failed command.
+func waitStatusError(status syscall.WaitStatus) error {
+	if !status.Exited() || status.ExitStatus() != 0 {
+		return exitStatusError{status}
+	}
+	return nil
+}
+
+// readInputs returns the inputs of the command in the hermetic sandbox or of the traced command.
+func readInputs() ([]string, error) {
+	declared := append([]string(nil), inputs...)
+	for _, list := range inputLists {
+		data, err := ioutil.ReadFile(list)
+		if err != nil {
+			return nil, err
+		}
+		declared = append(declared, list)
+		declared = append(declared, strings.Fields(string(data))...)
+	}
+	return declared, nil
+}
+
+// isAllowedPath returns true if the path is in one of the allowed directories, or an ancestor of
+// one of them or of the working directory, which are needed to look up paths.
+func isAllowedPath(path, dir string, allowed []string) bool {
+	if path == "/" || isAncestor(path, dir) {
+		return true
+	}
+	for _, a := range allowed {
+		if path == a || isAncestor(a, path) || isAncestor(path, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor returns true if dir is a parent directory of path.
+func isAncestor(dir, path string) bool {
+	return strings.HasPrefix(path, strings.TrimSuffix(dir, "/")+"/")
+}
+
+func absPath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
+
+// traceReport is the --trace-inputs report.
+type traceReport struct {
+	Label            string   `json:"label"`
+	UndeclaredInputs []string `json:"undeclared_inputs"`
+}
+
+// writeTraceReport writes the files in the source and output trees that the command accessed but
+// that aren't inputs to the --trace-inputs report. Directories and paths that don't exist, which the
+// command may have probed for, aren't inputs.
+func writeTraceReport(accessed, declared []string, sandboxDir string) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	excluded := []string{absPath(dir, sandboxDir), absPath(dir, outputRoot)}
+	for _, input := range declared {
+		excluded = append(excluded, absPath(dir, input))
+	}
+
+	report := traceReport{Label: traceLabel, UndeclaredInputs: []string{}}
+	for _, path := range accessed {
+		if !isAncestor(dir, path) || isAllowedPath(path, dir, excluded) {
+			continue
+		}
+		if fi, err := os.Lstat(path); err != nil || fi.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		report.UndeclaredInputs = append(report.UndeclaredInputs, rel)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	// The reports are merged into a list with one report per line.
+	return ioutil.WriteFile(traceInputs, append(data, '\n'), 0666)
+}
+
+// undeclaredPathsError returns the error for a command that accessed paths that aren't in the
+// hermetic sandbox. The error doesn't depend on whether the command failed, as the command may
+// ignore missing files.
+func undeclaredPathsError(command string, undeclared []string) error {
+	const maxPaths = 20
+
+	errorMessage := "undeclared inputs in hermetic sbox command(" + command + ")\n\n"
+	errorMessage += fmt.Sprintf("the command tried to access %v paths that aren't inputs or tools:\n", len(undeclared))
+	for i, path := range undeclared {
+		if i == maxPaths {
+			errorMessage += fmt.Sprintf("  ...%v more\n", len(undeclared)-maxPaths)
+			break
+		}
+		errorMessage += "  " + path + "\n"
+	}
+	errorMessage += "add them to the inputs or tools of the rule."
+	return errors.New(errorMessage)
+}
@@ -0,0 +1,261 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// This file traces the paths that a command and its child processes access with ptrace. The
+// syscall numbers and registers are the ones of x86_64.
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// tracingSupported is whether --hermetic and --trace-inputs can be used.
+const tracingSupported = true
+
+// Syscalls that aren't in the syscall package.
+const (
+	sysExecveat   = 322
+	sysStatx      = 332
+	sysOpenat2    = 437
+	sysFaccessat2 = 439
+)
+
+const (
+	atFdcwd = -0x64
+
+	ptraceOptions = syscall.PTRACE_O_TRACESYSGOOD | syscall.PTRACE_O_TRACEEXEC |
+		syscall.PTRACE_O_TRACEFORK | syscall.PTRACE_O_TRACEVFORK | syscall.PTRACE_O_TRACECLONE |
+		ptraceOExitkill
+	ptraceOExitkill = 0x100000
+
+	// The stop signal of the syscall stops with PTRACE_O_TRACESYSGOOD.
+	syscallStop = syscall.SIGTRAP | 0x80
+)
+
+// pathSyscall is a syscall that accesses a path. Relative paths are relative to the directory of
+// the file descriptor argument, or to the working directory if the syscall doesn't have one.
+type pathSyscall struct {
+	dirfdArg int
+	pathArg  int
+}
+
+// The syscalls that access paths, by number.
+var pathSyscalls = map[uint64]pathSyscall{
+	syscall.SYS_OPEN:       {-1, 0},
+	syscall.SYS_CREAT:      {-1, 0},
+	syscall.SYS_STAT:       {-1, 0},
+	syscall.SYS_LSTAT:      {-1, 0},
+	syscall.SYS_ACCESS:     {-1, 0},
+	syscall.SYS_EXECVE:     {-1, 0},
+	syscall.SYS_CHDIR:      {-1, 0},
+	syscall.SYS_READLINK:   {-1, 0},
+	syscall.SYS_MKDIR:      {-1, 0},
+	syscall.SYS_OPENAT:     {0, 1},
+	syscall.SYS_MKDIRAT:    {0, 1},
+	syscall.SYS_NEWFSTATAT: {0, 1},
+	syscall.SYS_READLINKAT: {0, 1},
+	syscall.SYS_FACCESSAT:  {0, 1},
+	sysExecveat:            {0, 1},
+	sysStatx:               {0, 1},
+	sysOpenat2:             {0, 1},
+	sysFaccessat2:          {0, 1},
+}
+
+type tracer struct {
+	// The root directory of the traced processes, which is removed from the paths that are read
+	// from /proc.
+	root string
+
+	// Whether the accesses are recorded, see traceFileAccesses.
+	recording bool
+
+	// The processes that have stopped after being attached.
+	started map[int]bool
+	// The processes that are stopped in a syscall, between the syscall entry and exit stops.
+	inSyscall map[int]bool
+
+	paths map[string]bool
+}
+
+// traceFileAccesses runs the command with ptrace and returns the absolute paths that the command
+// and its child processes accessed, and the wait status of the command. If afterExec is true only
+// the accesses after the command executes another program are recorded. Paths that are relative
+// to the working directory or a file descriptor are resolved with /proc, with the root directory
+// of the traced processes removed.
+func traceFileAccesses(cmd *exec.Cmd, root string, afterExec bool) (paths []string,
+	status syscall.WaitStatus, err error) {
+
+	// All of the ptrace requests must be made by the thread that started the command.
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Ptrace = true
+	if err := cmd.Start(); err != nil {
+		return nil, status, err
+	}
+	pid := cmd.Process.Pid
+
+	// The command stops with a SIGTRAP once it is executed.
+	if _, err := syscall.Wait4(pid, &status, syscall.WALL, nil); err != nil {
+		return nil, status, err
+	} else if !status.Stopped() {
+		return nil, status, nil
+	}
+	if err := syscall.PtraceSetOptions(pid, ptraceOptions); err != nil {
+		return nil, status, fmt.Errorf("failed to trace the command: %s", err)
+	}
+	if err := syscall.PtraceSyscall(pid, 0); err != nil {
+		return nil, status, fmt.Errorf("failed to trace the command: %s", err)
+	}
+
+	t := &tracer{
+		root:      root,
+		recording: !afterExec,
+		started:   map[int]bool{pid: true},
+		inSyscall: make(map[int]bool),
+		paths:     make(map[string]bool),
+	}
+
+	// Wait until all of the traced processes exited, including the ones that outlived the command.
+	for {
+		var ws syscall.WaitStatus
+		wpid, err := syscall.Wait4(-1, &ws, syscall.WALL, nil)
+		if err == syscall.EINTR {
+			continue
+		} else if err == syscall.ECHILD {
+			break
+		} else if err != nil {
+			return nil, status, err
+		}
+
+		if ws.Exited() || ws.Signaled() {
+			delete(t.inSyscall, wpid)
+			if wpid == pid {
+				status = ws
+			}
+			continue
+		} else if !ws.Stopped() {
+			continue
+		}
+
+		signal := 0
+		switch sig := ws.StopSignal(); {
+		case !t.started[wpid]:
+			// New child processes stop with a SIGSTOP once they are attached.
+			t.started[wpid] = true
+			if sig != syscall.SIGSTOP {
+				signal = int(sig)
+			}
+		case sig == syscallStop:
+			t.inSyscall[wpid] = !t.inSyscall[wpid]
+			if t.inSyscall[wpid] && t.recording {
+				t.syscallEntry(wpid)
+			}
+		case sig == syscall.SIGTRAP && ws.TrapCause() == syscall.PTRACE_EVENT_EXEC:
+			// The exit stop of the execve follows, even if the process that called it had another pid.
+			t.inSyscall[wpid] = true
+			t.recording = true
+		case sig == syscall.SIGTRAP && ws.TrapCause() > 0:
+			// The fork, vfork and clone events.
+		default:
+			signal = int(sig)
+		}
+
+		// The process may have been killed while it was stopped.
+		syscall.PtraceSyscall(wpid, signal)
+	}
+
+	for path := range t.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, status, nil
+}
+
+// runTraced runs the command with ptrace and returns the paths that it accessed.
+func runTraced(cmd *exec.Cmd) ([]string, error) {
+	paths, status, err := traceFileAccesses(cmd, "", false)
+	if err != nil {
+		return nil, err
+	}
+	return paths, waitStatusError(status)
+}
+
+// syscallEntry records the path that a syscall accesses, if any.
+func (t *tracer) syscallEntry(pid int) {
+	var regs syscall.PtraceRegs
+	if err := syscall.PtraceGetRegs(pid, &regs); err != nil {
+		return
+	}
+	s, ok := pathSyscalls[regs.Orig_rax]
+	if !ok {
+		return
+	}
+	args := [...]uint64{regs.Rdi, regs.Rsi, regs.Rdx, regs.R10, regs.R8, regs.R9}
+
+	path := readString(pid, uintptr(args[s.pathArg]))
+	if path == "" {
+		return
+	}
+	if !filepath.IsAbs(path) {
+		dir := fmt.Sprintf("/proc/%d/cwd", pid)
+		if s.dirfdArg >= 0 {
+			if fd := int32(args[s.dirfdArg]); fd != atFdcwd {
+				dir = fmt.Sprintf("/proc/%d/fd/%d", pid, fd)
+			}
+		}
+		base, err := os.Readlink(dir)
+		if err != nil {
+			return
+		}
+		if t.root != "" && base == t.root {
+			base = "/"
+		} else if t.root != "" && strings.HasPrefix(base, t.root+"/") {
+			base = strings.TrimPrefix(base, t.root)
+		}
+		path = filepath.Join(base, path)
+	}
+	t.paths[filepath.Clean(path)] = true
+}
+
+// readString reads a NUL terminated string of up to PATH_MAX bytes from the memory of a traced
+// process.
+func readString(pid int, addr uintptr) string {
+	const maxLen = 4096
+	var buf []byte
+	chunk := make([]byte, 256)
+	for len(buf) < maxLen {
+		n, _ := syscall.PtracePeekData(pid, addr+uintptr(len(buf)), chunk)
+		if i := bytes.IndexByte(chunk[:n], 0); i != -1 {
+			return string(append(buf, chunk[:i]...))
+		}
+		if n < len(chunk) {
+			break
+		}
+		buf = append(buf, chunk...)
+	}
+	return ""
+}
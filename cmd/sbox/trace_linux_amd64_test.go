@@ -0,0 +1,67 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestTraceFileAccesses(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sbox_trace_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"parent", "sub/child", "sub/relative"} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(name), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The parent reads a file, forks a subshell that reads a file after changing the directory,
+	// and executes cat, which opens a relative path with openat.
+	script := "read -r _ < parent; (cd sub && read -r _ < child); cd sub && exec cat relative"
+	cmd := exec.Command("bash", "-c", script)
+	cmd.Dir = dir
+
+	paths, status, err := traceFileAccesses(cmd, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.Exited() || status.ExitStatus() != 0 {
+		t.Fatalf("command failed: %v", exitStatusError{status})
+	}
+
+	accessed := make(map[string]bool)
+	for _, path := range paths {
+		accessed[path] = true
+	}
+	for _, name := range []string{"parent", "sub/child", "sub/relative"} {
+		if path := filepath.Join(dir, name); !accessed[path] {
+			t.Errorf("expected %q in the accessed paths, got %q", path, paths)
+		}
+	}
+	if cat, err := exec.LookPath("cat"); err == nil && !accessed[cat] {
+		t.Errorf("expected %q in the accessed paths, got %q", cat, paths)
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build linux,!amd64
+
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// tracingSupported is whether --hermetic and --trace-inputs can be used. The tracer only knows the
+// syscalls and registers of x86_64.
+const tracingSupported = false
+
+var errTracingUnsupported = errors.New("tracing commands is only supported on linux/amd64")
+
+func traceFileAccesses(cmd *exec.Cmd, root string, afterExec bool) (paths []string,
+	status syscall.WaitStatus, err error) {
+	return nil, status, errTracingUnsupported
+}
+
+func runTraced(cmd *exec.Cmd) ([]string, error) {
+	return nil, errTracingUnsupported
+}
@@ -74,7 +74,8 @@ _COMMON_ATTRS = {
     "module_name": attr.string(mandatory = True),
     "module_type": attr.string(mandatory = True),
     "module_variant": attr.string(),
-    "module_deps": attr.label_list(providers = [SoongModuleInfo]),
+    # Converted modules are native Bazel rules without SoongModuleInfo.
+    "module_deps": attr.label_list(),
 }
 
 
@@ -296,17 +297,26 @@ func extractModuleProperties(aModule android.Module) map[string]string {
 	return ret
 }
 
-func createBazelOverlay(ctx *android.Context, bazelOverlayDir string) error {
+// createBazelOverlay writes a BUILD file for every package with modules. If convert is true,
+// modules of supported module types are converted to native Bazel rules, see
+// bazel_overlay_convert.go.
+func createBazelOverlay(ctx *android.Context, bazelOverlayDir string, convert bool) error {
 	blueprintCtx := ctx.Context
-	blueprintCtx.VisitAllModules(func(module blueprint.Module) {
-		buildFile, err := buildFileForModule(blueprintCtx, module)
-		if err != nil {
-			panic(err)
+	if convert {
+		if err := createConvertedBuildFiles(blueprintCtx); err != nil {
+			return err
 		}
+	} else {
+		blueprintCtx.VisitAllModules(func(module blueprint.Module) {
+			buildFile, err := buildFileForModule(blueprintCtx, module)
+			if err != nil {
+				panic(err)
+			}
 
-		buildFile.Write([]byte(generateSoongModuleTarget(blueprintCtx, module) + "\n\n"))
-		buildFile.Close()
-	})
+			buildFile.Write([]byte(generateSoongModuleTarget(blueprintCtx, module) + "\n\n"))
+			buildFile.Close()
+		})
+	}
 
 	if err := writeReadOnlyFile(bazelOverlayDir, "WORKSPACE", ""); err != nil {
 		return err
@@ -351,6 +361,19 @@ func generateSoongModuleTarget(
 	blueprintCtx *blueprint.Context,
 	module blueprint.Module) string {
 
+	return generateSoongModuleTargetWithDepLabels(blueprintCtx, module,
+		func(depModule blueprint.Module) string {
+			return qualifiedTargetLabel(blueprintCtx, depModule)
+		})
+}
+
+// Same as generateSoongModuleTarget, but the labels of the dependencies are returned by
+// depLabel, so that they can refer to converted targets.
+func generateSoongModuleTargetWithDepLabels(
+	blueprintCtx *blueprint.Context,
+	module blueprint.Module,
+	depLabel func(blueprint.Module) string) string {
+
 	var props map[string]string
 	if aModule, ok := module.(android.Module); ok {
 		props = extractModuleProperties(aModule)
@@ -362,7 +385,7 @@ func generateSoongModuleTarget(
 	// out the implications of that.
 	depLabels := map[string]bool{}
 	blueprintCtx.VisitDirectDeps(module, func(depModule blueprint.Module) {
-		depLabels[qualifiedTargetLabel(blueprintCtx, depModule)] = true
+		depLabels[depLabel(depModule)] = true
 	})
 
 	depLabelList := "[\n"
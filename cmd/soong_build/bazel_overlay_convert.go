@@ -0,0 +1,577 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// This file contains the converter mode of the bazel overlay, which maps the modules of a few
+// module types to native Bazel rules instead of generic soong_module targets. A module is
+// converted only if every property it sets is understood by the converter of its module type
+// and all the modules it references are converted as well; all the other modules fall back to
+// soong_module targets. conversion_report.txt in the overlay lists the outcome for each module.
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/blueprint"
+	"github.com/google/blueprint/proptools"
+
+	"android/soong/android"
+)
+
+const conversionReportFile = "conversion_report.txt"
+
+// A bazelAttribute is an attribute of a generated Bazel target with its value in Starlark.
+type bazelAttribute struct {
+	name  string
+	value string
+}
+
+// A bazelConverter converts the modules of a Soong module type to targets of a native Bazel rule.
+type bazelConverter struct {
+	// The Bazel rule the module type is converted to.
+	rule string
+
+	// The properties the converter understands, in addition to toleratedProps.
+	props []string
+
+	// convert returns the attributes of the Bazel target for the module, or an error if the
+	// module can't be converted.
+	convert func(ctx *conversionContext, m *convertedModule) ([]bazelAttribute, error)
+}
+
+// Properties which have no equivalent in the generated Bazel targets, but which don't change
+// what the module builds, so they don't prevent the conversion.
+var toleratedProps = map[string]bool{
+	"name":                true,
+	"defaults":            true, // already applied to the other properties
+	"visibility":          true, // Bazel has native visibility semantics. Handle later.
+	"owner":               true,
+	"notice":              true,
+	"required":            true,
+	"host_required":       true,
+	"target_required":     true,
+	"dist":                true,
+	"dists":               true,
+	"enabled":             true,
+	"host_supported":      true,
+	"device_supported":    true,
+	"compile_multilib":    true,
+	"vendor":              true,
+	"proprietary":         true,
+	"soc_specific":        true,
+	"device_specific":     true,
+	"product_specific":    true,
+	"system_ext_specific": true,
+	"apex_available":      true,
+	"min_sdk_version":     true,
+
+	// cc properties which only select variants or the default libraries of the platform.
+	"vendor_available":          true,
+	"recovery_available":        true,
+	"ramdisk_available":         true,
+	"native_bridge_supported":   true,
+	"double_loadable":           true,
+	"system_shared_libs":        true,
+	"stl":                       true,
+	"sdk_version":               true,
+	"export_header_lib_headers": true,
+	"export_static_lib_headers": true,
+	"export_shared_lib_headers": true,
+}
+
+var ccLibraryProps = []string{
+	"srcs",
+	"exclude_srcs",
+	"cflags",
+	"local_include_dirs",
+	"export_include_dirs",
+	"static_libs",
+	"whole_static_libs",
+	"shared_libs",
+	"header_libs",
+}
+
+var bazelConverters = map[string]bazelConverter{
+	"cc_library":         {rule: "cc_library", props: ccLibraryProps, convert: convertCcLibrary},
+	"cc_library_static":  {rule: "cc_library", props: ccLibraryProps, convert: convertCcLibrary},
+	"cc_library_shared":  {rule: "cc_library", props: ccLibraryProps, convert: convertCcLibrary},
+	"cc_library_headers": {rule: "cc_library", props: ccLibraryProps, convert: convertCcLibrary},
+	"cc_binary": {
+		rule:    "cc_binary",
+		props:   []string{"srcs", "exclude_srcs", "cflags", "local_include_dirs", "static_libs", "whole_static_libs", "shared_libs", "header_libs"},
+		convert: convertCcBinary,
+	},
+	"filegroup": {
+		rule:    "filegroup",
+		props:   []string{"srcs", "exclude_srcs"},
+		convert: convertFilegroup,
+	},
+	"genrule": {
+		rule:    "genrule",
+		props:   []string{"srcs", "exclude_srcs", "out", "cmd", "tools", "tool_files"},
+		convert: convertGenrule,
+	},
+}
+
+// A convertedModule is a module considered for the conversion. The properties are taken from
+// its first variant, since a single Bazel target represents all the variants.
+type convertedModule struct {
+	name       string
+	moduleType string
+	pkg        string
+	variants   []blueprint.Module
+	props      map[string]reflect.Value
+
+	// Why the module can't be converted, or empty if it is.
+	reason     string
+	attributes []bazelAttribute
+}
+
+func (m *convertedModule) label() string {
+	if m.pkg == "." {
+		return "//:" + m.name
+	}
+	return "//" + m.pkg + ":" + m.name
+}
+
+func (m *convertedModule) converted() bool {
+	return m.reason == ""
+}
+
+func (m *convertedModule) stringProp(name string) string {
+	if v, ok := m.props[name]; ok {
+		v = reflect.Indirect(v)
+		if v.Kind() == reflect.String {
+			return v.String()
+		}
+	}
+	return ""
+}
+
+func (m *convertedModule) stringListProp(name string) []string {
+	var ret []string
+	if v, ok := m.props[name]; ok && v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			if e := reflect.Indirect(v.Index(i)); e.Kind() == reflect.String {
+				ret = append(ret, e.String())
+			}
+		}
+	}
+	return ret
+}
+
+type conversionContext struct {
+	modules map[string]*convertedModule
+}
+
+// depLabel returns the label of the Bazel target of the module with the given name, or an
+// error if the module isn't converted.
+func (ctx *conversionContext) depLabel(name string) (string, error) {
+	dep, ok := ctx.modules[name]
+	if !ok {
+		return "", fmt.Errorf("depends on unknown module %q", name)
+	}
+	if !dep.converted() {
+		return "", fmt.Errorf("depends on unconverted module %q", name)
+	}
+	return dep.label(), nil
+}
+
+func (ctx *conversionContext) depLabels(names []string) ([]string, error) {
+	var ret []string
+	for _, name := range names {
+		label, err := ctx.depLabel(name)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, label)
+	}
+	return android.SortedUniqueStrings(ret), nil
+}
+
+// Extract the values of the properties set on a module, keyed by the module property name.
+// Properties of interface type (arch, multilib and target) are included if any of their
+// fields is set.
+func extractRawModuleProperties(aModule android.Module) map[string]reflect.Value {
+	ret := map[string]reflect.Value{}
+	for _, properties := range aModule.GetProperties() {
+		structValue := reflect.ValueOf(properties).Elem()
+		structType := structValue.Type()
+		for i := 0; i < structValue.NumField(); i++ {
+			field := structType.Field(i)
+			if field.PkgPath != "" || proptools.HasTag(field, "blueprint", "mutated") {
+				continue
+			}
+
+			fieldValue := structValue.Field(i)
+			if fieldValue.Kind() == reflect.Interface {
+				if fieldValue.IsNil() {
+					continue
+				}
+				fieldValue = fieldValue.Elem()
+			}
+			if isZero(fieldValue) {
+				continue
+			}
+			ret[proptools.PropertyNameForField(field.Name)] = fieldValue
+		}
+	}
+	return ret
+}
+
+// Convert the modules of the blueprint context, returning them sorted by label.
+func convertModules(blueprintCtx *blueprint.Context) []*convertedModule {
+	ctx := &conversionContext{modules: make(map[string]*convertedModule)}
+	blueprintCtx.VisitAllModules(func(module blueprint.Module) {
+		name := blueprintCtx.ModuleName(module)
+		if m, ok := ctx.modules[name]; ok {
+			m.variants = append(m.variants, module)
+			return
+		}
+
+		m := &convertedModule{
+			name:       name,
+			moduleType: blueprintCtx.ModuleType(module),
+			pkg:        packagePath(blueprintCtx, module),
+			variants:   []blueprint.Module{module},
+		}
+		if aModule, ok := module.(android.Module); ok {
+			m.props = extractRawModuleProperties(aModule)
+		}
+		ctx.modules[name] = m
+	})
+
+	var modules []*convertedModule
+	for _, m := range ctx.modules {
+		modules = append(modules, m)
+		converter, ok := bazelConverters[m.moduleType]
+		if !ok {
+			m.reason = "unsupported module type"
+			continue
+		}
+		for _, prop := range android.SortedStringKeys(m.props) {
+			if !toleratedProps[prop] && !android.InList(prop, converter.props) {
+				m.reason = fmt.Sprintf("unsupported property %q", prop)
+				break
+			}
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].label() < modules[j].label()
+	})
+
+	// Converting a module fails if any module it depends on isn't converted, which may in turn
+	// make the modules depending on it fail. Repeat until no more modules fail.
+	for changed := true; changed; {
+		changed = false
+		for _, m := range modules {
+			if !m.converted() {
+				continue
+			}
+			attributes, err := bazelConverters[m.moduleType].convert(ctx, m)
+			if err != nil {
+				m.reason = err.Error()
+				m.attributes = nil
+				changed = true
+				continue
+			}
+			m.attributes = attributes
+		}
+	}
+
+	return modules
+}
+
+// Write the BUILD files of the bazel overlay with converted targets for the modules that can be
+// converted, and soong_module targets for the variants of the others, along with the
+// conversion report.
+func createConvertedBuildFiles(blueprintCtx *blueprint.Context) error {
+	modules := convertModules(blueprintCtx)
+
+	// Dependencies on any variant of a converted module refer to its converted target.
+	convertedLabels := map[blueprint.Module]string{}
+	for _, m := range modules {
+		if m.converted() {
+			for _, variant := range m.variants {
+				convertedLabels[variant] = m.label()
+			}
+		}
+	}
+	depLabel := func(depModule blueprint.Module) string {
+		if label, ok := convertedLabels[depModule]; ok {
+			return label
+		}
+		return qualifiedTargetLabel(blueprintCtx, depModule)
+	}
+
+	for _, m := range modules {
+		var targets []string
+		if m.converted() {
+			targets = append(targets, generateConvertedTarget(m))
+		} else {
+			for _, variant := range m.variants {
+				targets = append(targets, generateSoongModuleTargetWithDepLabels(blueprintCtx, variant, depLabel))
+			}
+		}
+
+		buildFile, err := buildFileForModule(blueprintCtx, m.variants[0])
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			buildFile.Write([]byte(target + "\n\n"))
+		}
+		buildFile.Close()
+	}
+
+	return writeReadOnlyFile(bazelOverlayDir, conversionReportFile, generateConversionReport(modules))
+}
+
+// Return the Bazel target of a converted module.
+func generateConvertedTarget(m *convertedModule) string {
+	target := bazelConverters[m.moduleType].rule + "(\n"
+	target += fmt.Sprintf("    name = \"%s\",\n", escapeString(m.name))
+	for _, attribute := range m.attributes {
+		target += fmt.Sprintf("    %s = %s,\n", attribute.name, attribute.value)
+	}
+	return target + ")"
+}
+
+// Return the conversion report, listing the outcome for each module.
+func generateConversionReport(modules []*convertedModule) string {
+	converted := 0
+	var lines []string
+	for _, m := range modules {
+		if m.converted() {
+			converted++
+			lines = append(lines, fmt.Sprintf("%s %s: converted to %s", m.label(), m.moduleType,
+				bazelConverters[m.moduleType].rule))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s %s: not converted: %s", m.label(), m.moduleType, m.reason))
+		}
+	}
+	header := fmt.Sprintf("# %d of %d modules converted to native Bazel rules\n", converted, len(modules))
+	return header + strings.Join(lines, "\n") + "\n"
+}
+
+// Return the Starlark representation of a list of strings.
+func starlarkStringList(values []string, indent int) string {
+	ret := "[\n"
+	for _, v := range values {
+		ret += makeIndent(indent+1) + "\"" + escapeString(v) + "\",\n"
+	}
+	return ret + makeIndent(indent) + "]"
+}
+
+// Return the Starlark expression for a list of source files, which may contain globs and
+// references to other modules (":module"). Files are relative to the package of the module.
+func srcsExpression(ctx *conversionContext, srcs, excludes []string) (string, error) {
+	var files, globs, labels []string
+	for _, src := range srcs {
+		if strings.HasPrefix(src, ":") {
+			if strings.Contains(src, "{") {
+				return "", fmt.Errorf("unsupported output tag in %q", src)
+			}
+			label, err := ctx.depLabel(strings.TrimPrefix(src, ":"))
+			if err != nil {
+				return "", err
+			}
+			labels = append(labels, label)
+		} else if strings.Contains(src, "*") {
+			globs = append(globs, src)
+		} else {
+			files = append(files, src)
+		}
+	}
+	for _, exclude := range excludes {
+		if strings.HasPrefix(exclude, ":") {
+			return "", fmt.Errorf("unsupported module reference in exclude_srcs %q", exclude)
+		}
+	}
+
+	var parts []string
+	if len(files) > 0 || (len(globs) == 0 && len(labels) == 0) {
+		parts = append(parts, starlarkStringList(files, 1))
+	}
+	if len(globs) > 0 {
+		glob := "glob(" + starlarkStringList(globs, 1)
+		if len(excludes) > 0 {
+			glob += ", exclude = " + starlarkStringList(excludes, 1)
+		}
+		parts = append(parts, glob+")")
+	} else if len(excludes) > 0 && len(files) > 0 {
+		// Exclusions only apply to globs in Bazel, so apply them to the files here.
+		parts[0] = starlarkStringList(android.RemoveListFromList(files, excludes), 1)
+	}
+	if len(labels) > 0 {
+		parts = append(parts, starlarkStringList(labels, 1))
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// Return the attributes shared by cc_library and cc_binary targets.
+func ccCommonAttributes(ctx *conversionContext, m *convertedModule) ([]bazelAttribute, error) {
+	var attributes []bazelAttribute
+
+	// Headers in the local include directories are private headers of the module.
+	srcs := m.stringListProp("srcs")
+	for _, dir := range m.stringListProp("local_include_dirs") {
+		srcs = append(srcs, filepath.Join(dir, "**", "*.h"))
+	}
+	if len(srcs) > 0 {
+		value, err := srcsExpression(ctx, srcs, m.stringListProp("exclude_srcs"))
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, bazelAttribute{"srcs", value})
+	}
+
+	copts := m.stringListProp("cflags")
+	for _, dir := range m.stringListProp("local_include_dirs") {
+		copts = append(copts, "-I"+filepath.Join(m.pkg, dir))
+	}
+	if len(copts) > 0 {
+		attributes = append(attributes, bazelAttribute{"copts", starlarkStringList(copts, 1)})
+	}
+
+	var depNames []string
+	for _, prop := range []string{"whole_static_libs", "static_libs", "shared_libs", "header_libs"} {
+		depNames = append(depNames, m.stringListProp(prop)...)
+	}
+	deps, err := ctx.depLabels(depNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(deps) > 0 {
+		attributes = append(attributes, bazelAttribute{"deps", starlarkStringList(deps, 1)})
+	}
+
+	return attributes, nil
+}
+
+func convertCcLibrary(ctx *conversionContext, m *convertedModule) ([]bazelAttribute, error) {
+	attributes, err := ccCommonAttributes(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	exportedDirs := m.stringListProp("export_include_dirs")
+	if len(exportedDirs) > 0 {
+		var hdrs []string
+		for _, dir := range exportedDirs {
+			hdrs = append(hdrs, filepath.Join(dir, "**", "*.h"))
+		}
+		attributes = append(attributes,
+			bazelAttribute{"hdrs", "glob(" + starlarkStringList(hdrs, 1) + ")"},
+			bazelAttribute{"includes", starlarkStringList(exportedDirs, 1)})
+	}
+
+	return attributes, nil
+}
+
+func convertCcBinary(ctx *conversionContext, m *convertedModule) ([]bazelAttribute, error) {
+	return ccCommonAttributes(ctx, m)
+}
+
+func convertFilegroup(ctx *conversionContext, m *convertedModule) ([]bazelAttribute, error) {
+	srcs, err := srcsExpression(ctx, m.stringListProp("srcs"), m.stringListProp("exclude_srcs"))
+	if err != nil {
+		return nil, err
+	}
+	return []bazelAttribute{{"srcs", srcs}}, nil
+}
+
+func convertGenrule(ctx *conversionContext, m *convertedModule) ([]bazelAttribute, error) {
+	var attributes []bazelAttribute
+
+	if srcs := m.stringListProp("srcs"); len(srcs) > 0 {
+		value, err := srcsExpression(ctx, srcs, m.stringListProp("exclude_srcs"))
+		if err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, bazelAttribute{"srcs", value})
+	}
+
+	outs := m.stringListProp("out")
+	if len(outs) == 0 {
+		return nil, fmt.Errorf("no outputs")
+	}
+	attributes = append(attributes, bazelAttribute{"outs", starlarkStringList(outs, 1)})
+
+	// Tools are referenced from the command by module name or file path.
+	toolLabels := map[string]string{}
+	var tools []string
+	for _, tool := range m.stringListProp("tools") {
+		label, err := ctx.depLabel(tool)
+		if err != nil {
+			return nil, err
+		}
+		toolLabels[tool] = label
+		tools = append(tools, label)
+	}
+	for _, toolFile := range m.stringListProp("tool_files") {
+		if strings.HasPrefix(toolFile, ":") || strings.Contains(toolFile, "*") {
+			return nil, fmt.Errorf("unsupported tool_files entry %q", toolFile)
+		}
+		toolLabels[toolFile] = toolFile
+		tools = append(tools, toolFile)
+	}
+
+	cmd, err := convertGenruleCmd(m.stringProp("cmd"), tools, toolLabels)
+	if err != nil {
+		return nil, err
+	}
+	attributes = append(attributes, bazelAttribute{"cmd", "\"" + escapeString(cmd) + "\""})
+
+	if len(tools) > 0 {
+		attributes = append(attributes, bazelAttribute{"tools", starlarkStringList(tools, 1)})
+	}
+
+	return attributes, nil
+}
+
+// Translate the variables of a Soong genrule command to the equivalent Bazel genrule "Make"
+// variables.
+func convertGenruleCmd(cmd string, tools []string, toolLabels map[string]string) (string, error) {
+	// Expand turns $$ into $, but Bazel also needs $$ for a literal $.
+	return android.Expand(strings.ReplaceAll(cmd, "$$", "$$$$"), func(name string) (string, error) {
+		switch name {
+		case "in":
+			return "$(SRCS)", nil
+		case "out":
+			return "$(OUTS)", nil
+		case "genDir":
+			return "$(RULEDIR)", nil
+		case "location":
+			if len(tools) == 0 {
+				return "", fmt.Errorf("$(location) used without tools or tool_files")
+			}
+			return "$(location " + tools[0] + ")", nil
+		}
+		for _, prefix := range []string{"location ", "locations "} {
+			if strings.HasPrefix(name, prefix) {
+				label, ok := toolLabels[strings.TrimSpace(strings.TrimPrefix(name, prefix))]
+				if !ok {
+					return "", fmt.Errorf("unsupported cmd variable $(%s)", name)
+				}
+				return "$(" + prefix + label + ")", nil
+			}
+		}
+		return "", fmt.Errorf("unsupported cmd variable $(%s)", name)
+	})
+}
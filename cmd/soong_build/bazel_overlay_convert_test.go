@@ -0,0 +1,288 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"android/soong/android"
+	"testing"
+)
+
+// A module with a subset of the properties of cc modules.
+type ccLikeModule struct {
+	android.ModuleBase
+	properties struct {
+		Srcs                []string
+		Exclude_srcs        []string
+		Cflags              []string
+		Local_include_dirs  []string
+		Export_include_dirs []string
+		Static_libs         []string
+		Shared_libs         []string
+		Sanitize            struct {
+			Address *bool
+		}
+	}
+}
+
+func (m *ccLikeModule) GenerateAndroidBuildActions(ctx android.ModuleContext) {
+}
+
+func ccLikeModuleFactory() android.Module {
+	module := &ccLikeModule{}
+	module.AddProperties(&module.properties)
+	android.InitAndroidModule(module)
+	return module
+}
+
+// A module with the properties of genrule modules.
+type genruleLikeModule struct {
+	android.ModuleBase
+	properties struct {
+		Srcs       []string
+		Out        []string
+		Cmd        *string
+		Tools      []string
+		Tool_files []string
+	}
+}
+
+func (m *genruleLikeModule) GenerateAndroidBuildActions(ctx android.ModuleContext) {
+}
+
+func genruleLikeModuleFactory() android.Module {
+	module := &genruleLikeModule{}
+	module.AddProperties(&module.properties)
+	android.InitAndroidModule(module)
+	return module
+}
+
+func TestConvertModules(t *testing.T) {
+	bp := `
+filegroup {
+	name: "fg",
+	srcs: ["*.txt", "b.txt"],
+	exclude_srcs: ["c.txt"],
+}
+
+cc_library {
+	name: "libfoo",
+	srcs: ["foo.cpp", ":fg"],
+	cflags: ["-Wall"],
+	local_include_dirs: ["private"],
+	export_include_dirs: ["include"],
+	static_libs: ["libbar"],
+}
+
+cc_library {
+	name: "libbar",
+	srcs: ["bar.cpp"],
+}
+
+cc_binary {
+	name: "bin",
+	srcs: ["main.cpp"],
+	shared_libs: ["libfoo"],
+}
+
+cc_library {
+	name: "libsan",
+	srcs: ["san.cpp"],
+	sanitize: {
+		address: true,
+	},
+}
+
+cc_binary {
+	name: "bin_sanitized_dep",
+	srcs: ["main.cpp"],
+	static_libs: ["libsan"],
+}
+
+genrule {
+	name: "gen",
+	srcs: ["in.txt"],
+	out: ["out.h"],
+	tools: ["bin"],
+	cmd: "$(location) $(in) > $(out) && echo $$HOME",
+}
+
+custom {
+	name: "other",
+}
+`
+	fs := map[string][]byte{
+		"a/Android.bp": []byte(bp),
+		"a/b.txt":      nil,
+		"a/d.txt":      nil,
+		"a/in.txt":     nil,
+	}
+
+	config := android.TestConfig(buildDir, nil, "", fs)
+	ctx := android.NewTestContext()
+	ctx.RegisterModuleType("custom", customModuleFactory)
+	ctx.RegisterModuleType("filegroup", android.FileGroupFactory)
+	ctx.RegisterModuleType("cc_library", ccLikeModuleFactory)
+	ctx.RegisterModuleType("cc_binary", ccLikeModuleFactory)
+	ctx.RegisterModuleType("genrule", genruleLikeModuleFactory)
+	ctx.Register(config)
+
+	_, errs := ctx.ParseFileList(".", []string{"a/Android.bp"})
+	android.FailIfErrored(t, errs)
+	_, errs = ctx.PrepareBuildActions(config)
+	android.FailIfErrored(t, errs)
+
+	modules := convertModules(ctx.Context.Context)
+
+	expectedTargets := map[string]string{
+		"fg": `filegroup(
+    name = "fg",
+    srcs = [
+        "b.txt",
+    ] + glob([
+        "*.txt",
+    ], exclude = [
+        "c.txt",
+    ]),
+)`,
+		"libfoo": `cc_library(
+    name = "libfoo",
+    srcs = [
+        "foo.cpp",
+    ] + glob([
+        "private/**/*.h",
+    ]) + [
+        "//a:fg",
+    ],
+    copts = [
+        "-Wall",
+        "-Ia/private",
+    ],
+    deps = [
+        "//a:libbar",
+    ],
+    hdrs = glob([
+        "include/**/*.h",
+    ]),
+    includes = [
+        "include",
+    ],
+)`,
+		"bin": `cc_binary(
+    name = "bin",
+    srcs = [
+        "main.cpp",
+    ],
+    deps = [
+        "//a:libfoo",
+    ],
+)`,
+		"gen": `genrule(
+    name = "gen",
+    srcs = [
+        "in.txt",
+    ],
+    outs = [
+        "out.h",
+    ],
+    cmd = "$(location //a:bin) $(SRCS) > $(OUTS) && echo $$HOME",
+    tools = [
+        "//a:bin",
+    ],
+)`,
+	}
+
+	for _, m := range modules {
+		expected, ok := expectedTargets[m.name]
+		if !ok {
+			continue
+		}
+		if !m.converted() {
+			t.Errorf("expected %q to be converted, but it wasn't: %s", m.name, m.reason)
+			continue
+		}
+		if actual := generateConvertedTarget(m); actual != expected {
+			t.Errorf("expected converted target of %q to be '%s', got '%s'", m.name, expected, actual)
+		}
+	}
+
+	expectedReport := `# 5 of 8 modules converted to native Bazel rules
+//a:bin cc_binary: converted to cc_binary
+//a:bin_sanitized_dep cc_binary: not converted: depends on unconverted module "libsan"
+//a:fg filegroup: converted to filegroup
+//a:gen genrule: converted to genrule
+//a:libbar cc_library: converted to cc_library
+//a:libfoo cc_library: converted to cc_library
+//a:libsan cc_library: not converted: unsupported property "sanitize"
+//a:other custom: not converted: unsupported module type
+`
+	if actual := generateConversionReport(modules); actual != expectedReport {
+		t.Errorf("expected conversion report to be '%s', got '%s'", expectedReport, actual)
+	}
+}
+
+func TestConvertGenruleCmd(t *testing.T) {
+	tools := []string{"//a:tool", "script.sh"}
+	toolLabels := map[string]string{
+		"tool":      "//a:tool",
+		"script.sh": "script.sh",
+	}
+
+	testCases := []struct {
+		cmd      string
+		tools    []string
+		expected string
+		err      string
+	}{
+		{
+			cmd:      "$(location) --in $(in) --out $(out) --dir $(genDir)",
+			tools:    tools,
+			expected: "$(location //a:tool) --in $(SRCS) --out $(OUTS) --dir $(RULEDIR)",
+		},
+		{
+			cmd:      "$(location script.sh) $(locations tool) $$FOO",
+			tools:    tools,
+			expected: "$(location script.sh) $(locations //a:tool) $$FOO",
+		},
+		{
+			cmd: "$(location) $(in)",
+			err: "$(location) used without tools or tool_files",
+		},
+		{
+			cmd:   "$(location other) $(in)",
+			tools: tools,
+			err:   "unsupported cmd variable $(location other)",
+		},
+		{
+			cmd:   "$(depfile)",
+			tools: tools,
+			err:   "unsupported cmd variable $(depfile)",
+		},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := convertGenruleCmd(testCase.cmd, testCase.tools, toolLabels)
+		if testCase.err != "" {
+			if err == nil || err.Error() != testCase.err {
+				t.Errorf("expected error %q converting %q, got %v", testCase.err, testCase.cmd, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error converting %q: %s", testCase.cmd, err)
+		} else if actual != testCase.expected {
+			t.Errorf("expected %q to be converted to %q, got %q", testCase.cmd, testCase.expected, actual)
+		}
+	}
+}
@@ -26,13 +26,18 @@ import (
 )
 
 var (
-	docFile         string
-	bazelOverlayDir string
+	docFile             string
+	bazelOverlayDir     string
+	bazelOverlayConvert bool
+	moduleGraphFile     string
 )
 
 func init() {
 	flag.StringVar(&docFile, "soong_docs", "", "build documentation file to output")
 	flag.StringVar(&bazelOverlayDir, "bazel_overlay_dir", "", "path to the bazel overlay directory")
+	flag.BoolVar(&bazelOverlayConvert, "bazel_overlay_convert", false,
+		"convert supported module types to native Bazel rules in the bazel overlay")
+	flag.StringVar(&moduleGraphFile, "module_graph_file", "", "JSON file to output the module graph to")
 }
 
 func newNameResolver(config android.Config) *android.NameResolver {
@@ -71,6 +76,28 @@ func main() {
 		configuration.SetStopBefore(bootstrap.StopBeforePrepareBuildActions)
 	}
 
+	// soong_ui --query sets SOONG_COLLECT_MODULE_GRAPH to get the module graph of the main
+	// soong_build run. Read it through configuration so that soong_build will rerun when it
+	// changes, and remove a stale graph when it isn't set.
+	if moduleGraphFile == "" && shouldPrepareBuildActions() {
+		defaultModuleGraphFile := filepath.Join(configuration.BuildDir(), "module_graph.json")
+		if configuration.IsEnvTrue("SOONG_COLLECT_MODULE_GRAPH") {
+			moduleGraphFile = defaultModuleGraphFile
+		} else {
+			os.Remove(defaultModuleGraphFile)
+		}
+	}
+
+	if moduleGraphFile != "" {
+		configuration.SetRecordModuleGraph()
+	}
+
+	// soong_ui attributes the failed build actions to their modules with the module actions table,
+	// which only needs the outputs of the modules rather than the whole module graph.
+	if shouldPrepareBuildActions() {
+		configuration.SetRecordModuleActions()
+	}
+
 	ctx.SetNameInterface(newNameResolver(configuration))
 
 	ctx.SetAllowMissingDependencies(configuration.AllowMissingDependencies())
@@ -88,7 +115,14 @@ func main() {
 	bootstrap.Main(ctx.Context, configuration, extraNinjaDeps...)
 
 	if bazelOverlayDir != "" {
-		if err := createBazelOverlay(ctx, bazelOverlayDir); err != nil {
+		if err := createBazelOverlay(ctx, bazelOverlayDir, bazelOverlayConvert); err != nil {
+			fmt.Fprintf(os.Stderr, "%s", err)
+			os.Exit(1)
+		}
+	}
+
+	if moduleGraphFile != "" {
+		if err := android.WriteModuleGraph(ctx, moduleGraphFile); err != nil {
 			fmt.Fprintf(os.Stderr, "%s", err)
 			os.Exit(1)
 		}
@@ -110,6 +144,12 @@ func main() {
 			fmt.Fprintf(os.Stderr, "error writing soong_build metrics %s: %s", metricsFile, err)
 			os.Exit(1)
 		}
+
+		moduleActionsFile := filepath.Join(bootstrap.BuildDir, "module_actions.json")
+		if err := android.WriteModuleActions(ctx, moduleActionsFile); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing module actions %s: %s", moduleActionsFile, err)
+			os.Exit(1)
+		}
 	}
 }
 
@@ -27,6 +27,7 @@ import (
 	"android/soong/ui/build"
 	"android/soong/ui/logger"
 	"android/soong/ui/metrics"
+	"android/soong/ui/query"
 	"android/soong/ui/status"
 	"android/soong/ui/terminal"
 	"android/soong/ui/tracer"
@@ -91,6 +92,22 @@ var commands []command = []command{
 		config:      buildActionConfig,
 		stdio:       stdio,
 		run:         make,
+	}, {
+		flag:         "--query",
+		description:  "query the dependencies between modules (deps, rdeps, somepath, allpaths)",
+		simpleOutput: true,
+		logsPrefix:   "query-",
+		config:       dumpVarConfig,
+		stdio:        customStdio,
+		run:          runQuery,
+	}, {
+		flag:         "--finder-daemon",
+		description:  "keep the list of source files up to date for the builds in the same output directory",
+		simpleOutput: true,
+		logsPrefix:   "finder-daemon-",
+		config:       dumpVarConfig,
+		stdio:        customStdio,
+		run:          runFinderDaemon,
 	},
 }
 
@@ -180,8 +197,10 @@ func main() {
 	trace.SetOutput(filepath.Join(logsDir, c.logsPrefix+"build.trace"))
 	stat.AddOutput(status.NewVerboseLog(log, filepath.Join(logsDir, c.logsPrefix+"verbose.log")))
 	stat.AddOutput(status.NewErrorLog(log, filepath.Join(logsDir, c.logsPrefix+"error.log")))
-	stat.AddOutput(status.NewProtoErrorLog(log, buildErrorFile))
+	stat.AddOutput(status.NewProtoErrorLog(log, buildErrorFile, config.ModuleActionsFile()))
 	stat.AddOutput(status.NewCriticalPath(log))
+	resourceUsage := status.NewResourceUsage(log)
+	stat.AddOutput(resourceUsage)
 	stat.AddOutput(status.NewBuildProgressLog(log, filepath.Join(logsDir, c.logsPrefix+"build_progress.pb")))
 
 	buildCtx.Verbosef("Detected %.3v GB total RAM", float32(config.TotalRAM())/(1024*1024*1024))
@@ -189,6 +208,12 @@ func main() {
 		config.Parallel(), config.RemoteParallel(), config.HighmemParallel())
 
 	defer met.Dump(soongMetricsFile)
+	defer func() {
+		met.SetActionResourceUsage(resourceUsage.Metrics())
+		st := stat.StartTool()
+		resourceUsage.Print(st)
+		st.Finish()
+	}()
 	defer build.DumpRBEMetrics(buildCtx, config, rbeMetricsFile)
 
 	if start, ok := os.LookupEnv("TRACE_BEGIN_SOONG"); ok {
@@ -349,6 +374,69 @@ func dumpVars(ctx build.Context, config build.Config, args []string, _ string) {
 	}
 }
 
+func runQuery(ctx build.Context, config build.Config, args []string, _ string) {
+	flags := flag.NewFlagSet("query", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(ctx.Writer, "usage: %s --query [--json] <query>\n\n", os.Args[0])
+		fmt.Fprintln(ctx.Writer, "In query mode, run Soong and print the module variants matching a query over")
+		fmt.Fprintln(ctx.Writer, "the module graph to stdout. The supported queries are:")
+		fmt.Fprintln(ctx.Writer, "")
+		fmt.Fprintln(ctx.Writer, "  deps(X)         the modules X depends on, directly or transitively")
+		fmt.Fprintln(ctx.Writer, "  rdeps(X)        the modules which depend on X, directly or transitively")
+		fmt.Fprintln(ctx.Writer, "  somepath(X, Y)  a shortest dependency path from X to Y")
+		fmt.Fprintln(ctx.Writer, "  allpaths(X, Y)  the modules on any dependency path from X to Y")
+		fmt.Fprintln(ctx.Writer, "")
+		fmt.Fprintln(ctx.Writer, "X and Y are module names, which match all the variants of the module, or a")
+		fmt.Fprintln(ctx.Writer, "single variant written as name{variant}.")
+		fmt.Fprintln(ctx.Writer, "")
+		flags.PrintDefaults()
+	}
+	jsonOutput := flags.Bool("json", false, "Print the result as JSON")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	// soong_build writes the module graph when SOONG_COLLECT_MODULE_GRAPH is set.
+	config.Environment().Set("SOONG_COLLECT_MODULE_GRAPH", "true")
+	build.Build(ctx, config, build.BuildProductConfig|build.BuildSoong)
+
+	graph, err := query.LoadGraph(config.ModuleGraphFile())
+	if err != nil {
+		ctx.Fatal(err)
+	}
+
+	result, err := graph.Query(strings.Join(flags.Args(), " "))
+	if err != nil {
+		ctx.Fatal(err)
+	}
+
+	if *jsonOutput {
+		err = result.WriteJSON(os.Stdout)
+	} else {
+		err = result.WriteText(os.Stdout)
+	}
+	if err != nil {
+		ctx.Fatal(err)
+	}
+}
+
+func runFinderDaemon(ctx build.Context, config build.Config, args []string, _ string) {
+	flags := flag.NewFlagSet("finder-daemon", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(ctx.Writer, "usage: %s --finder-daemon\n\n", os.Args[0])
+		fmt.Fprintln(ctx.Writer, "In finder daemon mode, watch the source tree for added and removed Android.bp,")
+		fmt.Fprintln(ctx.Writer, "Android.mk and other build files until interrupted. Builds that use the same")
+		fmt.Fprintln(ctx.Writer, "output directory get the list of build files from the daemon instead of checking")
+		fmt.Fprintln(ctx.Writer, "every directory in the source tree for changes. Linux only.")
+	}
+	flags.Parse(args)
+
+	build.RunFinderDaemon(ctx, config)
+}
+
 func stdio() terminal.StdioInterface {
 	return terminal.StdioImpl{}
 }
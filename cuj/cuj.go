@@ -17,6 +17,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -92,7 +93,7 @@ func (t *Test) Run(logsDir string) {
 	trace.SetOutput(filepath.Join(logsDir, "build.trace"))
 	stat.AddOutput(status.NewVerboseLog(log, filepath.Join(logsDir, "verbose.log")))
 	stat.AddOutput(status.NewErrorLog(log, filepath.Join(logsDir, "error.log")))
-	stat.AddOutput(status.NewProtoErrorLog(log, filepath.Join(logsDir, "build_error")))
+	stat.AddOutput(status.NewProtoErrorLog(log, filepath.Join(logsDir, "build_error"), config.ModuleActionsFile()))
 	stat.AddOutput(status.NewCriticalPath(log))
 
 	defer met.Dump(filepath.Join(logsDir, "soong_metrics"))
@@ -130,6 +131,12 @@ func touchIntentFile() error {
 }
 
 func main() {
+	thresholds := metrics.DefaultThresholds
+	thresholds.RegisterFlags(flag.CommandLine)
+	baseline := flag.String("baseline", "",
+		"cuj_metrics.pb of a previous run to compare the metrics with, exiting with status 1 on regressions")
+	flag.Parse()
+
 	outDir := os.Getenv("OUT_DIR")
 	if outDir == "" {
 		outDir = "out"
@@ -213,7 +220,6 @@ func main() {
 	}
 
 	cujMetrics := metrics.NewCriticalUserJourneysMetrics()
-	defer cujMetrics.Dump(filepath.Join(cujDir, "logs", "cuj_metrics.pb"))
 
 	for i, t := range tests {
 		logsSubDir := fmt.Sprintf("%02d_%s", i, t.name)
@@ -233,4 +239,21 @@ func main() {
 			cujMetrics.Add(t.name, t.results.metrics)
 		}
 	}
+
+	cujMetrics.Dump(filepath.Join(cujDir, "logs", "cuj_metrics.pb"))
+
+	if *baseline != "" {
+		base, err := metrics.ReadMetricsFile(*baseline)
+		if err != nil {
+			fmt.Printf("error reading baseline metrics: %s\n", err)
+			os.Exit(1)
+		}
+		if regressions := metrics.Regressions(cujMetrics.Diff(base, thresholds)); len(regressions) > 0 {
+			fmt.Printf("%d regressions compared to %s:\n", len(regressions), *baseline)
+			for _, r := range regressions {
+				fmt.Println("  ", r)
+			}
+			os.Exit(1)
+		}
+	}
 }
@@ -0,0 +1,544 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finder
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"android/soong/finder/fs"
+)
+
+// This file provides a Daemon that keeps the node tree of a Finder up to date while the
+// filesystem changes, so that the Finders of later executions can load the tree from the
+// Daemon instead of calling Stat on every directory mentioned in their db.
+// The Daemon is told which directories changed by a dirWatcher (inotify on Linux), and
+// relists exactly those directories before answering each request.
+// If the Daemon isn't running, or if it was started with different CacheParams, the Finder
+// falls back to loading its db as usual.
+
+// How often the Daemon applies the changes reported by its dirWatcher, so that the changes
+// don't pile up in the kernel between requests
+const daemonSyncInterval = time.Second
+
+// How long a Finder waits for the Daemon to send the cache, which can take a while if many
+// directories changed since the previous request
+const daemonResponseTimeout = time.Minute
+
+// a daemonRequest is sent by a Finder to ask the Daemon for its cache
+type daemonRequest struct {
+	Version string
+}
+
+// a daemonResponse contains the cache of the Daemon in the format of the db file, or the reason
+// why the Daemon can't provide it
+type daemonResponse struct {
+	Error string
+	Db    []byte
+
+	// the directories that changed since the previous response, unless ChangedDirsKnown is false
+	ChangedDirs      []string
+	ChangedDirsKnown bool
+}
+
+// a dirWatcher reports which directories changed since the previous call to Changes
+type dirWatcher interface {
+	// Watch starts reporting the changes to the entries and the attributes of the directory
+	Watch(path string) error
+
+	// Unwatch stops reporting the changes to the directory
+	Unwatch(path string)
+
+	// Changes returns the directories that changed since the previous call, and the
+	// directories that are no longer watched because they were removed or moved.
+	// If changes were lost, Changes returns overflow = true instead.
+	// Changes doesn't block.
+	Changes() (changed []string, unwatched []string, overflow bool, err error)
+
+	Close() error
+}
+
+// NewWithDaemon creates a new Finder like New, but first tries to load the cache from the
+// Daemon that <dial> connects to.
+func NewWithDaemon(cacheParams CacheParams, filesystem fs.FileSystem,
+	logger Logger, dbPath string, dial func() (net.Conn, error)) (f *Finder, err error) {
+	return newImplWithDaemon(cacheParams, filesystem, logger, dbPath, defaultNumThreads, dial)
+}
+
+// newImplWithDaemon is like NewWithDaemon but accepts more params
+func newImplWithDaemon(cacheParams CacheParams, filesystem fs.FileSystem,
+	logger Logger, dbPath string, numThreads int, dial func() (net.Conn, error)) (f *Finder, err error) {
+	f = newUnloaded(cacheParams, filesystem, logger, dbPath, numThreads)
+
+	err = f.loadFromDaemon(dial)
+	if err != nil {
+		f.verbosef("Not using finder daemon: %v\n", err)
+		f.nodes = *newPathMap("/")
+		f.loadFromFilesystem()
+	}
+
+	err = f.checkLoaded()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// loadFromDaemon populates the in-memory cache with the cache of the Daemon
+func (f *Finder) loadFromDaemon(dial func() (net.Conn, error)) error {
+	startTime := time.Now()
+
+	conn, err := dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(daemonResponseTimeout))
+
+	err = gob.NewEncoder(conn).Encode(daemonRequest{Version: f.cacheMetadata.Version})
+	if err != nil {
+		return err
+	}
+	var response daemonResponse
+	err = gob.NewDecoder(conn).Decode(&response)
+	if err != nil {
+		return err
+	}
+	if response.Error != "" {
+		return errors.New(response.Error)
+	}
+	f.verbosef("Received %v bytes from finder daemon in %v\n", len(response.Db), time.Since(startTime))
+
+	reader := bufio.NewReader(bytes.NewReader(response.Db))
+	if !f.validateCacheHeader(reader) {
+		return errors.New("Finder daemon cache header does not match")
+	}
+	for done := false; !done; {
+		data, err := f.readLine(reader)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		done = err == io.EOF
+		if len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+		dirs, err := f.parseCacheEntry(data)
+		if err != nil {
+			return err
+		}
+		// The Daemon has already confirmed that these directories are up to date
+		for _, dir := range dirs {
+			node := f.nodes.GetNode(dir.Path, true)
+			node.mapNode = mapNode{statResponse: dir.statResponse, FileNames: dir.FileNames}
+		}
+	}
+	f.nodes.UpdateNumDescendentsRecursive()
+
+	if response.ChangedDirsKnown {
+		f.changedDirs = make(map[string]bool, len(response.ChangedDirs))
+		for _, dir := range response.ChangedDirs {
+			f.changedDirs[dir] = true
+		}
+	}
+
+	// keep the db up to date for the executions that don't use the Daemon
+	if db, err := f.readDb(); err != nil || !bytes.Equal(db, response.Db) {
+		f.setModified()
+	}
+	f.goDumpDb()
+
+	f.verbosef("Loaded cache from finder daemon in %v\n", time.Since(startTime))
+	return nil
+}
+
+// readDb returns the contents of the db file
+func (f *Finder) readDb() ([]byte, error) {
+	reader, err := f.filesystem.Open(f.DbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// relistDirAsync is like statDirAsync but relists the directory even if its stats are unchanged,
+// because the modification time of a directory can't tell apart changes made within its
+// granularity
+func (f *Finder) relistDirAsync(node *pathMap) {
+	f.threadPool.Run(
+		func() {
+			old := node.mapNode
+			oldDirNames := node.childNames()
+
+			node.mapNode = mapNode{
+				statResponse: f.statDirSync(node.path),
+				FileNames:    []string{},
+			}
+			f.setModified()
+			if node.statResponse.ModTime != 0 {
+				f.listDirSync(node)
+			} else {
+				node.children = map[string]*pathMap{}
+			}
+
+			// most relisted directories are unchanged, because they were relisted just in case
+			if !f.isInfoUpToDate(old.statResponse, node.statResponse) ||
+				!sameNames(old.FileNames, node.FileNames) ||
+				!sameNames(oldDirNames, node.childNames()) {
+				f.recordChange(node.path)
+			}
+		},
+	)
+}
+
+// childNames returns the names of the subdirectories of the node
+func (m *pathMap) childNames() []string {
+	names := make([]string, 0, len(m.children))
+	for name := range m.children {
+		names = append(names, name)
+	}
+	return names
+}
+
+// sameNames tells whether <a> and <b> contain the same names in any order
+func sameNames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// a Daemon keeps the cache of a Finder up to date and serves it to other Finders
+type Daemon struct {
+	finder  *Finder
+	watcher dirWatcher
+
+	// the directories that the watcher reports changes to
+	watched map[string]bool
+	// whether the node tree may contain directories that aren't watched yet
+	incomplete bool
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+	waitGroup sync.WaitGroup
+}
+
+// NewDaemon creates a Daemon that scans the filesystem like New and then starts watching every
+// directory in its cache. Callers of NewDaemon should call <d.Serve()> to serve the cache and
+// <d.Close()> when done.
+func NewDaemon(cacheParams CacheParams, filesystem fs.FileSystem,
+	logger Logger, dbPath string) (d *Daemon, err error) {
+	watcher, err := newDirWatcher()
+	if err != nil {
+		return nil, err
+	}
+	d, err = newDaemonImpl(cacheParams, filesystem, logger, dbPath, defaultNumThreads, watcher)
+	if err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+// newDaemonImpl is like NewDaemon but accepts more params
+func newDaemonImpl(cacheParams CacheParams, filesystem fs.FileSystem,
+	logger Logger, dbPath string, numThreads int, watcher dirWatcher) (d *Daemon, err error) {
+	f, err := newImpl(cacheParams, filesystem, logger, dbPath, numThreads)
+	if err != nil {
+		return nil, err
+	}
+	// the db dump reads the node tree, which the Daemon is about to modify
+	f.WaitForDbDump()
+
+	d = &Daemon{
+		finder:  f,
+		watcher: watcher,
+		watched: make(map[string]bool),
+		done:    make(chan struct{}),
+	}
+
+	startTime := time.Now()
+	f.lock()
+	err = d.sync(true)
+	f.unlock()
+	if err != nil {
+		return nil, err
+	}
+	f.verbosef("Finder daemon started watching %v directories in %v\n", len(d.watched), time.Since(startTime))
+
+	return d, nil
+}
+
+// Serve answers the requests of the Finders that connect to <listener> until the Daemon is closed
+func (d *Daemon) Serve(listener net.Listener) error {
+	d.waitGroup.Add(1)
+	go d.syncPeriodically()
+
+	go func() {
+		<-d.done
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-d.done:
+				return nil
+			default:
+				return err
+			}
+		}
+		go d.serveConn(conn)
+	}
+}
+
+// Close stops the Daemon from watching the filesystem and serving requests
+func (d *Daemon) Close() error {
+	d.closeOnce.Do(func() {
+		close(d.done)
+		d.waitGroup.Wait()
+
+		// wait for any request being answered
+		d.finder.lock()
+		d.closeErr = d.watcher.Close()
+		d.finder.unlock()
+	})
+	return d.closeErr
+}
+
+func (d *Daemon) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(daemonResponseTimeout))
+
+	var request daemonRequest
+	err := gob.NewDecoder(conn).Decode(&request)
+	if err != nil {
+		d.finder.verbosef("Finder daemon failed to read request: %v\n", err)
+		return
+	}
+	err = gob.NewEncoder(conn).Encode(d.respond(request))
+	if err != nil {
+		d.finder.verbosef("Finder daemon failed to send response: %v\n", err)
+	}
+}
+
+// respond brings the cache up to date and returns it
+func (d *Daemon) respond(request daemonRequest) daemonResponse {
+	f := d.finder
+	if request.Version != f.cacheMetadata.Version {
+		return daemonResponse{Error: fmt.Sprintf("Finder daemon version is %q, not %q",
+			f.cacheMetadata.Version, request.Version)}
+	}
+
+	select {
+	case <-d.done:
+		return daemonResponse{Error: "Finder daemon is shutting down"}
+	default:
+	}
+
+	startTime := time.Now()
+	f.lock()
+	defer f.unlock()
+
+	err := d.sync(false)
+	if err != nil {
+		return daemonResponse{Error: err.Error()}
+	}
+	db, err := f.serializeDb()
+	if err != nil {
+		return daemonResponse{Error: err.Error()}
+	}
+	// the Finder that receives the cache dumps it to the db, so later changes are relative to it
+	changedDirs, known := f.ChangedDirs()
+	f.changedDirsLock.Lock()
+	f.changedDirs = make(map[string]bool)
+	f.changedDirsLock.Unlock()
+
+	f.verbosef("Finder daemon answered request in %v\n", time.Since(startTime))
+	return daemonResponse{Db: db, ChangedDirs: changedDirs, ChangedDirsKnown: known}
+}
+
+func (d *Daemon) syncPeriodically() {
+	defer d.waitGroup.Done()
+
+	ticker := time.NewTicker(daemonSyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+			d.finder.lock()
+			err := d.sync(false)
+			d.finder.unlock()
+			if err != nil {
+				d.finder.verbosef("Finder daemon failed to update cache: %v\n", err)
+			}
+		}
+	}
+}
+
+// sync relists the directories that the watcher reported as changed, and starts watching the
+// directories that were added to the node tree.
+// A directory could have changed between being listed and being watched, so the newly watched
+// directories are relisted as well, which can discover more directories to watch.
+// On the first sync every directory is newly watched but was also just loaded, so they are
+// only restatted instead.
+// sync must be called while holding the Finder's lock.
+func (d *Daemon) sync(initial bool) error {
+	changed, unwatched, overflow, err := d.watcher.Changes()
+	if err != nil {
+		return err
+	}
+
+	if overflow {
+		// some changes were lost, so start over by rewatching and relisting every directory
+		d.finder.verbosef("Finder daemon missed filesystem events, relisting all directories\n")
+		for path := range d.watched {
+			d.watcher.Unwatch(path)
+		}
+		d.watched = make(map[string]bool)
+	} else {
+		if !initial && !d.incomplete && len(changed) == 0 && len(unwatched) == 0 {
+			return nil
+		}
+		d.forget(unwatched)
+		d.refresh(changed, true)
+	}
+
+	d.incomplete = true
+	for {
+		added, err := d.watchNewDirs()
+		if err != nil {
+			return err
+		}
+		if len(added) == 0 {
+			break
+		}
+		d.refresh(added, !initial)
+	}
+	d.incomplete = false
+
+	err = d.finder.getErr()
+	d.finder.fsErrs = nil
+	return err
+}
+
+// forget stops watching the given directories and their subdirectories, whose watches still
+// report the old paths if the directory was moved, and records them as removed
+func (d *Daemon) forget(dirs []string) {
+	if len(dirs) == 0 {
+		return
+	}
+	removed := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		removed[dir] = true
+	}
+	for path := range d.watched {
+		for dir := path; ; dir = filepath.Dir(dir) {
+			if removed[dir] {
+				d.watcher.Unwatch(path)
+				delete(d.watched, path)
+				d.finder.recordChange(path)
+				break
+			}
+			if dir == "/" || dir == "." {
+				break
+			}
+		}
+	}
+}
+
+// refresh relists the given directories, or if <force> is false, restats them and relists the
+// ones whose stats changed
+func (d *Daemon) refresh(dirs []string, force bool) {
+	f := d.finder
+
+	// look up every node before any of them is relisted, which can remove nodes from the tree
+	seen := make(map[string]bool, len(dirs))
+	nodes := make([]*pathMap, 0, len(dirs))
+	for _, dir := range dirs {
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		node := f.nodes.GetNode(dir, false)
+		if node != nil && node.ModTime != 0 {
+			nodes = append(nodes, node)
+		}
+	}
+
+	f.threadPool = newThreadPool(f.numDbLoadingThreads)
+	for _, node := range nodes {
+		if force {
+			f.relistDirAsync(node)
+		} else {
+			f.statDirAsync(node)
+		}
+	}
+	f.threadPool.Wait()
+	f.threadPool = nil
+}
+
+// watchNewDirs starts watching the directories in the node tree that aren't watched yet, and
+// returns them
+func (d *Daemon) watchNewDirs() (added []string, err error) {
+	var walk func(node *pathMap) error
+	walk = func(node *pathMap) error {
+		if node.ModTime != 0 && !d.watched[node.path] {
+			err := d.watcher.Watch(node.path)
+			if os.IsNotExist(err) {
+				// the directory was just removed, relisting its parent will remove it from the tree
+				return nil
+			}
+			if err != nil && !os.IsPermission(err) {
+				return err
+			}
+			// an unreadable directory is treated as empty, so there are no changes to watch
+			d.watched[node.path] = true
+			added = append(added, node.path)
+		}
+		for _, child := range node.children {
+			if err := walk(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	err = walk(&d.finder.nodes)
+	return added, err
+}
@@ -0,0 +1,281 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finder
+
+import (
+	"errors"
+	"io/ioutil"
+	"log"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+
+	"android/soong/finder/fs"
+)
+
+// a fakeDirWatcher reports the changes that the test tells it about
+type fakeDirWatcher struct {
+	mutex     sync.Mutex
+	watched   map[string]bool
+	changed   []string
+	unwatched []string
+	overflow  bool
+}
+
+func newFakeDirWatcher() *fakeDirWatcher {
+	return &fakeDirWatcher{watched: make(map[string]bool)}
+}
+
+func (w *fakeDirWatcher) Watch(path string) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.watched[path] = true
+	return nil
+}
+
+func (w *fakeDirWatcher) Unwatch(path string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	delete(w.watched, path)
+}
+
+func (w *fakeDirWatcher) Changes() (changed []string, unwatched []string, overflow bool, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	changed, unwatched, overflow = w.changed, w.unwatched, w.overflow
+	w.changed, w.unwatched, w.overflow = nil, nil, false
+	return changed, unwatched, overflow, nil
+}
+
+func (w *fakeDirWatcher) Close() error {
+	return nil
+}
+
+// change reports that the directories changed
+func (w *fakeDirWatcher) change(dirs ...string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.changed = append(w.changed, dirs...)
+}
+
+// lose reports that changes were lost
+func (w *fakeDirWatcher) lose() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.overflow = true
+}
+
+// remove reports that the directories are no longer watched
+func (w *fakeDirWatcher) remove(dirs ...string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, dir := range dirs {
+		delete(w.watched, dir)
+	}
+	w.unwatched = append(w.unwatched, dirs...)
+}
+
+func (w *fakeDirWatcher) watchedDirs() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	dirs := []string{}
+	for dir := range w.watched {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+func newDaemon(t *testing.T, filesystem *fs.MockFs, cacheParams CacheParams) (*Daemon, *fakeDirWatcher) {
+	filesystem.MkDirs("/finder")
+	if cacheParams.WorkingDirectory == "" {
+		cacheParams.WorkingDirectory = "/cwd"
+	}
+	watcher := newFakeDirWatcher()
+	logger := log.New(ioutil.Discard, "", 0)
+	d, err := newDaemonImpl(cacheParams, filesystem, logger, "/finder/finder-db", 2, watcher)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return d, watcher
+}
+
+// finderFromDaemon creates a Finder that loads its cache from the Daemon
+func finderFromDaemon(t *testing.T, d *Daemon, filesystem *fs.MockFs, cacheParams CacheParams) *Finder {
+	if cacheParams.WorkingDirectory == "" {
+		cacheParams.WorkingDirectory = "/cwd"
+	}
+	dial := func() (net.Conn, error) {
+		client, server := net.Pipe()
+		go d.serveConn(server)
+		return client, nil
+	}
+	logger := log.New(ioutil.Discard, "", 0)
+	f, err := newImplWithDaemon(cacheParams, filesystem, logger, "/finder/finder-db", 2, dial)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return f
+}
+
+func TestDaemon(t *testing.T) {
+	// setup filesystem
+	filesystem := newFs()
+	fs.Create(t, "/tmp/a/findme.txt", filesystem)
+	fs.Create(t, "/tmp/b/ignoreme.txt", filesystem)
+	fs.Create(t, "/tmp/b/c/findme.txt", filesystem)
+	cacheParams := CacheParams{
+		RootDirs:     []string{"/tmp"},
+		IncludeFiles: []string{"findme.txt"},
+	}
+
+	// start the daemon
+	d, watcher := newDaemon(t, filesystem, cacheParams)
+	defer d.Close()
+	fs.AssertSameResponse(t, watcher.watchedDirs(), []string{"/tmp", "/tmp/a", "/tmp/b", "/tmp/b/c"})
+	filesystem.ClearMetrics()
+
+	// a finder using the daemon doesn't touch the filesystem
+	finder := finderFromDaemon(t, d, filesystem, cacheParams)
+	foundPaths := finder.FindNamedAt("/tmp", "findme.txt")
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths, []string{"/tmp/a/findme.txt", "/tmp/b/c/findme.txt"})
+	fs.AssertSameStatCalls(t, filesystem.StatCalls, []string{})
+	fs.AssertSameReadDirCalls(t, filesystem.ReadDirCalls, []string{})
+	// the daemon didn't start from a db, so it can't tell what changed
+	if _, ok := finder.ChangedDirs(); ok {
+		t.Errorf("want unknown changes from a daemon that didn't load a db")
+	}
+
+	// add a file and a directory, within the same tick of the clock
+	fs.Create(t, "/tmp/b/findme.txt", filesystem)
+	fs.Create(t, "/tmp/d/e/findme.txt", filesystem)
+	watcher.change("/tmp/b", "/tmp")
+	filesystem.ClearMetrics()
+
+	// the daemon relists the changed directories, and relists the new ones after watching them
+	finder = finderFromDaemon(t, d, filesystem, cacheParams)
+	foundPaths = finder.FindNamedAt("/tmp", "findme.txt")
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths,
+		[]string{"/tmp/a/findme.txt", "/tmp/b/c/findme.txt", "/tmp/b/findme.txt", "/tmp/d/e/findme.txt"})
+	fs.AssertSameReadDirCalls(t, filesystem.ReadDirCalls,
+		[]string{"/tmp", "/tmp/b", "/tmp/d", "/tmp/d", "/tmp/d/e", "/tmp/d/e"})
+	fs.AssertSameResponse(t, watcher.watchedDirs(),
+		[]string{"/tmp", "/tmp/a", "/tmp/b", "/tmp/b/c", "/tmp/d", "/tmp/d/e"})
+	assertChangedDirs(t, finder, []string{"/tmp", "/tmp/b", "/tmp/d", "/tmp/d/e"})
+
+	// nothing changed
+	finder = finderFromDaemon(t, d, filesystem, cacheParams)
+	finder.Shutdown()
+	assertChangedDirs(t, finder, []string{})
+
+	// remove a directory
+	fs.RemoveAll(t, "/tmp/b", filesystem)
+	watcher.change("/tmp")
+	watcher.remove("/tmp/b/c", "/tmp/b")
+
+	finder = finderFromDaemon(t, d, filesystem, cacheParams)
+	foundPaths = finder.FindNamedAt("/tmp", "findme.txt")
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths, []string{"/tmp/a/findme.txt", "/tmp/d/e/findme.txt"})
+	fs.AssertSameResponse(t, watcher.watchedDirs(), []string{"/tmp", "/tmp/a", "/tmp/d", "/tmp/d/e"})
+	assertChangedDirs(t, finder, []string{"/tmp", "/tmp/b", "/tmp/b/c"})
+
+	// move a directory, which only reports the moved directory as unwatched
+	filesystem.Clock.Tick()
+	fs.Move(t, "/tmp/d", "/tmp/f", filesystem)
+	watcher.change("/tmp")
+	watcher.remove("/tmp/d")
+
+	finder = finderFromDaemon(t, d, filesystem, cacheParams)
+	foundPaths = finder.FindNamedAt("/tmp", "findme.txt")
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths, []string{"/tmp/a/findme.txt", "/tmp/f/e/findme.txt"})
+	fs.AssertSameResponse(t, watcher.watchedDirs(), []string{"/tmp", "/tmp/a", "/tmp/f", "/tmp/f/e"})
+	assertChangedDirs(t, finder, []string{"/tmp", "/tmp/d", "/tmp/d/e", "/tmp/f", "/tmp/f/e"})
+}
+
+func assertChangedDirs(t *testing.T, finder *Finder, expected []string) {
+	t.Helper()
+	changedDirs, ok := finder.ChangedDirs()
+	if !ok {
+		t.Fatalf("want known changes from the daemon")
+	}
+	fs.AssertSameResponse(t, changedDirs, expected)
+}
+
+func TestDaemonOverflow(t *testing.T) {
+	// setup filesystem
+	filesystem := newFs()
+	fs.Create(t, "/tmp/a/findme.txt", filesystem)
+	cacheParams := CacheParams{
+		RootDirs:     []string{"/tmp"},
+		IncludeFiles: []string{"findme.txt"},
+	}
+	d, watcher := newDaemon(t, filesystem, cacheParams)
+	defer d.Close()
+
+	// changes that the watcher lost are found by relisting every directory
+	fs.Create(t, "/tmp/a/b/findme.txt", filesystem)
+	watcher.lose()
+	filesystem.ClearMetrics()
+
+	finder := finderFromDaemon(t, d, filesystem, cacheParams)
+	foundPaths := finder.FindNamedAt("/tmp", "findme.txt")
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths, []string{"/tmp/a/b/findme.txt", "/tmp/a/findme.txt"})
+	fs.AssertSameReadDirCalls(t, filesystem.ReadDirCalls, []string{"/tmp", "/tmp/a", "/tmp/a/b", "/tmp/a/b"})
+	fs.AssertSameResponse(t, watcher.watchedDirs(), []string{"/tmp", "/tmp/a", "/tmp/a/b"})
+}
+
+func TestDaemonFallback(t *testing.T) {
+	// setup filesystem
+	filesystem := newFs()
+	fs.Create(t, "/tmp/a/findme.txt", filesystem)
+	fs.Create(t, "/tmp/a/skipme.txt", filesystem)
+	cacheParams := CacheParams{
+		RootDirs:     []string{"/tmp"},
+		IncludeFiles: []string{"findme.txt"},
+	}
+	d, _ := newDaemon(t, filesystem, cacheParams)
+	defer d.Close()
+
+	// the daemon isn't running
+	logger := log.New(ioutil.Discard, "", 0)
+	dial := func() (net.Conn, error) {
+		return nil, errors.New("connection refused")
+	}
+	finder, err := newImplWithDaemon(CacheParams{WorkingDirectory: "/cwd", RootDirs: []string{"/tmp"},
+		IncludeFiles: []string{"findme.txt"}}, filesystem, logger, "/finder/finder-db", 2, dial)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	foundPaths := finder.FindAll()
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths, []string{"/tmp/a/findme.txt"})
+
+	// the daemon was started with other params
+	filesystem.ClearMetrics()
+	finder = finderFromDaemon(t, d, filesystem, CacheParams{
+		RootDirs:     []string{"/tmp"},
+		IncludeFiles: []string{"findme.txt", "skipme.txt"},
+	})
+	foundPaths = finder.FindAll()
+	finder.Shutdown()
+	fs.AssertSameResponse(t, foundPaths, []string{"/tmp/a/findme.txt", "/tmp/a/skipme.txt"})
+	fs.AssertSameReadDirCalls(t, filesystem.ReadDirCalls, []string{"/tmp", "/tmp/a"})
+}
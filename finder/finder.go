@@ -80,7 +80,8 @@ import (
 // 4. The parsing of the db and the initial setup of the pathMap tree must complete before
 //      beginning to call listDirSync (because listDirSync can create new entries in the pathMap)
 
-// see cmd/finder.go or finder_test.go for usage examples
+// see cmd/finder.go or finder_test.go for usage examples, and daemon.go for keeping the cache
+// up to date across executions
 
 // Update versionString whenever making a backwards-incompatible change to the cache file format
 const versionString = "Android finder version 1"
@@ -157,6 +158,11 @@ type Finder struct {
 	// non-temporary state
 	modifiedFlag int32
 	nodes        pathMap
+
+	// the directories that were added, removed or relisted since the db was last dumped, or nil
+	// if there was no db to compare against
+	changedDirs     map[string]bool
+	changedDirsLock sync.Mutex
 }
 
 var defaultNumThreads = runtime.NumCPU() * 2
@@ -170,6 +176,20 @@ func New(cacheParams CacheParams, filesystem fs.FileSystem,
 // newImpl is like New but accepts more params
 func newImpl(cacheParams CacheParams, filesystem fs.FileSystem,
 	logger Logger, dbPath string, numThreads int) (f *Finder, err error) {
+	f = newUnloaded(cacheParams, filesystem, logger, dbPath, numThreads)
+
+	f.loadFromFilesystem()
+
+	err = f.checkLoaded()
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// newUnloaded creates a Finder with an empty cache
+func newUnloaded(cacheParams CacheParams, filesystem fs.FileSystem,
+	logger Logger, dbPath string, numThreads int) *Finder {
 	numDbLoadingThreads := numThreads
 	numSearchingThreads := numThreads
 
@@ -181,7 +201,7 @@ func newImpl(cacheParams CacheParams, filesystem fs.FileSystem,
 		},
 	}
 
-	f = &Finder{
+	return &Finder{
 		numDbLoadingThreads: numDbLoadingThreads,
 		numSearchingThreads: numSearchingThreads,
 		cacheMetadata:       metadata,
@@ -193,27 +213,28 @@ func newImpl(cacheParams CacheParams, filesystem fs.FileSystem,
 
 		shutdownWaitgroup: sync.WaitGroup{},
 	}
+}
 
-	f.loadFromFilesystem()
-
+// checkLoaded returns an error if loading the cache failed
+func (f *Finder) checkLoaded() error {
 	// check for any filesystem errors
-	err = f.getErr()
+	err := f.getErr()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// confirm that every path mentioned in the CacheConfig exists
-	for _, path := range cacheParams.RootDirs {
+	for _, path := range f.cacheMetadata.Config.RootDirs {
 		if !filepath.IsAbs(path) {
 			path = filepath.Join(f.cacheMetadata.Config.WorkingDirectory, path)
 		}
 		node := f.nodes.GetNode(filepath.Clean(path), false)
 		if node == nil || node.ModTime == 0 {
-			return nil, fmt.Errorf("path %v was specified to be included in the cache but does not exist\n", path)
+			return fmt.Errorf("path %v was specified to be included in the cache but does not exist\n", path)
 		}
 	}
 
-	return f, nil
+	return nil
 }
 
 // FindNamed searches for every cached file
@@ -322,6 +343,23 @@ func (f *Finder) FindMatching(rootPath string, filter WalkFunc) []string {
 	return results
 }
 
+// ChangedDirs returns the directories that were added, removed or relisted since the db was
+// last dumped, which may have invalidated the results of searches done before then.
+// ok is false if the Finder didn't load a db, in which case every directory may have changed.
+func (f *Finder) ChangedDirs() (dirs []string, ok bool) {
+	f.changedDirsLock.Lock()
+	defer f.changedDirsLock.Unlock()
+	if f.changedDirs == nil {
+		return nil, false
+	}
+	dirs = make([]string, 0, len(f.changedDirs))
+	for dir := range f.changedDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	return dirs, true
+}
+
 // Shutdown declares that the finder is no longer needed and waits for its cleanup to complete
 // Currently, that only entails waiting for the database dump to complete.
 func (f *Finder) Shutdown() {
@@ -373,8 +411,11 @@ func (f *Finder) verbosef(format string, args ...interface{}) {
 func (f *Finder) loadFromFilesystem() {
 	f.threadPool = newThreadPool(f.numDbLoadingThreads)
 
+	f.changedDirs = make(map[string]bool)
 	err := f.startFromExternalCache()
 	if err != nil {
+		// every directory is new, so there's no point in recording them
+		f.changedDirs = nil
 		f.startWithoutExternalCache()
 	}
 
@@ -818,9 +859,14 @@ func (f *Finder) loadBytes(id int, data []byte) (m *pathMap, dirsToWalk []string
 		// make a note to walk it later
 		if !f.isInfoUpToDate(cachedNode.statResponse, updated) && updated.ModTime != 0 {
 			f.setModified()
+			f.recordChange(cachedNode.Path)
 			// make a note that the directory needs to be walked
 			dirsToWalk = append(dirsToWalk, cachedNode.Path)
 		} else {
+			if cachedNode.ModTime != 0 && updated.ModTime == 0 {
+				// the directory was removed
+				f.recordChange(cachedNode.Path)
+			}
 			container.mapNode.FileNames = cachedNode.FileNames
 		}
 	}
@@ -1061,6 +1107,15 @@ func (f *Finder) setModified() {
 	atomic.StoreInt32(&f.modifiedFlag, newVal)
 }
 
+// recordChange notes that the directory at <path> was added, removed or relisted
+func (f *Finder) recordChange(path string) {
+	f.changedDirsLock.Lock()
+	defer f.changedDirsLock.Unlock()
+	if f.changedDirs != nil {
+		f.changedDirs[path] = true
+	}
+}
+
 // sortedDirEntries exports directory entries to facilitate dumping them to the external cache
 func (f *Finder) sortedDirEntries() []dirFullInfo {
 	startTime := time.Now()
@@ -1260,6 +1315,7 @@ func (f *Finder) statDirAsync(dir *pathMap) {
 					FileNames:    []string{},
 				}
 				f.setModified()
+				f.recordChange(path)
 				if node.statResponse.ModTime != 0 {
 					// modification time was updated, so re-scan for
 					// child directories
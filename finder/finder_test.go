@@ -1016,6 +1016,59 @@ func TestDirectoriesMoved(t *testing.T) {
 	finder2.Shutdown()
 }
 
+func TestChangedDirs(t *testing.T) {
+	// setup filesystem
+	filesystem := newFs()
+	fs.Create(t, "/tmp/findme.txt", filesystem)
+	fs.Create(t, "/tmp/a/findme.txt", filesystem)
+	fs.Create(t, "/tmp/a/1/findme.txt", filesystem)
+	fs.Create(t, "/tmp/b/findme.txt", filesystem)
+
+	// run the first finder, which has no db to compare against
+	finder := newFinder(
+		t,
+		filesystem,
+		CacheParams{
+			RootDirs:     []string{"/tmp"},
+			IncludeFiles: []string{"findme.txt"},
+		},
+	)
+	finder.Shutdown()
+	if _, ok := finder.ChangedDirs(); ok {
+		t.Errorf("want unknown changes without a db")
+	}
+
+	// nothing changed
+	finder2 := finderWithSameParams(t, finder)
+	finder2.Shutdown()
+	changedDirs, ok := finder2.ChangedDirs()
+	if !ok {
+		t.Fatalf("want known changes with a db")
+	}
+	fs.AssertSameResponse(t, changedDirs, []string{})
+
+	// modify the filesystem
+	filesystem.Clock.Tick()
+	fs.Move(t, "/tmp/a", "/tmp/c", filesystem)
+	fs.Create(t, "/tmp/b/2/findme.txt", filesystem)
+
+	// run the third finder
+	finder3 := finderWithSameParams(t, finder2)
+	finder3.Shutdown()
+	changedDirs, ok = finder3.ChangedDirs()
+	if !ok {
+		t.Fatalf("want known changes with a db")
+	}
+	fs.AssertSameResponse(t, changedDirs,
+		[]string{"/tmp", "/tmp/a", "/tmp/a/1", "/tmp/b", "/tmp/b/2", "/tmp/c", "/tmp/c/1"})
+
+	// the changes are relative to the db that the third finder dumped
+	finder4 := finderWithSameParams(t, finder3)
+	finder4.Shutdown()
+	changedDirs, _ = finder4.ChangedDirs()
+	fs.AssertSameResponse(t, changedDirs, []string{})
+}
+
 func TestDirectoriesSwapped(t *testing.T) {
 	// setup filesystem
 	filesystem := newFs()
@@ -0,0 +1,128 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finder
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// The events that change the entries or the stats of a directory
+const inotifyWatchMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM |
+	syscall.IN_MOVED_TO | syscall.IN_ATTRIB | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF |
+	syscall.IN_ONLYDIR | syscall.IN_DONT_FOLLOW
+
+// an inotifyWatcher is a dirWatcher that uses one inotify watch per directory
+type inotifyWatcher struct {
+	fd    int
+	buf   []byte
+	paths map[int32]string
+	wds   map[string]int32
+}
+
+func newDirWatcher() (dirWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	return &inotifyWatcher{
+		fd:    fd,
+		buf:   make([]byte, 64*1024),
+		paths: make(map[int32]string),
+		wds:   make(map[string]int32),
+	}, nil
+}
+
+func (w *inotifyWatcher) Watch(path string) error {
+	wd, err := syscall.InotifyAddWatch(w.fd, path, inotifyWatchMask)
+	if err == syscall.ENOSPC {
+		return fmt.Errorf("failed to watch %v: out of inotify watches, "+
+			"increase the fs.inotify.max_user_watches sysctl", path)
+	}
+	if err == syscall.ENOTDIR {
+		// the directory was replaced by a file
+		err = syscall.ENOENT
+	}
+	if err != nil {
+		return &os.PathError{Op: "inotify_add_watch", Path: path, Err: err}
+	}
+	// adding a watch to an already watched directory returns the same descriptor, which happens
+	// when the directory was moved
+	if oldPath, found := w.paths[int32(wd)]; found {
+		delete(w.wds, oldPath)
+	}
+	w.paths[int32(wd)] = path
+	w.wds[path] = int32(wd)
+	return nil
+}
+
+func (w *inotifyWatcher) Unwatch(path string) {
+	wd, found := w.wds[path]
+	if !found {
+		return
+	}
+	delete(w.wds, path)
+	delete(w.paths, wd)
+	// fails if the watch was already removed by the kernel
+	syscall.InotifyRmWatch(w.fd, uint32(wd))
+}
+
+func (w *inotifyWatcher) Changes() (changed []string, unwatched []string, overflow bool, err error) {
+	for {
+		n, err := syscall.Read(w.fd, w.buf)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err == syscall.EAGAIN {
+			return changed, unwatched, overflow, nil
+		}
+		if err != nil {
+			return nil, nil, false, os.NewSyscallError("read", err)
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&w.buf[offset]))
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				overflow = true
+				continue
+			}
+			path, found := w.paths[event.Wd]
+			if !found {
+				continue
+			}
+			switch {
+			case event.Mask&syscall.IN_IGNORED != 0:
+				// the directory was removed
+				delete(w.paths, event.Wd)
+				delete(w.wds, path)
+				unwatched = append(unwatched, path)
+			case event.Mask&syscall.IN_MOVE_SELF != 0:
+				// the watch follows the directory to its new path, which is unknown
+				w.Unwatch(path)
+				unwatched = append(unwatched, path)
+			default:
+				changed = append(changed, path)
+			}
+		}
+	}
+}
+
+func (w *inotifyWatcher) Close() error {
+	return syscall.Close(w.fd)
+}
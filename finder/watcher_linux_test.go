@@ -0,0 +1,110 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package finder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"android/soong/finder/fs"
+)
+
+func TestInotifyWatcher(t *testing.T) {
+	root, err := ioutil.TempDir("", "finder_watcher_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	watcher, err := newDirWatcher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+	for _, dir := range []string{root, sub} {
+		if err := watcher.Watch(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changes := func() (changed, unwatched []string) {
+		t.Helper()
+		changed, unwatched, overflow, err := watcher.Changes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if overflow {
+			t.Fatal("unexpected overflow")
+		}
+		return dedupe(changed), dedupe(unwatched)
+	}
+
+	// no changes
+	changed, unwatched := changes()
+	fs.AssertSameResponse(t, changed, []string{})
+	fs.AssertSameResponse(t, unwatched, []string{})
+
+	// a file is created
+	if err := ioutil.WriteFile(filepath.Join(sub, "Android.bp"), nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	changed, unwatched = changes()
+	fs.AssertSameResponse(t, changed, []string{sub})
+	fs.AssertSameResponse(t, unwatched, []string{})
+
+	// a directory is moved
+	moved := filepath.Join(root, "moved")
+	if err := os.Rename(sub, moved); err != nil {
+		t.Fatal(err)
+	}
+	changed, unwatched = changes()
+	fs.AssertSameResponse(t, changed, []string{root})
+	fs.AssertSameResponse(t, unwatched, []string{sub})
+
+	// a directory is removed
+	if err := watcher.Watch(moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(moved); err != nil {
+		t.Fatal(err)
+	}
+	changed, unwatched = changes()
+	fs.AssertSameResponse(t, changed, []string{moved, root})
+	fs.AssertSameResponse(t, unwatched, []string{moved})
+
+	// a directory that doesn't exist
+	if err := watcher.Watch(moved); !os.IsNotExist(err) {
+		t.Errorf("want a not exist error when watching a removed directory, got %v", err)
+	}
+}
+
+func dedupe(list []string) []string {
+	seen := make(map[string]bool)
+	ret := []string{}
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			ret = append(ret, s)
+		}
+	}
+	return ret
+}
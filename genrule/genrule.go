@@ -40,6 +40,8 @@ func registerGenruleBuildComponents(ctx android.RegistrationContext) {
 	ctx.RegisterModuleType("gensrcs", GenSrcsFactory)
 	ctx.RegisterModuleType("genrule", GenRuleFactory)
 
+	ctx.RegisterSingletonType("undeclared_inputs", undeclaredInputsSingletonFactory)
+
 	ctx.FinalDepsMutators(func(ctx android.RegisterMutatorsContext) {
 		ctx.BottomUp("genrule_tool_deps", toolDepsMutator).Parallel()
 	})
@@ -113,6 +115,12 @@ type generatorProperties struct {
 
 	// input files to exclude
 	Exclude_srcs []string `android:"path,arch_variant"`
+
+	// Run the command in a hermetic sandbox that only contains the srcs, tools and tool_files, and
+	// fail if the command tries to access any other file in the source or output tree.  Tools that
+	// load other files, such as shared libraries, can't be used in the sandbox.  Only supported on
+	// Linux.
+	Sandbox_inputs *bool
 }
 
 type Module struct {
@@ -138,6 +146,10 @@ type Module struct {
 	outputFiles android.Paths
 	outputDeps  android.Paths
 
+	// The reports of the inputs that the commands accessed but didn't declare, if
+	// SOONG_TRACE_GENRULE_INPUTS is set.
+	undeclaredInputsReports android.WritablePaths
+
 	subName string
 	subDir  string
 
@@ -156,6 +168,8 @@ type generateTask struct {
 	cmd         string
 	shard       int
 	shards      int
+
+	undeclaredInputsReport android.WritablePath
 }
 
 func (g *Module) GeneratedSourceFiles() android.Paths {
@@ -411,6 +425,28 @@ func (g *Module) GenerateAndroidBuildActions(ctx android.ModuleContext) {
 			sandboxCommand = sandboxCommand + hashSrcFiles(srcFiles)
 		}
 
+		if Bool(g.properties.Sandbox_inputs) {
+			sandboxCommand = sandboxCommand + " --hermetic"
+		} else if ctx.Config().IsEnvTrue("SOONG_TRACE_GENRULE_INPUTS") {
+			task.undeclaredInputsReport = undeclaredInputsReportPath(ctx, task)
+			g.undeclaredInputsReports = append(g.undeclaredInputsReports, task.undeclaredInputsReport)
+			sandboxCommand = sandboxCommand + fmt.Sprintf(" --trace-inputs %s --trace-inputs-label //%s:%s",
+				task.undeclaredInputsReport, ctx.ModuleDir(), ctx.ModuleName())
+		}
+
+		// The traced commands aren't cached, their reports wouldn't be written when their outputs are
+		// restored from the cache.  Neither are the commands with depfiles, which list inputs that
+		// aren't declared.
+		useCache := ctx.Config().SboxCache() && task.undeclaredInputsReport == nil && !Bool(g.properties.Depfile)
+		if useCache {
+			sandboxCommand = sandboxCommand + " --cache-dir " + shared.SboxCacheDirForOutDir(buildDir)
+		}
+
+		if Bool(g.properties.Sandbox_inputs) || task.undeclaredInputsReport != nil || useCache {
+			sandboxInputs := append(task.in.Strings(), g.deps.Strings()...)
+			sandboxCommand = sandboxCommand + android.JoinWithPrefix(sandboxInputs, " --input ")
+		}
+
 		sandboxCommand = sandboxCommand + fmt.Sprintf(" -c %s %s $allouts",
 			rawCommand, depfilePlaceholder)
 
@@ -503,11 +539,16 @@ func (g *Module) generateSourceFile(ctx android.ModuleContext, task generateTask
 		desc += " " + strconv.Itoa(task.shard)
 	}
 
+	implicitOutputs := append(android.WritablePaths(nil), task.out[1:]...)
+	if task.undeclaredInputsReport != nil {
+		implicitOutputs = append(implicitOutputs, task.undeclaredInputsReport)
+	}
+
 	params := android.BuildParams{
 		Rule:            rule,
 		Description:     desc,
 		Output:          task.out[0],
-		ImplicitOutputs: task.out[1:],
+		ImplicitOutputs: implicitOutputs,
 		Inputs:          task.in,
 		Implicits:       g.deps,
 		Args: map[string]string{
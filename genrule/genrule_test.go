@@ -66,6 +66,10 @@ func testContext(config android.Config) *android.TestContext {
 }
 
 func testConfig(bp string, fs map[string][]byte) android.Config {
+	return testConfigWithEnv(bp, nil, fs)
+}
+
+func testConfigWithEnv(bp string, env map[string]string, fs map[string][]byte) android.Config {
 	bp += `
 		tool {
 			name: "tool",
@@ -121,7 +125,7 @@ func testConfig(bp string, fs map[string][]byte) android.Config {
 		mockFS[k] = v
 	}
 
-	return android.TestArchConfig(buildDir, nil, bp, mockFS)
+	return android.TestArchConfig(buildDir, env, bp, mockFS)
 }
 
 func TestGenruleCmd(t *testing.T) {
@@ -589,6 +593,128 @@ func TestGenruleHashInputs(t *testing.T) {
 	}
 }
 
+func TestGenruleSandboxInputs(t *testing.T) {
+	bp := `
+		genrule {
+			name: "gen",
+			tools: ["tool"],
+			tool_files: ["tool_file1"],
+			srcs: ["in1.txt", "in2.txt"],
+			out: ["out"],
+			cmd: "$(location) $(in) > $(out)",
+			sandbox_inputs: true,
+		}
+	`
+
+	config := testConfig(bp, nil)
+	ctx := testContext(config)
+	_, errs := ctx.ParseFileList(".", []string{"Android.bp"})
+	if errs == nil {
+		_, errs = ctx.PrepareBuildActions(config)
+	}
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	command := ctx.ModuleForTests("gen", "").Rule("generator").RuleParams.Command
+	expected := " --hermetic --input in1.txt --input in2.txt --input out/tool --input tool_file1 "
+	if !strings.Contains(command, expected) {
+		t.Errorf("Expected command %q to contain %q", command, expected)
+	}
+}
+
+func TestGenruleSboxCache(t *testing.T) {
+	bp := `
+		genrule {
+			name: "gen",
+			tools: ["tool"],
+			srcs: ["in1.txt"],
+			out: ["out"],
+			cmd: "$(location) $(in) > $(out)",
+		}
+		genrule {
+			name: "gen_depfile",
+			tools: ["tool"],
+			srcs: ["in1.txt"],
+			out: ["out"],
+			depfile: true,
+			cmd: "$(location) $(in) > $(out) && echo foo > $(depfile)",
+		}
+	`
+
+	config := testConfigWithEnv(bp, map[string]string{"SOONG_SBOX_CACHE": "true"}, nil)
+	ctx := testContext(config)
+	_, errs := ctx.ParseFileList(".", []string{"Android.bp"})
+	if errs == nil {
+		_, errs = ctx.PrepareBuildActions(config)
+	}
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	command := ctx.ModuleForTests("gen", "").Rule("generator").RuleParams.Command
+	expected := " --cache-dir " + buildDir + "/.sbox_cache --input in1.txt --input out/tool "
+	if !strings.Contains(command, expected) {
+		t.Errorf("Expected command %q to contain %q", command, expected)
+	}
+
+	command = ctx.ModuleForTests("gen_depfile", "").Rule("generator").RuleParams.Command
+	if strings.Contains(command, "--cache-dir") {
+		t.Errorf("Expected command %q of the genrule with a depfile not to use the cache", command)
+	}
+}
+
+func TestGenruleTraceInputs(t *testing.T) {
+	bp := `
+		genrule {
+			name: "gen",
+			tools: ["tool"],
+			srcs: ["in1.txt"],
+			out: ["out"],
+			cmd: "$(location) $(in) > $(out)",
+		}
+		genrule {
+			name: "gen_sandboxed",
+			srcs: ["in2.txt"],
+			out: ["out"],
+			cmd: "cat $(in) > $(out)",
+			sandbox_inputs: true,
+		}
+	`
+
+	config := testConfigWithEnv(bp, map[string]string{"SOONG_TRACE_GENRULE_INPUTS": "true"}, nil)
+	ctx := testContext(config)
+	_, errs := ctx.ParseFileList(".", []string{"Android.bp"})
+	if errs == nil {
+		_, errs = ctx.PrepareBuildActions(config)
+	}
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	report := buildDir + "/.intermediates/gen/undeclared_inputs.json"
+	gen := ctx.ModuleForTests("gen", "").Output("undeclared_inputs.json")
+	if g, w := gen.ImplicitOutputs.Strings(), []string{report}; !reflect.DeepEqual(g, w) {
+		t.Errorf("Expected implicit outputs %q, got %q", w, g)
+	}
+	command := gen.RuleParams.Command
+	expected := " --trace-inputs " + report + " --trace-inputs-label //:gen --input in1.txt --input out/tool "
+	if !strings.Contains(command, expected) {
+		t.Errorf("Expected command %q to contain %q", command, expected)
+	}
+
+	// Genrules in the hermetic sandbox aren't traced.
+	sandboxedCommand := ctx.ModuleForTests("gen_sandboxed", "").Rule("generator").RuleParams.Command
+	if strings.Contains(sandboxedCommand, "--trace-inputs") {
+		t.Errorf("Unexpected \"--trace-inputs\" found in command: %q", sandboxedCommand)
+	}
+
+	merged := ctx.SingletonForTests("undeclared_inputs").Output("undeclared_inputs.json")
+	if g, w := merged.Inputs.Strings(), []string{report}; !reflect.DeepEqual(g, w) {
+		t.Errorf("Expected merged reports %q, got %q", w, g)
+	}
+}
+
 func TestGenSrcs(t *testing.T) {
 	testcases := []struct {
 		name string
@@ -0,0 +1,72 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package genrule
+
+// This file contains the undeclared inputs report of the genrules. When SOONG_TRACE_GENRULE_INPUTS
+// is set, sbox traces the commands of the genrules that don't run in the hermetic sandbox and writes
+// the files that they accessed without declaring them as srcs, tools or tool_files to a report for
+// each command. The undeclared_inputs singleton merges the reports into
+// out/soong/undeclared_inputs.json to find the genrules that would break in the hermetic sandbox.
+
+import (
+	"strconv"
+
+	"github.com/google/blueprint"
+
+	"android/soong/android"
+)
+
+var mergeUndeclaredInputs = pctx.AndroidStaticRule("mergeUndeclaredInputs",
+	blueprint.RuleParams{
+		// Each report is a JSON object on a single line.
+		Command:        `(echo '{"commands": ['; xargs cat < $out.rsp | paste -s -d , -; echo ']}') > $out`,
+		Rspfile:        "$out.rsp",
+		RspfileContent: "$in",
+	})
+
+func undeclaredInputsReportPath(ctx android.ModuleContext, task generateTask) android.WritablePath {
+	name := "undeclared_inputs"
+	if task.shards > 1 {
+		name += strconv.Itoa(task.shard)
+	}
+	return android.PathForModuleOut(ctx, name+".json")
+}
+
+func undeclaredInputsSingletonFactory() android.Singleton {
+	return &undeclaredInputsSingleton{}
+}
+
+type undeclaredInputsSingleton struct{}
+
+func (s *undeclaredInputsSingleton) GenerateBuildActions(ctx android.SingletonContext) {
+	var reports android.Paths
+	ctx.VisitAllModules(func(module android.Module) {
+		if g, ok := module.(*Module); ok {
+			reports = append(reports, g.undeclaredInputsReports.Paths()...)
+		}
+	})
+	if len(reports) == 0 {
+		return
+	}
+
+	output := android.PathForOutput(ctx, "undeclared_inputs.json")
+	ctx.Build(pctx, android.BuildParams{
+		Rule:        mergeUndeclaredInputs,
+		Description: "merge undeclared inputs reports",
+		Inputs:      reports,
+		Output:      output,
+	})
+	ctx.Phony("undeclared_inputs", output)
+}
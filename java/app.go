@@ -19,6 +19,7 @@ package java
 import (
 	"path/filepath"
 	"reflect"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -111,10 +112,11 @@ func (as *AndroidAppSet) APKCertsFile() android.Path {
 }
 
 var TargetCpuAbi = map[string]string{
-	"arm":    "ARMEABI_V7A",
-	"arm64":  "ARM64_V8A",
-	"x86":    "X86",
-	"x86_64": "X86_64",
+	"arm":     "ARMEABI_V7A",
+	"arm64":   "ARM64_V8A",
+	"riscv64": "RISCV64",
+	"x86":     "X86",
+	"x86_64":  "X86_64",
 }
 
 func SupportedAbis(ctx android.ModuleContext) []string {
@@ -133,6 +135,21 @@ func SupportedAbis(ctx android.ModuleContext) []string {
 	return result
 }
 
+// Matches the locales in PRODUCT_AAPT_CONFIG, such as "en", "en_US" or "fr-rCA", as opposed to its
+// screen sizes and densities.
+var aaptConfigLocaleRegexp = regexp.MustCompile(`^[a-z]{2,3}([_-]r?[A-Z]{2})?$`)
+
+// SupportedLocales returns the locales of the device, which are the locales in PRODUCT_AAPT_CONFIG.
+func SupportedLocales(ctx android.ModuleContext) []string {
+	var result []string
+	for _, c := range ctx.Config().ProductAAPTConfig() {
+		if aaptConfigLocaleRegexp.MatchString(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 func (as *AndroidAppSet) GenerateAndroidBuildActions(ctx android.ModuleContext) {
 	as.packedOutput = android.PathForModuleOut(ctx, ctx.ModuleName()+".zip")
 	as.apkcertsFile = android.PathForModuleOut(ctx, "apkcerts.txt")
@@ -144,7 +161,11 @@ func (as *AndroidAppSet) GenerateAndroidBuildActions(ctx android.ModuleContext)
 	if dpis := ctx.Config().ProductAAPTPrebuiltDPI(); len(dpis) > 0 {
 		screenDensities = strings.ToUpper(strings.Join(dpis, ","))
 	}
-	// TODO(asmundak): handle locales.
+	// Without locales in PRODUCT_AAPT_CONFIG the device may be configured for any language.
+	locales := "all"
+	if l := SupportedLocales(ctx); len(l) > 0 {
+		locales = strings.Join(l, ",")
+	}
 	// TODO(asmundak): do we support device features
 	ctx.Build(pctx,
 		android.BuildParams{
@@ -157,6 +178,7 @@ func (as *AndroidAppSet) GenerateAndroidBuildActions(ctx android.ModuleContext)
 				"abis":              strings.Join(SupportedAbis(ctx), ","),
 				"allow-prereleased": strconv.FormatBool(proptools.Bool(as.properties.Prerelease)),
 				"screen-densities":  screenDensities,
+				"locales":           locales,
 				"sdk-version":       ctx.Config().PlatformSdkVersion(),
 				"stem":              as.BaseModuleName(),
 				"apkcerts":          as.apkcertsFile.String(),
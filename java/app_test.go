@@ -180,6 +180,7 @@ func TestAndroidAppSet_Variants(t *testing.T) {
 		deviceArch          *string
 		deviceSecondaryArch *string
 		aaptPrebuiltDPI     []string
+		aaptConfig          []string
 		sdkVersion          int
 		expected            map[string]string
 	}{
@@ -192,6 +193,7 @@ func TestAndroidAppSet_Variants(t *testing.T) {
 				"abis":              "X86",
 				"allow-prereleased": "false",
 				"screen-densities":  "LDPI,XXHDPI",
+				"locales":           "all",
 				"sdk-version":       "29",
 				"stem":              "foo",
 			},
@@ -206,6 +208,21 @@ func TestAndroidAppSet_Variants(t *testing.T) {
 				"abis":              "X86_64,X86",
 				"allow-prereleased": "false",
 				"screen-densities":  "all",
+				"locales":           "all",
+				"sdk-version":       "30",
+				"stem":              "foo",
+			},
+		},
+		{
+			name:       "Locales",
+			deviceArch: proptools.StringPtr("x86"),
+			aaptConfig: []string{"normal", "large", "en_US", "fr", "pt-rBR", "xhdpi"},
+			sdkVersion: 30,
+			expected: map[string]string{
+				"abis":              "X86",
+				"allow-prereleased": "false",
+				"screen-densities":  "all",
+				"locales":           "en_US,fr,pt-rBR",
 				"sdk-version":       "30",
 				"stem":              "foo",
 			},
@@ -215,6 +232,9 @@ func TestAndroidAppSet_Variants(t *testing.T) {
 	for _, test := range testCases {
 		config := testAppConfig(nil, bp, nil)
 		config.TestProductVariables.AAPTPrebuiltDPI = test.aaptPrebuiltDPI
+		if test.aaptConfig != nil {
+			config.TestProductVariables.AAPTConfig = test.aaptConfig
+		}
 		config.TestProductVariables.Platform_sdk_version = &test.sdkVersion
 		config.TestProductVariables.DeviceArch = test.deviceArch
 		config.TestProductVariables.DeviceSecondaryArch = test.deviceSecondaryArch
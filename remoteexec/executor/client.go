@@ -0,0 +1,322 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The exec strategies of the remote execution wrapper, see remoteexec.REParams.
+const (
+	LocalExecStrategy               = "local"
+	RemoteExecStrategy              = "remote"
+	RemoteLocalFallbackExecStrategy = "remote_local_fallback"
+)
+
+// Options are the flags of the remote execution wrapper that are generated by the templates of
+// remoteexec.REParams.
+type Options struct {
+	Labels            map[string]string
+	Platform          map[string]string
+	ExecStrategy      string
+	Inputs            []string
+	InputListPaths    []string
+	OutputFiles       []string
+	OutputDirectories []string
+	ToolchainInputs   []string
+}
+
+// ParseArgs parses the arguments of the remote execution wrapper into the options and the wrapped
+// command, which follows a "--" argument.
+//
+// The list flags are comma separated, but the ninja variables that are expanded into them, such as
+// $in, are space separated. The arguments that follow a list flag and don't start with "-" are
+// added to the list.
+func ParseArgs(args []string) (*Options, []string, error) {
+	opts := &Options{
+		Labels:       make(map[string]string),
+		Platform:     make(map[string]string),
+		ExecStrategy: RemoteExecStrategy,
+	}
+
+	var list *[]string
+	for i, arg := range args {
+		if arg == "--" {
+			if i+1 == len(args) {
+				return nil, nil, fmt.Errorf("missing command after --")
+			}
+			return opts, args[i+1:], nil
+		}
+
+		if !strings.HasPrefix(arg, "-") {
+			if list == nil {
+				return nil, nil, fmt.Errorf("unexpected argument %q", arg)
+			}
+			*list = appendList(*list, arg)
+			continue
+		}
+
+		flag := strings.TrimLeft(arg, "-")
+		eq := strings.IndexByte(flag, '=')
+		if eq == -1 {
+			return nil, nil, fmt.Errorf("flag %q has no value", arg)
+		}
+		name, value := flag[:eq], flag[eq+1:]
+
+		list = nil
+		switch name {
+		case "labels":
+			if err := parseKeyValues(opts.Labels, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid --labels: %s", err)
+			}
+		case "platform":
+			if err := parseKeyValues(opts.Platform, value); err != nil {
+				return nil, nil, fmt.Errorf("invalid --platform: %s", err)
+			}
+		case "exec_strategy":
+			switch value {
+			case LocalExecStrategy, RemoteExecStrategy, RemoteLocalFallbackExecStrategy:
+				opts.ExecStrategy = value
+			default:
+				return nil, nil, fmt.Errorf("unknown --exec_strategy %q", value)
+			}
+		case "inputs":
+			list = &opts.Inputs
+		case "input_list_paths":
+			list = &opts.InputListPaths
+		case "output_files":
+			list = &opts.OutputFiles
+		case "output_directories":
+			list = &opts.OutputDirectories
+		case "toolchain_inputs":
+			list = &opts.ToolchainInputs
+		default:
+			return nil, nil, fmt.Errorf("unknown flag %q", arg)
+		}
+		if list != nil {
+			*list = appendList(*list, value)
+		}
+	}
+	return nil, nil, fmt.Errorf("missing -- before the command")
+}
+
+func appendList(list []string, value string) []string {
+	for _, s := range strings.Split(value, ",") {
+		if s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+func parseKeyValues(m map[string]string, value string) error {
+	for _, kv := range strings.Split(value, ",") {
+		if kv == "" {
+			continue
+		}
+		i := strings.IndexByte(kv, '=')
+		if i == -1 {
+			return fmt.Errorf("%q is not a key=value pair", kv)
+		}
+		m[kv[:i]] = kv[i+1:]
+	}
+	return nil
+}
+
+// Run runs the command with the exec strategy of the options. The paths of the inputs and the
+// outputs are relative to execRoot, which is the working directory of the command. The command is
+// executed by the executor if the exec strategy is remote, and falls back to running locally if it
+// fails with the remote_local_fallback strategy. Run returns the exit code of the command.
+func Run(e Executor, opts *Options, args []string, execRoot string, stdout, stderr io.Writer) (int, error) {
+	if opts.ExecStrategy == LocalExecStrategy {
+		return runLocally(args, execRoot, stdout, stderr)
+	}
+
+	result, err := runRemotely(e, opts, args, execRoot)
+	if opts.ExecStrategy == RemoteLocalFallbackExecStrategy && (err != nil || result.ExitCode != 0) {
+		return runLocally(args, execRoot, stdout, stderr)
+	} else if err != nil {
+		return 0, err
+	}
+
+	if err := download(e, result, opts, execRoot, stdout, stderr); err != nil {
+		return 0, err
+	}
+	return result.ExitCode, nil
+}
+
+func runLocally(args []string, execRoot string, stdout, stderr io.Writer) (int, error) {
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = execRoot
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), nil
+	}
+	return 0, err
+}
+
+// runRemotely uploads the inputs of the command and executes it.
+func runRemotely(e Executor, opts *Options, args []string, execRoot string) (*ActionResult, error) {
+	inputs, blobs, err := collectInputs(opts, execRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var digests []Digest
+	for digest := range blobs {
+		digests = append(digests, digest)
+	}
+	missing, err := e.FindMissingBlobs(digests)
+	if err != nil {
+		return nil, err
+	}
+	for _, digest := range missing {
+		data, err := ioutil.ReadFile(blobs[digest])
+		if err != nil {
+			return nil, err
+		}
+		if err := e.WriteBlob(digest, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return e.Execute(&Action{
+		Args:              args,
+		Platform:          opts.Platform,
+		Inputs:            inputs,
+		OutputFiles:       opts.OutputFiles,
+		OutputDirectories: opts.OutputDirectories,
+	})
+}
+
+// collectInputs returns the input files of the command, including the files in input directories
+// and the files that are listed in the input lists, and the paths of the files by digest.
+func collectInputs(opts *Options, execRoot string) ([]File, map[Digest]string, error) {
+	paths := append(append([]string(nil), opts.Inputs...), opts.ToolchainInputs...)
+	for _, list := range opts.InputListPaths {
+		data, err := ioutil.ReadFile(filepath.Join(execRoot, list))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read input list: %s", err)
+		}
+		paths = append(paths, list)
+		paths = append(paths, strings.Fields(string(data))...)
+	}
+
+	files := make(map[string]File)
+	blobs := make(map[Digest]string)
+	for _, input := range paths {
+		rel, err := execRootRel(execRoot, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = filepath.Walk(filepath.Join(execRoot, rel), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.Mode()&os.ModeSymlink != 0 {
+				// Upload the target of the symlink, the symlink may not be valid on the worker.
+				if info, err = os.Stat(path); err != nil {
+					return err
+				}
+			}
+			if info.IsDir() {
+				return nil
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			digest := NewDigest(data)
+			rel, err := filepath.Rel(execRoot, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			files[rel] = File{Path: rel, Digest: digest, Executable: info.Mode()&0100 != 0}
+			blobs[digest] = path
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read input: %s", err)
+		}
+	}
+
+	var inputs []File
+	for _, file := range files {
+		inputs = append(inputs, file)
+	}
+	sort.Slice(inputs, func(i, j int) bool { return inputs[i].Path < inputs[j].Path })
+	return inputs, blobs, nil
+}
+
+// execRootRel returns the path relative to the exec root.
+func execRootRel(execRoot, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(execRoot, path)
+		if err != nil {
+			return "", err
+		}
+		path = rel
+	}
+	path = filepath.Clean(path)
+	if path == ".." || strings.HasPrefix(path, "../") {
+		return "", fmt.Errorf("input %q is outside of the exec root %q", path, execRoot)
+	}
+	return path, nil
+}
+
+// download writes the outputs, stdout and stderr of an executed command.
+func download(e Executor, result *ActionResult, opts *Options, execRoot string, stdout, stderr io.Writer) error {
+	// Remove the stale files in the output directories.
+	for _, dir := range opts.OutputDirectories {
+		if err := os.RemoveAll(filepath.Join(execRoot, dir)); err != nil {
+			return err
+		}
+	}
+
+	for _, output := range result.OutputFiles {
+		data, err := e.ReadBlob(output.Digest)
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(execRoot, output.Path), data, output.Executable); err != nil {
+			return err
+		}
+	}
+
+	for _, std := range []struct {
+		digest Digest
+		w      io.Writer
+	}{{result.Stdout, stdout}, {result.Stderr, stderr}} {
+		data, err := e.ReadBlob(std.digest)
+		if err != nil {
+			return err
+		}
+		if _, err := std.w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package executor runs the commands that are wrapped by the remote execution templates of the
+// remoteexec package through a pluggable Executor, modeled after the content addressed storage and
+// the execution service of the remote execution API. The LocalServer Executor runs the actions in
+// temporary directories on the local machine, so that the remote execution rules can be tested end
+// to end without a remote execution backend.
+package executor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Digest identifies a blob in the content addressed storage by its SHA-256 hash and its size.
+type Digest struct {
+	Hash string
+	Size int64
+}
+
+// NewDigest returns the digest of the data.
+func NewDigest(data []byte) Digest {
+	h := sha256.Sum256(data)
+	return Digest{Hash: hex.EncodeToString(h[:]), Size: int64(len(data))}
+}
+
+func (d Digest) String() string {
+	return fmt.Sprintf("%s/%d", d.Hash, d.Size)
+}
+
+// File is an input or output file of an action, relative to the root directory of the action.
+type File struct {
+	Path       string
+	Digest     Digest
+	Executable bool
+}
+
+// Action is a command to execute with the files that it reads and writes.
+type Action struct {
+	// Args is the command line of the action. The first argument is the program, which is relative
+	// to the root directory of the action if it contains a slash.
+	Args []string
+
+	// Platform is the key value pairs that select the worker of the action, such as the container
+	// image.
+	Platform map[string]string
+
+	// Inputs is the files that are available to the action. Their contents must be in the content
+	// addressed storage of the Executor before the action is executed.
+	Inputs []File
+
+	// OutputFiles and OutputDirectories are the paths of the outputs of the action, which are
+	// returned in the ActionResult if the action creates them.
+	OutputFiles       []string
+	OutputDirectories []string
+}
+
+// ActionResult is the result of an executed action. The contents of the outputs, stdout and stderr
+// are in the content addressed storage of the Executor.
+type ActionResult struct {
+	ExitCode int
+
+	// OutputFiles is the output files and the files in the output directories that the action
+	// created.
+	OutputFiles []File
+
+	Stdout Digest
+	Stderr Digest
+}
+
+// Executor executes actions and stores their inputs and outputs.
+type Executor interface {
+	// FindMissingBlobs returns the digests that aren't in the content addressed storage.
+	FindMissingBlobs(digests []Digest) ([]Digest, error)
+
+	// WriteBlob adds the data with the digest to the content addressed storage.
+	WriteBlob(digest Digest, data []byte) error
+
+	// ReadBlob returns the data with the digest from the content addressed storage.
+	ReadBlob(digest Digest) ([]byte, error)
+
+	// Execute runs the action. An error is returned if the action couldn't be run, an action that
+	// fails returns an ActionResult with a non-zero ExitCode.
+	Execute(action *Action) (*ActionResult, error)
+}
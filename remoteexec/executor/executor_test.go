@@ -0,0 +1,271 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    *Options
+		command []string
+		err     bool
+	}{
+		{
+			name: "all flags",
+			args: []string{
+				"--labels=name=turbine,type=tool",
+				"--platform=Pool=java16,container-image=docker://image",
+				"--exec_strategy=remote_local_fallback",
+				"--inputs=turbine.jar,out.rsp,a.jar", "b.jar",
+				"--input_list_paths=out.rsp",
+				"--output_files=out.tmp",
+				"--output_directories=classes",
+				"--toolchain_inputs=java",
+				"--", "java", "-jar", "turbine.jar",
+			},
+			want: &Options{
+				Labels:            map[string]string{"name": "turbine", "type": "tool"},
+				Platform:          map[string]string{"Pool": "java16", "container-image": "docker://image"},
+				ExecStrategy:      RemoteLocalFallbackExecStrategy,
+				Inputs:            []string{"turbine.jar", "out.rsp", "a.jar", "b.jar"},
+				InputListPaths:    []string{"out.rsp"},
+				OutputFiles:       []string{"out.tmp"},
+				OutputDirectories: []string{"classes"},
+				ToolchainInputs:   []string{"java"},
+			},
+			command: []string{"java", "-jar", "turbine.jar"},
+		},
+		{
+			name: "defaults",
+			args: []string{"--", "true"},
+			want: &Options{
+				Labels:       map[string]string{},
+				Platform:     map[string]string{},
+				ExecStrategy: RemoteExecStrategy,
+			},
+			command: []string{"true"},
+		},
+		{
+			name: "unknown flag",
+			args: []string{"--foo=bar", "--", "true"},
+			err:  true,
+		},
+		{
+			name: "unknown exec strategy",
+			args: []string{"--exec_strategy=foo", "--", "true"},
+			err:  true,
+		},
+		{
+			name: "argument after a non-list flag",
+			args: []string{"--exec_strategy=local", "foo", "--", "true"},
+			err:  true,
+		},
+		{
+			name: "missing command",
+			args: []string{"--exec_strategy=local"},
+			err:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			opts, command, err := ParseArgs(test.args)
+			if test.err {
+				if err == nil {
+					t.Fatalf("want an error, got %+v", opts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(opts, test.want) {
+				t.Errorf("want options\n%+v\ngot\n%+v", test.want, opts)
+			}
+			if !reflect.DeepEqual(command, test.command) {
+				t.Errorf("want command %q, got %q", test.command, command)
+			}
+		})
+	}
+}
+
+func TestLocalServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "executor_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := NewLocalServer(dir)
+
+	in := []byte("input\n")
+	digest := NewDigest(in)
+	if missing, err := s.FindMissingBlobs([]Digest{digest}); err != nil || len(missing) != 1 {
+		t.Fatalf("want the blob to be missing, got %v, error %v", missing, err)
+	}
+	if err := s.WriteBlob(Digest{Hash: digest.Hash, Size: 1}, in); err == nil {
+		t.Errorf("want an error for a blob with the wrong digest")
+	}
+	if err := s.WriteBlob(digest, in); err != nil {
+		t.Fatal(err)
+	}
+	if missing, err := s.FindMissingBlobs([]Digest{digest}); err != nil || len(missing) != 0 {
+		t.Fatalf("want the blob to be found, got missing %v, error %v", missing, err)
+	}
+
+	result, err := s.Execute(&Action{
+		Args: []string{"/bin/sh", "-c",
+			"cat src/in > gen/out && mkdir -p gen/dir/sub && cp src/in gen/dir/sub/f && echo out && echo err >&2 && exit 3"},
+		Inputs:            []File{{Path: "src/in", Digest: digest}},
+		OutputFiles:       []string{"gen/out", "gen/missing"},
+		OutputDirectories: []string{"gen/dir"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.ExitCode != 3 {
+		t.Errorf("want exit code 3, got %d", result.ExitCode)
+	}
+	want := []File{{Path: "gen/out", Digest: digest}, {Path: "gen/dir/sub/f", Digest: digest}}
+	if !reflect.DeepEqual(result.OutputFiles, want) {
+		t.Errorf("want outputs %+v, got %+v", want, result.OutputFiles)
+	}
+	for std, want := range map[Digest]string{result.Stdout: "out\n", result.Stderr: "err\n"} {
+		if data, err := s.ReadBlob(std); err != nil || string(data) != want {
+			t.Errorf("want output %q, got %q, error %v", want, data, err)
+		}
+	}
+
+	if _, err := s.Execute(&Action{
+		Args:   []string{"/bin/true"},
+		Inputs: []File{{Path: "../in", Digest: digest}},
+	}); err == nil {
+		t.Errorf("want an error for an input outside of the root directory")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "executor_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := NewLocalServer(filepath.Join(dir, "server"))
+	execRoot := filepath.Join(dir, "root")
+
+	writeTestFile := func(path, contents string, mode os.FileMode) {
+		t.Helper()
+		path = filepath.Join(execRoot, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile("tool.sh", "#!/bin/sh\nmkdir -p out/classes && cat $(cat out/in.rsp) > out/classes/all && echo done\n", 0755)
+	writeTestFile("a", "a\n", 0644)
+	writeTestFile("b", "b\n", 0644)
+	writeTestFile("undeclared", "undeclared\n", 0644)
+	writeTestFile("out/in.rsp", "a b", 0644)
+	writeTestFile("out/classes/stale", "stale\n", 0644)
+
+	run := func(strategy string) (int, string) {
+		t.Helper()
+		os.Remove(filepath.Join(execRoot, "out/classes/all"))
+		opts, args, err := ParseArgs([]string{
+			"--exec_strategy=" + strategy,
+			"--inputs=a",
+			"--input_list_paths=out/in.rsp",
+			"--output_directories=out/classes",
+			"--toolchain_inputs=tool.sh",
+			"--", "./tool.sh",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stdout, stderr bytes.Buffer
+		code, err := Run(s, opts, args, execRoot, &stdout, &stderr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code, stdout.String()
+	}
+	readOutput := func() string {
+		t.Helper()
+		data, err := ioutil.ReadFile(filepath.Join(execRoot, "out/classes/all"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if code, stdout := run(RemoteExecStrategy); code != 0 || stdout != "done\n" {
+		t.Fatalf("want a successful remote action, got exit code %d, stdout %q", code, stdout)
+	}
+	if got := readOutput(); got != "a\nb\n" {
+		t.Errorf("want the output to be downloaded, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(execRoot, "out/classes/stale")); !os.IsNotExist(err) {
+		t.Errorf("want the stale file in the output directory to be removed, got %v", err)
+	}
+
+	// Files that aren't inputs aren't available to the remote actions.
+	if code, _ := runWithCommand(t, s, execRoot, RemoteExecStrategy,
+		"cat a undeclared > out/classes/all"); code == 0 {
+		t.Errorf("want the remote action to fail without its inputs")
+	}
+
+	// The remote_local_fallback strategy runs the action locally, where all of the files are
+	// available.
+	if code, stdout := runWithCommand(t, s, execRoot, RemoteLocalFallbackExecStrategy,
+		"cat a undeclared > out/classes/all"); code != 0 || stdout != "" {
+		t.Errorf("want the action to fall back to running locally, got exit code %d, stdout %q", code, stdout)
+	}
+	if got := readOutput(); got != "a\nundeclared\n" {
+		t.Errorf("want the local output, got %q", got)
+	}
+	if code, _ := runWithCommand(t, s, execRoot, LocalExecStrategy,
+		"cat a undeclared > out/classes/all"); code != 0 {
+		t.Errorf("want the local action to succeed, got exit code %d", code)
+	}
+}
+
+func runWithCommand(t *testing.T, s *LocalServer, execRoot, strategy, command string) (int, string) {
+	t.Helper()
+	opts, args, err := ParseArgs([]string{
+		"--exec_strategy=" + strategy,
+		"--inputs=a",
+		"--output_files=out/classes/all",
+		"--", "/bin/sh", "-c", command,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	code, err := Run(s, opts, args, execRoot, &stdout, &stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code, stdout.String()
+}
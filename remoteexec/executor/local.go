@@ -0,0 +1,242 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The environment of the actions that are executed by the LocalServer, which doesn't depend on the
+// environment of the client like the environment of a remote worker.
+var localServerEnv = []string{
+	"PATH=/usr/local/bin:/usr/bin:/bin",
+	"LANG=C.UTF-8",
+}
+
+// LocalServer is an Executor that stores the blobs in a directory and executes each action in a
+// new temporary directory that only contains the inputs of the action, which catches the inputs
+// that are missing from the remote execution templates. The programs of the host are available to
+// the actions, and the platform of the actions is ignored. The directory can be shared by the
+// LocalServers of multiple processes.
+type LocalServer struct {
+	dir string
+}
+
+var _ Executor = (*LocalServer)(nil)
+
+// NewLocalServer returns a LocalServer that stores the blobs and runs the actions in dir.
+func NewLocalServer(dir string) *LocalServer {
+	return &LocalServer{dir: dir}
+}
+
+func (s *LocalServer) blobPath(digest Digest) string {
+	return filepath.Join(s.dir, "cas", digest.Hash)
+}
+
+func (s *LocalServer) FindMissingBlobs(digests []Digest) ([]Digest, error) {
+	var missing []Digest
+	for _, digest := range digests {
+		if _, err := os.Stat(s.blobPath(digest)); os.IsNotExist(err) {
+			missing = append(missing, digest)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+func (s *LocalServer) WriteBlob(digest Digest, data []byte) error {
+	if got := NewDigest(data); got != digest {
+		return fmt.Errorf("blob %s has digest %s", digest, got)
+	}
+	path := s.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+
+	// Write the blob to a temporary file first, other processes may be reading it.
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *LocalServer) ReadBlob(digest Digest) ([]byte, error) {
+	data, err := ioutil.ReadFile(s.blobPath(digest))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("blob %s not found", digest)
+	}
+	return data, err
+}
+
+func (s *LocalServer) Execute(action *Action) (*ActionResult, error) {
+	if len(action.Args) == 0 {
+		return nil, fmt.Errorf("action has no command")
+	}
+	if err := os.MkdirAll(s.dir, 0777); err != nil {
+		return nil, err
+	}
+	root, err := ioutil.TempDir(s.dir, "exec")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(root)
+
+	for _, input := range action.Inputs {
+		path, err := actionPath(root, input.Path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := s.ReadBlob(input.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %s", input.Path, err)
+		}
+		if err := writeFile(path, data, input.Executable); err != nil {
+			return nil, err
+		}
+	}
+
+	// Like a remote worker, create the parent directories of the outputs but not the outputs.
+	for _, output := range append(append([]string(nil), action.OutputFiles...), action.OutputDirectories...) {
+		path, err := actionPath(root, output)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			return nil, err
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(action.Args[0], action.Args[1:]...)
+	cmd.Dir = root
+	cmd.Env = localServerEnv
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := &ActionResult{}
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		result.ExitCode = exitErr.ExitCode()
+	} else if err != nil {
+		// The program couldn't be started, most likely because it isn't an input of the action.
+		fmt.Fprintln(&stderr, err)
+		result.ExitCode = 127
+	}
+
+	for _, output := range action.OutputFiles {
+		file, err := s.storeOutput(root, output)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result.OutputFiles = append(result.OutputFiles, file)
+	}
+	for _, output := range action.OutputDirectories {
+		dir := filepath.Join(root, output)
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+			file, err := s.storeOutput(root, rel)
+			if err != nil {
+				return err
+			}
+			result.OutputFiles = append(result.OutputFiles, file)
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	if result.Stdout, err = s.storeBlob(stdout.Bytes()); err != nil {
+		return nil, err
+	}
+	if result.Stderr, err = s.storeBlob(stderr.Bytes()); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// storeOutput adds an output of an action in the root directory to the content addressed storage.
+func (s *LocalServer) storeOutput(root, path string) (File, error) {
+	fi, err := os.Stat(filepath.Join(root, path))
+	if err != nil {
+		return File{}, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(root, path))
+	if err != nil {
+		return File{}, err
+	}
+	digest, err := s.storeBlob(data)
+	if err != nil {
+		return File{}, err
+	}
+	return File{Path: filepath.ToSlash(path), Digest: digest, Executable: fi.Mode()&0100 != 0}, nil
+}
+
+func (s *LocalServer) storeBlob(data []byte) (Digest, error) {
+	digest := NewDigest(data)
+	return digest, s.WriteBlob(digest, data)
+}
+
+// actionPath returns the path of a file of an action in the root directory of the action, and
+// rejects the paths that are outside of it.
+func actionPath(root, path string) (string, error) {
+	clean := filepath.Clean(path)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("path %q is outside of the root directory of the action", path)
+	}
+	return filepath.Join(root, clean), nil
+}
+
+// writeFile replaces the file at path with the data.
+func writeFile(path string, data []byte, executable bool) error {
+	mode := os.FileMode(0644)
+	if executable {
+		mode = 0755
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return ioutil.WriteFile(path, data, mode)
+}
@@ -89,6 +89,9 @@ func init() {
 	})
 }
 
+// wrapper returns the path to the remote execution wrapper. RBE_WRAPPER can point to
+// fake_rewrapper to run the remote execution rules with a local server, see
+// android/soong/remoteexec/executor.
 func wrapper(cfg android.Config) string {
 	if override := cfg.Getenv("RBE_WRAPPER"); override != "" {
 		return override
@@ -204,3 +207,18 @@ func EnvOverrideFunc(envVar, defaultVal string) func(ctx android.PackageVarConte
 		return defaultVal
 	}
 }
+
+// WrapCommand implements android.RuleBuilderRemoteWrapper. It returns command wrapped with the
+// remote execution wrapper, with inputs and outputs added to the inputs and outputs of the params.
+// rspFile replaces the RSPFile of the params if it is not empty.
+func (r *REParams) WrapCommand(cfg android.Config, inputs []string, rspFile string, outputs []string, command string) string {
+	params := *r
+	params.Inputs = append(append([]string(nil), r.Inputs...), inputs...)
+	if rspFile != "" {
+		params.RSPFile = rspFile
+	}
+	params.OutputFiles = append(append([]string(nil), r.OutputFiles...), outputs...)
+	return params.NoVarTemplate(cfg) + command
+}
+
+var _ android.RuleBuilderRemoteWrapper = (*REParams)(nil)
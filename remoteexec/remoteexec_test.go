@@ -99,3 +99,22 @@ func TestTemplateDeterminism(t *testing.T) {
 		}
 	}
 }
+
+func TestWrapCommand(t *testing.T) {
+	params := &REParams{
+		Labels:       map[string]string{"type": "tool", "name": "cp"},
+		ExecStrategy: RemoteExecStrategy,
+		Inputs:       []string{"extra_input"},
+		OutputFiles:  []string{"extra_output"},
+	}
+	want := fmt.Sprintf("prebuilts/remoteexecution-client/live/rewrapper --labels=name=cp,type=tool --platform=\"container-image=%s\" --exec_strategy=remote --inputs=extra_input,in,$out.rsp --input_list_paths=$out.rsp --output_files=extra_output,out -- bash -c 'cp in out'", DefaultImage)
+	got := params.WrapCommand(android.NullConfig(""), []string{"in", "$out.rsp"}, "$out.rsp", []string{"out"}, "bash -c 'cp in out'")
+	if got != want {
+		t.Errorf("WrapCommand() returned\n%s\nwant\n%s", got, want)
+	}
+
+	// The params are not modified.
+	if len(params.Inputs) != 1 || len(params.OutputFiles) != 1 || params.RSPFile != "" {
+		t.Errorf("WrapCommand() modified the params: %+v", params)
+	}
+}
@@ -46,7 +46,7 @@ func (mod *Module) SubAndroidMk(data *android.AndroidMkData, obj interface{}) {
 }
 
 func (mod *Module) AndroidMk() android.AndroidMkData {
-	if mod.Properties.HideFromMake {
+	if mod.Properties.HideFromMake || !mod.IsForPlatform() {
 		return android.AndroidMkData{
 			Disabled: true,
 		}
@@ -82,6 +82,9 @@ func (mod *Module) AndroidMk() android.AndroidMkData {
 		// If the compiler is disabled, this is a SourceProvider.
 		mod.SubAndroidMk(&ret, mod.sourceProvider)
 	}
+	if mod.sanitize != nil {
+		mod.SubAndroidMk(&ret, mod.sanitize)
+	}
 	ret.SubName += mod.Properties.SubName
 
 	return ret
@@ -120,6 +123,32 @@ func (test *testDecorator) AndroidMk(ctx AndroidMkContext, ret *android.AndroidM
 	// TODO(chh): add test data with androidMkWriteTestData(test.data, ctx, ret)
 }
 
+func (fuzz *fuzzDecorator) AndroidMk(ctx AndroidMkContext, ret *android.AndroidMkData) {
+	ctx.SubAndroidMk(ret, fuzz.binaryDecorator)
+
+	fuzzFiles := fuzz.fuzzPackagedModule.FuzzFilesForMake()
+	ret.Extra = append(ret.Extra, func(w io.Writer, outputFile android.Path) {
+		fmt.Fprintln(w, "LOCAL_IS_FUZZ_TARGET := true")
+		if len(fuzzFiles) > 0 {
+			fmt.Fprintln(w, "LOCAL_TEST_DATA :=", strings.Join(fuzzFiles, " "))
+		}
+		if len(fuzz.installedSharedDeps) > 0 {
+			fmt.Fprintln(w, "LOCAL_FUZZ_INSTALLED_SHARED_DEPS :=",
+				strings.Join(fuzz.installedSharedDeps, " "))
+		}
+	})
+}
+
+func (sanitize *sanitize) AndroidMk(ctx AndroidMkContext, ret *android.AndroidMkData) {
+	// Add a suffix for hwasan-enabled rlibs and static libraries to allow surfacing both the
+	// sanitized and non-sanitized variants to make without a name conflict.
+	if ret.Class == "RLIB_LIBRARIES" || ret.Class == "STATIC_LIBRARIES" {
+		if Bool(sanitize.Properties.Sanitize.Hwaddress) {
+			ret.SubName += ".hwasan"
+		}
+	}
+}
+
 func (library *libraryDecorator) AndroidMk(ctx AndroidMkContext, ret *android.AndroidMkData) {
 	ctx.SubAndroidMk(ret, library.baseCompiler)
 
@@ -91,6 +91,10 @@ func (binary *binaryDecorator) compilerProps() []interface{} {
 		&binary.stripper.StripProperties)
 }
 
+func (binary *binaryDecorator) isDependencyRoot() bool {
+	return true
+}
+
 func (binary *binaryDecorator) nativeCoverage() bool {
 	return true
 }
@@ -28,6 +28,7 @@ var (
 		"rust_ffi_host",
 		"rust_ffi_host_shared",
 		"rust_ffi_host_static",
+		"rust_fuzz",
 		"rust_proc_macro",
 		"rust_test",
 		"rust_test_host",
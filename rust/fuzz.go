@@ -0,0 +1,137 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"android/soong/android"
+	"android/soong/cc"
+	cc_config "android/soong/cc/config"
+)
+
+// The crate providing the libFuzzer entry point macros for rust fuzz targets.
+var LibFuzzerSysCrateName = "liblibfuzzer_sys"
+
+func init() {
+	android.RegisterModuleType("rust_fuzz", RustFuzzFactory)
+}
+
+type fuzzDecorator struct {
+	*binaryDecorator
+
+	fuzzPackagedModule  cc.FuzzPackagedModule
+	installedSharedDeps []string
+}
+
+var _ compiler = (*fuzzDecorator)(nil)
+
+// rust_fuzz creates a device fuzzer binary built against libFuzzer through the
+// libfuzzer-sys crate. Fuzz targets are installed to /data/fuzz on your device,
+// or $ANDROID_PRODUCT_OUT/data/fuzz in your build tree, and are packaged
+// together with cc_fuzz targets.
+func RustFuzzFactory() android.Module {
+	module, _ := NewRustFuzz(android.DeviceSupported)
+	return module.Init()
+}
+
+func NewRustFuzz(hod android.HostOrDeviceSupported) (*Module, *fuzzDecorator) {
+	module, binary := NewRustBinary(hod)
+	fuzz := &fuzzDecorator{
+		binaryDecorator: binary,
+	}
+
+	// Change the defaults for the binaryDecorator's baseCompiler
+	fuzz.binaryDecorator.baseCompiler.dir = "fuzz"
+	fuzz.binaryDecorator.baseCompiler.dir64 = "fuzz"
+	fuzz.binaryDecorator.baseCompiler.location = InstallInData
+	module.sanitize.SetSanitizer(cc.Fuzzer, true)
+	module.compiler = fuzz
+	return module, fuzz
+}
+
+func (fuzzer *fuzzDecorator) compilerFlags(ctx ModuleContext, flags Flags) Flags {
+	flags = fuzzer.binaryDecorator.compilerFlags(ctx, flags)
+
+	// `../lib` for installed fuzz targets (both host and device), and `./lib` for fuzz
+	// target packages.
+	flags.LinkFlags = append(flags.LinkFlags, `-Wl,-rpath,\$$ORIGIN/../lib`)
+	flags.LinkFlags = append(flags.LinkFlags, `-Wl,-rpath,\$$ORIGIN/lib`)
+	return flags
+}
+
+func (fuzzer *fuzzDecorator) compilerDeps(ctx DepsContext, deps Deps) Deps {
+	deps.StaticLibs = append(deps.StaticLibs,
+		cc_config.LibFuzzerRuntimeLibrary(ctx.RustModule().ccToolchain(ctx)))
+	deps.Rlibs = append(deps.Rlibs, LibFuzzerSysCrateName)
+	deps = fuzzer.binaryDecorator.compilerDeps(ctx, deps)
+	return deps
+}
+
+func (fuzzer *fuzzDecorator) compilerProps() []interface{} {
+	return append(fuzzer.binaryDecorator.compilerProps(),
+		&fuzzer.fuzzPackagedModule.FuzzProperties)
+}
+
+func (fuzzer *fuzzDecorator) autoDep(ctx BaseModuleContext) autoDep {
+	return rlibAutoDep
+}
+
+func (fuzzer *fuzzDecorator) install(ctx ModuleContext) {
+	fuzzer.binaryDecorator.baseCompiler.dir = filepath.Join(
+		"fuzz", ctx.Target().Arch.ArchType.String(), ctx.ModuleName())
+	fuzzer.binaryDecorator.baseCompiler.dir64 = filepath.Join(
+		"fuzz", ctx.Target().Arch.ArchType.String(), ctx.ModuleName())
+	fuzzer.binaryDecorator.install(ctx)
+
+	fuzzer.fuzzPackagedModule.CollectFuzzFiles(ctx)
+	fuzzer.installedSharedDeps = cc.InstalledFuzzSharedDeps(ctx)
+}
+
+var _ cc.FuzzModule = (*Module)(nil)
+
+func (mod *Module) IsFuzzModule() bool {
+	_, ok := mod.compiler.(*fuzzDecorator)
+	return ok
+}
+
+func (mod *Module) FuzzPackagedModule() cc.FuzzPackagedModule {
+	if fuzzer, ok := mod.compiler.(*fuzzDecorator); ok {
+		return fuzzer.fuzzPackagedModule
+	}
+	panic(fmt.Errorf("FuzzPackagedModule called on non-fuzz module: %q", mod.BaseModuleName()))
+}
+
+func (mod *Module) InstallPrevented() bool {
+	return mod.Properties.PreventInstall
+}
+
+func (mod *Module) UnstrippedOutputFile() android.Path {
+	if mod.outputFile.Valid() {
+		return mod.outputFile.Path()
+	}
+	return nil
+}
+
+var _ cc.SharedFuzzDependency = (*Module)(nil)
+
+// Rust dylibs are shared libraries that fuzz targets need at runtime.
+func (mod *Module) IsSharedFuzzDependency() bool {
+	if library, ok := mod.compiler.(libraryInterface); ok {
+		return library.dylib() && !mod.UseVndk()
+	}
+	return false
+}
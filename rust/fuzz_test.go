@@ -0,0 +1,94 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"android/soong/android"
+)
+
+func TestRustFuzz(t *testing.T) {
+	ctx := testRust(t, `
+		rust_library {
+			name: "libtest_fuzzing",
+			crate_name: "test_fuzzing",
+			srcs: ["foo.rs"],
+		}
+		rust_fuzz {
+			name: "fuzz_libtest",
+			srcs: ["foo.rs"],
+			rustlibs: ["libtest_fuzzing"],
+		}
+	`)
+
+	// Check that the fuzzer variant of the library is created.
+	variants := ctx.ModuleVariantsForTests("libtest_fuzzing")
+	if !android.InList("android_arm64_armv8-a_rlib_hwasan_fuzzer", variants) {
+		t.Errorf("missing fuzzer variant of 'libtest_fuzzing'; variants: %#v", variants)
+	}
+
+	// Check that the sancov flags are passed to the fuzz target and its dependency.
+	fuzz := ctx.ModuleForTests("fuzz_libtest", "android_arm64_armv8-a_hwasan_fuzzer").Rule("rustc")
+	if !strings.Contains(fuzz.Args["rustcFlags"], "-C passes='sancov'") {
+		t.Errorf("rust_fuzz module does not contain -C passes='sancov' flag; rustcFlags: %#v", fuzz.Args["rustcFlags"])
+	}
+	if !strings.Contains(fuzz.Args["rustcFlags"], "--cfg fuzzing") {
+		t.Errorf("rust_fuzz module does not contain --cfg fuzzing flag; rustcFlags: %#v", fuzz.Args["rustcFlags"])
+	}
+
+	lib := ctx.ModuleForTests("libtest_fuzzing", "android_arm64_armv8-a_rlib_hwasan_fuzzer").Rule("rustc")
+	if !strings.Contains(lib.Args["rustcFlags"], "-C passes='sancov'") {
+		t.Errorf("rust_fuzz dependent library does not contain -C passes='sancov' flag; rustcFlags: %#v", lib.Args["rustcFlags"])
+	}
+	if !strings.Contains(lib.Args["rustcFlags"], "--cfg fuzzing") {
+		t.Errorf("rust_fuzz dependent library does not contain --cfg fuzzing flag; rustcFlags: %#v", lib.Args["rustcFlags"])
+	}
+}
+
+func TestRustFuzzPackaging(t *testing.T) {
+	ctx := testRust(t, `
+		rust_fuzz {
+			name: "fuzz_libtest",
+			srcs: ["foo.rs"],
+			fuzz_config: {
+				cc: ["foo@example.com"],
+			},
+		}
+	`)
+
+	fuzz := ctx.ModuleForTests("fuzz_libtest", "android_arm64_armv8-a_hwasan_fuzzer").Module().(*Module)
+	packager := ctx.SingletonForTests("cc_fuzz_packaging")
+
+	// Check that the rust fuzz target is zipped with its config like a cc_fuzz target.
+	fuzzZip := packager.Output(filepath.Join(buildDir, ".intermediates", "fuzz", "target", "arm64", "fuzz_libtest.zip"))
+	inputs := fuzzZip.Implicits.Strings()
+	if !android.InList(fuzz.UnstrippedOutputFile().String(), inputs) {
+		t.Errorf("fuzz_libtest.zip does not contain the fuzz target %q; inputs: %#v",
+			fuzz.UnstrippedOutputFile(), inputs)
+	}
+	if !android.SuffixInList(inputs, "config/config.json") {
+		t.Errorf("fuzz_libtest.zip does not contain the fuzz config; inputs: %#v", inputs)
+	}
+
+	// Check that the zip of the fuzz target is added to the per-arch zip.
+	archZip := packager.Output(filepath.Join(buildDir, "fuzz-target-arm64.zip"))
+	if !android.InList(fuzzZip.Output.String(), archZip.Implicits.Strings()) {
+		t.Errorf("fuzz-target-arm64.zip does not contain %q; inputs: %#v",
+			fuzzZip.Output, archZip.Implicits.Strings())
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"testing"
+
+	"android/soong/android"
+	"android/soong/cc"
+)
+
+// Test that cc vendor modules link against the vendor variant of rust libraries.
+func TestVendorLinkage(t *testing.T) {
+	ctx := testRustVndk(t, `
+			cc_binary {
+				name: "fizz_vendor",
+				static_libs: ["libfoo_vendor"],
+				soc_specific: true,
+			}
+			rust_ffi_static {
+				name: "libfoo_vendor",
+				crate_name: "foo",
+				srcs: ["foo.rs"],
+				vendor_available: true,
+			}
+		`)
+
+	vendorBinary := ctx.ModuleForTests("fizz_vendor", "android_vendor.VER_arm64_armv8-a").Module().(*cc.Module)
+
+	if !android.InList("libfoo_vendor.vendor", vendorBinary.Properties.AndroidMkStaticLibs) {
+		t.Errorf("vendorBinary should have a dependency on libfoo_vendor.vendor; static libs: %#v",
+			vendorBinary.Properties.AndroidMkStaticLibs)
+	}
+}
+
+// Test that rust vendor modules depend on the vendor variants of their dependencies.
+func TestVendorRustModule(t *testing.T) {
+	ctx := testRustVndk(t, `
+			rust_binary {
+				name: "fizz_vendor",
+				srcs: ["foo.rs"],
+				rustlibs: ["libbar"],
+				shared_libs: ["liblog"],
+				vendor: true,
+			}
+			rust_library {
+				name: "libbar",
+				crate_name: "bar",
+				srcs: ["foo.rs"],
+				vendor_available: true,
+			}
+		`)
+
+	variants := ctx.ModuleVariantsForTests("libbar")
+	if !android.InList("android_vendor.VER_arm64_armv8-a_dylib", variants) {
+		t.Errorf("missing vendor variant of 'libbar'; variants: %#v", variants)
+	}
+	if !android.InList("android_arm64_armv8-a_dylib", variants) {
+		t.Errorf("missing core variant of 'libbar'; variants: %#v", variants)
+	}
+
+	// vendor: true modules only have a vendor variant.
+	if variants := ctx.ModuleVariantsForTests("fizz_vendor"); android.InList("android_arm64_armv8-a", variants) {
+		t.Errorf("unexpected core variant of 'fizz_vendor'; variants: %#v", variants)
+	}
+
+	fizz := ctx.ModuleForTests("fizz_vendor", "android_vendor.VER_arm64_armv8-a").Module().(*Module)
+	if !android.InList("libbar.vendor", fizz.Properties.AndroidMkDylibs) {
+		t.Errorf("fizz_vendor should depend on libbar.vendor; dylibs: %#v", fizz.Properties.AndroidMkDylibs)
+	}
+	if !android.InList("liblog.vendor", fizz.Properties.AndroidMkSharedLibs) {
+		t.Errorf("fizz_vendor should depend on liblog.vendor; shared libs: %#v", fizz.Properties.AndroidMkSharedLibs)
+	}
+
+	libbar := ctx.ModuleForTests("libbar", "android_vendor.VER_arm64_armv8-a_dylib").Module().(*Module)
+	if libbar.Properties.SubName != ".vendor" {
+		t.Errorf("expected SubName \".vendor\" for the vendor variant of libbar, got %q", libbar.Properties.SubName)
+	}
+}
+
+// Test that vendor_available and vendor: true are mutually exclusive.
+func TestVendorAvailableAndVendorError(t *testing.T) {
+	testRustError(t, "doesn't make sense at the same time as `vendor: true`", `
+			rust_library {
+				name: "libfoo",
+				crate_name: "foo",
+				srcs: ["foo.rs"],
+				vendor: true,
+				vendor_available: true,
+			}
+		`)
+}
@@ -84,6 +84,9 @@ type libraryDecorator struct {
 	MutatedProperties LibraryMutatedProperties
 	includeDirs       android.Paths
 	sourceProvider    SourceProvider
+
+	// exported headers captured by the vendor snapshot
+	collectedSnapshotHeaders android.Paths
 }
 
 type libraryInterface interface {
@@ -41,6 +41,10 @@ func init() {
 	android.PreDepsMutators(func(ctx android.RegisterMutatorsContext) {
 		ctx.BottomUp("rust_libraries", LibraryMutator).Parallel()
 		ctx.BottomUp("rust_begin", BeginMutator).Parallel()
+		ctx.BottomUp("rust_vendor_snapshot", VendorSnapshotMutator).Parallel()
+	})
+	android.PostDepsMutators(func(ctx android.RegisterMutatorsContext) {
+		ctx.BottomUp("rust_sanitize_runtime", SanitizerRuntimeMutator).Parallel()
 	})
 	pctx.Import("android/soong/rust/config")
 	pctx.ImportAs("cc_config", "android/soong/cc/config")
@@ -64,17 +68,31 @@ type BaseProperties struct {
 	AndroidMkSharedLibs    []string
 	AndroidMkStaticLibs    []string
 
+	// Names of the rlib dependencies, recorded by the vendor snapshot
+	SnapshotRlibs []string `blueprint:"mutated"`
+
 	SubName string `blueprint:"mutated"`
 
+	// Set by imageMutator
+	CoreVariantNeeded    bool     `blueprint:"mutated"`
+	ExtraVariants        []string `blueprint:"mutated"`
+	ImageVariationPrefix string   `blueprint:"mutated"`
+	VndkVersion          string   `blueprint:"mutated"`
+
 	PreventInstall bool
 	HideFromMake   bool
+
+	// Minimum sdk version that the artifact should support when it runs as part of mainline modules(APEX).
+	Min_sdk_version *string
 }
 
 type Module struct {
 	android.ModuleBase
 	android.DefaultableModuleBase
+	android.ApexModuleBase
 
-	Properties BaseProperties
+	Properties       BaseProperties
+	VendorProperties cc.VendorProperties
 
 	hod      android.HostOrDeviceSupported
 	multilib android.Multilib
@@ -82,6 +100,7 @@ type Module struct {
 	compiler         compiler
 	coverage         *coverage
 	clippy           *clippy
+	sanitize         *sanitize
 	cachedToolchain  config.Toolchain
 	sourceProvider   SourceProvider
 	subAndroidMkOnce map[SubAndroidMkProvider]bool
@@ -106,29 +125,6 @@ func (mod *Module) OutputFiles(tag string) (android.Paths, error) {
 	}
 }
 
-var _ android.ImageInterface = (*Module)(nil)
-
-func (mod *Module) ImageMutatorBegin(ctx android.BaseModuleContext) {}
-
-func (mod *Module) CoreVariantNeeded(ctx android.BaseModuleContext) bool {
-	return true
-}
-
-func (mod *Module) RamdiskVariantNeeded(android.BaseModuleContext) bool {
-	return mod.InRamdisk()
-}
-
-func (mod *Module) RecoveryVariantNeeded(android.BaseModuleContext) bool {
-	return mod.InRecovery()
-}
-
-func (mod *Module) ExtraImageVariations(android.BaseModuleContext) []string {
-	return nil
-}
-
-func (c *Module) SetImageVariation(ctx android.BaseModuleContext, variant string, module android.Module) {
-}
-
 func (mod *Module) BuildStubs() bool {
 	return false
 }
@@ -193,10 +189,6 @@ func (mod *Module) UseSdk() bool {
 	return false
 }
 
-func (mod *Module) UseVndk() bool {
-	return false
-}
-
 func (mod *Module) MustUseVendorVariant() bool {
 	return false
 }
@@ -205,10 +197,6 @@ func (mod *Module) IsVndk() bool {
 	return false
 }
 
-func (mod *Module) HasVendorVariant() bool {
-	return false
-}
-
 func (mod *Module) SdkVersion() string {
 	return ""
 }
@@ -386,6 +374,7 @@ func DefaultsFactory(props ...interface{}) android.Module {
 	module.AddProperties(props...)
 	module.AddProperties(
 		&BaseProperties{},
+		&cc.VendorProperties{},
 		&BaseCompilerProperties{},
 		&BinaryCompilerProperties{},
 		&LibraryCompilerProperties{},
@@ -395,6 +384,7 @@ func DefaultsFactory(props ...interface{}) android.Module {
 		&TestProperties{},
 		&cc.CoverageProperties{},
 		&ClippyProperties{},
+		&SanitizeProperties{},
 	)
 
 	android.InitDefaultsModule(module)
@@ -543,6 +533,7 @@ var _ cc.LinkableInterface = (*Module)(nil)
 
 func (mod *Module) Init() android.Module {
 	mod.AddProperties(&mod.Properties)
+	mod.AddProperties(&mod.VendorProperties)
 
 	if mod.compiler != nil {
 		mod.AddProperties(mod.compiler.compilerProps()...)
@@ -553,11 +544,15 @@ func (mod *Module) Init() android.Module {
 	if mod.clippy != nil {
 		mod.AddProperties(mod.clippy.props()...)
 	}
+	if mod.sanitize != nil {
+		mod.AddProperties(mod.sanitize.props()...)
+	}
 	if mod.sourceProvider != nil {
 		mod.AddProperties(mod.sourceProvider.SourceProviderProps()...)
 	}
 
 	android.InitAndroidArchModule(mod, mod.hod, mod.multilib)
+	android.InitApexModule(mod)
 
 	android.InitDefaultableModule(mod)
 
@@ -588,6 +583,7 @@ func newModule(hod android.HostOrDeviceSupported, multilib android.Multilib) *Mo
 	module := newBaseModule(hod, multilib)
 	module.coverage = &coverage{}
 	module.clippy = &clippy{}
+	module.sanitize = &sanitize{}
 	return module
 }
 
@@ -677,6 +673,16 @@ func (mod *Module) GenerateAndroidBuildActions(actx android.ModuleContext) {
 		return
 	}
 
+	mod.Properties.SubName = ""
+	if mod.UseVndk() && mod.HasVendorVariant() {
+		// .vendor.{version} suffix is added for vendor variant or .product.{version} suffix is
+		// added for product variant only when we have vendor and product variants with core
+		// variant. The suffix is not added for vendor-only or product-only module.
+		mod.Properties.SubName += mod.nameSuffixWithVndkVersion(ctx)
+	} else if mod.isSnapshotPrebuilt() {
+		mod.Properties.SubName += vendorSuffix
+	}
+
 	deps := mod.depsToPaths(ctx)
 	flags := Flags{
 		Toolchain: toolchain,
@@ -691,6 +697,9 @@ func (mod *Module) GenerateAndroidBuildActions(actx android.ModuleContext) {
 	if mod.clippy != nil {
 		flags, deps = mod.clippy.flags(ctx, flags, deps)
 	}
+	if mod.sanitize != nil {
+		flags, deps = mod.sanitize.flags(ctx, flags, deps)
+	}
 
 	// SourceProvider needs to call GenerateSource() before compiler calls compile() so it can provide the source.
 	// TODO(b/162588681) This shouldn't have to run for every variant.
@@ -704,7 +713,12 @@ func (mod *Module) GenerateAndroidBuildActions(actx android.ModuleContext) {
 		outputFile := mod.compiler.compile(ctx, flags, deps)
 
 		mod.outputFile = android.OptionalPathForPath(outputFile)
-		if mod.outputFile.Valid() && !mod.Properties.PreventInstall {
+
+		// glob exported headers for snapshot, if BOARD_VNDK_VERSION is current.
+		if ctx.DeviceConfig().VndkVersion() == "current" && mod.inVendor() {
+			mod.collectHeadersForSnapshot(ctx)
+		}
+		if mod.outputFile.Valid() && mod.installable() {
 			mod.compiler.install(ctx)
 		}
 	}
@@ -750,6 +764,13 @@ var (
 	testPerSrcDepTag    = dependencyTag{name: "rust_unit_tests"}
 )
 
+var _ cc.SanitizableDependencyTag = dependencyTag{}
+
+// Sanitizer requirements only propagate across rust library dependencies.
+func (d dependencyTag) SanitizableDependencyTag() bool {
+	return d.library
+}
+
 type autoDep struct {
 	variation string
 	depTag    dependencyTag
@@ -768,6 +789,9 @@ func (mod *Module) begin(ctx BaseModuleContext) {
 	if mod.coverage != nil {
 		mod.coverage.begin(ctx)
 	}
+	if mod.sanitize != nil {
+		mod.sanitize.begin(ctx)
+	}
 }
 
 func (mod *Module) depsToPaths(ctx android.ModuleContext) PathDeps {
@@ -795,7 +819,7 @@ func (mod *Module) depsToPaths(ctx android.ModuleContext) PathDeps {
 					return
 				}
 				directDylibDeps = append(directDylibDeps, rustDep)
-				mod.Properties.AndroidMkDylibs = append(mod.Properties.AndroidMkDylibs, depName)
+				mod.Properties.AndroidMkDylibs = append(mod.Properties.AndroidMkDylibs, mod.makeLibName(ctx, rustDep, depName))
 			case rlibDepTag:
 				rlib, ok := rustDep.compiler.(libraryInterface)
 				if !ok || !rlib.rlib() {
@@ -804,7 +828,8 @@ func (mod *Module) depsToPaths(ctx android.ModuleContext) PathDeps {
 				}
 				depPaths.coverageFiles = append(depPaths.coverageFiles, rustDep.CoverageFiles()...)
 				directRlibDeps = append(directRlibDeps, rustDep)
-				mod.Properties.AndroidMkRlibs = append(mod.Properties.AndroidMkRlibs, depName)
+				mod.Properties.AndroidMkRlibs = append(mod.Properties.AndroidMkRlibs, mod.makeLibName(ctx, rustDep, depName))
+				mod.Properties.SnapshotRlibs = append(mod.Properties.SnapshotRlibs, rustDep.BaseModuleName())
 			case procMacroDepTag:
 				directProcMacroDeps = append(directProcMacroDeps, rustDep)
 				mod.Properties.AndroidMkProcMacroLibs = append(mod.Properties.AndroidMkProcMacroLibs, depName)
@@ -880,7 +905,7 @@ func (mod *Module) depsToPaths(ctx android.ModuleContext) PathDeps {
 				}
 				depPaths.coverageFiles = append(depPaths.coverageFiles, ccDep.CoverageFiles()...)
 				directStaticLibDeps = append(directStaticLibDeps, ccDep)
-				mod.Properties.AndroidMkStaticLibs = append(mod.Properties.AndroidMkStaticLibs, depName)
+				mod.Properties.AndroidMkStaticLibs = append(mod.Properties.AndroidMkStaticLibs, mod.makeLibName(ctx, ccDep, depName))
 			case cc.IsSharedDepTag(depTag):
 				depPaths.linkDirs = append(depPaths.linkDirs, linkPath)
 				depPaths.linkObjects = append(depPaths.linkObjects, linkObject.String())
@@ -891,7 +916,7 @@ func (mod *Module) depsToPaths(ctx android.ModuleContext) PathDeps {
 					depPaths.depGeneratedHeaders = append(depPaths.depGeneratedHeaders, mod.ExportedGeneratedHeaders()...)
 				}
 				directSharedLibDeps = append(directSharedLibDeps, ccDep)
-				mod.Properties.AndroidMkSharedLibs = append(mod.Properties.AndroidMkSharedLibs, depName)
+				mod.Properties.AndroidMkSharedLibs = append(mod.Properties.AndroidMkSharedLibs, mod.makeLibName(ctx, ccDep, depName))
 				exportDep = true
 			case depTag == cc.CrtBeginDepTag:
 				depPaths.CrtBegin = linkObject
@@ -972,6 +997,25 @@ func (mod *Module) InstallInData() bool {
 	return mod.compiler.inData()
 }
 
+func (mod *Module) RelativeInstallPath() string {
+	if mod.compiler != nil {
+		return mod.compiler.relativeInstallPath()
+	}
+	return ""
+}
+
+func (mod *Module) installable() bool {
+	if mod.Properties.PreventInstall {
+		return false
+	}
+
+	// The platform variant doesn't need further condition. Apex variants however might not
+	// be installable because it will likely to be included in the APEX and won't appear
+	// in the system partition. Modules installed to /data, such as fuzz targets, are the
+	// exception since even their APEX variants are installed there.
+	return mod.IsForPlatform() || mod.InstallInData()
+}
+
 func linkPathFromFilePath(filepath android.Path) string {
 	return strings.Split(filepath.String(), filepath.Base())[0]
 }
@@ -987,7 +1031,10 @@ func (mod *Module) DepsMutator(actx android.BottomUpMutatorContext) {
 		commonDepVariations = append(commonDepVariations,
 			blueprint.Variation{Mutator: "version", Variation: ""})
 	}
-	if !mod.Host() {
+	if mod.UseVndk() {
+		commonDepVariations = append(commonDepVariations, mod.ImageVariation())
+		mod.rewriteVendorDeps(ctx, &deps)
+	} else if !mod.Host() {
 		commonDepVariations = append(commonDepVariations,
 			blueprint.Variation{Mutator: "image", Variation: android.CoreVariation})
 	}
@@ -1064,6 +1111,69 @@ func (mod *Module) disableClippy() {
 	}
 }
 
+var _ android.ApexModule = (*Module)(nil)
+
+func (mod *Module) MinSdkVersion() string {
+	return String(mod.Properties.Min_sdk_version)
+}
+
+// Only shared (rust_ffi_shared) and dylib rust libraries are installable to APEX.
+func (mod *Module) IsInstallableToApex() bool {
+	if mod.compiler != nil {
+		if library, ok := mod.compiler.(libraryInterface); ok {
+			return library.shared() || library.dylib()
+		}
+	}
+	return false
+}
+
+func (mod *Module) DepIsInSameApex(ctx android.BaseModuleContext, dep android.Module) bool {
+	depTag := ctx.OtherModuleDependencyTag(dep)
+
+	// proc_macros and bindgen tools are host tools that only run during the build.
+	if depTag == procMacroDepTag || depTag == customBindgenDepTag {
+		return false
+	}
+
+	if ccm, ok := dep.(*cc.Module); ok && ccm.HasStubsVariants() {
+		if cc.IsSharedDepTag(depTag) || cc.IsRuntimeDepTag(depTag) {
+			// dynamic dep to a stubs lib crosses APEX boundary
+			return false
+		}
+	}
+
+	return true
+}
+
+func (mod *Module) ShouldSupportSdkVersion(ctx android.BaseModuleContext, sdkVersion int) error {
+	// We don't check for prebuilt modules
+	if _, ok := mod.compiler.(*prebuiltLibraryDecorator); ok {
+		return nil
+	}
+	minSdkVersion := mod.MinSdkVersion()
+	if minSdkVersion == "apex_inherit" {
+		return nil
+	}
+	ver, err := cc.DecodeSdkVersionString(ctx, minSdkVersion)
+	if err != nil {
+		return err
+	}
+	if ver > sdkVersion {
+		return fmt.Errorf("newer SDK(%v)", ver)
+	}
+	return nil
+}
+
+// IsDylibDepTag returns true if the dependency tag links a rust dylib.
+func IsDylibDepTag(depTag blueprint.DependencyTag) bool {
+	return depTag == dylibDepTag
+}
+
+// IsRlibDepTag returns true if the dependency tag links a rust rlib.
+func IsRlibDepTag(depTag blueprint.DependencyTag) bool {
+	return depTag == rlibDepTag
+}
+
 var _ android.HostToolProvider = (*Module)(nil)
 
 func (mod *Module) HostToolPath() android.OptionalPath {
@@ -80,12 +80,15 @@ func testRustCov(t *testing.T, bp string) *android.TestContext {
 	return testRustContext(t, bp, true)
 }
 
-func testRustContext(t *testing.T, bp string, coverage bool) *android.TestContext {
-	// TODO (b/140435149)
-	if runtime.GOOS != "linux" {
-		t.Skip("Only the Linux toolchain is supported for Rust")
-	}
+func testRustVndk(t *testing.T, bp string) *android.TestContext {
+	t.Helper()
+	config := testConfig(bp)
+	config.TestProductVariables.DeviceVndkVersion = StringPtr("current")
+	config.TestProductVariables.Platform_vndk_version = StringPtr("VER")
+	return testRustWithConfig(t, config)
+}
 
+func testRustContext(t *testing.T, bp string, coverage bool) *android.TestContext {
 	t.Helper()
 	config := testConfig(bp)
 
@@ -95,6 +98,16 @@ func testRustContext(t *testing.T, bp string, coverage bool) *android.TestContex
 		config.TestProductVariables.NativeCoveragePaths = []string{"*"}
 	}
 
+	return testRustWithConfig(t, config)
+}
+
+func testRustWithConfig(t *testing.T, config android.Config) *android.TestContext {
+	// TODO (b/140435149)
+	if runtime.GOOS != "linux" {
+		t.Skip("Only the Linux toolchain is supported for Rust")
+	}
+
+	t.Helper()
 	ctx := CreateTestContext()
 	ctx.Register(config)
 
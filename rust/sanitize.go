@@ -0,0 +1,339 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"fmt"
+
+	"github.com/google/blueprint"
+	"github.com/google/blueprint/proptools"
+
+	"android/soong/android"
+	"android/soong/cc"
+	cc_config "android/soong/cc/config"
+)
+
+type SanitizeProperties struct {
+	// enable AddressSanitizer, HWAddressSanitizer, and others.
+	Sanitize struct {
+		Address   *bool `android:"arch_variant"`
+		Hwaddress *bool `android:"arch_variant"`
+		Fuzzer    *bool `android:"arch_variant"`
+		Never     *bool `android:"arch_variant"`
+	}
+	SanitizerEnabled bool `blueprint:"mutated"`
+	SanitizeDep      bool `blueprint:"mutated"`
+
+	// Used when we need to place libraries in their own directory, such as ASAN.
+	InSanitizerDir bool `blueprint:"mutated"`
+}
+
+var fuzzerFlags = []string{
+	"-C passes='sancov'",
+
+	"--cfg fuzzing",
+	"-C llvm-args=-sanitizer-coverage-level=4",
+	"-C llvm-args=-sanitizer-coverage-trace-compares",
+	"-C llvm-args=-sanitizer-coverage-inline-8bit-counters",
+	"-C llvm-args=-sanitizer-coverage-trace-geps",
+	"-C llvm-args=-sanitizer-coverage-prune-blocks=0",
+
+	// Sancov breaks with lto
+	// TODO: Remove when https://bugs.llvm.org/show_bug.cgi?id=41734 is resolved and sancov works with LTO
+	"-C lto=no",
+}
+
+var asanFlags = []string{
+	"-Z sanitizer=address",
+}
+
+var hwasanFlags = []string{
+	"-Z sanitizer=hwaddress",
+	"-C target-feature=+tagged-globals",
+}
+
+type sanitize struct {
+	Properties SanitizeProperties
+}
+
+func (sanitize *sanitize) props() []interface{} {
+	return []interface{}{&sanitize.Properties}
+}
+
+func (sanitize *sanitize) begin(ctx BaseModuleContext) {
+	s := &sanitize.Properties.Sanitize
+
+	// Never always wins.
+	if Bool(s.Never) {
+		return
+	}
+
+	var globalSanitizers []string
+
+	if ctx.Device() {
+		globalSanitizers = ctx.Config().SanitizeDevice()
+		if len(globalSanitizers) > 0 {
+			var found bool
+			if found, globalSanitizers = android.RemoveFromList("address", globalSanitizers); found && s.Address == nil {
+				s.Address = proptools.BoolPtr(true)
+			}
+
+			if found, globalSanitizers = android.RemoveFromList("hwaddress", globalSanitizers); found && s.Hwaddress == nil {
+				s.Hwaddress = proptools.BoolPtr(true)
+			}
+
+			if found, globalSanitizers = android.RemoveFromList("fuzzer", globalSanitizers); found && s.Fuzzer == nil {
+				s.Fuzzer = proptools.BoolPtr(true)
+			}
+		}
+	}
+
+	toolchain := ctx.RustModule().ccToolchain(ctx)
+
+	// Drop sanitizers the target toolchain has no runtime for.
+	if !cc_config.SanitizerSupported(toolchain, "address") {
+		s.Address = nil
+	}
+	if !cc_config.SanitizerSupported(toolchain, "hwaddress") {
+		s.Hwaddress = nil
+	}
+	if !cc_config.SanitizerSupported(toolchain, "fuzzer") {
+		s.Fuzzer = nil
+	}
+
+	// TODO: Remove once host sanitizers are supported by the rust toolchain.
+	if ctx.Host() {
+		s.Address = nil
+		s.Hwaddress = nil
+		s.Fuzzer = nil
+	}
+
+	// HWASan requires AArch64 hardware feature (top-byte-ignore), and is
+	// incompatible with ASan.
+	if Bool(s.Hwaddress) {
+		s.Address = nil
+	}
+
+	if ctx.Os() == android.Android && Bool(s.Fuzzer) {
+		// The fuzzer runtime on device is built with HWASan where available.
+		if cc_config.SanitizerSupported(toolchain, "hwaddress") {
+			s.Hwaddress = proptools.BoolPtr(true)
+			s.Address = nil
+		} else {
+			s.Address = proptools.BoolPtr(true)
+		}
+	}
+
+	if Bool(s.Address) || Bool(s.Hwaddress) || Bool(s.Fuzzer) {
+		sanitize.Properties.SanitizerEnabled = true
+	}
+}
+
+func (sanitize *sanitize) flags(ctx ModuleContext, flags Flags, deps PathDeps) (Flags, PathDeps) {
+	if !sanitize.Properties.SanitizerEnabled {
+		return flags, deps
+	}
+	if Bool(sanitize.Properties.Sanitize.Fuzzer) {
+		flags.RustFlags = append(flags.RustFlags, fuzzerFlags...)
+	}
+	if Bool(sanitize.Properties.Sanitize.Hwaddress) {
+		flags.RustFlags = append(flags.RustFlags, hwasanFlags...)
+	} else if Bool(sanitize.Properties.Sanitize.Address) {
+		flags.RustFlags = append(flags.RustFlags, asanFlags...)
+	}
+	return flags, deps
+}
+
+// SanitizerRuntimeMutator adds the sanitizer runtime libraries to rust binaries and shared
+// libraries once the cc sanitizer mutators have settled which variants are sanitized.
+func SanitizerRuntimeMutator(mctx android.BottomUpMutatorContext) {
+	mod, ok := mctx.Module().(*Module)
+	if !ok || mod.sanitize == nil || !mod.Enabled() {
+		return
+	}
+	if !mod.sanitize.Properties.SanitizerEnabled || !mctx.Device() {
+		return
+	}
+
+	// Static libraries and rlibs do not carry a dependency on the runtime; it is added
+	// to the binaries or shared libraries that link them.
+	if mod.compiler == nil {
+		return
+	}
+	if lib, ok := mod.compiler.(libraryInterface); ok && !lib.shared() && !lib.dylib() {
+		return
+	}
+
+	toolchain := mod.ccToolchain(mctx)
+	var runtimeLibrary string
+	if Bool(mod.sanitize.Properties.Sanitize.Hwaddress) {
+		runtimeLibrary = cc_config.HWAddressSanitizerRuntimeLibrary(toolchain)
+	} else if Bool(mod.sanitize.Properties.Sanitize.Address) {
+		runtimeLibrary = cc_config.AddressSanitizerRuntimeLibrary(toolchain)
+	} else {
+		return
+	}
+
+	// The runtime libraries are not mutated by the sanitizer mutators, so the
+	// dependency is added with *FarVariation* like cc does.
+	imageVariation := blueprint.Variation{Mutator: "image", Variation: android.CoreVariation}
+	if mod.UseVndk() {
+		imageVariation = mod.ImageVariation()
+	}
+	variations := append(mctx.Target().Variations(),
+		blueprint.Variation{Mutator: "link", Variation: "shared"}, imageVariation)
+	mctx.AddFarVariationDependencies(variations, cc.SharedDepTag(), runtimeLibrary)
+}
+
+func boolPtr(v bool) *bool {
+	if v {
+		return &v
+	}
+	return nil
+}
+
+func (sanitize *sanitize) SetSanitizer(t cc.SanitizerType, b bool) {
+	sanitizerSet := false
+	switch t {
+	case cc.Fuzzer:
+		sanitize.Properties.Sanitize.Fuzzer = boolPtr(b)
+		sanitizerSet = true
+	case cc.Asan:
+		sanitize.Properties.Sanitize.Address = boolPtr(b)
+		sanitizerSet = true
+	case cc.Hwasan:
+		sanitize.Properties.Sanitize.Hwaddress = boolPtr(b)
+		sanitizerSet = true
+	default:
+		panic(fmt.Errorf("setting unsupported sanitizerType %d", t))
+	}
+	if b && sanitizerSet {
+		sanitize.Properties.SanitizerEnabled = true
+	}
+}
+
+// Check if the sanitizer is explicitly disabled (as opposed to nil by
+// virtue of not being set).
+func (sanitize *sanitize) isSanitizerExplicitlyDisabled(t cc.SanitizerType) bool {
+	if sanitize == nil {
+		return false
+	}
+	if Bool(sanitize.Properties.Sanitize.Never) {
+		return true
+	}
+	sanitizerVal := sanitize.getSanitizerBoolPtr(t)
+	return sanitizerVal != nil && *sanitizerVal == false
+}
+
+// There isn't an analog of the method above (ie:isSanitizerExplicitlyEnabled)
+// because enabling a sanitizer either directly (via the blueprint) or
+// indirectly (via a mutator) sets the bool ptr to true, and you can't
+// distinguish between the cases. It isn't needed though - both cases can be
+// treated identically.
+func (sanitize *sanitize) isSanitizerEnabled(t cc.SanitizerType) bool {
+	if sanitize == nil || !sanitize.Properties.SanitizerEnabled {
+		return false
+	}
+
+	sanitizerVal := sanitize.getSanitizerBoolPtr(t)
+	return sanitizerVal != nil && *sanitizerVal == true
+}
+
+func (sanitize *sanitize) getSanitizerBoolPtr(t cc.SanitizerType) *bool {
+	switch t {
+	case cc.Fuzzer:
+		return sanitize.Properties.Sanitize.Fuzzer
+	case cc.Asan:
+		return sanitize.Properties.Sanitize.Address
+	case cc.Hwasan:
+		return sanitize.Properties.Sanitize.Hwaddress
+	default:
+		return nil
+	}
+}
+
+var _ cc.PlatformSanitizeable = (*Module)(nil)
+
+func (mod *Module) SanitizerSupported(t cc.SanitizerType) bool {
+	if mod.sanitize == nil || mod.Host() {
+		return false
+	}
+	// Prebuilts and proc macros cannot be rebuilt with a sanitizer.
+	switch mod.compiler.(type) {
+	case *prebuiltLibraryDecorator, *procMacroDecorator:
+		return false
+	}
+	switch t {
+	case cc.Fuzzer, cc.Asan, cc.Hwasan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (mod *Module) IsSanitizerEnabled(t cc.SanitizerType) bool {
+	return mod.sanitize.isSanitizerEnabled(t)
+}
+
+func (mod *Module) IsSanitizerExplicitlyDisabled(t cc.SanitizerType) bool {
+	return mod.sanitize.isSanitizerExplicitlyDisabled(t)
+}
+
+func (mod *Module) SanitizeNever() bool {
+	return Bool(mod.sanitize.Properties.Sanitize.Never)
+}
+
+func (mod *Module) SetSanitizer(t cc.SanitizerType, b bool) {
+	if mod.sanitize != nil {
+		mod.sanitize.SetSanitizer(t, b)
+	}
+}
+
+func (mod *Module) SanitizeDep() bool {
+	return mod.sanitize != nil && mod.sanitize.Properties.SanitizeDep
+}
+
+func (mod *Module) SetSanitizeDep(b bool) {
+	if mod.sanitize != nil {
+		mod.sanitize.Properties.SanitizeDep = b
+	}
+}
+
+func (mod *Module) SetInSanitizerDir() {
+	if mod.sanitize != nil {
+		mod.sanitize.Properties.InSanitizerDir = true
+	}
+}
+
+func (mod *Module) StaticallyLinked() bool {
+	if lib, ok := mod.compiler.(libraryInterface); ok {
+		return lib.rlib() || lib.static()
+	}
+	return false
+}
+
+// Returns true for dependency roots (binaries)
+func (mod *Module) IsDependencyRoot() bool {
+	if root, ok := mod.compiler.(interface {
+		isDependencyRoot() bool
+	}); ok {
+		return root.isDependencyRoot()
+	}
+	return false
+}
+
+func (mod *Module) InstallInSanitizerDir() bool {
+	return mod.sanitize != nil && mod.sanitize.Properties.InSanitizerDir
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"strings"
+	"testing"
+
+	"android/soong/android"
+)
+
+// Test that hwasan propagates from a rust binary to its static dependencies.
+func TestHwasanSanitizer(t *testing.T) {
+	ctx := testRust(t, `
+		rust_binary {
+			name: "fizz",
+			srcs: ["foo.rs"],
+			static_libs: ["libbar"],
+			rustlibs: ["libbaz"],
+			sanitize: {
+				hwaddress: true,
+			},
+		}
+		rust_library_rlib {
+			name: "libbaz",
+			srcs: ["foo.rs"],
+			crate_name: "baz",
+		}
+		rust_ffi_static {
+			name: "libbar",
+			srcs: ["foo.rs"],
+			crate_name: "bar",
+		}
+		rust_binary {
+			name: "buzz",
+			srcs: ["foo.rs"],
+			sanitize: {
+				never: true,
+			},
+		}`)
+
+	fizz := ctx.ModuleForTests("fizz", "android_arm64_armv8-a_hwasan").Rule("rustc")
+	if !strings.Contains(fizz.Args["rustcFlags"], "-Z sanitizer=hwaddress") {
+		t.Errorf("missing hwasan rustc flag for 'fizz'; rustcFlags: %#v", fizz.Args["rustcFlags"])
+	}
+	if !strings.Contains(fizz.Args["linkFlags"], "libclang_rt.hwasan-aarch64-android") {
+		t.Errorf("hwasan runtime not linked into 'fizz'; linkFlags: %#v", fizz.Args["linkFlags"])
+	}
+
+	variants := ctx.ModuleVariantsForTests("libbar")
+	if !android.InList("android_arm64_armv8-a_static_hwasan", variants) {
+		t.Errorf("missing hwasan variant of 'libbar'; variants: %#v", variants)
+	}
+	if !android.InList("android_arm64_armv8-a_static", variants) {
+		t.Errorf("missing non-sanitized variant of 'libbar'; variants: %#v", variants)
+	}
+
+	variants = ctx.ModuleVariantsForTests("libbaz")
+	if !android.InList("android_arm64_armv8-a_rlib_hwasan", variants) {
+		t.Errorf("missing hwasan variant of 'libbaz'; variants: %#v", variants)
+	}
+	libbaz := ctx.ModuleForTests("libbaz", "android_arm64_armv8-a_rlib_hwasan").Rule("rustc")
+	if !strings.Contains(libbaz.Args["rustcFlags"], "-Z sanitizer=hwaddress") {
+		t.Errorf("missing hwasan rustc flag for 'libbaz'; rustcFlags: %#v", libbaz.Args["rustcFlags"])
+	}
+	fizzMod := ctx.ModuleForTests("fizz", "android_arm64_armv8-a_hwasan").Module().(*Module)
+	if !android.InList("libbaz.hwasan", fizzMod.Properties.AndroidMkRlibs) {
+		t.Errorf("hwasan variant of 'libbaz' not exported to make for 'fizz'; rlibs: %#v",
+			fizzMod.Properties.AndroidMkRlibs)
+	}
+
+	buzz := ctx.ModuleForTests("buzz", "android_arm64_armv8-a").Rule("rustc")
+	if strings.Contains(buzz.Args["rustcFlags"], "-Z sanitizer") {
+		t.Errorf("sanitizer enabled for 'buzz' with sanitize.never; rustcFlags: %#v", buzz.Args["rustcFlags"])
+	}
+}
+
+// Test that asan propagates from a rust binary to its static and rlib dependencies.
+func TestAsanSanitizer(t *testing.T) {
+	ctx := testRust(t, `
+		rust_binary {
+			name: "fizz",
+			srcs: ["foo.rs"],
+			static_libs: ["libbar"],
+			rustlibs: ["libbaz"],
+			sanitize: {
+				address: true,
+			},
+		}
+		rust_ffi_static {
+			name: "libbar",
+			srcs: ["foo.rs"],
+			crate_name: "bar",
+		}
+		rust_library_rlib {
+			name: "libbaz",
+			srcs: ["foo.rs"],
+			crate_name: "baz",
+		}`)
+
+	fizz := ctx.ModuleForTests("fizz", "android_arm64_armv8-a_asan").Rule("rustc")
+	if !strings.Contains(fizz.Args["rustcFlags"], "-Z sanitizer=address") {
+		t.Errorf("missing asan rustc flag for 'fizz'; rustcFlags: %#v", fizz.Args["rustcFlags"])
+	}
+	if !strings.Contains(fizz.Args["linkFlags"], "libclang_rt.asan-aarch64-android") {
+		t.Errorf("asan runtime not linked into 'fizz'; linkFlags: %#v", fizz.Args["linkFlags"])
+	}
+
+	for _, lib := range []struct{ name, variant string }{
+		{"libbar", "android_arm64_armv8-a_static_asan"},
+		{"libbaz", "android_arm64_armv8-a_rlib_asan"},
+	} {
+		variants := ctx.ModuleVariantsForTests(lib.name)
+		if !android.InList(lib.variant, variants) {
+			t.Errorf("missing asan variant of %q; variants: %#v", lib.name, variants)
+			continue
+		}
+		rustc := ctx.ModuleForTests(lib.name, lib.variant).Rule("rustc")
+		if !strings.Contains(rustc.Args["rustcFlags"], "-Z sanitizer=address") {
+			t.Errorf("missing asan rustc flag for %q; rustcFlags: %#v", lib.name, rustc.Args["rustcFlags"])
+		}
+	}
+}
+
+// Test that the fuzzer sanitizer, which implies hwasan on arm64 devices, propagates from a rust
+// binary to its static and rlib dependencies.
+func TestFuzzerSanitizer(t *testing.T) {
+	ctx := testRust(t, `
+		rust_binary {
+			name: "fizz",
+			srcs: ["foo.rs"],
+			static_libs: ["libbar"],
+			rustlibs: ["libbaz"],
+			sanitize: {
+				fuzzer: true,
+			},
+		}
+		rust_ffi_static {
+			name: "libbar",
+			srcs: ["foo.rs"],
+			crate_name: "bar",
+		}
+		rust_library_rlib {
+			name: "libbaz",
+			srcs: ["foo.rs"],
+			crate_name: "baz",
+		}`)
+
+	fizz := ctx.ModuleForTests("fizz", "android_arm64_armv8-a_hwasan_fuzzer").Rule("rustc")
+	if !strings.Contains(fizz.Args["rustcFlags"], "-C passes='sancov'") {
+		t.Errorf("missing fuzzer rustc flags for 'fizz'; rustcFlags: %#v", fizz.Args["rustcFlags"])
+	}
+
+	for _, lib := range []struct{ name, variant string }{
+		{"libbar", "android_arm64_armv8-a_static_hwasan_fuzzer"},
+		{"libbaz", "android_arm64_armv8-a_rlib_hwasan_fuzzer"},
+	} {
+		variants := ctx.ModuleVariantsForTests(lib.name)
+		if !android.InList(lib.variant, variants) {
+			t.Errorf("missing fuzzer variant of %q; variants: %#v", lib.name, variants)
+			continue
+		}
+		rustc := ctx.ModuleForTests(lib.name, lib.variant).Rule("rustc")
+		if !strings.Contains(rustc.Args["rustcFlags"], "-C passes='sancov'") {
+			t.Errorf("missing fuzzer rustc flags for %q; rustcFlags: %#v", lib.name, rustc.Args["rustcFlags"])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"sync"
+
+	"android/soong/android"
+	"android/soong/cc"
+)
+
+// The vendor snapshot itself is generated by the cc vendor-snapshot singleton, which captures
+// rust static libraries and rlibs through the cc.SnapshotLibrary interface. Static libraries are
+// linked by cc modules and are restored as vendor_snapshot_static modules; rlibs are restored as
+// the vendor_snapshot_rlib modules defined here.
+
+const vendorSnapshotRlibSuffix = ".vendor_rlib."
+
+var (
+	vendorSnapshotsLock    sync.Mutex
+	vendorSnapshotRlibsKey = android.NewOnceKey("vendorSnapshotRlibs")
+)
+
+func vendorSnapshotRlibs(config android.Config) *cc.SnapshotMap {
+	return config.Once(vendorSnapshotRlibsKey, func() interface{} {
+		return cc.NewSnapshotMap()
+	}).(*cc.SnapshotMap)
+}
+
+func init() {
+	android.RegisterModuleType("vendor_snapshot_rlib", VendorSnapshotRlibFactory)
+}
+
+type vendorSnapshotRlibProperties struct {
+	// snapshot version.
+	Version string
+
+	// Target arch name of the snapshot (e.g. 'arm64' for variant 'aosp_arm64')
+	Target_arch string
+}
+
+type vendorSnapshotRlibDecorator struct {
+	*prebuiltLibraryDecorator
+	properties vendorSnapshotRlibProperties
+}
+
+var _ compiler = (*vendorSnapshotRlibDecorator)(nil)
+
+// vendor_snapshot_rlib is a snapshot of a vendor rlib captured by the vendor snapshot. It
+// replaces the source module of the same name for vendor modules built against the
+// BOARD_VNDK_VERSION it was captured with.
+func VendorSnapshotRlibFactory() android.Module {
+	module, prebuilt := NewPrebuiltRlib(android.DeviceSupported)
+	snapshot := &vendorSnapshotRlibDecorator{
+		prebuiltLibraryDecorator: prebuilt,
+	}
+	module.compiler = snapshot
+	module.AddProperties(&snapshot.properties)
+
+	android.AddLoadHook(module, func(ctx android.LoadHookContext) {
+		if snapshot.version() != ctx.DeviceConfig().VndkVersion() {
+			ctx.Module().Disable()
+		}
+	})
+
+	return module.Init()
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) Name(name string) string {
+	return name + snapshot.NameSuffix()
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) NameSuffix() string {
+	versionSuffix := snapshot.version()
+	if snapshot.arch() != "" {
+		versionSuffix += "." + snapshot.arch()
+	}
+	return vendorSnapshotRlibSuffix + versionSuffix
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) version() string {
+	return snapshot.properties.Version
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) arch() string {
+	return snapshot.properties.Target_arch
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) isSnapshotPrebuilt() bool {
+	return true
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) matchesWithDevice(config android.DeviceConfig) bool {
+	arches := config.Arches()
+	if len(arches) == 0 || arches[0].ArchType.String() != snapshot.arch() {
+		return false
+	}
+	return len(snapshot.prebuiltSrcs()) > 0
+}
+
+func (snapshot *vendorSnapshotRlibDecorator) compile(ctx ModuleContext, flags Flags, deps PathDeps) android.Path {
+	if !snapshot.matchesWithDevice(ctx.DeviceConfig()) {
+		return nil
+	}
+	return snapshot.prebuiltLibraryDecorator.compile(ctx, flags, deps)
+}
+
+// VendorSnapshotMutator gathers all rlib snapshot modules for vendor, and disables the
+// unnecessary ones.
+func VendorSnapshotMutator(ctx android.BottomUpMutatorContext) {
+	vndkVersion := ctx.DeviceConfig().VndkVersion()
+	// don't need snapshot if current
+	if vndkVersion == "current" || vndkVersion == "" {
+		return
+	}
+
+	module, ok := ctx.Module().(*Module)
+	if !ok || !module.Enabled() || module.VndkVersion() != vndkVersion {
+		return
+	}
+
+	snapshot, ok := module.compiler.(*vendorSnapshotRlibDecorator)
+	if !ok {
+		return
+	}
+
+	if !snapshot.matchesWithDevice(ctx.DeviceConfig()) {
+		module.Disable()
+		return
+	}
+
+	vendorSnapshotsLock.Lock()
+	defer vendorSnapshotsLock.Unlock()
+	vendorSnapshotRlibs(ctx.Config()).Add(module.BaseModuleName(), ctx.Arch().ArchType, ctx.ModuleName())
+}
+
+// rewriteVendorDeps replaces the dependencies of a vendor or product variant with the LL-NDK
+// stubs and the vendor snapshot prebuilts that provide them.
+func (mod *Module) rewriteVendorDeps(ctx DepsContext, deps *Deps) {
+	vndkVersion := mod.VndkVersion()
+
+	for i, lib := range deps.SharedLibs {
+		deps.SharedLibs[i] = cc.RewriteVendorSharedLib(ctx, vndkVersion, lib)
+	}
+	for i, lib := range deps.StaticLibs {
+		deps.StaticLibs[i] = cc.RewriteVendorStaticLib(ctx, vndkVersion, lib)
+	}
+	if deps.CrtBegin != "" {
+		deps.CrtBegin = cc.RewriteVendorObject(ctx, vndkVersion, deps.CrtBegin)
+	}
+	if deps.CrtEnd != "" {
+		deps.CrtEnd = cc.RewriteVendorObject(ctx, vndkVersion, deps.CrtEnd)
+	}
+
+	rlibs := vendorSnapshotRlibs(ctx.Config())
+	for i, lib := range deps.Rlibs {
+		deps.Rlibs[i] = cc.RewriteSnapshotLib(ctx, vndkVersion, lib, rlibs)
+	}
+	if mod.compiler != nil && !mod.compiler.Disabled() {
+		if autoDep := mod.compiler.(autoDeppable).autoDep(ctx); autoDep.depTag == rlibDepTag {
+			for i, lib := range deps.Rustlibs {
+				deps.Rustlibs[i] = cc.RewriteSnapshotLib(ctx, vndkVersion, lib, rlibs)
+			}
+		}
+	}
+}
+
+var _ cc.SnapshotLibrary = (*Module)(nil)
+
+func (mod *Module) IsVendorSnapshotLibrary(inVendorProprietaryPath bool) bool {
+	if !mod.Enabled() || mod.Properties.HideFromMake {
+		return false
+	}
+	// skip proprietary modules
+	if inVendorProprietaryPath {
+		return false
+	}
+	if mod.Target().Os.Class != android.Device {
+		return false
+	}
+	if mod.Target().NativeBridge == android.NativeBridgeEnabled {
+		return false
+	}
+	// the module must be installed in /vendor
+	if !mod.IsForPlatform() || mod.isSnapshotPrebuilt() || !mod.inVendor() {
+		return false
+	}
+	// TODO: add support for sanitized variants
+	if mod.sanitize != nil && mod.sanitize.Properties.SanitizerEnabled {
+		return false
+	}
+	// Only static libraries and rlibs are captured; prebuilts and fuzzers are excluded by
+	// requiring the plain library decorator.
+	library, ok := mod.compiler.(*libraryDecorator)
+	if !ok || !(library.static() || library.rlib()) {
+		return false
+	}
+	return mod.outputFile.Valid() && BoolDefault(mod.VendorProperties.Vendor_available, true)
+}
+
+func (mod *Module) SnapshotLibType() string {
+	if library, ok := mod.compiler.(libraryInterface); ok && library.static() {
+		return "static"
+	}
+	return "rlib"
+}
+
+func (mod *Module) SnapshotOutputFile() android.Path {
+	return mod.outputFile.Path()
+}
+
+func (mod *Module) SnapshotExportedDirs() android.Paths {
+	if library, ok := mod.compiler.(*libraryDecorator); ok && library.static() {
+		return library.includeDirs
+	}
+	return nil
+}
+
+func (mod *Module) SnapshotHeaders() android.Paths {
+	if library, ok := mod.compiler.(*libraryDecorator); ok {
+		return library.collectedSnapshotHeaders
+	}
+	return nil
+}
+
+func (mod *Module) SnapshotCrateName() string {
+	return mod.CrateName()
+}
+
+func (mod *Module) SnapshotRlibs() []string {
+	return mod.Properties.SnapshotRlibs
+}
+
+// collectHeadersForSnapshot collects the headers exported by static libraries so that they
+// can be captured in the vendor snapshot.
+func (mod *Module) collectHeadersForSnapshot(ctx android.ModuleContext) {
+	if library, ok := mod.compiler.(*libraryDecorator); ok && library.static() {
+		library.collectedSnapshotHeaders = cc.GlobHeadersForSnapshot(ctx, library.includeDirs)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2020 The Android Open Source Project
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rust
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"android/soong/android"
+	"android/soong/cc"
+)
+
+func TestVendorSnapshotCapture(t *testing.T) {
+	ctx := testRustVndk(t, `
+			rust_ffi_static {
+				name: "libffi_vendor_available",
+				crate_name: "ffi_vendor_available",
+				srcs: ["foo.rs"],
+				vendor_available: true,
+			}
+			rust_library_rlib {
+				name: "librlib_vendor",
+				crate_name: "rlib_vendor",
+				srcs: ["foo.rs"],
+				vendor: true,
+			}
+			rust_library_rlib {
+				name: "librlib_system",
+				crate_name: "rlib_system",
+				srcs: ["foo.rs"],
+			}
+		`)
+
+	snapshotSingleton := ctx.SingletonForTests("vendor-snapshot")
+	archDir := filepath.Join(buildDir, "vendor-snapshot", "arm64", "arch-arm64-armv8-a")
+
+	for _, tc := range []struct {
+		name, variant, snapshotFile string
+	}{
+		{"libffi_vendor_available", "android_vendor.VER_arm64_armv8-a_static", "static/libffi_vendor_available.a"},
+		{"librlib_vendor", "android_vendor.VER_arm64_armv8-a_rlib", "rlib/librlib_vendor.rlib"},
+	} {
+		module := ctx.ModuleForTests(tc.name, tc.variant).Module().(*Module)
+		out := snapshotSingleton.Output(filepath.Join(archDir, tc.snapshotFile))
+		if out.Input.String() != module.outputFile.Path().String() {
+			t.Errorf("The input of snapshot %q must be %q, but %q", tc.name, module.outputFile.Path(), out.Input)
+		}
+		snapshotSingleton.Output(filepath.Join(archDir, tc.snapshotFile+".json"))
+	}
+
+	// Only vendor variants are captured.
+	for _, o := range snapshotSingleton.AllOutputs() {
+		if strings.Contains(o, "librlib_system") {
+			t.Errorf("librlib_system must not be captured, found %q", o)
+		}
+	}
+}
+
+func TestVendorSnapshotUse(t *testing.T) {
+	// TODO (b/140435149)
+	if runtime.GOOS != "linux" {
+		t.Skip("Only the Linux toolchain is supported for Rust")
+	}
+
+	frameworkBp := `
+			rust_library_rlib {
+				name: "librlib_vendor",
+				crate_name: "rlib_vendor",
+				srcs: ["foo.rs"],
+				vendor_available: true,
+			}
+		`
+
+	vendorProprietaryBp := `
+			rust_library_rlib {
+				name: "librlib_client",
+				crate_name: "rlib_client",
+				srcs: ["foo.rs"],
+				rlibs: ["librlib_vendor"],
+				no_stdlibs: true,
+				vendor: true,
+			}
+			vendor_snapshot_rlib {
+				name: "librlib_vendor",
+				crate_name: "rlib_vendor",
+				version: "BOARD",
+				target_arch: "arm64",
+				vendor: true,
+				arch: {
+					arm64: {
+						srcs: ["librlib_vendor.rlib"],
+					},
+				},
+			}
+		`
+
+	fs := map[string][]byte{
+		"foo.rs":                     nil,
+		"vendor/Android.bp":          []byte(vendorProprietaryBp),
+		"vendor/foo.rs":              nil,
+		"vendor/librlib_vendor.rlib": nil,
+	}
+	cc.GatherRequiredFilesForTest(fs)
+
+	config := android.TestArchConfig(buildDir, nil, frameworkBp+GatherRequiredDepsForTest(), fs)
+	config.TestProductVariables.DeviceVndkVersion = StringPtr("BOARD")
+	config.TestProductVariables.Platform_vndk_version = StringPtr("VER")
+
+	ctx := CreateTestContext()
+	ctx.Register(config)
+	_, errs := ctx.ParseFileList(".", []string{"Android.bp", "vendor/Android.bp"})
+	android.FailIfErrored(t, errs)
+	_, errs = ctx.PrepareBuildActions(config)
+	android.FailIfErrored(t, errs)
+
+	variant := "android_vendor.BOARD_arm64_armv8-a_rlib"
+
+	// librlib_client uses librlib_vendor.vendor_rlib.BOARD.arm64
+	client := ctx.ModuleForTests("librlib_client", variant)
+	snapshot := ctx.ModuleForTests("librlib_vendor.vendor_rlib.BOARD.arm64", variant).Module().(*Module)
+	libFlags := client.Rule("rustc").Args["libFlags"]
+	if !strings.Contains(libFlags, "rlib_vendor="+snapshot.outputFile.Path().String()) {
+		t.Errorf("libFlags for librlib_client must contain %q, but was %q", snapshot.outputFile.Path(), libFlags)
+	}
+
+	rlibs := client.Module().(*Module).Properties.AndroidMkRlibs
+	if !android.InList("librlib_vendor.vendor", rlibs) {
+		t.Errorf("librlib_client should depend on librlib_vendor.vendor in Make; rlibs: %#v", rlibs)
+	}
+
+	// The source module has no vendor.BOARD variant.
+	if variants := ctx.ModuleVariantsForTests("librlib_vendor"); android.InList(variant, variants) {
+		t.Errorf("librlib_vendor must not have variant %q, but it does", variant)
+	}
+}
@@ -24,3 +24,8 @@ import (
 func TempDirForOutDir(outDir string) (tempPath string) {
 	return filepath.Join(outDir, ".temp")
 }
+
+// Given the out directory, returns the directory of the local action cache of sbox (which is kept between executions of Soong)
+func SboxCacheDirForOutDir(outDir string) string {
+	return filepath.Join(outDir, ".sbox_cache")
+}
@@ -254,6 +254,9 @@ func Build(ctx Context, config Config, what int) {
 			installCleanIfNecessary(ctx, config)
 		}
 
+		// Trim the sbox cache even when ninja fails, since failed builds still add to it.
+		defer trimSboxCache(ctx, config)
+
 		// Run ninja
 		runNinja(ctx, config)
 	}
@@ -46,6 +46,7 @@ type configImpl struct {
 	checkbuild bool
 	dist       bool
 	skipMake   bool
+	explain    bool
 
 	// From the product config
 	katiArgs        []string
@@ -513,6 +514,8 @@ func (c *configImpl) parseArgs(ctx Context, args []string) {
 			c.verbose = true
 		} else if arg == "--skip-make" {
 			c.skipMake = true
+		} else if arg == "--explain" {
+			c.explain = true
 		} else if len(arg) > 0 && arg[0] == '-' {
 			parseArgNum := func(def int) int {
 				if len(arg) > 2 {
@@ -637,6 +640,8 @@ func (c *configImpl) Tapas(ctx Context, apps []string, arch, variant string) {
 		product = "aosp_arm"
 	case "arm64":
 		product = "aosm_arm64"
+	case "riscv64":
+		product = "aosp_riscv64"
 	case "x86":
 		product = "aosp_x86"
 	case "x86_64":
@@ -714,6 +719,12 @@ func (c *configImpl) SkipMake() bool {
 	return c.skipMake
 }
 
+// Explain returns true if --explain was passed, which asks ninja to explain why it reruns actions
+// and summarizes the explanations in NinjaExplainFile.
+func (c *configImpl) Explain() bool {
+	return c.explain
+}
+
 func (c *configImpl) TargetProduct() string {
 	if v, ok := c.environ.Get("TARGET_PRODUCT"); ok {
 		return v
@@ -955,6 +966,39 @@ func (c *configImpl) CombinedNinjaFile() string {
 	return filepath.Join(c.OutDir(), "combined"+c.KatiSuffix()+".ninja")
 }
 
+// ModuleActionsFile is the table written by soong_build that maps the outputs of the build actions
+// to the modules which created them.
+func (c *configImpl) ModuleActionsFile() string {
+	return filepath.Join(c.SoongOutDir(), "module_actions.json")
+}
+
+// SoongGlobDir contains the results of the globs of soong_build, which are rewritten by the
+// bootstrap ninja when the globbed directories change.
+func (c *configImpl) SoongGlobDir() string {
+	return filepath.Join(c.SoongOutDir(), ".glob")
+}
+
+// ModuleGraphFile is the module graph that soong_build writes when SOONG_COLLECT_MODULE_GRAPH is set.
+func (c *configImpl) ModuleGraphFile() string {
+	return filepath.Join(c.SoongOutDir(), "module_graph.json")
+}
+
+// SoongBpListFile is the copy of Android.bp.list that soong_build last ran with.
+func (c *configImpl) SoongBpListFile() string {
+	return filepath.Join(c.SoongOutDir(), ".soong_build.Android.bp.list")
+}
+
+// ChangedDirsFile lists the source directories that the finder saw change since soong_build last
+// ran. It doesn't exist if the changes are unknown.
+func (c *configImpl) ChangedDirsFile() string {
+	return filepath.Join(c.FileListDir(), "changed_dirs.list")
+}
+
+// NinjaExplainFile is the summary of the reasons ninja reran actions when --explain is passed.
+func (c *configImpl) NinjaExplainFile() string {
+	return filepath.Join(c.OutDir(), "ninja_explain.txt")
+}
+
 func (c *configImpl) SoongAndroidMk() string {
 	return filepath.Join(c.SoongOutDir(), "Android-"+c.TargetProduct()+".mk")
 }
@@ -17,12 +17,15 @@ package build
 import (
 	"android/soong/finder"
 	"android/soong/finder/fs"
+	"android/soong/ui/build/paths"
 	"android/soong/ui/logger"
 	"bytes"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"android/soong/ui/metrics"
 )
@@ -31,11 +34,28 @@ import (
 // This file stores configuration information about which files to find
 
 // NewSourceFinder returns a new Finder configured to search for source files.
+// If a finder daemon started by RunFinderDaemon is running, the Finder loads the source files
+// from it instead of checking the directories in its cache for changes.
 // Callers of NewSourceFinder should call <f.Shutdown()> when done
 func NewSourceFinder(ctx Context, config Config) (f *finder.Finder) {
 	ctx.BeginTrace(metrics.RunSetupTool, "find modules")
 	defer ctx.EndTrace()
 
+	cacheParams := sourceFinderParams(ctx)
+	dumpDir := config.FileListDir()
+	dial := func() (net.Conn, error) {
+		return paths.DialSocket(finderDaemonSocket(config), finderDaemonDialTimeout)
+	}
+	f, err := finder.NewWithDaemon(cacheParams, fs.OsFs, logger.New(ioutil.Discard),
+		filepath.Join(dumpDir, "files.db"), dial)
+	if err != nil {
+		ctx.Fatalf("Could not create module-finder: %v", err)
+	}
+	return f
+}
+
+// sourceFinderParams returns the parameters of the Finders that search for source files
+func sourceFinderParams(ctx Context) finder.CacheParams {
 	dir, err := os.Getwd()
 	if err != nil {
 		ctx.Fatalf("No working directory for module-finder: %v", err.Error())
@@ -53,7 +73,7 @@ func NewSourceFinder(ctx Context, config Config) (f *finder.Finder) {
 		}
 	}
 
-	cacheParams := finder.CacheParams{
+	return finder.CacheParams{
 		WorkingDirectory: dir,
 		RootDirs:         []string{"."},
 		ExcludeDirs:      []string{".git", ".repo"},
@@ -68,13 +88,58 @@ func NewSourceFinder(ctx Context, config Config) (f *finder.Finder) {
 			"TEST_MAPPING",
 		},
 	}
+}
+
+// How long NewSourceFinder waits to connect to the finder daemon
+const finderDaemonDialTimeout = time.Second
+
+func finderDaemonSocket(config Config) string {
+	return filepath.Join(config.FileListDir(), "finder.sock")
+}
+
+// RunFinderDaemon watches the source tree for changes to keep the source files found by
+// NewSourceFinder up to date, and serves them to NewSourceFinder in later builds that use the
+// same output directory, until ctx is canceled.
+func RunFinderDaemon(ctx Context, config Config) {
 	dumpDir := config.FileListDir()
-	f, err = finder.New(cacheParams, filesystem, logger.New(ioutil.Discard),
+	os.MkdirAll(dumpDir, 0777)
+
+	// Listen before creating the daemon, which rewrites the database of a running daemon.
+	socket := finderDaemonSocket(config)
+	listener, err := paths.ListenSocket(socket)
+	if err == paths.ErrSocketInUse {
+		ctx.Fatalf("A finder daemon is already running for %v", config.OutDir())
+	} else if err != nil {
+		ctx.Fatalf("Could not listen on %v: %v", socket, err)
+	}
+	defer os.Remove(socket)
+
+	d, err := finder.NewDaemon(sourceFinderParams(ctx), fs.OsFs, finderDaemonLogger{ctx.Logger},
 		filepath.Join(dumpDir, "files.db"))
 	if err != nil {
-		ctx.Fatalf("Could not create module-finder: %v", err)
+		ctx.Fatalf("Could not create finder daemon: %v", err)
 	}
-	return f
+	defer d.Close()
+
+	go func() {
+		<-ctx.Done()
+		d.Close()
+	}()
+
+	ctx.Println("Finder daemon is watching the source tree for builds in", config.OutDir())
+	if err := d.Serve(listener); err != nil {
+		ctx.Fatalf("Finder daemon failed: %v", err)
+	}
+}
+
+// finderDaemonLogger writes the messages of the finder daemon to the verbose log
+type finderDaemonLogger struct {
+	logger.Logger
+}
+
+func (l finderDaemonLogger) Output(calldepth int, s string) error {
+	l.Verbose(s)
+	return nil
 }
 
 // FindSources searches for source files known to <f> and writes them to the filesystem for
@@ -127,6 +192,9 @@ func FindSources(ctx Context, config Config, f *finder.Finder) {
 		ctx.Fatalf("Could not find modules: %v", err)
 	}
 
+	changedDirs, known := f.ChangedDirs()
+	recordChangedDirs(ctx, config, changedDirs, known)
+
 	if config.Dist() {
 		f.WaitForDbDump()
 		distFile(ctx, config, f.DbPath, "module_paths")
@@ -31,6 +31,13 @@ func runNinja(ctx Context, config Config) {
 	ctx.BeginTrace(metrics.PrimaryNinja, "ninja")
 	defer ctx.EndTrace()
 
+	if config.Explain() {
+		// Summarize the explanations once the ninja reader below has processed all of them.
+		explain := newNinjaExplain()
+		ctx.Status.AddOutput(explain)
+		defer writeNinjaExplain(ctx, config, explain)
+	}
+
 	fifo := filepath.Join(config.OutDir(), ".ninja_fifo")
 	nr := status.NewNinjaReader(ctx, ctx.Status.StartTool(), fifo)
 	defer nr.Close()
@@ -43,6 +50,10 @@ func runNinja(ctx Context, config Config) {
 		"--frontend_file", fifo,
 	}
 
+	if config.Explain() {
+		args = append(args, "-d", "explain")
+	}
+
 	args = append(args, config.NinjaArgs()...)
 
 	var parallel int
@@ -0,0 +1,225 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"android/soong/ui/status"
+)
+
+// The number of root causes printed to the terminal by --explain.
+const ninjaExplainTopCauses = 10
+
+type explainReason int
+
+const (
+	// An input of the output is newer than the output.
+	explainChangedInput explainReason = iota
+	// An input of the output is newer than the mtime recorded in the build log for the
+	// restat output.
+	explainRestat
+	explainCommandLineChanged
+	explainNotInBuildLog
+	explainMissingOutput
+	explainMissingDeps
+)
+
+var explainReasonDescriptions = map[explainReason]string{
+	explainChangedInput:       "changed file",
+	explainRestat:             "restat output older than",
+	explainCommandLineChanged: "command line changed",
+	explainNotInBuildLog:      "command not found in build log",
+	explainMissingOutput:      "missing output",
+	explainMissingDeps:        "missing deps",
+}
+
+var explainPatterns = []struct {
+	re     *regexp.Regexp
+	reason explainReason
+}{
+	{regexp.MustCompile(`^output (.+) older than most recent input (.+) \(-?\d+ vs -?\d+\)$`), explainChangedInput},
+	{regexp.MustCompile(`^restat of output (.+) older than most recent input (.+) \(-?\d+ vs -?\d+\)$`), explainRestat},
+	{regexp.MustCompile(`^recorded mtime of (.+) older than most recent input (.+) \(-?\d+ vs -?\d+\)$`), explainRestat},
+	{regexp.MustCompile(`^command line changed for (.+)$`), explainCommandLineChanged},
+	{regexp.MustCompile(`^command line not found in log for (.+)$`), explainNotInBuildLog},
+	{regexp.MustCompile(`^output (.+) of phony edge with no inputs doesn't exist$`), explainMissingOutput},
+	{regexp.MustCompile(`^output (.+) doesn't exist$`), explainMissingOutput},
+	{regexp.MustCompile(`^deps for '?(.+?)'? are missing$`), explainMissingDeps},
+}
+
+// ninjaExplainPrefix matches the explanations of ninja -d explain, which are either printed by
+// ninja ("ninja explain: ...") or sent as debug messages through the frontend ("ninja: explain: ...").
+var ninjaExplainPrefix = regexp.MustCompile(`^ninja:? explain: `)
+
+type explanation struct {
+	reason explainReason
+	input  string
+}
+
+// A rebuildCause is the root cause of a dirty output: a changed file that isn't the output of
+// another dirty action, or a reason which doesn't depend on the inputs.
+type rebuildCause struct {
+	reason explainReason
+	file   string
+}
+
+func (c rebuildCause) String() string {
+	if c.file == "" {
+		return explainReasonDescriptions[c.reason]
+	}
+	return explainReasonDescriptions[c.reason] + " " + c.file
+}
+
+// ninjaExplain is a StatusOutput which collects the explanations of ninja -d explain for why each
+// output is dirty.
+type ninjaExplain struct {
+	explanations map[string]explanation
+	outputs      []string
+}
+
+func newNinjaExplain() *ninjaExplain {
+	return &ninjaExplain{
+		explanations: make(map[string]explanation),
+	}
+}
+
+func (n *ninjaExplain) StartAction(action *status.Action, counts status.Counts)       {}
+func (n *ninjaExplain) FinishAction(result status.ActionResult, counts status.Counts) {}
+func (n *ninjaExplain) Flush()                                                        {}
+func (n *ninjaExplain) Write(p []byte) (int, error)                                   { return len(p), nil }
+
+func (n *ninjaExplain) Message(level status.MsgLevel, msg string) {
+	loc := ninjaExplainPrefix.FindStringIndex(msg)
+	if loc == nil {
+		return
+	}
+	msg = strings.TrimSpace(msg[loc[1]:])
+	for _, pattern := range explainPatterns {
+		if match := pattern.re.FindStringSubmatch(msg); match != nil {
+			output := match[1]
+			if _, ok := n.explanations[output]; ok {
+				// Only the first reason of an output is used, ninja stops there.
+				return
+			}
+			e := explanation{reason: pattern.reason}
+			if len(match) > 2 {
+				e.input = match[2]
+			}
+			n.explanations[output] = e
+			n.outputs = append(n.outputs, output)
+			return
+		}
+	}
+}
+
+// rootCause follows the changed inputs of an output through the outputs of other dirty actions to
+// the cause of the rebuild.
+func (n *ninjaExplain) rootCause(output string, causes map[string]rebuildCause, visiting map[string]bool) rebuildCause {
+	if cause, ok := causes[output]; ok {
+		return cause
+	}
+
+	e := n.explanations[output]
+	cause := rebuildCause{reason: e.reason}
+	if e.input != "" {
+		if _, dirty := n.explanations[e.input]; dirty && !visiting[e.input] {
+			visiting[output] = true
+			cause = n.rootCause(e.input, causes, visiting)
+			delete(visiting, output)
+		} else {
+			cause.file = e.input
+		}
+	}
+	causes[output] = cause
+	return cause
+}
+
+type rebuildCauseGroup struct {
+	cause   rebuildCause
+	outputs []string
+}
+
+// groups returns the dirty outputs grouped by root cause, from the cause of the most outputs to
+// the cause of the fewest.
+func (n *ninjaExplain) groups() []*rebuildCauseGroup {
+	causes := make(map[string]rebuildCause)
+	byCause := make(map[rebuildCause]*rebuildCauseGroup)
+	var groups []*rebuildCauseGroup
+	for _, output := range n.outputs {
+		cause := n.rootCause(output, causes, make(map[string]bool))
+		group := byCause[cause]
+		if group == nil {
+			group = &rebuildCauseGroup{cause: cause}
+			byCause[cause] = group
+			groups = append(groups, group)
+		}
+		group.outputs = append(group.outputs, output)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].outputs) > len(groups[j].outputs)
+	})
+	return groups
+}
+
+// summary returns the dirty outputs grouped by root cause, with the reason of each output that
+// isn't its root cause.
+func (n *ninjaExplain) summary(groups []*rebuildCauseGroup) []byte {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%d outputs were dirty, grouped by root cause:\n", len(n.outputs))
+	for _, group := range groups {
+		fmt.Fprintf(buf, "\n%d: %s\n", len(group.outputs), group.cause)
+		for _, output := range group.outputs {
+			e := n.explanations[output]
+			if e.input != "" && (e.input != group.cause.file || e.reason != group.cause.reason) {
+				fmt.Fprintf(buf, "    %s (%s %s)\n", output, explainReasonDescriptions[e.reason], e.input)
+			} else {
+				fmt.Fprintf(buf, "    %s\n", output)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+// writeNinjaExplain writes the summary of the explanations of ninja to NinjaExplainFile, and
+// prints the most common root causes.
+func writeNinjaExplain(ctx Context, config Config, n *ninjaExplain) {
+	groups := n.groups()
+	if err := ioutil.WriteFile(config.NinjaExplainFile(), n.summary(groups), 0666); err != nil {
+		ctx.Println("Failed to write the ninja explanations:", err)
+		return
+	}
+
+	st := ctx.Status.StartTool()
+	defer st.Finish()
+	if len(n.outputs) == 0 {
+		st.Print("ninja explain: no outputs were dirty")
+		return
+	}
+	st.Print(fmt.Sprintf("ninja explain: %d outputs were dirty, top causes (see %s):",
+		len(n.outputs), config.NinjaExplainFile()))
+	for i, group := range groups {
+		if i == ninjaExplainTopCauses {
+			st.Print(fmt.Sprintf("  ... and %d more causes", len(groups)-i))
+			break
+		}
+		st.Print(fmt.Sprintf("  %6d  %s", len(group.outputs), group.cause))
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"reflect"
+	"testing"
+
+	"android/soong/ui/status"
+)
+
+func TestNinjaExplain(t *testing.T) {
+	n := newNinjaExplain()
+	for _, msg := range []string{
+		// A source file change propagating through two actions.
+		"ninja explain: output out/a.o older than most recent input a.cpp (100 vs 200)",
+		"ninja explain: out/a.o is dirty",
+		"ninja explain: output out/liba.so older than most recent input out/a.o (100 vs 300)",
+		"ninja: explain: output out/bin older than most recent input out/liba.so (100 vs 400)",
+		// Only the first reason of an output is used.
+		"ninja explain: command line changed for out/bin",
+		"ninja explain: output out/b.o older than most recent input a.cpp (100 vs 200)",
+		// Restat outputs propagate like other outputs.
+		"ninja explain: restat of output out/gen.h older than most recent input out/gen.stamp (100 vs 200)",
+		"ninja explain: recorded mtime of out/gen.stamp older than most recent input gen.py (100 vs 200)",
+		"ninja explain: output out/c.o older than most recent input out/gen.h (100 vs 300)",
+		// Causes without inputs.
+		"ninja explain: command line changed for out/d.o",
+		"ninja explain: command line changed for out/e.o",
+		"ninja explain: output out/f.o doesn't exist",
+		"ninja explain: command line not found in log for out/g.o",
+		"ninja explain: deps for 'out/h.o' are missing",
+		// Other messages are ignored.
+		"ninja explain: out/d.o is dirty",
+		"ninja: build stopped",
+		"[1/2] //foo:bar javac",
+	} {
+		n.Message(status.PrintLvl, msg)
+	}
+
+	type group struct {
+		cause   string
+		outputs []string
+	}
+	var got []group
+	for _, g := range n.groups() {
+		got = append(got, group{g.cause.String(), g.outputs})
+	}
+	want := []group{
+		{"changed file a.cpp", []string{"out/a.o", "out/liba.so", "out/bin", "out/b.o"}},
+		{"restat output older than gen.py", []string{"out/gen.h", "out/gen.stamp", "out/c.o"}},
+		{"command line changed", []string{"out/d.o", "out/e.o"}},
+		{"missing output", []string{"out/f.o"}},
+		{"command not found in build log", []string{"out/g.o"}},
+		{"missing deps", []string{"out/h.o"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want groups:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestNinjaExplainCycle(t *testing.T) {
+	n := newNinjaExplain()
+	n.Message(status.PrintLvl, "ninja explain: output a older than most recent input b (1 vs 2)")
+	n.Message(status.PrintLvl, "ninja explain: output b older than most recent input a (1 vs 2)")
+
+	groups := n.groups()
+	if len(groups) != 1 || groups[0].cause.String() != "changed file a" {
+		t.Errorf("want a single group for changed file a, got %v", groups)
+	}
+}
@@ -17,6 +17,7 @@ package paths
 import (
 	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -132,6 +133,33 @@ func listen(name string, lookup socketAddrFunc) (net.Listener, error) {
 	return net.Listen("unix", socket)
 }
 
+// DialSocket connects to the unix socket at name, which may be longer than the limit on the
+// length of socket paths.
+func DialSocket(name string, timeout time.Duration) (net.Conn, error) {
+	return dial(name, getSocketAddr, timeout)
+}
+
+// ErrSocketInUse is returned by ListenSocket when another process is listening on the socket.
+var ErrSocketInUse = errors.New("another process is listening on the socket")
+
+// ListenSocket listens on the unix socket at name, which may be longer than the limit on the
+// length of socket paths. It returns ErrSocketInUse if another process answers on the socket,
+// otherwise it replaces any stale socket left behind at name.
+func ListenSocket(name string) (net.Listener, error) {
+	return listenSocket(name, getSocketAddr, timeoutDuration)
+}
+
+func listenSocket(name string, lookup socketAddrFunc, timeout time.Duration) (net.Listener, error) {
+	if conn, err := dial(name, lookup, timeout); err == nil {
+		conn.Close()
+		return nil, ErrSocketInUse
+	}
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return listen(name, lookup)
+}
+
 func SendLog(logSocket string, entry *LogEntry, done chan interface{}) {
 	sendLog(logSocket, getSocketAddr, timeoutDuration, entry, done)
 }
@@ -17,6 +17,7 @@ package paths
 import (
 	"context"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -152,3 +153,59 @@ func TestSendLogError(t *testing.T) {
 		<-done
 	})
 }
+
+func TestListenSocket(t *testing.T) {
+	d, err := ioutil.TempDir("", "listen_socket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(d)
+
+	t.Run("Missing file", func(t *testing.T) {
+		f := filepath.Join(d, "missing")
+		ln, err := listenSocket(f, getSocketAddr, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ln.Close()
+	})
+
+	// A socket left behind by a process that exited is replaced
+	t.Run("Stale socket", func(t *testing.T) {
+		f := filepath.Join(d, "stale")
+		ln, err := listen(f, getSocketAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ln.(*net.UnixListener).SetUnlinkOnClose(false)
+		ln.Close()
+		if _, err := os.Stat(f); err != nil {
+			t.Fatal(err)
+		}
+
+		ln, err = listenSocket(f, getSocketAddr, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ln.Close()
+	})
+
+	// The socket of a process that is listening is kept
+	t.Run("Socket in use", func(t *testing.T) {
+		f := filepath.Join(d, "in_use")
+		ln, err := listen(f, getSocketAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer ln.Close()
+
+		if _, err := listenSocket(f, getSocketAddr, 0); err != ErrSocketInUse {
+			t.Fatalf("want %v, got %v", ErrSocketInUse, err)
+		}
+		conn, err := dial(f, getSocketAddr, 0)
+		if err != nil {
+			t.Fatalf("the socket in use was removed: %v", err)
+		}
+		conn.Close()
+	})
+}
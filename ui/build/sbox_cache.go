@@ -0,0 +1,73 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"strconv"
+
+	"android/soong/actioncache"
+	"android/soong/shared"
+	soong_metrics_proto "android/soong/ui/metrics/metrics_proto"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// The default maximum size of the sbox action cache, overridden by SOONG_SBOX_CACHE_MAX_SIZE.
+const defaultSboxCacheMaxSize = 10 << 30
+
+// trimSboxCache evicts the least recently used actions from the sbox action cache that is enabled
+// by SOONG_SBOX_CACHE, and reports the lookups of the actions that ran since the last build. The
+// sbox processes only add to the cache, so that they don't need to coordinate the evictions.
+func trimSboxCache(ctx Context, config Config) {
+	if !config.Environment().IsEnvTrue("SOONG_SBOX_CACHE") {
+		return
+	}
+
+	maxSize := int64(defaultSboxCacheMaxSize)
+	if v, ok := config.Environment().Get("SOONG_SBOX_CACHE_MAX_SIZE"); ok {
+		p, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			ctx.Fatalf("Failed to parse SOONG_SBOX_CACHE_MAX_SIZE=%q: %v", v, err)
+		}
+		maxSize = p
+	}
+
+	cache := actioncache.New(shared.SboxCacheDirForOutDir(config.SoongOutDir()))
+	stats, err := cache.TakeStats()
+	if err != nil {
+		ctx.Println("Failed to read the sbox action cache stats:", err)
+		return
+	}
+	size, evicted, err := cache.Trim(maxSize)
+	if err != nil {
+		ctx.Println("Failed to trim the sbox action cache:", err)
+		return
+	}
+
+	ctx.Verbosef("sbox action cache:")
+	ctx.Verbosef(" hits: %d", stats.Hits)
+	ctx.Verbosef(" misses: %d", stats.Misses)
+	ctx.Verbosef(" size: %d MB", size/1e6)
+	ctx.Verbosef(" evicted: %d MB", evicted/1e6)
+
+	if ctx.Metrics != nil {
+		ctx.Metrics.SetActionCacheMetrics(&soong_metrics_proto.ActionCacheMetrics{
+			Hits:         proto.Uint64(uint64(stats.Hits)),
+			Misses:       proto.Uint64(uint64(stats.Misses)),
+			SizeBytes:    proto.Uint64(uint64(size)),
+			EvictedBytes: proto.Uint64(uint64(evicted)),
+		})
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	soong_metrics_proto "android/soong/ui/metrics/metrics_proto"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// This file reports the globs whose results changed since soong_build last ran, which is what
+// forces soong_build to run again when no Android.bp file was modified.
+// The directories that the finder saw change accumulate in ChangedDirsFile until soong_build
+// runs. If any changed, the results of the globs are saved before running the bootstrap ninja,
+// which rewrites the results of the globs that depend on the changed directories, and compared
+// against the new results if soong_build ran.
+
+// The number of added or removed files of each glob printed to the terminal.
+const soongGlobChangeMaxFiles = 5
+
+// globResults maps the name of the file that contains the results of each glob to the results
+type globResults map[string][]string
+
+// soongGlobSnapshot is the state of the globs before running the bootstrap ninja
+type soongGlobSnapshot struct {
+	ninjaModTime time.Time
+	globs        globResults
+	bpList       []string
+}
+
+// recordChangedDirs adds <dirs>, the source directories that the finder saw change, to
+// ChangedDirsFile. If the finder doesn't know what changed, or if ChangedDirsFile doesn't exist,
+// the changes since soong_build last ran are unknown.
+func recordChangedDirs(ctx Context, config Config, dirs []string, known bool) {
+	file := config.ChangedDirsFile()
+	if !known {
+		os.Remove(file)
+		return
+	}
+	previous, err := readLines(file)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			ctx.Verbosef("Failed to read %s: %v", file, err)
+			os.Remove(file)
+		}
+		return
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		ctx.Fatalf("No working directory: %v", err)
+	}
+	merged := make(map[string]bool, len(previous)+len(dirs))
+	for _, dir := range previous {
+		merged[dir] = true
+	}
+	for _, dir := range dirs {
+		if rel, err := filepath.Rel(wd, dir); err == nil {
+			merged[rel] = true
+		}
+	}
+	if len(merged) == len(previous) {
+		return
+	}
+
+	err = writeLines(file, sortedKeys(merged))
+	if err != nil {
+		ctx.Verbosef("Failed to write %s: %v", file, err)
+		os.Remove(file)
+	}
+}
+
+// saveSoongGlobResults returns the state of the globs before running the bootstrap ninja, or nil
+// if no source directories changed since soong_build last ran.
+func saveSoongGlobResults(ctx Context, config Config) *soongGlobSnapshot {
+	changedDirs, err := readLines(config.ChangedDirsFile())
+	if err == nil {
+		ctx.Verbosef("%d source directories changed since soong_build last ran", len(changedDirs))
+		if len(changedDirs) == 0 {
+			return nil
+		}
+	}
+
+	info, err := os.Stat(config.SoongNinjaFile())
+	if err != nil {
+		// soong_build never ran
+		return nil
+	}
+
+	globs, err := readGlobResults(config.SoongGlobDir())
+	if err != nil {
+		ctx.Verbosef("Failed to read the results of the globs: %v", err)
+		return nil
+	}
+
+	bpList, err := readLines(config.SoongBpListFile())
+	if err != nil && !os.IsNotExist(err) {
+		ctx.Verbosef("Failed to read %s: %v", config.SoongBpListFile(), err)
+	}
+
+	return &soongGlobSnapshot{
+		ninjaModTime: info.ModTime(),
+		globs:        globs,
+		bpList:       bpList,
+	}
+}
+
+// reportSoongGlobChanges prints and records the globs whose results changed if soong_build ran
+// again, and then notes that soong_build has seen the changed directories.
+func reportSoongGlobChanges(ctx Context, config Config, snapshot *soongGlobSnapshot) {
+	if snapshot != nil {
+		info, err := os.Stat(config.SoongNinjaFile())
+		if err == nil && info.ModTime().After(snapshot.ninjaModTime) {
+			globs, err := readGlobResults(config.SoongGlobDir())
+			if err != nil {
+				ctx.Verbosef("Failed to read the results of the globs: %v", err)
+			}
+			changes := diffGlobResults(snapshot.globs, globs)
+
+			if snapshot.bpList != nil {
+				bpList, err := readLines(filepath.Join(config.FileListDir(), "Android.bp.list"))
+				if err == nil {
+					if change := diffFileLists("Android.bp.list", snapshot.bpList, bpList); change != nil {
+						changes = append([]*soong_metrics_proto.GlobChange{change}, changes...)
+					}
+				}
+			}
+
+			printSoongGlobChanges(ctx, changes)
+			if ctx.Metrics != nil {
+				ctx.Metrics.SetSoongGlobChanges(changes)
+			}
+		}
+	}
+
+	err := ioutil.WriteFile(config.ChangedDirsFile(), nil, 0666)
+	if err != nil {
+		ctx.Verbosef("Failed to write %s: %v", config.ChangedDirsFile(), err)
+	}
+	_, err = copyFile(filepath.Join(config.FileListDir(), "Android.bp.list"), config.SoongBpListFile())
+	if err != nil {
+		ctx.Verbosef("Failed to copy Android.bp.list: %v", err)
+	}
+}
+
+func printSoongGlobChanges(ctx Context, changes []*soong_metrics_proto.GlobChange) {
+	if len(changes) == 0 {
+		return
+	}
+
+	st := ctx.Status.StartTool()
+	defer st.Finish()
+	st.Print(fmt.Sprintf("soong_build ran again because the results of %d globs changed:", len(changes)))
+	printFiles := func(prefix string, files []string) {
+		for i, file := range files {
+			if i == soongGlobChangeMaxFiles {
+				st.Print(fmt.Sprintf("    ... and %d more", len(files)-i))
+				break
+			}
+			st.Print("    " + prefix + " " + file)
+		}
+	}
+	for _, change := range changes {
+		st.Print("  " + change.GetGlob())
+		printFiles("+", change.AddedFiles)
+		printFiles("-", change.RemovedFiles)
+	}
+}
+
+// readGlobResults reads the results of the globs written under <dir>. Each file lists the paths
+// matched by one glob, one per line, and its name is derived from the glob pattern.
+func readGlobResults(dir string) (globResults, error) {
+	globs := make(globResults)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".glob") {
+			return nil
+		}
+		files, err := readLines(path)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		globs[strings.TrimSuffix(name, ".glob")] = files
+		return nil
+	})
+	return globs, err
+}
+
+// diffGlobResults returns the changes to the results of the globs that exist both <before> and
+// <after>, sorted by glob.
+func diffGlobResults(before, after globResults) []*soong_metrics_proto.GlobChange {
+	names := make([]string, 0, len(before))
+	for name := range before {
+		if _, ok := after[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []*soong_metrics_proto.GlobChange
+	for _, name := range names {
+		if change := diffFileLists(name, before[name], after[name]); change != nil {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// diffFileLists returns the files added to and removed from the list of files matched by <glob>,
+// or nil if the list didn't change.
+func diffFileLists(glob string, before, after []string) *soong_metrics_proto.GlobChange {
+	inBefore := make(map[string]bool, len(before))
+	for _, file := range before {
+		inBefore[file] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, file := range after {
+		inAfter[file] = true
+	}
+
+	change := &soong_metrics_proto.GlobChange{Glob: proto.String(glob)}
+	for _, file := range after {
+		if !inBefore[file] {
+			change.AddedFiles = append(change.AddedFiles, file)
+		}
+	}
+	for _, file := range before {
+		if !inAfter[file] {
+			change.RemovedFiles = append(change.RemovedFiles, file)
+		}
+	}
+	if len(change.AddedFiles) == 0 && len(change.RemovedFiles) == 0 {
+		return nil
+	}
+	sort.Strings(change.AddedFiles)
+	sort.Strings(change.RemovedFiles)
+	return change
+}
+
+// readLines returns the non-empty lines of a file
+func readLines(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func writeLines(path string, lines []string) error {
+	return ioutil.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0666)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,158 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	soong_metrics_proto "android/soong/ui/metrics/metrics_proto"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func TestReadGlobResults(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a/star.java.glob":   "a/b.java\na/c.java\n",
+		"a/star.java.glob.d": "a/star.java.glob: a\n",
+		"empty/star.glob":    "\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	globs, err := readGlobResults(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := globResults{
+		"a/star.java": {"a/b.java", "a/c.java"},
+		"empty/star":  {},
+	}
+	if !reflect.DeepEqual(globs, want) {
+		t.Errorf("want %q, got %q", want, globs)
+	}
+
+	// soong_build never ran
+	globs, err = readGlobResults(filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(globs) != 0 {
+		t.Errorf("want no globs, got %q", globs)
+	}
+}
+
+func TestDiffGlobResults(t *testing.T) {
+	before := globResults{
+		"a/star.java":    {"a/b.java", "a/c.java"},
+		"b/star.java":    {"b/b.java"},
+		"d/star.java":    {"d/d.java"},
+		"old/star.java":  {"old/a.java"},
+		"same/star.java": {"same/a.java", "same/b.java"},
+	}
+	after := globResults{
+		"a/star.java":    {"a/b.java", "a/d.java", "a/a.java"},
+		"b/star.java":    {},
+		"d/star.java":    {"d/d.java"},
+		"new/star.java":  {"new/a.java"},
+		"same/star.java": {"same/b.java", "same/a.java"},
+	}
+
+	got := diffGlobResults(before, after)
+	want := []*soong_metrics_proto.GlobChange{
+		{
+			Glob:         proto.String("a/star.java"),
+			AddedFiles:   []string{"a/a.java", "a/d.java"},
+			RemovedFiles: []string{"a/c.java"},
+		},
+		{
+			Glob:         proto.String("b/star.java"),
+			RemovedFiles: []string{"b/b.java"},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d changes, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if !proto.Equal(got[i], want[i]) {
+			t.Errorf("change %d: want %v, got %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestRecordChangedDirs(t *testing.T) {
+	ctx := testContext()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := Environment([]string{})
+	env.Set("OUT_DIR", t.TempDir())
+	config := Config{&configImpl{
+		environ: &env,
+	}}
+	if err := os.MkdirAll(config.FileListDir(), 0777); err != nil {
+		t.Fatal(err)
+	}
+	file := config.ChangedDirsFile()
+
+	check := func(want []string) {
+		t.Helper()
+		got, err := readLines(file)
+		if want == nil {
+			if !os.IsNotExist(err) {
+				t.Errorf("want unknown changes, got %q, %v", got, err)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	}
+
+	// the changes are unknown until soong_build runs
+	recordChangedDirs(ctx, config, []string{filepath.Join(wd, "a")}, true)
+	check(nil)
+
+	// soong_build ran
+	if err := ioutil.WriteFile(file, nil, 0666); err != nil {
+		t.Fatal(err)
+	}
+	recordChangedDirs(ctx, config, []string{filepath.Join(wd, "b"), filepath.Join(wd, "a")}, true)
+	check([]string{"a", "b"})
+
+	// the changes accumulate until soong_build runs again
+	recordChangedDirs(ctx, config, []string{filepath.Join(wd, "c"), filepath.Join(wd, "a")}, true)
+	check([]string{"a", "b", "c"})
+
+	// the finder lost track of the changes
+	recordChangedDirs(ctx, config, nil, false)
+	check(nil)
+}
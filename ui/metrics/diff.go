@@ -0,0 +1,292 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+// This file compares the metrics of two builds, or of two runs of the critical user journey
+// tests, to find the phases, soong_build counts and actions which regressed.
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	soong_metrics_proto "android/soong/ui/metrics/metrics_proto"
+)
+
+// Thresholds are the increases above which a metric is a regression.
+type Thresholds struct {
+	// The relative increase of the time of a phase or of the actions of a rule or module, e.g.
+	// 0.1 for 10%.
+	Time float64
+
+	// The smallest increase of a time which is a regression, to ignore the noise in short phases.
+	MinTime time.Duration
+
+	// The relative increase of the soong_build module, variant and allocation counts.
+	Count float64
+}
+
+var DefaultThresholds = Thresholds{
+	Time:    0.1,
+	MinTime: time.Second,
+	Count:   0.05,
+}
+
+// RegisterFlags registers the flags which set the thresholds on a FlagSet.
+func (t *Thresholds) RegisterFlags(fs *flag.FlagSet) {
+	fs.Var(percentFlag{&t.Time}, "time_threshold", "the relative increase of a time which is a regression, in percent")
+	fs.DurationVar(&t.MinTime, "min_time", t.MinTime, "the smallest increase of a time which is a regression")
+	fs.Var(percentFlag{&t.Count}, "count_threshold", "the relative increase of a soong_build count which is a regression, in percent")
+}
+
+type percentFlag struct {
+	ratio *float64
+}
+
+func (p percentFlag) String() string {
+	if p.ratio == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g", *p.ratio*100)
+}
+
+func (p percentFlag) Set(s string) error {
+	var percent float64
+	if _, err := fmt.Sscanf(s, "%g", &percent); err != nil {
+		return fmt.Errorf("invalid percentage %q", s)
+	}
+	*p.ratio = percent / 100
+	return nil
+}
+
+type Unit int
+
+const (
+	Nanoseconds Unit = iota
+	Bytes
+	Count
+)
+
+// A Change is the difference of a metric between the base and the current build.
+type Change struct {
+	// The name of the metric, prefixed with the name of the critical user journey if it comes
+	// from one.
+	Name string
+
+	Unit          Unit
+	Base, Current uint64
+
+	// Whether the metric increased, or decreased, by more than the thresholds.
+	Regression, Improvement bool
+}
+
+func (c Change) format(value uint64) string {
+	switch c.Unit {
+	case Nanoseconds:
+		return time.Duration(value).Round(time.Millisecond).String()
+	case Bytes:
+		return fmt.Sprintf("%.1fMB", float64(value)/(1024*1024))
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func (c Change) String() string {
+	var delta string
+	if c.Base != 0 {
+		delta = fmt.Sprintf(" (%+.1f%%)", (float64(c.Current)-float64(c.Base))/float64(c.Base)*100)
+	}
+	return fmt.Sprintf("%s: %s -> %s%s", c.Name, c.format(c.Base), c.format(c.Current), delta)
+}
+
+func (c *Change) classify(t Thresholds) {
+	ratio, minDelta := t.Count, uint64(0)
+	if c.Unit == Nanoseconds {
+		ratio, minDelta = t.Time, uint64(t.MinTime)
+	}
+	exceeds := func(from, to uint64) bool {
+		return to > from && to-from > minDelta && float64(to-from) > float64(from)*ratio
+	}
+	c.Regression = exceeds(c.Base, c.Current)
+	c.Improvement = exceeds(c.Current, c.Base)
+}
+
+// Regressions returns the changes which are regressions.
+func Regressions(changes []Change) []Change {
+	var ret []Change
+	for _, c := range changes {
+		if c.Regression {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// DiffMetrics compares the metrics of a build with the metrics of a base build. Only the metrics
+// present in both builds are compared.
+func DiffMetrics(base, current *soong_metrics_proto.MetricsBase, t Thresholds) []Change {
+	var changes []Change
+	add := func(name string, unit Unit, base, current uint64) {
+		c := Change{Name: name, Unit: unit, Base: base, Current: current}
+		c.classify(t)
+		changes = append(changes, c)
+	}
+
+	// Phase times
+	basePhases, currentPhases := phaseTimes(base), phaseTimes(current)
+	for _, name := range sortedKeys(basePhases) {
+		if currentTime, ok := currentPhases[name]; ok {
+			add(name, Nanoseconds, basePhases[name], currentTime)
+		}
+	}
+
+	// soong_build counts
+	if b, c := base.GetSoongBuildMetrics(), current.GetSoongBuildMetrics(); b != nil && c != nil {
+		add("soong_build modules", Count, uint64(b.GetModules()), uint64(c.GetModules()))
+		add("soong_build variants", Count, uint64(b.GetVariants()), uint64(c.GetVariants()))
+		add("soong_build allocations", Count, b.GetTotalAllocCount(), c.GetTotalAllocCount())
+		add("soong_build allocated size", Bytes, b.GetTotalAllocSize(), c.GetTotalAllocSize())
+		add("soong_build max heap size", Bytes, b.GetMaxHeapSize(), c.GetMaxHeapSize())
+	}
+
+	// Action CPU times
+	if b, c := base.GetActionResourceUsage(), current.GetActionResourceUsage(); b != nil && c != nil {
+		diffResourceUsage := func(prefix string, base, current []*soong_metrics_proto.ResourceUsage) {
+			currentTimes := cpuTimes(current)
+			baseTimes := cpuTimes(base)
+			for _, name := range sortedKeys(baseTimes) {
+				if currentTime, ok := currentTimes[name]; ok {
+					add(prefix+name, Nanoseconds, baseTimes[name], currentTime)
+				}
+			}
+		}
+		diffResourceUsage("rule ", b.GetByRule(), c.GetByRule())
+		diffResourceUsage("module ", b.GetByModule(), c.GetByModule())
+	}
+
+	return changes
+}
+
+// DiffCriticalUserJourneys compares the metrics of the critical user journeys with the same name
+// in two runs of the critical user journey tests.
+func DiffCriticalUserJourneys(base, current *soong_metrics_proto.CriticalUserJourneysMetrics, t Thresholds) []Change {
+	baseCujs := make(map[string]*soong_metrics_proto.MetricsBase)
+	for _, cuj := range base.GetCujs() {
+		baseCujs[cuj.GetName()] = cuj.GetMetrics()
+	}
+
+	var changes []Change
+	for _, cuj := range current.GetCujs() {
+		baseMetrics, ok := baseCujs[cuj.GetName()]
+		if !ok {
+			continue
+		}
+		for _, c := range DiffMetrics(baseMetrics, cuj.GetMetrics(), t) {
+			if cuj.GetName() != "" {
+				c.Name = cuj.GetName() + ": " + c.Name
+			}
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Diff compares the metrics of the critical user journeys with those of a base run.
+func (c *CriticalUserJourneysMetrics) Diff(base *soong_metrics_proto.CriticalUserJourneysMetrics, t Thresholds) []Change {
+	return DiffCriticalUserJourneys(base, &c.cujs, t)
+}
+
+// ReadMetricsFile reads a soong_metrics file, or a file of critical user journey metrics. The
+// metrics of a single build are returned as a critical user journey without a name.
+func ReadMetricsFile(file string) (*soong_metrics_proto.CriticalUserJourneysMetrics, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fields of MetricsBase don't have the same wire types as those of
+	// CriticalUserJourneysMetrics, so a MetricsBase doesn't parse as critical user journeys with
+	// names.
+	cujs := &soong_metrics_proto.CriticalUserJourneysMetrics{}
+	if err := proto.Unmarshal(data, cujs); err == nil && isCriticalUserJourneys(cujs) {
+		return cujs, nil
+	}
+
+	metrics := &soong_metrics_proto.MetricsBase{}
+	if err := proto.Unmarshal(data, metrics); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics file %s: %s", file, err)
+	}
+	return &soong_metrics_proto.CriticalUserJourneysMetrics{
+		Cujs: []*soong_metrics_proto.CriticalUserJourneyMetrics{{Metrics: metrics}},
+	}, nil
+}
+
+func isCriticalUserJourneys(cujs *soong_metrics_proto.CriticalUserJourneysMetrics) bool {
+	if len(cujs.GetCujs()) == 0 {
+		return false
+	}
+	for _, cuj := range cujs.GetCujs() {
+		if cuj.GetName() == "" || cuj.GetMetrics() == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// phaseTimes returns the real time of the phases of a build, keyed by the name and description of
+// the phase. The times of phases which ran several times with the same description are summed.
+func phaseTimes(m *soong_metrics_proto.MetricsBase) map[string]uint64 {
+	ret := make(map[string]uint64)
+	add := func(perfs ...*soong_metrics_proto.PerfInfo) {
+		for _, perf := range perfs {
+			if perf == nil {
+				continue
+			}
+			name := perf.GetName()
+			if perf.GetDesc() != "" && perf.GetDesc() != name {
+				name += " " + perf.GetDesc()
+			}
+			ret[name] += perf.GetRealTime()
+		}
+	}
+	add(m.GetSetupTools()...)
+	add(m.GetKatiRuns()...)
+	add(m.GetSoongRuns()...)
+	add(m.GetNinjaRuns()...)
+	add(m.GetTotal())
+	return ret
+}
+
+// cpuTimes returns the CPU time of the actions in nanoseconds, keyed by rule or module.
+func cpuTimes(usages []*soong_metrics_proto.ResourceUsage) map[string]uint64 {
+	ret := make(map[string]uint64)
+	for _, usage := range usages {
+		ret[usage.GetName()] += (usage.GetUserTime() + usage.GetSystemTime()) * uint64(time.Millisecond)
+	}
+	return ret
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	var ret []string
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
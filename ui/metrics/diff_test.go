@@ -0,0 +1,150 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	soong_metrics_proto "android/soong/ui/metrics/metrics_proto"
+)
+
+func testMetrics(ninja, kati time.Duration, modules uint32, javacMs uint64) *soong_metrics_proto.MetricsBase {
+	return &soong_metrics_proto.MetricsBase{
+		KatiRuns: []*soong_metrics_proto.PerfInfo{
+			{Name: proto.String("kati"), Desc: proto.String("kati build"), RealTime: proto.Uint64(uint64(kati))},
+		},
+		NinjaRuns: []*soong_metrics_proto.PerfInfo{
+			{Name: proto.String("ninja"), Desc: proto.String("ninja"), RealTime: proto.Uint64(uint64(ninja))},
+		},
+		SoongBuildMetrics: &soong_metrics_proto.SoongBuildMetrics{
+			Modules: proto.Uint32(modules),
+		},
+		ActionResourceUsage: &soong_metrics_proto.ActionResourceUsage{
+			ByRule: []*soong_metrics_proto.ResourceUsage{
+				{Name: proto.String("javac"), UserTime: proto.Uint64(javacMs)},
+			},
+		},
+	}
+}
+
+func TestDiffMetrics(t *testing.T) {
+	base := testMetrics(100*time.Second, 10*time.Second, 1000, 50000)
+	current := testMetrics(120*time.Second, 10500*time.Millisecond, 1100, 40000)
+
+	var got []string
+	var regressions []string
+	for _, c := range DiffMetrics(base, current, DefaultThresholds) {
+		got = append(got, c.String())
+		if c.Regression {
+			regressions = append(regressions, c.Name)
+		}
+		if c.Name == "rule javac" && !c.Improvement {
+			t.Errorf("want rule javac to be an improvement")
+		}
+	}
+
+	want := []string{
+		"kati kati build: 10s -> 10.5s (+5.0%)",
+		"ninja: 1m40s -> 2m0s (+20.0%)",
+		"soong_build modules: 1000 -> 1100 (+10.0%)",
+		"soong_build variants: 0 -> 0",
+		"soong_build allocations: 0 -> 0",
+		"soong_build allocated size: 0.0MB -> 0.0MB",
+		"soong_build max heap size: 0.0MB -> 0.0MB",
+		"rule javac: 50s -> 40s (-20.0%)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want changes:\n%q\ngot:\n%q", want, got)
+	}
+	if want := []string{"ninja", "soong_build modules"}; !reflect.DeepEqual(regressions, want) {
+		t.Errorf("want regressions %q, got %q", want, regressions)
+	}
+}
+
+func TestDiffMinTime(t *testing.T) {
+	// A 50% increase which is below the minimum time isn't a regression.
+	base := testMetrics(time.Second, 0, 0, 0)
+	current := testMetrics(1500*time.Millisecond, 0, 0, 0)
+	if r := Regressions(DiffMetrics(base, current, DefaultThresholds)); len(r) > 0 {
+		t.Errorf("want no regressions, got %v", r)
+	}
+
+	thresholds := DefaultThresholds
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	thresholds.RegisterFlags(fs)
+	if err := fs.Parse([]string{"-min_time=100ms", "-time_threshold=60"}); err != nil {
+		t.Fatal(err)
+	}
+	if r := Regressions(DiffMetrics(base, current, thresholds)); len(r) > 0 {
+		t.Errorf("want no regressions with a 60%% threshold, got %v", r)
+	}
+	fs.Parse([]string{"-time_threshold=40"})
+	if r := Regressions(DiffMetrics(base, current, thresholds)); len(r) != 1 || r[0].Name != "ninja" {
+		t.Errorf("want a ninja regression with a 40%% threshold, got %v", r)
+	}
+}
+
+func TestReadMetricsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "metrics_diff")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	build := testMetrics(time.Minute, 0, 10, 0)
+	build.BuildDateTimestamp = proto.Int64(1600000000)
+	build.TargetProduct = proto.String("aosp_arm64")
+	cujs := &soong_metrics_proto.CriticalUserJourneysMetrics{
+		Cujs: []*soong_metrics_proto.CriticalUserJourneyMetrics{
+			{Name: proto.String("nothing"), Metrics: build},
+		},
+	}
+
+	for _, test := range []struct {
+		file      string
+		msg       proto.Message
+		wantNames []string
+	}{
+		{"soong_metrics", build, []string{""}},
+		{"cuj_metrics.pb", cujs, []string{"nothing"}},
+	} {
+		file := filepath.Join(dir, test.file)
+		if err := writeMessageToFile(test.msg, file); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadMetricsFile(file)
+		if err != nil {
+			t.Fatalf("%s: %s", test.file, err)
+		}
+		var names []string
+		for _, cuj := range got.GetCujs() {
+			names = append(names, cuj.GetName())
+			if !proto.Equal(cuj.GetMetrics(), build) {
+				t.Errorf("%s: want metrics %v, got %v", test.file, build, cuj.GetMetrics())
+			}
+		}
+		if !reflect.DeepEqual(names, test.wantNames) {
+			t.Errorf("%s: want critical user journeys %q, got %q", test.file, test.wantNames, names)
+		}
+	}
+}
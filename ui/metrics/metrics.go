@@ -70,6 +70,18 @@ func (m *Metrics) BuildConfig(b *soong_metrics_proto.BuildConfig) {
 	m.metrics.BuildConfig = b
 }
 
+func (m *Metrics) SetActionResourceUsage(usage *soong_metrics_proto.ActionResourceUsage) {
+	m.metrics.ActionResourceUsage = usage
+}
+
+func (m *Metrics) SetActionCacheMetrics(metrics *soong_metrics_proto.ActionCacheMetrics) {
+	m.metrics.ActionCacheMetrics = metrics
+}
+
+func (m *Metrics) SetSoongGlobChanges(changes []*soong_metrics_proto.GlobChange) {
+	m.metrics.SoongGlobChanges = changes
+}
+
 func (m *Metrics) SetMetadataMetrics(metadata map[string]string) {
 	for k, v := range metadata {
 		switch k {
@@ -125,6 +137,8 @@ func (m *Metrics) getArch(arch string) *soong_metrics_proto.MetricsBase_Arch {
 		return soong_metrics_proto.MetricsBase_ARM.Enum()
 	case "arm64":
 		return soong_metrics_proto.MetricsBase_ARM64.Enum()
+	case "riscv64":
+		return soong_metrics_proto.MetricsBase_RISCV64.Enum()
 	case "x86":
 		return soong_metrics_proto.MetricsBase_X86.Enum()
 	case "x86_64":
@@ -71,6 +71,7 @@ const (
 	MetricsBase_ARM64   MetricsBase_Arch = 2
 	MetricsBase_X86     MetricsBase_Arch = 3
 	MetricsBase_X86_64  MetricsBase_Arch = 4
+	MetricsBase_RISCV64 MetricsBase_Arch = 5
 )
 
 var MetricsBase_Arch_name = map[int32]string{
@@ -79,6 +80,7 @@ var MetricsBase_Arch_name = map[int32]string{
 	2: "ARM64",
 	3: "X86",
 	4: "X86_64",
+	5: "RISCV64",
 }
 
 var MetricsBase_Arch_value = map[string]int32{
@@ -87,6 +89,7 @@ var MetricsBase_Arch_value = map[string]int32{
 	"ARM64":   2,
 	"X86":     3,
 	"X86_64":  4,
+	"RISCV64": 5,
 }
 
 func (x MetricsBase_Arch) Enum() *MetricsBase_Arch {
@@ -197,12 +200,18 @@ type MetricsBase struct {
 	// The metrics for calling Ninja.
 	NinjaRuns []*PerfInfo `protobuf:"bytes,20,rep,name=ninja_runs,json=ninjaRuns" json:"ninja_runs,omitempty"`
 	// The metrics for the whole build
-	Total                *PerfInfo          `protobuf:"bytes,21,opt,name=total" json:"total,omitempty"`
-	SoongBuildMetrics    *SoongBuildMetrics `protobuf:"bytes,22,opt,name=soong_build_metrics,json=soongBuildMetrics" json:"soong_build_metrics,omitempty"`
-	BuildConfig          *BuildConfig       `protobuf:"bytes,23,opt,name=build_config,json=buildConfig" json:"build_config,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Total             *PerfInfo          `protobuf:"bytes,21,opt,name=total" json:"total,omitempty"`
+	SoongBuildMetrics *SoongBuildMetrics `protobuf:"bytes,22,opt,name=soong_build_metrics,json=soongBuildMetrics" json:"soong_build_metrics,omitempty"`
+	BuildConfig       *BuildConfig       `protobuf:"bytes,23,opt,name=build_config,json=buildConfig" json:"build_config,omitempty"`
+	// The resource usage of the actions run by ninja.
+	ActionResourceUsage *ActionResourceUsage `protobuf:"bytes,24,opt,name=action_resource_usage,json=actionResourceUsage" json:"action_resource_usage,omitempty"`
+	// The metrics of the local action cache of sbox.
+	ActionCacheMetrics *ActionCacheMetrics `protobuf:"bytes,25,opt,name=action_cache_metrics,json=actionCacheMetrics" json:"action_cache_metrics,omitempty"`
+	// The changes to the results of globs that made soong_build run again.
+	SoongGlobChanges     []*GlobChange `protobuf:"bytes,26,rep,name=soong_glob_changes,json=soongGlobChanges" json:"soong_glob_changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *MetricsBase) Reset()         { *m = MetricsBase{} }
@@ -396,6 +405,27 @@ func (m *MetricsBase) GetBuildConfig() *BuildConfig {
 	return nil
 }
 
+func (m *MetricsBase) GetActionResourceUsage() *ActionResourceUsage {
+	if m != nil {
+		return m.ActionResourceUsage
+	}
+	return nil
+}
+
+func (m *MetricsBase) GetActionCacheMetrics() *ActionCacheMetrics {
+	if m != nil {
+		return m.ActionCacheMetrics
+	}
+	return nil
+}
+
+func (m *MetricsBase) GetSoongGlobChanges() []*GlobChange {
+	if m != nil {
+		return m.SoongGlobChanges
+	}
+	return nil
+}
+
 type BuildConfig struct {
 	UseGoma              *bool    `protobuf:"varint,1,opt,name=use_goma,json=useGoma" json:"use_goma,omitempty"`
 	UseRbe               *bool    `protobuf:"varint,2,opt,name=use_rbe,json=useRbe" json:"use_rbe,omitempty"`
@@ -746,6 +776,283 @@ func (m *SoongBuildMetrics) GetMaxHeapSize() uint64 {
 	return 0
 }
 
+type ActionResourceUsage struct {
+	// The resource usage of the actions, aggregated by the rule that created them.
+	ByRule []*ResourceUsage `protobuf:"bytes,1,rep,name=by_rule,json=byRule" json:"by_rule,omitempty"`
+	// The resource usage of the actions, aggregated by the module that created them.
+	ByModule []*ResourceUsage `protobuf:"bytes,2,rep,name=by_module,json=byModule" json:"by_module,omitempty"`
+	// The actions which used the most CPU time.
+	TopActions           []*ResourceUsage `protobuf:"bytes,3,rep,name=top_actions,json=topActions" json:"top_actions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ActionResourceUsage) Reset()         { *m = ActionResourceUsage{} }
+func (m *ActionResourceUsage) String() string { return proto.CompactTextString(m) }
+func (*ActionResourceUsage) ProtoMessage()    {}
+func (*ActionResourceUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{7}
+}
+
+func (m *ActionResourceUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionResourceUsage.Unmarshal(m, b)
+}
+func (m *ActionResourceUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionResourceUsage.Marshal(b, m, deterministic)
+}
+func (m *ActionResourceUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionResourceUsage.Merge(m, src)
+}
+func (m *ActionResourceUsage) XXX_Size() int {
+	return xxx_messageInfo_ActionResourceUsage.Size(m)
+}
+func (m *ActionResourceUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionResourceUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionResourceUsage proto.InternalMessageInfo
+
+func (m *ActionResourceUsage) GetByRule() []*ResourceUsage {
+	if m != nil {
+		return m.ByRule
+	}
+	return nil
+}
+
+func (m *ActionResourceUsage) GetByModule() []*ResourceUsage {
+	if m != nil {
+		return m.ByModule
+	}
+	return nil
+}
+
+func (m *ActionResourceUsage) GetTopActions() []*ResourceUsage {
+	if m != nil {
+		return m.TopActions
+	}
+	return nil
+}
+
+type ResourceUsage struct {
+	// The name of the rule, module or action.
+	Name *string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// The number of actions.
+	Actions *uint32 `protobuf:"varint,2,opt,name=actions" json:"actions,omitempty"`
+	// The user CPU time of the actions in milliseconds.
+	UserTime *uint64 `protobuf:"varint,3,opt,name=user_time,json=userTime" json:"user_time,omitempty"`
+	// The system CPU time of the actions in milliseconds.
+	SystemTime *uint64 `protobuf:"varint,4,opt,name=system_time,json=systemTime" json:"system_time,omitempty"`
+	// The largest peak resident set size of the actions in kilobytes.
+	MaxRssKb *uint64 `protobuf:"varint,5,opt,name=max_rss_kb,json=maxRssKb" json:"max_rss_kb,omitempty"`
+	// The total file system input of the actions in kilobytes.
+	IoInputKb *uint64 `protobuf:"varint,6,opt,name=io_input_kb,json=ioInputKb" json:"io_input_kb,omitempty"`
+	// The total file system output of the actions in kilobytes.
+	IoOutputKb           *uint64  `protobuf:"varint,7,opt,name=io_output_kb,json=ioOutputKb" json:"io_output_kb,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResourceUsage) Reset()         { *m = ResourceUsage{} }
+func (m *ResourceUsage) String() string { return proto.CompactTextString(m) }
+func (*ResourceUsage) ProtoMessage()    {}
+func (*ResourceUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{8}
+}
+
+func (m *ResourceUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResourceUsage.Unmarshal(m, b)
+}
+func (m *ResourceUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResourceUsage.Marshal(b, m, deterministic)
+}
+func (m *ResourceUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResourceUsage.Merge(m, src)
+}
+func (m *ResourceUsage) XXX_Size() int {
+	return xxx_messageInfo_ResourceUsage.Size(m)
+}
+func (m *ResourceUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResourceUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResourceUsage proto.InternalMessageInfo
+
+func (m *ResourceUsage) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *ResourceUsage) GetActions() uint32 {
+	if m != nil && m.Actions != nil {
+		return *m.Actions
+	}
+	return 0
+}
+
+func (m *ResourceUsage) GetUserTime() uint64 {
+	if m != nil && m.UserTime != nil {
+		return *m.UserTime
+	}
+	return 0
+}
+
+func (m *ResourceUsage) GetSystemTime() uint64 {
+	if m != nil && m.SystemTime != nil {
+		return *m.SystemTime
+	}
+	return 0
+}
+
+func (m *ResourceUsage) GetMaxRssKb() uint64 {
+	if m != nil && m.MaxRssKb != nil {
+		return *m.MaxRssKb
+	}
+	return 0
+}
+
+func (m *ResourceUsage) GetIoInputKb() uint64 {
+	if m != nil && m.IoInputKb != nil {
+		return *m.IoInputKb
+	}
+	return 0
+}
+
+func (m *ResourceUsage) GetIoOutputKb() uint64 {
+	if m != nil && m.IoOutputKb != nil {
+		return *m.IoOutputKb
+	}
+	return 0
+}
+
+type ActionCacheMetrics struct {
+	// The number of actions whose outputs were restored from the cache.
+	Hits *uint64 `protobuf:"varint,1,opt,name=hits" json:"hits,omitempty"`
+	// The number of actions that weren't in the cache.
+	Misses *uint64 `protobuf:"varint,2,opt,name=misses" json:"misses,omitempty"`
+	// The size of the cache in bytes after it was trimmed.
+	SizeBytes *uint64 `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes" json:"size_bytes,omitempty"`
+	// The size of the entries and outputs that were evicted from the cache in bytes.
+	EvictedBytes         *uint64  `protobuf:"varint,4,opt,name=evicted_bytes,json=evictedBytes" json:"evicted_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ActionCacheMetrics) Reset()         { *m = ActionCacheMetrics{} }
+func (m *ActionCacheMetrics) String() string { return proto.CompactTextString(m) }
+func (*ActionCacheMetrics) ProtoMessage()    {}
+func (*ActionCacheMetrics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{9}
+}
+
+func (m *ActionCacheMetrics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ActionCacheMetrics.Unmarshal(m, b)
+}
+func (m *ActionCacheMetrics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ActionCacheMetrics.Marshal(b, m, deterministic)
+}
+func (m *ActionCacheMetrics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ActionCacheMetrics.Merge(m, src)
+}
+func (m *ActionCacheMetrics) XXX_Size() int {
+	return xxx_messageInfo_ActionCacheMetrics.Size(m)
+}
+func (m *ActionCacheMetrics) XXX_DiscardUnknown() {
+	xxx_messageInfo_ActionCacheMetrics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ActionCacheMetrics proto.InternalMessageInfo
+
+func (m *ActionCacheMetrics) GetHits() uint64 {
+	if m != nil && m.Hits != nil {
+		return *m.Hits
+	}
+	return 0
+}
+
+func (m *ActionCacheMetrics) GetMisses() uint64 {
+	if m != nil && m.Misses != nil {
+		return *m.Misses
+	}
+	return 0
+}
+
+func (m *ActionCacheMetrics) GetSizeBytes() uint64 {
+	if m != nil && m.SizeBytes != nil {
+		return *m.SizeBytes
+	}
+	return 0
+}
+
+func (m *ActionCacheMetrics) GetEvictedBytes() uint64 {
+	if m != nil && m.EvictedBytes != nil {
+		return *m.EvictedBytes
+	}
+	return 0
+}
+
+type GlobChange struct {
+	// The glob whose results changed, or Android.bp.list for the list of Android.bp files.
+	Glob *string `protobuf:"bytes,1,opt,name=glob" json:"glob,omitempty"`
+	// The files that started matching the glob.
+	AddedFiles []string `protobuf:"bytes,2,rep,name=added_files,json=addedFiles" json:"added_files,omitempty"`
+	// The files that stopped matching the glob.
+	RemovedFiles         []string `protobuf:"bytes,3,rep,name=removed_files,json=removedFiles" json:"removed_files,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GlobChange) Reset()         { *m = GlobChange{} }
+func (m *GlobChange) String() string { return proto.CompactTextString(m) }
+func (*GlobChange) ProtoMessage()    {}
+func (*GlobChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6039342a2ba47b72, []int{10}
+}
+
+func (m *GlobChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GlobChange.Unmarshal(m, b)
+}
+func (m *GlobChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GlobChange.Marshal(b, m, deterministic)
+}
+func (m *GlobChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GlobChange.Merge(m, src)
+}
+func (m *GlobChange) XXX_Size() int {
+	return xxx_messageInfo_GlobChange.Size(m)
+}
+func (m *GlobChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_GlobChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GlobChange proto.InternalMessageInfo
+
+func (m *GlobChange) GetGlob() string {
+	if m != nil && m.Glob != nil {
+		return *m.Glob
+	}
+	return ""
+}
+
+func (m *GlobChange) GetAddedFiles() []string {
+	if m != nil {
+		return m.AddedFiles
+	}
+	return nil
+}
+
+func (m *GlobChange) GetRemovedFiles() []string {
+	if m != nil {
+		return m.RemovedFiles
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("soong_build_metrics.MetricsBase_BuildVariant", MetricsBase_BuildVariant_name, MetricsBase_BuildVariant_value)
 	proto.RegisterEnum("soong_build_metrics.MetricsBase_Arch", MetricsBase_Arch_name, MetricsBase_Arch_value)
@@ -757,6 +1064,10 @@ func init() {
 	proto.RegisterType((*CriticalUserJourneyMetrics)(nil), "soong_build_metrics.CriticalUserJourneyMetrics")
 	proto.RegisterType((*CriticalUserJourneysMetrics)(nil), "soong_build_metrics.CriticalUserJourneysMetrics")
 	proto.RegisterType((*SoongBuildMetrics)(nil), "soong_build_metrics.SoongBuildMetrics")
+	proto.RegisterType((*ActionResourceUsage)(nil), "soong_build_metrics.ActionResourceUsage")
+	proto.RegisterType((*ResourceUsage)(nil), "soong_build_metrics.ResourceUsage")
+	proto.RegisterType((*ActionCacheMetrics)(nil), "soong_build_metrics.ActionCacheMetrics")
+	proto.RegisterType((*GlobChange)(nil), "soong_build_metrics.GlobChange")
 }
 
 func init() {
@@ -764,69 +1075,93 @@ func init() {
 }
 
 var fileDescriptor_6039342a2ba47b72 = []byte{
-	// 1021 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xef, 0x6e, 0xdb, 0x36,
-	0x10, 0xaf, 0x12, 0x27, 0xb6, 0x4e, 0xb1, 0xab, 0x30, 0xed, 0xa2, 0xb6, 0x08, 0x66, 0x18, 0x6b,
-	0x11, 0x0c, 0x6b, 0x5a, 0x64, 0x45, 0x50, 0x04, 0xc5, 0x00, 0xc7, 0x09, 0xb2, 0x2e, 0x48, 0x5c,
-	0x30, 0x7f, 0x56, 0x6c, 0x1f, 0x04, 0x5a, 0xa2, 0x13, 0x75, 0x96, 0x28, 0x90, 0x54, 0x91, 0xf4,
-	0x1d, 0xf6, 0x54, 0x7b, 0x96, 0xbd, 0xc6, 0x30, 0xf0, 0x28, 0xd9, 0xca, 0xe6, 0xad, 0x41, 0xbf,
-	0x89, 0xf7, 0xfb, 0xc3, 0x3b, 0xf2, 0x78, 0x36, 0xb4, 0x53, 0xae, 0x65, 0x12, 0xa9, 0xad, 0x5c,
-	0x0a, 0x2d, 0xc8, 0x9a, 0x12, 0x22, 0xbb, 0x0c, 0x47, 0x45, 0x32, 0x89, 0xc3, 0x12, 0xea, 0xfd,
-	0x05, 0xe0, 0x1d, 0xdb, 0xef, 0x3d, 0xa6, 0x38, 0x79, 0x09, 0x0f, 0x2c, 0x21, 0x66, 0x9a, 0x87,
-	0x3a, 0x49, 0xb9, 0xd2, 0x2c, 0xcd, 0x03, 0xa7, 0xeb, 0x6c, 0x2e, 0x52, 0x82, 0xd8, 0x3e, 0xd3,
-	0xfc, 0xac, 0x42, 0xc8, 0x23, 0x68, 0x59, 0x45, 0x12, 0x07, 0x0b, 0x5d, 0x67, 0xd3, 0xa5, 0x4d,
-	0x5c, 0xbf, 0x8d, 0xc9, 0x2e, 0x3c, 0xca, 0x27, 0x4c, 0x8f, 0x85, 0x4c, 0xc3, 0x8f, 0x5c, 0xaa,
-	0x44, 0x64, 0x61, 0x24, 0x62, 0x9e, 0xb1, 0x94, 0x07, 0x8b, 0xc8, 0x5d, 0xaf, 0x08, 0x17, 0x16,
-	0x1f, 0x94, 0x30, 0x79, 0x0a, 0x1d, 0xcd, 0xe4, 0x25, 0xd7, 0x61, 0x2e, 0x45, 0x5c, 0x44, 0x3a,
-	0x68, 0xa0, 0xa0, 0x6d, 0xa3, 0xef, 0x6c, 0x90, 0xc4, 0xf0, 0xa0, 0xa4, 0xd9, 0x24, 0x3e, 0x32,
-	0x99, 0xb0, 0x4c, 0x07, 0x4b, 0x5d, 0x67, 0xb3, 0xb3, 0xfd, 0x7c, 0x6b, 0x4e, 0xcd, 0x5b, 0xb5,
-	0x7a, 0xb7, 0xf6, 0x0c, 0x72, 0x61, 0x45, 0xbb, 0x8b, 0x07, 0x27, 0x87, 0x94, 0x58, 0xbf, 0x3a,
-	0x40, 0x86, 0xe0, 0x95, 0xbb, 0x30, 0x19, 0x5d, 0x05, 0xcb, 0x68, 0xfe, 0xf4, 0xb3, 0xe6, 0x7d,
-	0x19, 0x5d, 0xed, 0x36, 0xcf, 0x4f, 0x8e, 0x4e, 0x86, 0x3f, 0x9f, 0x50, 0xb0, 0x16, 0x26, 0x48,
-	0xb6, 0x60, 0xad, 0x66, 0x38, 0xcd, 0xba, 0x89, 0x25, 0xae, 0xce, 0x88, 0x55, 0x02, 0xdf, 0x41,
-	0x99, 0x56, 0x18, 0xe5, 0xc5, 0x94, 0xde, 0x42, 0xba, 0x6f, 0x91, 0x41, 0x5e, 0x54, 0xec, 0x23,
-	0x70, 0xaf, 0x84, 0x2a, 0x93, 0x75, 0xbf, 0x28, 0xd9, 0x96, 0x31, 0xc0, 0x54, 0x29, 0xb4, 0xd1,
-	0x6c, 0x3b, 0x8b, 0xad, 0x21, 0x7c, 0x91, 0xa1, 0x67, 0x4c, 0xb6, 0xb3, 0x18, 0x3d, 0xd7, 0xa1,
-	0x89, 0x9e, 0x42, 0x05, 0x1e, 0xd6, 0xb0, 0x6c, 0x96, 0x43, 0x45, 0x7a, 0xe5, 0x66, 0x42, 0x85,
-	0xfc, 0x5a, 0x4b, 0x16, 0xac, 0x20, 0xec, 0x59, 0xf8, 0xc0, 0x84, 0xa6, 0x9c, 0x48, 0x0a, 0xa5,
-	0x8c, 0x45, 0x7b, 0xc6, 0x19, 0x98, 0xd8, 0x50, 0x91, 0x67, 0x70, 0xbf, 0xc6, 0xc1, 0xb4, 0x3b,
-	0xb6, 0x7d, 0xa6, 0x2c, 0x4c, 0xe4, 0x39, 0xac, 0xd5, 0x78, 0xd3, 0x12, 0xef, 0xdb, 0x83, 0x9d,
-	0x72, 0x6b, 0x79, 0x8b, 0x42, 0x87, 0x71, 0x22, 0x03, 0xdf, 0xe6, 0x2d, 0x0a, 0xbd, 0x9f, 0x48,
-	0xf2, 0x03, 0x78, 0x8a, 0xeb, 0x22, 0x0f, 0xb5, 0x10, 0x13, 0x15, 0xac, 0x76, 0x17, 0x37, 0xbd,
-	0xed, 0x8d, 0xb9, 0x47, 0xf4, 0x8e, 0xcb, 0xf1, 0xdb, 0x6c, 0x2c, 0x28, 0xa0, 0xe2, 0xcc, 0x08,
-	0xc8, 0x2e, 0xb8, 0xbf, 0x31, 0x9d, 0x84, 0xb2, 0xc8, 0x54, 0x40, 0xee, 0xa2, 0x6e, 0x19, 0x3e,
-	0x2d, 0x32, 0x45, 0xde, 0x00, 0x58, 0x26, 0x8a, 0xd7, 0xee, 0x22, 0x76, 0x11, 0xad, 0xd4, 0x59,
-	0x92, 0x7d, 0x60, 0x56, 0xfd, 0xe0, 0x4e, 0x6a, 0x14, 0xa0, 0xfa, 0x7b, 0x58, 0xd2, 0x42, 0xb3,
-	0x49, 0xf0, 0xb0, 0xeb, 0x7c, 0x5e, 0x68, 0xb9, 0xe4, 0x02, 0xe6, 0x8d, 0xa2, 0xe0, 0x2b, 0xb4,
-	0x78, 0x36, 0xd7, 0xe2, 0xd4, 0xc4, 0xf0, 0x49, 0x96, 0x1d, 0x46, 0x57, 0xd5, 0x3f, 0x43, 0x64,
-	0x00, 0x2b, 0x56, 0x15, 0x89, 0x6c, 0x9c, 0x5c, 0x06, 0xeb, 0x68, 0xd8, 0x9d, 0x6b, 0x88, 0xc2,
-	0x01, 0xf2, 0xa8, 0x37, 0x9a, 0x2d, 0x7a, 0x2f, 0x61, 0xe5, 0xd6, 0xd3, 0x6f, 0x41, 0xe3, 0xfc,
-	0xf4, 0x80, 0xfa, 0xf7, 0x48, 0x1b, 0x5c, 0xf3, 0xb5, 0x7f, 0xb0, 0x77, 0x7e, 0xe8, 0x3b, 0xa4,
-	0x09, 0x66, 0x5c, 0xf8, 0x0b, 0xbd, 0x37, 0xd0, 0xc0, 0xe6, 0xf0, 0xa0, 0x6a, 0x76, 0xff, 0x9e,
-	0x41, 0xfb, 0xf4, 0xd8, 0x77, 0x88, 0x0b, 0x4b, 0x7d, 0x7a, 0xbc, 0xf3, 0xca, 0x5f, 0x30, 0xb1,
-	0xf7, 0xaf, 0x77, 0xfc, 0x45, 0x02, 0xb0, 0xfc, 0xfe, 0xf5, 0x4e, 0xb8, 0xf3, 0xca, 0x6f, 0xf4,
-	0xfa, 0xe0, 0xd5, 0x72, 0x31, 0xd3, 0xb4, 0x50, 0x3c, 0xbc, 0x14, 0x29, 0xc3, 0x99, 0xdb, 0xa2,
-	0xcd, 0x42, 0xf1, 0x43, 0x91, 0x32, 0xd3, 0x7c, 0x06, 0x92, 0x23, 0x8e, 0x73, 0xb6, 0x45, 0x97,
-	0x0b, 0xc5, 0xe9, 0x88, 0xf7, 0x7e, 0x77, 0xa0, 0x55, 0x9d, 0x31, 0x21, 0xd0, 0x88, 0xb9, 0x8a,
-	0x50, 0xec, 0x52, 0xfc, 0x36, 0x31, 0x1c, 0xb9, 0x76, 0x3c, 0xe3, 0x37, 0xd9, 0x00, 0x50, 0x9a,
-	0x49, 0x8d, 0x33, 0x1e, 0x87, 0x71, 0x83, 0xba, 0x18, 0x31, 0xa3, 0x9d, 0x3c, 0x01, 0x57, 0x72,
-	0x36, 0xb1, 0x68, 0x03, 0xd1, 0x96, 0x09, 0x20, 0xb8, 0x01, 0x90, 0xf2, 0x54, 0xc8, 0x9b, 0xb0,
-	0x50, 0x1c, 0x47, 0x6d, 0x83, 0xba, 0x36, 0x72, 0xae, 0x78, 0xef, 0x4f, 0x07, 0x3a, 0xc7, 0x22,
-	0x2e, 0x26, 0xfc, 0xec, 0x26, 0xe7, 0x98, 0xd5, 0xaf, 0xd5, 0xd5, 0xa8, 0x1b, 0xa5, 0x79, 0x8a,
-	0xd9, 0x75, 0xb6, 0x5f, 0xcc, 0x9f, 0x21, 0xb7, 0xa4, 0xf6, 0xa6, 0x4e, 0x51, 0x56, 0x9b, 0x26,
-	0xa3, 0x59, 0x94, 0x7c, 0x0d, 0x5e, 0x8a, 0x9a, 0x50, 0xdf, 0xe4, 0x55, 0x95, 0x90, 0x4e, 0x6d,
-	0xc8, 0x37, 0xd0, 0xc9, 0x8a, 0x34, 0x14, 0xe3, 0xd0, 0x06, 0x15, 0xd6, 0xdb, 0xa6, 0x2b, 0x59,
-	0x91, 0x0e, 0xc7, 0x76, 0x3f, 0xd5, 0x7b, 0x51, 0xde, 0x44, 0xe9, 0x7a, 0xeb, 0x3a, 0x5d, 0x58,
-	0x3a, 0x1d, 0x0e, 0x4f, 0xcc, 0xbd, 0xb7, 0xa0, 0x71, 0xdc, 0x3f, 0x3a, 0xf0, 0x17, 0x7a, 0x13,
-	0x78, 0x3c, 0x90, 0x89, 0x4e, 0x22, 0x36, 0x39, 0x57, 0x5c, 0xfe, 0x24, 0x0a, 0x99, 0xf1, 0x9b,
-	0xaa, 0x1b, 0xab, 0x43, 0x77, 0x6a, 0x87, 0xbe, 0x0b, 0xcd, 0xaa, 0xdb, 0x17, 0xfe, 0xa7, 0x39,
-	0x6b, 0x53, 0x94, 0x56, 0x82, 0xde, 0x08, 0x9e, 0xcc, 0xd9, 0x4d, 0xcd, 0x9a, 0xbf, 0x11, 0x15,
-	0x1f, 0x54, 0xe0, 0xe0, 0x0b, 0x9e, 0x7f, 0xb2, 0xff, 0x9d, 0x2d, 0x45, 0x71, 0xef, 0x0f, 0x07,
-	0x56, 0xff, 0xf5, 0xd4, 0x48, 0x00, 0xcd, 0xea, 0xdc, 0x1c, 0x3c, 0xb7, 0x6a, 0x49, 0x1e, 0x43,
-	0xab, 0xfc, 0x2d, 0xb2, 0x05, 0xb5, 0xe9, 0x74, 0x4d, 0xbe, 0x85, 0x55, 0x7c, 0xee, 0x21, 0x9b,
-	0x4c, 0x44, 0x14, 0x46, 0xa2, 0xc8, 0x74, 0xd9, 0x67, 0xf7, 0x11, 0xe8, 0x9b, 0xf8, 0xc0, 0x84,
-	0xc9, 0x26, 0xf8, 0x75, 0xae, 0x4a, 0x3e, 0x55, 0x4d, 0xd7, 0x99, 0x51, 0x4f, 0x93, 0x4f, 0xdc,
-	0x0c, 0xff, 0x94, 0x5d, 0x87, 0x57, 0x9c, 0xe5, 0x96, 0x66, 0xbb, 0xcf, 0x4b, 0xd9, 0xf5, 0x8f,
-	0x9c, 0xe5, 0x86, 0xb3, 0xf7, 0xf0, 0x97, 0x72, 0xbe, 0x94, 0x75, 0x87, 0xf8, 0xff, 0xe7, 0xef,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x0a, 0x03, 0x26, 0x59, 0x0f, 0x09, 0x00, 0x00,
+	// 1395 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0x6f, 0x4f, 0xdc, 0x46,
+	0x13, 0x8f, 0xe1, 0xe0, 0xee, 0xc6, 0x1c, 0x39, 0x16, 0x92, 0x38, 0xc9, 0x93, 0x27, 0xc8, 0xcf,
+	0x93, 0x14, 0x55, 0x0d, 0x89, 0x68, 0x84, 0x22, 0x5a, 0xb5, 0x82, 0x0b, 0xa5, 0x14, 0xc1, 0x45,
+	0x4b, 0xa0, 0xe9, 0x1f, 0x69, 0xb5, 0xb6, 0xf7, 0xc0, 0x89, 0xed, 0x3d, 0x79, 0xd7, 0x88, 0xe3,
+	0x75, 0xdf, 0xf6, 0x55, 0x3f, 0x52, 0xbf, 0x44, 0xa5, 0xbe, 0xee, 0xf7, 0xa8, 0x76, 0xd6, 0xbe,
+	0x3b, 0xca, 0x35, 0x41, 0x79, 0x67, 0xcf, 0xfc, 0x7e, 0xbf, 0x9d, 0x19, 0xcf, 0xce, 0xdc, 0x41,
+	0x2b, 0x15, 0x3a, 0x8f, 0x43, 0xb5, 0xda, 0xcf, 0xa5, 0x96, 0x64, 0x51, 0x49, 0x99, 0x9d, 0xb0,
+	0xa0, 0x88, 0x93, 0x88, 0x95, 0x2e, 0xff, 0xb7, 0x16, 0xb8, 0xfb, 0xf6, 0x79, 0x8b, 0x2b, 0x41,
+	0x9e, 0xc1, 0x92, 0x05, 0x44, 0x5c, 0x0b, 0xa6, 0xe3, 0x54, 0x28, 0xcd, 0xd3, 0xbe, 0xe7, 0x2c,
+	0x3b, 0x2b, 0xd3, 0x94, 0xa0, 0xef, 0x25, 0xd7, 0xe2, 0x75, 0xe5, 0x21, 0x77, 0xa1, 0x61, 0x19,
+	0x71, 0xe4, 0x4d, 0x2d, 0x3b, 0x2b, 0x4d, 0x5a, 0xc7, 0xf7, 0xdd, 0x88, 0x6c, 0xc0, 0xdd, 0x7e,
+	0xc2, 0x75, 0x4f, 0xe6, 0x29, 0x3b, 0x13, 0xb9, 0x8a, 0x65, 0xc6, 0x42, 0x19, 0x89, 0x8c, 0xa7,
+	0xc2, 0x9b, 0x46, 0xec, 0x9d, 0x0a, 0x70, 0x6c, 0xfd, 0x9d, 0xd2, 0x4d, 0x1e, 0xc1, 0xbc, 0xe6,
+	0xf9, 0x89, 0xd0, 0xac, 0x9f, 0xcb, 0xa8, 0x08, 0xb5, 0x57, 0x43, 0x42, 0xcb, 0x5a, 0x5f, 0x59,
+	0x23, 0x89, 0x60, 0xa9, 0x84, 0xd9, 0x20, 0xce, 0x78, 0x1e, 0xf3, 0x4c, 0x7b, 0x33, 0xcb, 0xce,
+	0xca, 0xfc, 0xda, 0x93, 0xd5, 0x09, 0x39, 0xaf, 0x8e, 0xe5, 0xbb, 0xba, 0x65, 0x3c, 0xc7, 0x96,
+	0xb4, 0x31, 0xbd, 0x7d, 0xb0, 0x43, 0x89, 0xd5, 0x1b, 0x77, 0x90, 0x2e, 0xb8, 0xe5, 0x29, 0x3c,
+	0x0f, 0x4f, 0xbd, 0x59, 0x14, 0x7f, 0xf4, 0x41, 0xf1, 0xcd, 0x3c, 0x3c, 0xdd, 0xa8, 0x1f, 0x1d,
+	0xec, 0x1d, 0x74, 0xbf, 0x3f, 0xa0, 0x60, 0x25, 0x8c, 0x91, 0xac, 0xc2, 0xe2, 0x98, 0xe0, 0x30,
+	0xea, 0x3a, 0xa6, 0xb8, 0x30, 0x02, 0x56, 0x01, 0x7c, 0x06, 0x65, 0x58, 0x2c, 0xec, 0x17, 0x43,
+	0x78, 0x03, 0xe1, 0x6d, 0xeb, 0xe9, 0xf4, 0x8b, 0x0a, 0xbd, 0x07, 0xcd, 0x53, 0xa9, 0xca, 0x60,
+	0x9b, 0x1f, 0x15, 0x6c, 0xc3, 0x08, 0x60, 0xa8, 0x14, 0x5a, 0x28, 0xb6, 0x96, 0x45, 0x56, 0x10,
+	0x3e, 0x4a, 0xd0, 0x35, 0x22, 0x6b, 0x59, 0x84, 0x9a, 0x77, 0xa0, 0x8e, 0x9a, 0x52, 0x79, 0x2e,
+	0xe6, 0x30, 0x6b, 0x5e, 0xbb, 0x8a, 0xf8, 0xe5, 0x61, 0x52, 0x31, 0x71, 0xae, 0x73, 0xee, 0xcd,
+	0xa1, 0xdb, 0xb5, 0xee, 0x6d, 0x63, 0x1a, 0x62, 0xc2, 0x5c, 0x2a, 0x65, 0x24, 0x5a, 0x23, 0x4c,
+	0xc7, 0xd8, 0xba, 0x8a, 0x3c, 0x86, 0x9b, 0x63, 0x18, 0x0c, 0x7b, 0xde, 0xb6, 0xcf, 0x10, 0x85,
+	0x81, 0x3c, 0x81, 0xc5, 0x31, 0xdc, 0x30, 0xc5, 0x9b, 0xb6, 0xb0, 0x43, 0xec, 0x58, 0xdc, 0xb2,
+	0xd0, 0x2c, 0x8a, 0x73, 0xaf, 0x6d, 0xe3, 0x96, 0x85, 0x7e, 0x19, 0xe7, 0xe4, 0x2b, 0x70, 0x95,
+	0xd0, 0x45, 0x9f, 0x69, 0x29, 0x13, 0xe5, 0x2d, 0x2c, 0x4f, 0xaf, 0xb8, 0x6b, 0x0f, 0x26, 0x96,
+	0xe8, 0x95, 0xc8, 0x7b, 0xbb, 0x59, 0x4f, 0x52, 0x40, 0xc6, 0x6b, 0x43, 0x20, 0x1b, 0xd0, 0x7c,
+	0xc7, 0x75, 0xcc, 0xf2, 0x22, 0x53, 0x1e, 0xb9, 0x0e, 0xbb, 0x61, 0xf0, 0xb4, 0xc8, 0x14, 0xf9,
+	0x12, 0xc0, 0x22, 0x91, 0xbc, 0x78, 0x1d, 0x72, 0x13, 0xbd, 0x15, 0x3b, 0x8b, 0xb3, 0xb7, 0xdc,
+	0xb2, 0x97, 0xae, 0xc5, 0x46, 0x02, 0xb2, 0x3f, 0x87, 0x19, 0x2d, 0x35, 0x4f, 0xbc, 0x5b, 0xcb,
+	0xce, 0x87, 0x89, 0x16, 0x4b, 0x8e, 0x61, 0xd2, 0x28, 0xf2, 0x6e, 0xa3, 0xc4, 0xe3, 0x89, 0x12,
+	0x87, 0xc6, 0x86, 0x57, 0xb2, 0xec, 0x30, 0xba, 0xa0, 0xfe, 0x69, 0x22, 0x1d, 0x98, 0xb3, 0xac,
+	0x50, 0x66, 0xbd, 0xf8, 0xc4, 0xbb, 0x83, 0x82, 0xcb, 0x13, 0x05, 0x91, 0xd8, 0x41, 0x1c, 0x75,
+	0x83, 0xd1, 0x0b, 0xf9, 0x19, 0x6e, 0xf1, 0x50, 0x9b, 0x49, 0x95, 0x0b, 0x25, 0x8b, 0x3c, 0x14,
+	0xac, 0x50, 0xfc, 0x44, 0x78, 0x1e, 0xaa, 0xad, 0x4c, 0x54, 0xdb, 0x44, 0x06, 0x2d, 0x09, 0x47,
+	0x06, 0x4f, 0x17, 0xf9, 0x55, 0x23, 0xf9, 0x01, 0x96, 0x4a, 0xf5, 0x90, 0x87, 0xa7, 0x62, 0x98,
+	0xfb, 0x5d, 0x14, 0xff, 0xe4, 0x3d, 0xe2, 0x1d, 0x83, 0xaf, 0x92, 0x27, 0xfc, 0x8a, 0x8d, 0xec,
+	0x03, 0xb1, 0xec, 0x93, 0x44, 0x06, 0x2c, 0x3c, 0xe5, 0xd9, 0x89, 0x50, 0xde, 0x3d, 0xfc, 0xa0,
+	0x0f, 0x27, 0x0a, 0xef, 0x24, 0x32, 0xe8, 0x20, 0x8e, 0xb6, 0xd1, 0x3f, 0x32, 0x28, 0xff, 0x19,
+	0xcc, 0x5d, 0x1a, 0x81, 0x0d, 0xa8, 0x1d, 0x1d, 0x6e, 0xd3, 0xf6, 0x0d, 0xd2, 0x82, 0xa6, 0x79,
+	0x7a, 0xb9, 0xbd, 0x75, 0xb4, 0xd3, 0x76, 0x48, 0x1d, 0xcc, 0xd8, 0x6c, 0x4f, 0xf9, 0xbb, 0x50,
+	0xc3, 0x4b, 0xe2, 0x42, 0x75, 0xe9, 0xdb, 0x37, 0x8c, 0x77, 0x93, 0xee, 0xb7, 0x1d, 0xd2, 0x84,
+	0x99, 0x4d, 0xba, 0xbf, 0xfe, 0xbc, 0x3d, 0x65, 0x6c, 0x6f, 0x5e, 0xac, 0xb7, 0xa7, 0x09, 0xc0,
+	0xec, 0x9b, 0x17, 0xeb, 0x6c, 0xfd, 0x79, 0xbb, 0x66, 0x58, 0x74, 0xf7, 0xb0, 0x73, 0xbc, 0xfe,
+	0xbc, 0x3d, 0xe3, 0x6f, 0x82, 0x3b, 0xf6, 0x81, 0xcc, 0x8a, 0x29, 0x94, 0x60, 0x27, 0x32, 0xe5,
+	0xb8, 0x88, 0x1a, 0xb4, 0x5e, 0x28, 0xb1, 0x23, 0x53, 0x6e, 0x6e, 0xa4, 0x71, 0xe5, 0x81, 0xc0,
+	0xe5, 0xd3, 0xa0, 0xb3, 0x85, 0x12, 0x34, 0x10, 0xfe, 0xaf, 0x0e, 0x34, 0xaa, 0xc6, 0x23, 0x04,
+	0x6a, 0x91, 0x50, 0x21, 0x92, 0x9b, 0x14, 0x9f, 0x8d, 0x0d, 0xf7, 0x90, 0xdd, 0x59, 0xf8, 0x4c,
+	0x1e, 0x00, 0x28, 0xcd, 0x73, 0x8d, 0x8b, 0x0f, 0x37, 0x54, 0x8d, 0x36, 0xd1, 0x62, 0xf6, 0x1d,
+	0xb9, 0x0f, 0xcd, 0x5c, 0xf0, 0xc4, 0x7a, 0x6b, 0xe8, 0x6d, 0x18, 0x03, 0x3a, 0x1f, 0x00, 0xa4,
+	0x22, 0x95, 0xf9, 0x80, 0x15, 0x4a, 0xe0, 0xfe, 0xa9, 0xd1, 0xa6, 0xb5, 0x1c, 0x29, 0xe1, 0xff,
+	0xe5, 0xc0, 0xfc, 0xbe, 0x8c, 0x8a, 0x44, 0xbc, 0x1e, 0xf4, 0x05, 0x46, 0xf5, 0x53, 0xd5, 0xaf,
+	0x6a, 0xa0, 0xb4, 0x48, 0x31, 0xba, 0xf9, 0xb5, 0xa7, 0x93, 0x07, 0xeb, 0x25, 0xaa, 0x6d, 0xdf,
+	0x43, 0xa4, 0x8d, 0x8d, 0xd8, 0x60, 0x64, 0x25, 0x0f, 0xc1, 0x4d, 0x91, 0xc3, 0xf4, 0xa0, 0x5f,
+	0x65, 0x09, 0xe9, 0x50, 0x86, 0xfc, 0x1f, 0xe6, 0xb3, 0x22, 0x65, 0xb2, 0xc7, 0xac, 0x51, 0x61,
+	0xbe, 0x2d, 0x3a, 0x97, 0x15, 0x69, 0xb7, 0x67, 0xcf, 0x53, 0xfe, 0xd3, 0xf2, 0x4b, 0x94, 0xaa,
+	0x97, 0xbe, 0x6d, 0x13, 0x66, 0x0e, 0xbb, 0xdd, 0x03, 0xd3, 0x04, 0x0d, 0xa8, 0xed, 0x6f, 0xee,
+	0x6d, 0xb7, 0xa7, 0xfc, 0x04, 0xee, 0x75, 0xf2, 0x58, 0xc7, 0x21, 0x4f, 0x8e, 0x94, 0xc8, 0xbf,
+	0x93, 0x45, 0x9e, 0x89, 0x41, 0xd5, 0xa4, 0x55, 0xd1, 0x9d, 0xb1, 0xa2, 0x6f, 0x40, 0xbd, 0xba,
+	0x06, 0x53, 0xef, 0xb9, 0xb1, 0x63, 0xab, 0x85, 0x56, 0x04, 0x3f, 0x80, 0xfb, 0x13, 0x4e, 0x53,
+	0xa3, 0x89, 0x50, 0x0b, 0x8b, 0xb7, 0xca, 0x73, 0xf0, 0x16, 0x4c, 0xae, 0xec, 0xbf, 0x47, 0x4b,
+	0x91, 0xec, 0xff, 0xee, 0xc0, 0xc2, 0x95, 0xf9, 0x43, 0x3c, 0xa8, 0x57, 0x75, 0x73, 0xb0, 0x6e,
+	0xd5, 0x2b, 0xb9, 0x07, 0x8d, 0x72, 0x41, 0xdb, 0x84, 0x5a, 0x74, 0xf8, 0x4e, 0x3e, 0x85, 0x05,
+	0x9c, 0x81, 0x8c, 0x27, 0x89, 0x0c, 0x59, 0x28, 0x8b, 0x4c, 0x97, 0x7d, 0x76, 0x13, 0x1d, 0x9b,
+	0xc6, 0xde, 0x31, 0x66, 0xb2, 0x02, 0xed, 0x71, 0xac, 0x8a, 0x2f, 0xaa, 0xa6, 0x9b, 0x1f, 0x41,
+	0x0f, 0xe3, 0x0b, 0x61, 0x36, 0x62, 0xca, 0xcf, 0xd9, 0xa9, 0xe0, 0x7d, 0x0b, 0xb3, 0xdd, 0xe7,
+	0xa6, 0xfc, 0xfc, 0x5b, 0xc1, 0xfb, 0x06, 0xe3, 0xff, 0xe1, 0xc0, 0xe2, 0x84, 0x31, 0x45, 0xbe,
+	0x80, 0x7a, 0x30, 0x60, 0x79, 0x91, 0x88, 0xb2, 0x4a, 0xfe, 0xc4, 0x2a, 0x5d, 0x22, 0xd1, 0xd9,
+	0x60, 0x40, 0x8b, 0x44, 0x90, 0xaf, 0xa1, 0x19, 0x0c, 0xca, 0xfe, 0xf1, 0xa6, 0xae, 0x4d, 0x6f,
+	0x04, 0x03, 0xdb, 0x5f, 0xa4, 0x03, 0xae, 0x96, 0x7d, 0x66, 0xc7, 0x99, 0xe9, 0xc0, 0xeb, 0x4a,
+	0x80, 0x96, 0x7d, 0x9b, 0x8e, 0xf2, 0xff, 0x74, 0xa0, 0x75, 0x39, 0xa9, 0x49, 0x6d, 0xe6, 0x41,
+	0xbd, 0x3a, 0xc6, 0x7e, 0x95, 0xea, 0xd5, 0x5c, 0xeb, 0x42, 0x89, 0x7c, 0xfc, 0xd2, 0x9b, 0x79,
+	0x93, 0xe3, 0xb5, 0x7e, 0x08, 0xae, 0xbd, 0x9e, 0xe3, 0xb7, 0x1e, 0xac, 0x09, 0x01, 0xff, 0x01,
+	0x30, 0xc5, 0xcf, 0x95, 0x62, 0xef, 0x82, 0xb2, 0xf2, 0x8d, 0x94, 0x9f, 0x53, 0xa5, 0xf6, 0x02,
+	0xf2, 0x5f, 0x70, 0x63, 0xc9, 0xe2, 0xac, 0x5f, 0x68, 0xe3, 0x9e, 0xb5, 0x63, 0x21, 0x96, 0xbb,
+	0xc6, 0xb2, 0x17, 0x90, 0x65, 0x98, 0x8b, 0x25, 0x93, 0x85, 0x2e, 0x01, 0x75, 0xab, 0x1f, 0xcb,
+	0x2e, 0x9a, 0xf6, 0x02, 0xff, 0x17, 0x07, 0xc8, 0xd5, 0x15, 0x60, 0x52, 0x3c, 0x8d, 0xb5, 0x6d,
+	0xbe, 0x1a, 0xc5, 0x67, 0x72, 0x1b, 0x66, 0xd3, 0x58, 0x29, 0x61, 0x33, 0xac, 0xd1, 0xf2, 0x0d,
+	0xc7, 0x5a, 0x7c, 0x21, 0x58, 0x30, 0xd0, 0x42, 0x0d, 0xc7, 0x5a, 0x7c, 0x21, 0xb6, 0x8c, 0x81,
+	0xfc, 0x0f, 0x5a, 0xe2, 0x2c, 0x0e, 0xb5, 0x88, 0x4a, 0x84, 0x4d, 0x72, 0xae, 0x34, 0x22, 0xc8,
+	0xef, 0x01, 0x8c, 0xd6, 0x83, 0x39, 0xdd, 0xac, 0x99, 0xaa, 0xc0, 0xe6, 0xd9, 0x54, 0x8a, 0x47,
+	0x91, 0x88, 0x58, 0x2f, 0x4e, 0x30, 0x84, 0x69, 0x33, 0x71, 0xd0, 0xf4, 0x4d, 0x9c, 0xd8, 0x73,
+	0x72, 0x91, 0xca, 0xb3, 0x21, 0x64, 0x1a, 0x21, 0x73, 0xa5, 0x11, 0x41, 0x5b, 0xb7, 0x7e, 0x2c,
+	0x7f, 0x1c, 0x94, 0xdf, 0x9d, 0xe1, 0x9f, 0x97, 0xbf, 0x07, 0x00, 0x59, 0x8e, 0x1d, 0x49, 0xcc,
+	0x0c, 0x00, 0x00,
 }
@@ -0,0 +1,366 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// This package answers queries about the dependencies between modules over the
+// module graph written by soong_build (out/soong/module_graph.json).
+//
+// The supported queries are:
+//
+//   deps(X)        the modules X depends on, directly or transitively, and X
+//   rdeps(X)       the modules which depend on X, directly or transitively, and X
+//   somepath(X, Y) a shortest dependency path from X to Y
+//   allpaths(X, Y) the modules on any dependency path from X to Y
+//
+// X and Y are module names, which match all the variants of the module, or a
+// single variant written as name{variant}.
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// A Node is a variant of a module in the module graph.
+type Node struct {
+	Name    string
+	Variant string
+	Type    string
+
+	deps  []Edge
+	rdeps []Edge
+}
+
+// An Edge is a dependency of a module variant on another, with the description of its
+// dependency tag.
+type Edge struct {
+	From, To *Node
+	Tag      string
+}
+
+func (n *Node) String() string {
+	if n.Variant == "" {
+		return n.Name
+	}
+	return n.Name + "{" + n.Variant + "}"
+}
+
+// Graph is the module graph, with the dependency edges in both directions.
+type Graph struct {
+	nodes  []*Node
+	byName map[string][]*Node
+}
+
+// The subset of the module graph format of soong_build that is needed for queries.
+type jsonModuleGraph struct {
+	Modules []struct {
+		Name    string `json:"name"`
+		Type    string `json:"type"`
+		Variant string `json:"variant"`
+		Deps    []struct {
+			Name    string `json:"name"`
+			Variant string `json:"variant"`
+			Tag     string `json:"tag"`
+		} `json:"deps"`
+	} `json:"modules"`
+}
+
+// LoadGraph reads the module graph from a file written by soong_build --module_graph_file.
+func LoadGraph(file string) (*Graph, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g, err := ReadGraph(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module graph %s: %s", file, err)
+	}
+	return g, nil
+}
+
+// ReadGraph reads the module graph in the format written by soong_build --module_graph_file.
+func ReadGraph(r io.Reader) (*Graph, error) {
+	var data jsonModuleGraph
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	g := &Graph{byName: make(map[string][]*Node)}
+	byVariant := make(map[string]*Node)
+	key := func(name, variant string) string {
+		return name + "\x00" + variant
+	}
+	for _, m := range data.Modules {
+		n := &Node{Name: m.Name, Variant: m.Variant, Type: m.Type}
+		g.nodes = append(g.nodes, n)
+		g.byName[n.Name] = append(g.byName[n.Name], n)
+		byVariant[key(n.Name, n.Variant)] = n
+	}
+	for i, m := range data.Modules {
+		from := g.nodes[i]
+		for _, dep := range m.Deps {
+			to, ok := byVariant[key(dep.Name, dep.Variant)]
+			if !ok {
+				return nil, fmt.Errorf("%s depends on unknown module variant %s{%s}", from, dep.Name, dep.Variant)
+			}
+			edge := Edge{From: from, To: to, Tag: dep.Tag}
+			from.deps = append(from.deps, edge)
+			to.rdeps = append(to.rdeps, edge)
+		}
+	}
+	return g, nil
+}
+
+// Result is the result of a query.
+type Result struct {
+	// The modules matching the query. For somepath they are in the order of the path, otherwise
+	// they are sorted.
+	Nodes []*Node
+
+	// For somepath, the edges of the path.
+	Path []Edge
+}
+
+// Query evaluates a query expression against the module graph.
+func (g *Graph) Query(expr string) (*Result, error) {
+	function, args, err := parseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var sets [][]*Node
+	for _, arg := range args {
+		nodes, err := g.lookup(arg)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, nodes)
+	}
+
+	switch function {
+	case "deps":
+		return sortedResult(reachable(sets[0], false)), nil
+	case "rdeps":
+		return sortedResult(reachable(sets[0], true)), nil
+	case "somepath":
+		return somePath(sets[0], sets[1]), nil
+	case "allpaths":
+		return sortedResult(allPaths(sets[0], sets[1])), nil
+	default:
+		panic(fmt.Errorf("unhandled query function %q", function))
+	}
+}
+
+var queryArgs = map[string]int{
+	"deps":     1,
+	"rdeps":    1,
+	"somepath": 2,
+	"allpaths": 2,
+}
+
+// parseQuery splits a query expression into its function and arguments.
+func parseQuery(expr string) (string, []string, error) {
+	expr = strings.TrimSpace(expr)
+	open := strings.Index(expr, "(")
+	if open == -1 || !strings.HasSuffix(expr, ")") {
+		return "", nil, fmt.Errorf("invalid query %q, expected function(arguments)", expr)
+	}
+
+	function := strings.TrimSpace(expr[:open])
+	numArgs, ok := queryArgs[function]
+	if !ok {
+		return "", nil, fmt.Errorf("unknown query function %q, expected one of deps, rdeps, somepath or allpaths", function)
+	}
+
+	args := strings.Split(expr[open+1:len(expr)-1], ",")
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+		if args[i] == "" {
+			return "", nil, fmt.Errorf("invalid query %q, empty argument", expr)
+		}
+	}
+	if len(args) != numArgs {
+		return "", nil, fmt.Errorf("%s takes %d arguments, got %d", function, numArgs, len(args))
+	}
+	return function, args, nil
+}
+
+// lookup returns the variants matching a query argument, either a module name or
+// name{variant}.
+func (g *Graph) lookup(arg string) ([]*Node, error) {
+	name, variant := arg, ""
+	hasVariant := false
+	if i := strings.Index(arg, "{"); i != -1 && strings.HasSuffix(arg, "}") {
+		name, variant = arg[:i], arg[i+1:len(arg)-1]
+		hasVariant = true
+	}
+
+	nodes := g.byName[name]
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("no module named %q", name)
+	}
+	if !hasVariant {
+		return nodes, nil
+	}
+	for _, n := range nodes {
+		if n.Variant == variant {
+			return []*Node{n}, nil
+		}
+	}
+	var variants []string
+	for _, n := range nodes {
+		variants = append(variants, n.Variant)
+	}
+	return nil, fmt.Errorf("module %q has no variant %q, variants are: %s", name, variant, strings.Join(variants, ", "))
+}
+
+// reachable returns the nodes reachable from the start nodes by following the dependency edges,
+// or the reverse dependency edges, including the start nodes.
+func reachable(start []*Node, reverse bool) map[*Node]bool {
+	seen := make(map[*Node]bool)
+	queue := append([]*Node(nil), start...)
+	for _, n := range start {
+		seen[n] = true
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		edges := n.deps
+		if reverse {
+			edges = n.rdeps
+		}
+		for _, e := range edges {
+			other := e.To
+			if reverse {
+				other = e.From
+			}
+			if !seen[other] {
+				seen[other] = true
+				queue = append(queue, other)
+			}
+		}
+	}
+	return seen
+}
+
+// allPaths returns the nodes on any path from a node in from to a node in to.
+func allPaths(from, to []*Node) map[*Node]bool {
+	deps := reachable(from, false)
+	rdeps := reachable(to, true)
+	ret := make(map[*Node]bool)
+	for n := range deps {
+		if rdeps[n] {
+			ret[n] = true
+		}
+	}
+	return ret
+}
+
+// somePath returns a shortest path from a node in from to a node in to, or an empty result if
+// there is none.
+func somePath(from, to []*Node) *Result {
+	targets := make(map[*Node]bool)
+	for _, n := range to {
+		targets[n] = true
+	}
+
+	// Breadth first search, remembering the edge each node was reached through.
+	via := make(map[*Node]*Edge)
+	seen := make(map[*Node]bool)
+	queue := append([]*Node(nil), from...)
+	for _, n := range from {
+		seen[n] = true
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if targets[n] {
+			result := &Result{Nodes: []*Node{n}}
+			for e := via[n]; e != nil; e = via[e.From] {
+				result.Path = append([]Edge{*e}, result.Path...)
+				result.Nodes = append([]*Node{e.From}, result.Nodes...)
+			}
+			return result
+		}
+		for i := range n.deps {
+			e := &n.deps[i]
+			if !seen[e.To] {
+				seen[e.To] = true
+				via[e.To] = e
+				queue = append(queue, e.To)
+			}
+		}
+	}
+	return &Result{}
+}
+
+func sortedResult(nodes map[*Node]bool) *Result {
+	result := &Result{}
+	for n := range nodes {
+		result.Nodes = append(result.Nodes, n)
+	}
+	sort.Slice(result.Nodes, func(i, j int) bool {
+		if result.Nodes[i].Name != result.Nodes[j].Name {
+			return result.Nodes[i].Name < result.Nodes[j].Name
+		}
+		return result.Nodes[i].Variant < result.Nodes[j].Variant
+	})
+	return result
+}
+
+// WriteText writes the result with one module variant per line. The lines of a path also show
+// the dependency tag of the edge to the module.
+func (r *Result) WriteText(w io.Writer) error {
+	for i, n := range r.Nodes {
+		line := n.String()
+		if i > 0 && len(r.Path) > 0 && r.Path[i-1].Tag != "" {
+			line += "  (" + r.Path[i-1].Tag + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonResultModule struct {
+	Name    string `json:"name"`
+	Variant string `json:"variant"`
+	Type    string `json:"type"`
+	// For paths, the dependency tag of the edge to the module.
+	Tag string `json:"tag,omitempty"`
+}
+
+// WriteJSON writes the result as a JSON list of module variants.
+func (r *Result) WriteJSON(w io.Writer) error {
+	modules := []jsonResultModule{}
+	for i, n := range r.Nodes {
+		m := jsonResultModule{Name: n.Name, Variant: n.Variant, Type: n.Type}
+		if i > 0 && len(r.Path) > 0 {
+			m.Tag = r.Path[i-1].Tag
+		}
+		modules = append(modules, m)
+	}
+	data, err := json.MarshalIndent(modules, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
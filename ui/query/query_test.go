@@ -0,0 +1,202 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package query
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// bin{a} -> libfoo{a} -> libbar{a} -> libbase{a}
+// bin{a} -> libbaz{a} -> libbase{a}
+// libfoo{b} -> libbar{b}
+const testGraph = `{
+  "modules": [
+    {"name": "bin", "type": "cc_binary", "variant": "a", "deps": [
+      {"name": "libfoo", "variant": "a", "tag": "shared"},
+      {"name": "libbaz", "variant": "a", "tag": "static"}
+    ]},
+    {"name": "libfoo", "type": "cc_library", "variant": "a", "deps": [
+      {"name": "libbar", "variant": "a", "tag": "static"}
+    ]},
+    {"name": "libfoo", "type": "cc_library", "variant": "b", "deps": [
+      {"name": "libbar", "variant": "b", "tag": "static"}
+    ]},
+    {"name": "libbar", "type": "cc_library", "variant": "a", "deps": [
+      {"name": "libbase", "variant": "a", "tag": "header"}
+    ]},
+    {"name": "libbar", "type": "cc_library", "variant": "b"},
+    {"name": "libbaz", "type": "cc_library", "variant": "a", "deps": [
+      {"name": "libbase", "variant": "a", "tag": "header"}
+    ]},
+    {"name": "libbase", "type": "cc_library_headers", "variant": "a"},
+    {"name": "unrelated", "type": "filegroup", "variant": ""}
+  ]
+}`
+
+func TestQuery(t *testing.T) {
+	g, err := ReadGraph(strings.NewReader(testGraph))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		query    string
+		expected string
+		err      string
+	}{
+		{
+			query: "deps(libfoo)",
+			expected: `libbar{a}
+libbar{b}
+libbase{a}
+libfoo{a}
+libfoo{b}
+`,
+		},
+		{
+			query: "deps(libfoo{b})",
+			expected: `libbar{b}
+libfoo{b}
+`,
+		},
+		{
+			query: "rdeps(libbase)",
+			expected: `bin{a}
+libbar{a}
+libbase{a}
+libbaz{a}
+libfoo{a}
+`,
+		},
+		{
+			query: "somepath(bin, libbase)",
+			expected: `bin{a}
+libbaz{a}  (static)
+libbase{a}  (header)
+`,
+		},
+		{
+			query:    "somepath(libfoo{b}, libbase)",
+			expected: ``,
+		},
+		{
+			query: " allpaths( bin , libbar ) ",
+			expected: `bin{a}
+libbar{a}
+libfoo{a}
+`,
+		},
+		{
+			query:    "deps(unrelated)",
+			expected: "unrelated\n",
+		},
+		{
+			query: "deps(missing)",
+			err:   `no module named "missing"`,
+		},
+		{
+			query: "deps(libfoo{c})",
+			err:   `module "libfoo" has no variant "c", variants are: a, b`,
+		},
+		{
+			query: "somepath(bin)",
+			err:   "somepath takes 2 arguments, got 1",
+		},
+		{
+			query: "kind(bin)",
+			err:   `unknown query function "kind", expected one of deps, rdeps, somepath or allpaths`,
+		},
+		{
+			query: "bin",
+			err:   `invalid query "bin", expected function(arguments)`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.query, func(t *testing.T) {
+			result, err := g.Query(testCase.query)
+			if testCase.err != "" {
+				if err == nil || err.Error() != testCase.err {
+					t.Fatalf("expected error %q, got %v", testCase.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf := &bytes.Buffer{}
+			if err := result.WriteText(buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != testCase.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", testCase.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	g, err := ReadGraph(strings.NewReader(testGraph))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := g.Query("somepath(bin, libbar)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := result.WriteJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `[
+  {
+    "name": "bin",
+    "variant": "a",
+    "type": "cc_binary"
+  },
+  {
+    "name": "libfoo",
+    "variant": "a",
+    "type": "cc_library",
+    "tag": "shared"
+  },
+  {
+    "name": "libbar",
+    "variant": "a",
+    "type": "cc_library",
+    "tag": "static"
+  }
+]
+`
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestReadGraphUnknownDep(t *testing.T) {
+	_, err := ReadGraph(strings.NewReader(`{"modules": [
+		{"name": "bin", "type": "cc_binary", "variant": "a", "deps": [{"name": "libfoo", "variant": "a"}]}
+	]}`))
+	expected := "bin{a} depends on unknown module variant libfoo{a}"
+	if err == nil || err.Error() != expected {
+		t.Errorf("expected error %q, got %v", expected, err)
+	}
+}
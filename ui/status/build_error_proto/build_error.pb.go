@@ -82,7 +82,15 @@ type BuildActionError struct {
 	// List of artifacts (i.e. files) that was produced by the command.
 	Artifacts []string `protobuf:"bytes,4,rep,name=artifacts" json:"artifacts,omitempty"`
 	// The error string produced by the build action.
-	Error                *string  `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	Error *string `protobuf:"bytes,5,opt,name=error" json:"error,omitempty"`
+	// The name of the Soong module that created the build action.
+	ModuleName *string `protobuf:"bytes,6,opt,name=module_name,json=moduleName" json:"module_name,omitempty"`
+	// The type of the Soong module, eg. cc_library.
+	ModuleType *string `protobuf:"bytes,7,opt,name=module_type,json=moduleType" json:"module_type,omitempty"`
+	// The variant of the Soong module, eg. android_arm64_armv8-a_shared.
+	ModuleVariant *string `protobuf:"bytes,8,opt,name=module_variant,json=moduleVariant" json:"module_variant,omitempty"`
+	// The Android.bp file that defines the Soong module.
+	BlueprintFile        *string  `protobuf:"bytes,9,opt,name=blueprint_file,json=blueprintFile" json:"blueprint_file,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -148,6 +156,34 @@ func (m *BuildActionError) GetError() string {
 	return ""
 }
 
+func (m *BuildActionError) GetModuleName() string {
+	if m != nil && m.ModuleName != nil {
+		return *m.ModuleName
+	}
+	return ""
+}
+
+func (m *BuildActionError) GetModuleType() string {
+	if m != nil && m.ModuleType != nil {
+		return *m.ModuleType
+	}
+	return ""
+}
+
+func (m *BuildActionError) GetModuleVariant() string {
+	if m != nil && m.ModuleVariant != nil {
+		return *m.ModuleVariant
+	}
+	return ""
+}
+
+func (m *BuildActionError) GetBlueprintFile() string {
+	if m != nil && m.BlueprintFile != nil {
+		return *m.BlueprintFile
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*BuildError)(nil), "soong_build_error.BuildError")
 	proto.RegisterType((*BuildActionError)(nil), "soong_build_error.BuildActionError")
@@ -156,20 +192,24 @@ func init() {
 func init() { proto.RegisterFile("build_error.proto", fileDescriptor_a2e15b05802a5501) }
 
 var fileDescriptor_a2e15b05802a5501 = []byte{
-	// 229 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xc1, 0x4a, 0xc3, 0x40,
-	0x10, 0x86, 0x49, 0x63, 0x95, 0x4c, 0xad, 0xd8, 0x41, 0x74, 0x04, 0x0f, 0xa1, 0x22, 0xe4, 0x94,
-	0x83, 0x6f, 0x60, 0x41, 0xf0, 0xe2, 0x25, 0x47, 0x2f, 0x61, 0xdd, 0xac, 0x65, 0xc1, 0x64, 0xc2,
-	0xce, 0xe6, 0xe8, 0x8b, 0xf8, 0xb4, 0x92, 0x69, 0xa5, 0xa5, 0x39, 0x7e, 0xdf, 0x3f, 0xfb, 0xef,
-	0xce, 0xc2, 0xea, 0x73, 0xf0, 0xdf, 0x4d, 0xed, 0x42, 0xe0, 0x50, 0xf6, 0x81, 0x23, 0xe3, 0x4a,
-	0x98, 0xbb, 0x6d, 0x7d, 0x14, 0xac, 0x7f, 0x00, 0x36, 0x23, 0xbe, 0x8e, 0x84, 0x4f, 0x70, 0xa5,
-	0xba, 0x6e, 0x9d, 0x88, 0xd9, 0x3a, 0xa1, 0x24, 0x4f, 0x8b, 0xac, 0x5a, 0xaa, 0x7d, 0xdf, 0x4b,
-	0x7c, 0x83, 0xa5, 0xb1, 0xd1, 0x73, 0xb7, 0x2b, 0x11, 0x9a, 0xe5, 0x69, 0xb1, 0x78, 0x7e, 0x2c,
-	0x27, 0xfd, 0xa5, 0x96, 0xbf, 0xe8, 0xb0, 0x5e, 0x51, 0x5d, 0x9a, 0x03, 0xc8, 0xfa, 0x37, 0x81,
-	0xeb, 0xd3, 0x11, 0xcc, 0x61, 0xd1, 0x38, 0xb1, 0xc1, 0xf7, 0xa3, 0xa3, 0x24, 0x4f, 0x8a, 0xac,
-	0x3a, 0x56, 0x48, 0x70, 0x61, 0xb9, 0x6d, 0x4d, 0xd7, 0xd0, 0x4c, 0xd3, 0x7f, 0xc4, 0x5b, 0x38,
-	0xe7, 0x21, 0xf6, 0x43, 0xa4, 0x54, 0x83, 0x3d, 0xe1, 0x03, 0x64, 0x26, 0x44, 0xff, 0x65, 0x6c,
-	0x14, 0x3a, 0xd3, 0xa5, 0x0e, 0x02, 0x6f, 0x60, 0xae, 0xcf, 0xa5, 0xb9, 0x1e, 0xda, 0xc1, 0xe6,
-	0xfe, 0xe3, 0x6e, 0xb2, 0x50, 0xad, 0x3f, 0xf9, 0x17, 0x00, 0x00, 0xff, 0xff, 0xb6, 0x18, 0x9e,
-	0x17, 0x5d, 0x01, 0x00, 0x00,
+	// 295 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x91, 0xc1, 0x4a, 0x03, 0x31,
+	0x10, 0x86, 0x69, 0x6b, 0x5b, 0x77, 0x6a, 0xc5, 0x06, 0xd1, 0x11, 0x04, 0x97, 0x8a, 0xd0, 0x53,
+	0x0f, 0xbe, 0x81, 0x05, 0xc5, 0x8b, 0x1e, 0x8a, 0x78, 0xf0, 0xb2, 0xa4, 0xbb, 0xd3, 0x12, 0xd8,
+	0x4d, 0x42, 0x92, 0x15, 0x7a, 0xf0, 0x85, 0x7c, 0x4a, 0xd9, 0x49, 0xb5, 0x8b, 0x3d, 0xfe, 0xdf,
+	0xff, 0xed, 0x6c, 0x32, 0x81, 0xc9, 0xaa, 0x56, 0x65, 0x91, 0x91, 0x73, 0xc6, 0xcd, 0xad, 0x33,
+	0xc1, 0x88, 0x89, 0x37, 0x46, 0x6f, 0xb2, 0x56, 0x31, 0xfd, 0x02, 0x58, 0x34, 0xf1, 0xb1, 0x49,
+	0xe2, 0x0e, 0x4e, 0x19, 0x67, 0x15, 0x79, 0x2f, 0x37, 0xe4, 0xb1, 0x93, 0xf6, 0x66, 0xc9, 0x72,
+	0xcc, 0xf4, 0x65, 0x07, 0xc5, 0x33, 0x8c, 0x65, 0x1e, 0x94, 0xd1, 0x71, 0x88, 0xc7, 0x6e, 0xda,
+	0x9b, 0x8d, 0xee, 0x6f, 0xe7, 0x07, 0xf3, 0xe7, 0x3c, 0xfc, 0x81, 0x65, 0xfe, 0xc5, 0xf2, 0x44,
+	0xee, 0x83, 0x9f, 0x7e, 0x77, 0xe1, 0xec, 0xbf, 0x22, 0x52, 0x18, 0x15, 0xe4, 0x73, 0xa7, 0x6c,
+	0xc3, 0xb0, 0x93, 0x76, 0x66, 0xc9, 0xb2, 0x8d, 0x04, 0xc2, 0x30, 0x37, 0x55, 0x25, 0x75, 0x81,
+	0x5d, 0x6e, 0x7f, 0xa3, 0xb8, 0x80, 0x81, 0xa9, 0x83, 0xad, 0x03, 0xf6, 0xb8, 0xd8, 0x25, 0x71,
+	0x0d, 0x89, 0x74, 0x41, 0xad, 0x65, 0x1e, 0x3c, 0x1e, 0xf1, 0xa5, 0xf6, 0x40, 0x9c, 0x43, 0x9f,
+	0x8f, 0x8b, 0x7d, 0xfe, 0x28, 0x06, 0x71, 0x03, 0xa3, 0xca, 0x14, 0x75, 0x49, 0x99, 0x96, 0x15,
+	0xe1, 0x80, 0x3b, 0x88, 0xe8, 0x55, 0x56, 0xd4, 0x12, 0xc2, 0xd6, 0x12, 0x0e, 0xdb, 0xc2, 0xdb,
+	0xd6, 0x52, 0xb3, 0xcf, 0x9d, 0xf0, 0x29, 0x9d, 0x92, 0x3a, 0xe0, 0x31, 0x3b, 0xe3, 0x48, 0xdf,
+	0x23, 0x6c, 0xb4, 0x55, 0x59, 0x93, 0x75, 0x4a, 0x87, 0x6c, 0xad, 0x4a, 0xc2, 0x24, 0x6a, 0x7f,
+	0xf4, 0x49, 0x95, 0xb4, 0xb8, 0xfa, 0xb8, 0x3c, 0x58, 0x70, 0xc6, 0x2f, 0xfb, 0x33, 0x00, 0xaf,
+	0x8c, 0xac, 0xae, 0xed, 0x01, 0x00, 0x00,
 }
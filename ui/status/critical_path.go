@@ -20,7 +20,16 @@ import (
 	"android/soong/ui/logger"
 )
 
-func NewCriticalPath(log logger.Logger) StatusOutput {
+// CriticalPath is a StatusOutput which computes the critical path of the build, the chain of
+// dependent actions with the longest total duration.
+type CriticalPath interface {
+	StatusOutput
+
+	// Actions returns the actions on the critical path, in the order they ran.
+	Actions() []*Action
+}
+
+func NewCriticalPath(log logger.Logger) CriticalPath {
 	return &criticalPath{
 		log:     log,
 		running: make(map[*Action]time.Time),
@@ -132,6 +141,15 @@ func (cp *criticalPath) Message(level MsgLevel, msg string) {}
 
 func (cp *criticalPath) Write(p []byte) (n int, err error) { return len(p), nil }
 
+func (cp *criticalPath) Actions() []*Action {
+	criticalPath := cp.criticalPath()
+	actions := make([]*Action, len(criticalPath))
+	for i, node := range criticalPath {
+		actions[len(criticalPath)-1-i] = node.action
+	}
+	return actions
+}
+
 func (cp *criticalPath) criticalPath() []*node {
 	var max *node
 
@@ -161,6 +161,14 @@ func TestCriticalPath(t *testing.T) {
 			if gotTime != tt.wantTime {
 				t.Errorf("cumulativeDuration[0].cumulativeDuration = %v, want %v", gotTime, tt.wantTime)
 			}
+
+			var actions []string
+			for _, action := range cp.Actions() {
+				actions = append([]string{action.Description}, actions...)
+			}
+			if !reflect.DeepEqual(actions, tt.want) {
+				t.Errorf("reversed criticalPath.Actions() = %v, want %v", actions, tt.want)
+			}
 		})
 	}
 }
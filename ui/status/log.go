@@ -16,6 +16,7 @@ package status
 
 import (
 	"compress/gzip"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -153,14 +154,24 @@ type errorProtoLog struct {
 	errorProto soong_build_error_proto.BuildError
 	filename   string
 	log        logger.Logger
+
+	// The module actions table of soong_build, loaded when the first action fails.
+	moduleActionsFile string
+	moduleActions     *moduleActions
+	loadedActions     bool
 }
 
-func NewProtoErrorLog(log logger.Logger, filename string) StatusOutput {
+// NewProtoErrorLog returns a StatusOutput which writes the failed actions to filename as a
+// BuildError proto, and as JSON to filename.json. The actions are attributed to the modules which
+// created them with the module actions table written by soong_build to moduleActionsFile.
+func NewProtoErrorLog(log logger.Logger, filename, moduleActionsFile string) StatusOutput {
 	os.Remove(filename)
+	os.Remove(filename + ".json")
 	return &errorProtoLog{
-		errorProto: soong_build_error_proto.BuildError{},
-		filename:   filename,
-		log:        log,
+		errorProto:        soong_build_error_proto.BuildError{},
+		filename:          filename,
+		log:               log,
+		moduleActionsFile: moduleActionsFile,
 	}
 }
 
@@ -171,18 +182,40 @@ func (e *errorProtoLog) FinishAction(result ActionResult, counts Counts) {
 		return
 	}
 
-	e.errorProto.ActionErrors = append(e.errorProto.ActionErrors, &soong_build_error_proto.BuildActionError{
+	actionError := &soong_build_error_proto.BuildActionError{
 		Description: proto.String(result.Description),
 		Command:     proto.String(result.Command),
 		Output:      proto.String(result.Output),
 		Artifacts:   result.Outputs,
 		Error:       proto.String(result.Error.Error()),
-	})
+	}
+
+	if !e.loadedActions && e.moduleActionsFile != "" {
+		e.loadedActions = true
+		var err error
+		if e.moduleActions, err = loadModuleActions(e.moduleActionsFile); err != nil {
+			e.log.Verbosef("Failed to load the module actions %s: %v", e.moduleActionsFile, err)
+		}
+	}
+	if module, ok := e.moduleActions.module(result.Action); ok {
+		actionError.ModuleName = proto.String(module.Name)
+		if module.Type != "" {
+			actionError.ModuleType = proto.String(module.Type)
+			actionError.ModuleVariant = proto.String(module.Variant)
+			actionError.BlueprintFile = proto.String(module.Blueprint)
+		}
+	}
+
+	e.errorProto.ActionErrors = append(e.errorProto.ActionErrors, actionError)
 
 	err := writeToFile(&e.errorProto, e.filename)
 	if err != nil {
 		e.log.Printf("Failed to write file %s: %v\n", e.filename, err)
 	}
+	err = writeJSONToFile(&e.errorProto, e.filename+".json")
+	if err != nil {
+		e.log.Printf("Failed to write file %s: %v\n", e.filename+".json", err)
+	}
 }
 
 func (e *errorProtoLog) Flush() {
@@ -251,6 +284,19 @@ func (b *buildProgressLog) updateCounters(counts Counts) {
 	}
 }
 
+func writeJSONToFile(v interface{}, outputPath string) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tempPath := outputPath + ".tmp"
+	if err := ioutil.WriteFile(tempPath, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, outputPath)
+}
+
 func writeToFile(pb proto.Message, outputPath string) (err error) {
 	data, err := proto.Marshal(pb)
 	if err != nil {
@@ -0,0 +1,157 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+
+	"android/soong/ui/logger"
+	"android/soong/ui/status/build_error_proto"
+)
+
+func TestProtoErrorLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proto_error_log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	moduleActionsFile := filepath.Join(dir, "module_actions.json")
+	err = ioutil.WriteFile(moduleActionsFile, []byte(`{
+		"modules": [
+			{"name": "libfoo", "type": "cc_library", "variant": "android_arm64_armv8-a_shared", "blueprint": "external/foo/Android.bp"}
+		],
+		"outputs": {
+			"out/soong/.intermediates/external/foo/libfoo/android_arm64_armv8-a_shared/obj/foo.o": 0,
+			"out/soong/.intermediates/external/foo/libfoo/android_arm64_armv8-a_shared/libfoo.so": 0
+		}
+	}`), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buildErrorFile := filepath.Join(dir, "build_error")
+	errorLog := NewProtoErrorLog(nil, buildErrorFile, moduleActionsFile)
+	for _, action := range []*Action{
+		{
+			Description: "//external/foo:libfoo clang++ foo.cpp",
+			Outputs:     []string{"out/soong/.intermediates/external/foo/libfoo/android_arm64_armv8-a_shared/obj/foo.o"},
+		},
+		{
+			// Attributed from the second output.
+			Description: "//external/foo:libfoo strip libfoo.so",
+			Outputs: []string{"out/soong/.intermediates/external/foo/libfoo/android_arm64_armv8-a_shared/libfoo.so.toc",
+				"out/soong/.intermediates/external/foo/libfoo/android_arm64_armv8-a_shared/libfoo.so"},
+		},
+		{
+			// Not in the module actions, attributed from the description.
+			Description: "//external/bar:libbar clang++ bar.cpp",
+			Outputs:     []string{"out/soong/.intermediates/external/bar/libbar/android_arm64_armv8-a_shared/obj/bar.o"},
+		},
+		{
+			// Not in the module actions and not a Soong action.
+			Description: "target C++: libbaz <= external/baz/baz.cpp",
+			Outputs:     []string{"out/target/product/generic/obj/SHARED_LIBRARIES/libbaz_intermediates/baz.o"},
+		},
+	} {
+		errorLog.FinishAction(ActionResult{Action: action, Error: errors.New("exited with code: 1")}, Counts{})
+	}
+
+	data, err := ioutil.ReadFile(buildErrorFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildError := &soong_build_error_proto.BuildError{}
+	if err := proto.Unmarshal(data, buildError); err != nil {
+		t.Fatal(err)
+	}
+
+	type module struct {
+		name, moduleType, variant, blueprint string
+	}
+	var got []module
+	for _, actionError := range buildError.GetActionErrors() {
+		got = append(got, module{actionError.GetModuleName(), actionError.GetModuleType(),
+			actionError.GetModuleVariant(), actionError.GetBlueprintFile()})
+	}
+	want := []module{
+		{"libfoo", "cc_library", "android_arm64_armv8-a_shared", "external/foo/Android.bp"},
+		{"libfoo", "cc_library", "android_arm64_armv8-a_shared", "external/foo/Android.bp"},
+		{"libbar", "", "", ""},
+		{"", "", "", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want modules %q, got %q", want, got)
+	}
+
+	// The JSON sidecar has the same contents.
+	data, err = ioutil.ReadFile(buildErrorFile + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonError := &soong_build_error_proto.BuildError{}
+	if err := json.Unmarshal(data, jsonError); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(jsonError, buildError) {
+		t.Errorf("want JSON build error %v, got %v", buildError, jsonError)
+	}
+}
+
+func TestProtoErrorLogMissingModuleActions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "proto_error_log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// soong_build didn't write the module actions, the actions are attributed from the description.
+	buildErrorFile := filepath.Join(dir, "build_error")
+	errorLog := NewProtoErrorLog(logger.New(ioutil.Discard), buildErrorFile, filepath.Join(dir, "module_actions.json"))
+	errorLog.FinishAction(ActionResult{
+		Action: &Action{
+			Description: "//external/foo:libfoo clang++ foo.cpp",
+			Outputs:     []string{"out/soong/.intermediates/external/foo/libfoo/android_arm64_armv8-a_shared/obj/foo.o"},
+		},
+		Error: errors.New("exited with code: 1"),
+	}, Counts{})
+
+	data, err := ioutil.ReadFile(buildErrorFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buildError := &soong_build_error_proto.BuildError{}
+	if err := proto.Unmarshal(data, buildError); err != nil {
+		t.Fatal(err)
+	}
+	if len(buildError.GetActionErrors()) != 1 {
+		t.Fatalf("want 1 action error, got %v", buildError.GetActionErrors())
+	}
+	actionError := buildError.GetActionErrors()[0]
+	if g, w := actionError.GetModuleName(), "libfoo"; g != w {
+		t.Errorf("want module name %q, got %q", w, g)
+	}
+	if actionError.ModuleType != nil || actionError.ModuleVariant != nil || actionError.BlueprintFile != nil {
+		t.Errorf("want only the module name, got %v", actionError)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+)
+
+// moduleActions is the table written by soong_build (out/soong/module_actions.json) that maps the
+// outputs of the build actions to the modules which created them.
+type moduleActions struct {
+	Modules []actionModule `json:"modules"`
+	Outputs map[string]int `json:"outputs"`
+}
+
+// An actionModule is the module variant which created a build action.
+type actionModule struct {
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Variant   string `json:"variant"`
+	Blueprint string `json:"blueprint"`
+}
+
+func loadModuleActions(file string) (*moduleActions, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	actions := &moduleActions{}
+	if err := json.NewDecoder(f).Decode(actions); err != nil {
+		return nil, err
+	}
+	return actions, nil
+}
+
+// module returns the module which created an action from the table, which soong_build writes
+// whenever it runs. As a last resort, Soong actions which aren't in the table, because it is
+// missing or out of date, are attributed from the module label that starts their descriptions
+// ("//dir:module ..."), which only gives the name of the module.
+func (m *moduleActions) module(action *Action) (actionModule, bool) {
+	if m != nil {
+		for _, output := range action.Outputs {
+			if i, ok := m.Outputs[output]; ok && i >= 0 && i < len(m.Modules) {
+				return m.Modules[i], true
+			}
+		}
+	}
+
+	if strings.HasPrefix(action.Description, "//") {
+		label := strings.Fields(action.Description)[0]
+		if i := strings.LastIndex(label, ":"); i != -1 {
+			return actionModule{Name: label[i+1:]}, true
+		}
+	}
+	return actionModule{}, false
+}
@@ -371,10 +371,24 @@ type Status_EdgeFinished struct {
 	// Number of milliseconds spent executing in user mode
 	UserTime *uint32 `protobuf:"varint,5,opt,name=user_time,json=userTime" json:"user_time,omitempty"`
 	// Number of milliseconds spent executing in kernel mode
-	SystemTime           *uint32  `protobuf:"varint,6,opt,name=system_time,json=systemTime" json:"system_time,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	SystemTime *uint32 `protobuf:"varint,6,opt,name=system_time,json=systemTime" json:"system_time,omitempty"`
+	// Max resident set size in kB
+	MaxRssKb *uint64 `protobuf:"varint,7,opt,name=max_rss_kb,json=maxRssKb" json:"max_rss_kb,omitempty"`
+	// Minor page faults
+	MinorPageFaults *uint64 `protobuf:"varint,8,opt,name=minor_page_faults,json=minorPageFaults" json:"minor_page_faults,omitempty"`
+	// Major page faults
+	MajorPageFaults *uint64 `protobuf:"varint,9,opt,name=major_page_faults,json=majorPageFaults" json:"major_page_faults,omitempty"`
+	// IO input in kB
+	IoInputKb *uint64 `protobuf:"varint,10,opt,name=io_input_kb,json=ioInputKb" json:"io_input_kb,omitempty"`
+	// IO output in kB
+	IoOutputKb *uint64 `protobuf:"varint,11,opt,name=io_output_kb,json=ioOutputKb" json:"io_output_kb,omitempty"`
+	// Voluntary context switches
+	VoluntaryContextSwitches *uint64 `protobuf:"varint,12,opt,name=voluntary_context_switches,json=voluntaryContextSwitches" json:"voluntary_context_switches,omitempty"`
+	// Involuntary context switches
+	InvoluntaryContextSwitches *uint64  `protobuf:"varint,13,opt,name=involuntary_context_switches,json=involuntaryContextSwitches" json:"involuntary_context_switches,omitempty"`
+	XXX_NoUnkeyedLiteral       struct{} `json:"-"`
+	XXX_unrecognized           []byte   `json:"-"`
+	XXX_sizecache              int32    `json:"-"`
 }
 
 func (m *Status_EdgeFinished) Reset()         { *m = Status_EdgeFinished{} }
@@ -444,6 +458,55 @@ func (m *Status_EdgeFinished) GetSystemTime() uint32 {
 	return 0
 }
 
+func (m *Status_EdgeFinished) GetMaxRssKb() uint64 {
+	if m != nil && m.MaxRssKb != nil {
+		return *m.MaxRssKb
+	}
+	return 0
+}
+
+func (m *Status_EdgeFinished) GetMinorPageFaults() uint64 {
+	if m != nil && m.MinorPageFaults != nil {
+		return *m.MinorPageFaults
+	}
+	return 0
+}
+
+func (m *Status_EdgeFinished) GetMajorPageFaults() uint64 {
+	if m != nil && m.MajorPageFaults != nil {
+		return *m.MajorPageFaults
+	}
+	return 0
+}
+
+func (m *Status_EdgeFinished) GetIoInputKb() uint64 {
+	if m != nil && m.IoInputKb != nil {
+		return *m.IoInputKb
+	}
+	return 0
+}
+
+func (m *Status_EdgeFinished) GetIoOutputKb() uint64 {
+	if m != nil && m.IoOutputKb != nil {
+		return *m.IoOutputKb
+	}
+	return 0
+}
+
+func (m *Status_EdgeFinished) GetVoluntaryContextSwitches() uint64 {
+	if m != nil && m.VoluntaryContextSwitches != nil {
+		return *m.VoluntaryContextSwitches
+	}
+	return 0
+}
+
+func (m *Status_EdgeFinished) GetInvoluntaryContextSwitches() uint64 {
+	if m != nil && m.InvoluntaryContextSwitches != nil {
+		return *m.InvoluntaryContextSwitches
+	}
+	return 0
+}
+
 type Status_Message struct {
 	// Message priority level (DEBUG, INFO, WARNING, ERROR).
 	Level *Status_Message_Level `protobuf:"varint,1,opt,name=level,enum=ninja.Status_Message_Level,def=0" json:"level,omitempty"`
@@ -511,39 +574,48 @@ func init() {
 }
 
 var fileDescriptor_eca3873955a29cfe = []byte{
-	// 539 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x54, 0xd1, 0x6e, 0xd3, 0x4a,
-	0x10, 0xbd, 0x4e, 0xe2, 0x38, 0x1e, 0x27, 0xb9, 0x61, 0x25, 0x90, 0xeb, 0x0a, 0x35, 0xea, 0x53,
-	0x5f, 0x08, 0x12, 0x42, 0x42, 0x20, 0x24, 0x44, 0x44, 0x5a, 0x8a, 0x20, 0x95, 0xb6, 0x45, 0x48,
-	0xbc, 0x44, 0x4e, 0x77, 0x5a, 0x8c, 0xec, 0x75, 0xe4, 0xdd, 0x54, 0xe2, 0x37, 0xf8, 0x09, 0xfe,
-	0x80, 0xaf, 0xe3, 0x01, 0xed, 0xec, 0xda, 0x75, 0x68, 0xdf, 0x7c, 0x76, 0xce, 0x9c, 0x39, 0x7b,
-	0x76, 0x64, 0x18, 0x5f, 0x55, 0xa5, 0xd4, 0x28, 0xc5, 0x6c, 0x53, 0x95, 0xba, 0x64, 0xbe, 0xcc,
-	0xe4, 0xf7, 0xf4, 0xf0, 0x4f, 0x00, 0xfd, 0x73, 0x9d, 0xea, 0xad, 0x62, 0x2f, 0x21, 0xd2, 0xa5,
-	0x4e, 0xf3, 0x15, 0x8a, 0x6b, 0x54, 0xb1, 0x37, 0xf5, 0x8e, 0xa2, 0x67, 0xf1, 0x8c, 0x78, 0x33,
-	0xcb, 0x99, 0x5d, 0x18, 0xc2, 0xc2, 0xd4, 0x39, 0xe8, 0xe6, 0x9b, 0xbd, 0x81, 0xd1, 0x7a, 0x9b,
-	0xe5, 0x62, 0xa5, 0x74, 0x5a, 0x69, 0x14, 0x71, 0x87, 0x9a, 0x93, 0xdd, 0xe6, 0xb9, 0xa1, 0x9c,
-	0x5b, 0x06, 0x1f, 0xae, 0x5b, 0x88, 0xcd, 0x61, 0x6c, 0x05, 0xae, 0x32, 0x99, 0xa9, 0x6f, 0x28,
-	0xe2, 0x2e, 0x29, 0xec, 0xdf, 0xa3, 0x70, 0xec, 0x28, 0xdc, 0xce, 0xac, 0x21, 0x7b, 0x0d, 0x43,
-	0xe3, 0xbc, 0xf1, 0xd0, 0x23, 0x85, 0xbd, 0x5d, 0x05, 0xe3, 0xb7, 0xb6, 0x10, 0xe1, 0x2d, 0x30,
-	0x57, 0xa0, 0xee, 0xc6, 0x80, 0x7f, 0xdf, 0x15, 0x4c, 0x7b, 0x33, 0x9f, 0xc6, 0x35, 0xe3, 0x9f,
-	0x42, 0x50, 0xa0, 0x52, 0xe9, 0x35, 0xc6, 0x7d, 0x6a, 0x7d, 0xb8, 0xdb, 0xfa, 0xc9, 0x16, 0x79,
-	0xcd, 0x4a, 0x9e, 0x00, 0xdc, 0xc6, 0xc9, 0x0e, 0xee, 0xa6, 0x3f, 0x6a, 0x67, 0x9c, 0x7c, 0x80,
-	0x61, 0x3b, 0x40, 0x36, 0x85, 0x68, 0x93, 0x56, 0x69, 0x9e, 0x63, 0x9e, 0xa9, 0xc2, 0x35, 0xb4,
-	0x8f, 0x58, 0x0c, 0xc1, 0x0d, 0x56, 0xeb, 0x52, 0x21, 0xbd, 0xc7, 0x80, 0xd7, 0x30, 0xf9, 0x1f,
-	0x46, 0x3b, 0x51, 0x26, 0xbf, 0x3d, 0x88, 0x5a, 0xd1, 0xb0, 0x31, 0x74, 0x32, 0xe1, 0x34, 0x3b,
-	0x99, 0x60, 0x8f, 0x01, 0x28, 0xd6, 0x95, 0xce, 0x0a, 0xab, 0x36, 0xe2, 0x21, 0x9d, 0x5c, 0x64,
-	0x05, 0xb2, 0x47, 0xd0, 0xcf, 0xe4, 0x66, 0xab, 0x55, 0xdc, 0x9d, 0x76, 0x8f, 0x42, 0xee, 0x90,
-	0x71, 0x50, 0x6e, 0x35, 0x15, 0x7a, 0x54, 0xa8, 0x21, 0x63, 0xd0, 0x13, 0xa8, 0x2e, 0x29, 0xe5,
-	0x90, 0xd3, 0xb7, 0x61, 0x5f, 0x96, 0x45, 0x91, 0x4a, 0x41, 0x09, 0x86, 0xbc, 0x86, 0xb6, 0x22,
-	0x55, 0x99, 0x63, 0x1c, 0xd8, 0x9b, 0x38, 0x98, 0xfc, 0xf2, 0x60, 0xd8, 0x7e, 0x94, 0x3b, 0xce,
-	0xf7, 0x60, 0x80, 0x52, 0xb4, 0x7d, 0x07, 0x28, 0x45, 0xed, 0x5a, 0xd1, 0xdb, 0xd0, 0xb2, 0x3d,
-	0xe0, 0x0e, 0x99, 0x73, 0x6b, 0x93, 0x56, 0x28, 0xe4, 0x0e, 0xb1, 0x7d, 0x08, 0xb7, 0x0a, 0x2b,
-	0xab, 0xe5, 0x93, 0xd6, 0xc0, 0x1c, 0x90, 0xd8, 0x01, 0x44, 0xea, 0x87, 0xd2, 0x58, 0xd8, 0x72,
-	0xdf, 0xbe, 0x9f, 0x3d, 0x32, 0x84, 0xe4, 0xa7, 0x07, 0x81, 0xdb, 0x01, 0xf6, 0x02, 0xfc, 0x1c,
-	0x6f, 0x30, 0x27, 0x9f, 0xe3, 0x7f, 0xb7, 0xdc, 0xb1, 0x66, 0x1f, 0x0d, 0xe5, 0x55, 0xef, 0x74,
-	0x79, 0x7c, 0xc6, 0x2d, 0xdf, 0x04, 0x51, 0x2f, 0x59, 0xc7, 0x46, 0xe4, 0xe0, 0xe1, 0x73, 0xf0,
-	0x89, 0xcf, 0x06, 0x40, 0x1d, 0x93, 0xff, 0x58, 0x04, 0xc1, 0x97, 0xb7, 0x7c, 0x79, 0xba, 0x3c,
-	0x99, 0x78, 0x2c, 0x04, 0x7f, 0xc1, 0xf9, 0x19, 0x9f, 0x74, 0xcc, 0xe7, 0xbb, 0xc5, 0xfc, 0xf3,
-	0xc9, 0xa4, 0x3b, 0x67, 0xef, 0xbb, 0x5f, 0xc7, 0x34, 0x7c, 0x55, 0xff, 0x1f, 0xfe, 0x06, 0x00,
-	0x00, 0xff, 0xff, 0xaf, 0x93, 0x48, 0xcf, 0x2a, 0x04, 0x00, 0x00,
+	// 677 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x94, 0x6f, 0x4f, 0xd4, 0x4e,
+	0x10, 0xc7, 0x7f, 0xf7, 0xff, 0x3a, 0xbd, 0x3b, 0x8e, 0x4d, 0x7e, 0xa6, 0x14, 0x94, 0x0b, 0x8f,
+	0x88, 0x89, 0x67, 0x62, 0x4c, 0x8c, 0x86, 0x44, 0x3d, 0x05, 0x44, 0x14, 0xcc, 0x82, 0x31, 0xf1,
+	0x49, 0xb3, 0xbd, 0x2e, 0xb0, 0xd8, 0x76, 0x2f, 0xdd, 0x2d, 0xc2, 0xdb, 0xf0, 0xb5, 0x18, 0xdf,
+	0x8e, 0x6f, 0xc5, 0xec, 0x6c, 0x7b, 0xf4, 0x00, 0x7d, 0xd6, 0xef, 0xcc, 0x67, 0xbe, 0x9d, 0x9d,
+	0xd9, 0x16, 0x06, 0x27, 0x99, 0x4c, 0x35, 0x4f, 0xa3, 0xf1, 0x2c, 0x93, 0x5a, 0x92, 0x56, 0x2a,
+	0xd2, 0x73, 0xb6, 0xf1, 0x13, 0xa0, 0x7d, 0xa4, 0x99, 0xce, 0x15, 0x79, 0x0e, 0xae, 0x96, 0x9a,
+	0xc5, 0x01, 0x8f, 0x4e, 0xb9, 0xf2, 0x6a, 0xa3, 0xda, 0xa6, 0xfb, 0xc4, 0x1b, 0x23, 0x37, 0xb6,
+	0xcc, 0xf8, 0xd8, 0x00, 0xdb, 0x26, 0x4f, 0x41, 0xcf, 0x9f, 0xc9, 0x4b, 0xe8, 0x87, 0xb9, 0x88,
+	0xa3, 0x40, 0x69, 0x96, 0x69, 0x1e, 0x79, 0x75, 0x2c, 0xf6, 0x17, 0x8b, 0x27, 0x06, 0x39, 0xb2,
+	0x04, 0xed, 0x85, 0x15, 0x45, 0x26, 0x30, 0xb0, 0x06, 0x27, 0x22, 0x15, 0xea, 0x8c, 0x47, 0x5e,
+	0x03, 0x1d, 0x56, 0xef, 0x70, 0xd8, 0x29, 0x10, 0xda, 0x0f, 0xab, 0x92, 0x6c, 0x41, 0xcf, 0x74,
+	0x3e, 0xef, 0xa1, 0x89, 0x0e, 0x2b, 0x8b, 0x0e, 0xa6, 0xdf, 0xb2, 0x05, 0x97, 0x5f, 0x0b, 0x73,
+	0x04, 0xac, 0x9e, 0x37, 0xd0, 0xba, 0xeb, 0x08, 0xa6, 0x7c, 0xfe, 0xfe, 0x1e, 0xaf, 0x28, 0xf2,
+	0x18, 0x3a, 0x09, 0x57, 0x8a, 0x9d, 0x72, 0xaf, 0x8d, 0xa5, 0xff, 0x2f, 0x96, 0x7e, 0xb4, 0x49,
+	0x5a, 0x52, 0xfe, 0x23, 0x80, 0xeb, 0x71, 0x92, 0xf5, 0xdb, 0xd3, 0xef, 0x57, 0x67, 0xec, 0xbf,
+	0x87, 0x5e, 0x75, 0x80, 0x64, 0x04, 0xee, 0x8c, 0x65, 0x2c, 0x8e, 0x79, 0x2c, 0x54, 0x52, 0x14,
+	0x54, 0x43, 0xc4, 0x83, 0xce, 0x05, 0xcf, 0x42, 0xa9, 0x38, 0xee, 0xa3, 0x4b, 0x4b, 0xe9, 0x2f,
+	0x41, 0x7f, 0x61, 0x94, 0xfe, 0xaf, 0x1a, 0xb8, 0x95, 0xd1, 0x90, 0x01, 0xd4, 0x45, 0x54, 0x78,
+	0xd6, 0x45, 0x44, 0xee, 0x03, 0xe0, 0x58, 0x03, 0x2d, 0x12, 0xeb, 0xd6, 0xa7, 0x0e, 0x46, 0x8e,
+	0x45, 0xc2, 0xc9, 0x3d, 0x68, 0x8b, 0x74, 0x96, 0x6b, 0xe5, 0x35, 0x46, 0x8d, 0x4d, 0x87, 0x16,
+	0xca, 0x74, 0x20, 0x73, 0x8d, 0x89, 0x26, 0x26, 0x4a, 0x49, 0x08, 0x34, 0x23, 0xae, 0xa6, 0x38,
+	0x65, 0x87, 0xe2, 0xb3, 0xa1, 0xa7, 0x32, 0x49, 0x58, 0x1a, 0xe1, 0x04, 0x1d, 0x5a, 0x4a, 0x9b,
+	0x49, 0x95, 0x8c, 0xb9, 0xd7, 0xb1, 0x27, 0x29, 0xa4, 0xff, 0xbb, 0x01, 0xbd, 0xea, 0x52, 0x6e,
+	0x75, 0xbe, 0x02, 0x5d, 0x9e, 0x46, 0xd5, 0xbe, 0x3b, 0x3c, 0x8d, 0xca, 0xae, 0x15, 0xee, 0x06,
+	0x2f, 0xdb, 0x32, 0x2d, 0x94, 0x89, 0xdb, 0x36, 0xf1, 0x0a, 0x39, 0xb4, 0x50, 0x64, 0x15, 0x9c,
+	0x5c, 0xf1, 0xcc, 0x7a, 0xb5, 0xd0, 0xab, 0x6b, 0x02, 0x68, 0xb6, 0x0e, 0xae, 0xba, 0x52, 0x9a,
+	0x27, 0x36, 0xdd, 0xb6, 0xfb, 0xb3, 0x21, 0x04, 0xd6, 0x00, 0x12, 0x76, 0x19, 0x64, 0x4a, 0x05,
+	0xdf, 0x42, 0x3c, 0x46, 0x93, 0x76, 0x13, 0x76, 0x49, 0x95, 0xda, 0x0f, 0xc9, 0x43, 0x58, 0x4e,
+	0x44, 0x2a, 0xb3, 0x60, 0xc6, 0xcc, 0x25, 0x64, 0x79, 0xac, 0x95, 0xd7, 0x45, 0x68, 0x09, 0x13,
+	0x9f, 0xd8, 0x29, 0xdf, 0xc1, 0x30, 0xb2, 0xec, 0xfc, 0x06, 0xeb, 0x14, 0x2c, 0x3b, 0x5f, 0x60,
+	0x1f, 0x80, 0x2b, 0x64, 0x80, 0xeb, 0x30, 0xaf, 0x05, 0xa4, 0x1c, 0x21, 0xf7, 0x4c, 0x64, 0x3f,
+	0x24, 0x23, 0xe8, 0x09, 0x19, 0xd8, 0x03, 0x1a, 0xc0, 0x45, 0x00, 0x84, 0x3c, 0xc4, 0xd0, 0x7e,
+	0x48, 0xb6, 0xc0, 0xbf, 0x90, 0x71, 0x9e, 0x6a, 0x96, 0x5d, 0x05, 0x53, 0xf3, 0x0f, 0xb9, 0xd4,
+	0x81, 0xfa, 0x2e, 0xf4, 0xf4, 0x8c, 0x2b, 0xaf, 0x87, 0xbc, 0x37, 0x27, 0xde, 0x58, 0xe0, 0xa8,
+	0xc8, 0x93, 0x57, 0xb0, 0x26, 0xd2, 0x7f, 0xd4, 0xf7, 0xb1, 0xde, 0x17, 0xe9, 0xdf, 0x1c, 0xfc,
+	0x1f, 0x35, 0xe8, 0x14, 0xdf, 0x0e, 0x79, 0x06, 0xad, 0x98, 0x5f, 0xf0, 0x18, 0xf7, 0x3b, 0xb8,
+	0xf9, 0x77, 0x28, 0xa8, 0xf1, 0x07, 0x83, 0xbc, 0x68, 0xee, 0x1d, 0xec, 0x1c, 0x52, 0xcb, 0x9b,
+	0x0b, 0x54, 0x7e, 0x9c, 0x75, 0x7b, 0xb5, 0x0a, 0xb9, 0xf1, 0x14, 0x5a, 0xc8, 0x93, 0x2e, 0x60,
+	0xc5, 0xf0, 0x3f, 0xe2, 0x42, 0xe7, 0xcb, 0x6b, 0x7a, 0xb0, 0x77, 0xb0, 0x3b, 0xac, 0x11, 0x07,
+	0x5a, 0xdb, 0x94, 0x1e, 0xd2, 0x61, 0xdd, 0x3c, 0xbe, 0xdd, 0x9e, 0x7c, 0xde, 0x1d, 0x36, 0x26,
+	0xe4, 0x5d, 0xe3, 0xeb, 0x00, 0x5f, 0x1e, 0x94, 0xff, 0xd5, 0x3f, 0x03, 0x00, 0x2f, 0x7a, 0x33,
+	0x13, 0x62, 0x05, 0x00, 0x00,
 }
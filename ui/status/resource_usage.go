@@ -0,0 +1,228 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"android/soong/ui/logger"
+	soong_metrics_proto "android/soong/ui/metrics/metrics_proto"
+)
+
+// The number of actions kept in the list of the most expensive actions.
+const topActionsCount = 50
+
+// The number of the most expensive actions printed to the terminal, the verbose log has all of them.
+const topActionsPrintCount = 5
+
+// The rule of actions whose description doesn't name one.
+const otherRule = "other"
+
+// ResourceUsage is a StatusOutput which aggregates the resource usage reported by ninja for each
+// action by rule and by module, and keeps the actions which used the most CPU time.
+type ResourceUsage struct {
+	log logger.Logger
+
+	byRule     map[string]*resourceUsage
+	byModule   map[string]*resourceUsage
+	topActions []*resourceUsage
+}
+
+type resourceUsage struct {
+	name       string
+	actions    uint32
+	userTime   uint64
+	systemTime uint64
+	maxRssKB   uint64
+	ioInputKB  uint64
+	ioOutputKB uint64
+}
+
+func (r *resourceUsage) add(stats ActionResultStats) {
+	r.actions++
+	r.userTime += uint64(stats.UserTime)
+	r.systemTime += uint64(stats.SystemTime)
+	if stats.MaxRssKB > r.maxRssKB {
+		r.maxRssKB = stats.MaxRssKB
+	}
+	r.ioInputKB += stats.IOInputKB
+	r.ioOutputKB += stats.IOOutputKB
+}
+
+func (r *resourceUsage) cpuTime() uint64 {
+	return r.userTime + r.systemTime
+}
+
+func NewResourceUsage(log logger.Logger) *ResourceUsage {
+	return &ResourceUsage{
+		log:      log,
+		byRule:   make(map[string]*resourceUsage),
+		byModule: make(map[string]*resourceUsage),
+	}
+}
+
+func (r *ResourceUsage) StartAction(action *Action, counts Counts) {}
+
+func (r *ResourceUsage) FinishAction(result ActionResult, counts Counts) {
+	if result.Stats == (ActionResultStats{}) {
+		// Ninja didn't report any usage for the action.
+		return
+	}
+
+	rule, module := actionRuleAndModule(result.Description)
+	add := func(m map[string]*resourceUsage, name string) {
+		if m[name] == nil {
+			m[name] = &resourceUsage{name: name}
+		}
+		m[name].add(result.Stats)
+	}
+	add(r.byRule, rule)
+	if module != "" {
+		add(r.byModule, module)
+	}
+
+	name := result.Description
+	if name == "" {
+		name = strings.Join(result.Outputs, " ")
+	}
+	action := &resourceUsage{name: name}
+	action.add(result.Stats)
+	i := sort.Search(len(r.topActions), func(i int) bool {
+		return r.topActions[i].cpuTime() < action.cpuTime()
+	})
+	if i < topActionsCount {
+		r.topActions = append(r.topActions, nil)
+		copy(r.topActions[i+1:], r.topActions[i:])
+		r.topActions[i] = action
+		if len(r.topActions) > topActionsCount {
+			r.topActions = r.topActions[:topActionsCount]
+		}
+	}
+}
+
+// actionRuleAndModule returns the rule and the module of an action from its description. Soong
+// descriptions start with the label of the module followed by the rule ("//dir:module rule ..."),
+// Kati descriptions start with the rule followed by the module ("target C++: module <= ...").
+func actionRuleAndModule(description string) (rule, module string) {
+	fields := strings.Fields(description)
+	if len(fields) == 0 {
+		return otherRule, ""
+	}
+	if strings.HasPrefix(fields[0], "//") && strings.Contains(fields[0], ":") {
+		if len(fields) > 1 {
+			rule = fields[1]
+		} else {
+			rule = otherRule
+		}
+		return rule, fields[0]
+	}
+	if i := strings.Index(description, ": "); i != -1 && !strings.ContainsAny(description[:i], "/\"'") {
+		rule = description[:i]
+		if rest := strings.Fields(description[i+2:]); len(rest) > 0 {
+			module = rest[0]
+		}
+		return rule, module
+	}
+	return otherRule, ""
+}
+
+func (r *ResourceUsage) Flush() {
+	if len(r.topActions) == 0 {
+		return
+	}
+
+	// Log the most expensive actions to the verbose log
+	r.log.Verbosef("top %d actions by CPU time:", len(r.topActions))
+	for _, line := range topActionsTable(r.topActions) {
+		r.log.Verbose(line)
+	}
+}
+
+// Print prints the few actions which used the most CPU time to the terminal through st.
+func (r *ResourceUsage) Print(st ToolStatus) {
+	if len(r.topActions) == 0 {
+		return
+	}
+
+	actions := r.topActions
+	if len(actions) > topActionsPrintCount {
+		actions = actions[:topActionsPrintCount]
+	}
+	st.Print(fmt.Sprintf("top %d actions by CPU time (see verbose.log for more):", len(actions)))
+	for _, line := range topActionsTable(actions) {
+		st.Print(line)
+	}
+}
+
+func topActionsTable(actions []*resourceUsage) []string {
+	lines := []string{"   user(s)  sys(s) max rss(MB)  action"}
+	for _, action := range actions {
+		lines = append(lines, fmt.Sprintf("  %8.1f %7.1f %11.1f  %s",
+			float64(action.userTime)/1000, float64(action.systemTime)/1000,
+			float64(action.maxRssKB)/1024, action.name))
+	}
+	return lines
+}
+
+func (r *ResourceUsage) Message(level MsgLevel, msg string) {}
+
+func (r *ResourceUsage) Write(p []byte) (n int, err error) { return len(p), nil }
+
+// Metrics returns the resource usage of the actions for the build metrics, or nil if ninja didn't
+// report any. The rules and modules are sorted by decreasing CPU time.
+func (r *ResourceUsage) Metrics() *soong_metrics_proto.ActionResourceUsage {
+	if len(r.topActions) == 0 {
+		return nil
+	}
+	return &soong_metrics_proto.ActionResourceUsage{
+		ByRule:     resourceUsageMetrics(sortedResourceUsage(r.byRule)),
+		ByModule:   resourceUsageMetrics(sortedResourceUsage(r.byModule)),
+		TopActions: resourceUsageMetrics(r.topActions),
+	}
+}
+
+func sortedResourceUsage(m map[string]*resourceUsage) []*resourceUsage {
+	var ret []*resourceUsage
+	for _, usage := range m {
+		ret = append(ret, usage)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].cpuTime() != ret[j].cpuTime() {
+			return ret[i].cpuTime() > ret[j].cpuTime()
+		}
+		return ret[i].name < ret[j].name
+	})
+	return ret
+}
+
+func resourceUsageMetrics(usages []*resourceUsage) []*soong_metrics_proto.ResourceUsage {
+	var ret []*soong_metrics_proto.ResourceUsage
+	for _, usage := range usages {
+		ret = append(ret, &soong_metrics_proto.ResourceUsage{
+			Name:       proto.String(usage.name),
+			Actions:    proto.Uint32(usage.actions),
+			UserTime:   proto.Uint64(usage.userTime),
+			SystemTime: proto.Uint64(usage.systemTime),
+			MaxRssKb:   proto.Uint64(usage.maxRssKB),
+			IoInputKb:  proto.Uint64(usage.ioInputKB),
+			IoOutputKb: proto.Uint64(usage.ioOutputKB),
+		})
+	}
+	return ret
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type messagesOutput struct {
+	counterOutput
+
+	msgs []string
+}
+
+func (m *messagesOutput) Message(level MsgLevel, msg string) {
+	if level == PrintLvl {
+		m.msgs = append(m.msgs, msg)
+	}
+}
+func (m *messagesOutput) Flush() {}
+
+func TestActionRuleAndModule(t *testing.T) {
+	tests := []struct {
+		description  string
+		rule, module string
+	}{
+		{"//frameworks/base:framework javac out/soong/.intermediates/framework.jar", "javac", "//frameworks/base:framework"},
+		{"//bionic/libc:libc clang++ bionic.cpp [arm]", "clang++", "//bionic/libc:libc"},
+		{"//:root", "other", "//:root"},
+		{"target C++: libfoo <= external/foo/foo.cpp", "target C++", "libfoo"},
+		{"Install: out/target/product/generic/system/bin/foo", "Install", "out/target/product/generic/system/bin/foo"},
+		{"echo \"a: b\"", "other", ""},
+		{"out/soong/build.ninja", "other", ""},
+		{"", "other", ""},
+	}
+	for _, test := range tests {
+		rule, module := actionRuleAndModule(test.description)
+		if rule != test.rule || module != test.module {
+			t.Errorf("%q: want rule %q module %q, got rule %q module %q",
+				test.description, test.rule, test.module, rule, module)
+		}
+	}
+}
+
+func TestResourceUsage(t *testing.T) {
+	r := NewResourceUsage(nil)
+	finish := func(description string, stats ActionResultStats) {
+		action := &Action{Description: description}
+		r.StartAction(action, Counts{})
+		r.FinishAction(ActionResult{Action: action, Stats: stats}, Counts{})
+	}
+
+	if r.Metrics() != nil {
+		t.Errorf("want no metrics before any action finished")
+	}
+
+	finish("//a:a javac a.java", ActionResultStats{UserTime: 3000, SystemTime: 500, MaxRssKB: 1000, IOInputKB: 10})
+	finish("//a:a d8 a.jar", ActionResultStats{UserTime: 2000, SystemTime: 100, MaxRssKB: 3000, IOOutputKB: 20})
+	finish("//b:b javac b.java", ActionResultStats{UserTime: 100, MaxRssKB: 2000, IOInputKB: 5})
+	finish("//b:b touch b", ActionResultStats{})
+
+	metrics := r.Metrics()
+
+	type usage struct {
+		name                      string
+		actions                   uint32
+		userTime, systemTime, rss uint64
+		ioInput, ioOutput         uint64
+	}
+	check := func(what string, want []usage) {
+		t.Helper()
+		var got []usage
+		switch what {
+		case "rule":
+			for _, u := range metrics.GetByRule() {
+				got = append(got, usage{u.GetName(), u.GetActions(), u.GetUserTime(), u.GetSystemTime(), u.GetMaxRssKb(), u.GetIoInputKb(), u.GetIoOutputKb()})
+			}
+		case "module":
+			for _, u := range metrics.GetByModule() {
+				got = append(got, usage{u.GetName(), u.GetActions(), u.GetUserTime(), u.GetSystemTime(), u.GetMaxRssKb(), u.GetIoInputKb(), u.GetIoOutputKb()})
+			}
+		case "action":
+			for _, u := range metrics.GetTopActions() {
+				got = append(got, usage{u.GetName(), u.GetActions(), u.GetUserTime(), u.GetSystemTime(), u.GetMaxRssKb(), u.GetIoInputKb(), u.GetIoOutputKb()})
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("by %s:\nwant %v\n got %v", what, want, got)
+		}
+	}
+
+	check("rule", []usage{
+		{"javac", 2, 3100, 500, 2000, 15, 0},
+		{"d8", 1, 2000, 100, 3000, 0, 20},
+	})
+	check("module", []usage{
+		{"//a:a", 2, 5000, 600, 3000, 10, 20},
+		{"//b:b", 1, 100, 0, 2000, 5, 0},
+	})
+	check("action", []usage{
+		{"//a:a javac a.java", 1, 3000, 500, 1000, 10, 0},
+		{"//a:a d8 a.jar", 1, 2000, 100, 3000, 0, 20},
+		{"//b:b javac b.java", 1, 100, 0, 2000, 5, 0},
+	})
+}
+
+func TestResourceUsageTopActions(t *testing.T) {
+	r := NewResourceUsage(nil)
+	for i := 1; i <= topActionsCount+10; i++ {
+		action := &Action{Description: fmt.Sprintf("//a:a%d cc", i)}
+		r.FinishAction(ActionResult{Action: action, Stats: ActionResultStats{UserTime: uint32(i)}}, Counts{})
+	}
+
+	top := r.Metrics().GetTopActions()
+	if len(top) != topActionsCount {
+		t.Fatalf("want %d top actions, got %d", topActionsCount, len(top))
+	}
+	for i, action := range top {
+		if want := uint64(topActionsCount + 10 - i); action.GetUserTime() != want {
+			t.Errorf("top action %d: want user time %d, got %d", i, want, action.GetUserTime())
+		}
+	}
+}
+
+func TestResourceUsagePrint(t *testing.T) {
+	status := &Status{}
+	output := &messagesOutput{}
+	status.AddOutput(output)
+
+	r := NewResourceUsage(nil)
+	r.Print(status.StartTool())
+	if len(output.msgs) != 0 {
+		t.Errorf("want nothing printed before any action finished, got %q", output.msgs)
+	}
+
+	for i := 1; i <= topActionsPrintCount+2; i++ {
+		action := &Action{Description: fmt.Sprintf("//a:a%d cc", i)}
+		r.FinishAction(ActionResult{Action: action, Stats: ActionResultStats{UserTime: uint32(i * 1000)}}, Counts{})
+	}
+	r.Print(status.StartTool())
+
+	want := []string{
+		"top 5 actions by CPU time (see verbose.log for more):",
+		"   user(s)  sys(s) max rss(MB)  action",
+		"       7.0     0.0         0.0  //a:a7 cc",
+		"       6.0     0.0         0.0  //a:a6 cc",
+		"       5.0     0.0         0.0  //a:a5 cc",
+		"       4.0     0.0         0.0  //a:a4 cc",
+		"       3.0     0.0         0.0  //a:a3 cc",
+	}
+	if !reflect.DeepEqual(output.msgs, want) {
+		t.Errorf("want:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(output.msgs, "\n"))
+	}
+}
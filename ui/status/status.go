@@ -54,6 +54,36 @@ type ActionResult struct {
 	// Error is nil if the Action succeeded, or set to an error if it
 	// failed.
 	Error error
+
+	// Stats is the resource usage of the Action, if it was reported.
+	Stats ActionResultStats
+}
+
+// ActionResultStats describes the resources used by an Action.
+type ActionResultStats struct {
+	// UserTime and SystemTime are the CPU time spent in user and kernel
+	// mode, in milliseconds.
+	UserTime   uint32
+	SystemTime uint32
+
+	// MaxRssKB is the peak resident set size in kilobytes.
+	MaxRssKB uint64
+
+	// MinorPageFaults and MajorPageFaults are the number of page faults
+	// serviced without and with I/O.
+	MinorPageFaults uint64
+	MajorPageFaults uint64
+
+	// IOInputKB and IOOutputKB are the kilobytes read from and written to
+	// the file system.
+	IOInputKB  uint64
+	IOOutputKB uint64
+
+	// VoluntaryContextSwitches and InvoluntaryContextSwitches are the
+	// number of context switches because the Action waited for a resource,
+	// or was preempted.
+	VoluntaryContextSwitches   uint64
+	InvoluntaryContextSwitches uint64
 }
 
 // Counts describes the number of actions in each state
@@ -15,15 +15,28 @@
 package tracer
 
 import (
-	"android/soong/ui/status"
+	"fmt"
 	"time"
+
+	"android/soong/ui/status"
+)
+
+// The process of the ninja actions in the trace, with one thread per action that could run
+// concurrently.
+const actionsPid = 1
+
+// The categories of the ninja actions in the trace.
+const (
+	actionCategory       = "action"
+	criticalPathCategory = "critical_path"
 )
 
 func (t *tracerImpl) StatusTracer() status.StatusOutput {
 	return &statusOutput{
 		tracer: t,
 
-		running: map[*status.Action]actionStatus{},
+		running:      map[*status.Action]actionStatus{},
+		criticalPath: status.NewCriticalPath(nil),
 	}
 }
 
@@ -32,11 +45,21 @@ type actionStatus struct {
 	start time.Time
 }
 
+type actionEvent struct {
+	action *status.Action
+	event  *viewerEvent
+}
+
 type statusOutput struct {
 	tracer *tracerImpl
 
 	cpus    []bool
 	running map[*status.Action]actionStatus
+
+	// The events of the finished actions are written when the build finishes, once the actions
+	// on the critical path are known.
+	finished     []actionEvent
+	criticalPath status.CriticalPath
 }
 
 func (s *statusOutput) StartAction(action *status.Action, counts status.Counts) {
@@ -58,6 +81,7 @@ func (s *statusOutput) StartAction(action *status.Action, counts status.Counts)
 		cpu:   cpu,
 		start: time.Now(),
 	}
+	s.criticalPath.StartAction(action, counts)
 }
 
 func (s *statusOutput) FinishAction(result status.ActionResult, counts status.Counts) {
@@ -67,23 +91,97 @@ func (s *statusOutput) FinishAction(result status.ActionResult, counts status.Co
 	}
 	delete(s.running, result.Action)
 	s.cpus[start.cpu] = false
+	s.criticalPath.FinishAction(result, counts)
 
 	str := result.Action.Description
 	if len(result.Action.Outputs) > 0 {
 		str = result.Action.Outputs[0]
 	}
 
-	s.tracer.writeEvent(&viewerEvent{
-		Name:  str,
-		Phase: "X",
-		Time:  uint64(start.start.UnixNano()) / 1000,
-		Dur:   uint64(time.Since(start.start).Nanoseconds()) / 1000,
-		Pid:   1,
-		Tid:   uint64(start.cpu),
+	s.finished = append(s.finished, actionEvent{result.Action, &viewerEvent{
+		Name:     str,
+		Category: actionCategory,
+		Phase:    "X",
+		Time:     uint64(start.start.UnixNano()) / 1000,
+		Dur:      uint64(time.Since(start.start).Nanoseconds()) / 1000,
+		Pid:      actionsPid,
+		Tid:      uint64(start.cpu),
+	}})
+}
+
+// Flush writes the events of the finished actions, with the actions on the critical path in their
+// own category and linked by flow events from each step of the critical path to the next.
+func (s *statusOutput) Flush() {
+	if len(s.finished) == 0 {
+		return
+	}
+
+	events := make(map[*status.Action]*viewerEvent, len(s.finished))
+	for _, finished := range s.finished {
+		events[finished.action] = finished.event
+	}
+	var criticalPath []*viewerEvent
+	for _, action := range s.criticalPath.Actions() {
+		if event := events[action]; event != nil {
+			event.Category = criticalPathCategory
+			criticalPath = append(criticalPath, event)
+		}
+	}
+
+	t := s.tracer
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.writeEventLocked(&viewerEvent{
+		Name:  "process_name",
+		Phase: "M",
+		Pid:   actionsPid,
+		Arg:   &nameArg{Name: "ninja"},
 	})
+	for cpu := range s.cpus {
+		t.writeEventLocked(&viewerEvent{
+			Name:  "thread_name",
+			Phase: "M",
+			Pid:   actionsPid,
+			Tid:   uint64(cpu),
+			Arg:   &nameArg{Name: fmt.Sprintf("action %d", cpu)},
+		})
+	}
+
+	for _, finished := range s.finished {
+		t.writeEventLocked(finished.event)
+	}
+
+	// The flow events start and end at the beginning of the slices of the critical path, which
+	// binds them to those slices.
+	for i := 1; i < len(criticalPath); i++ {
+		from, to := criticalPath[i-1], criticalPath[i]
+		id := t.nextFlowID
+		t.nextFlowID++
+		t.writeEventLocked(&viewerEvent{
+			Name:     "critical path",
+			Category: criticalPathCategory,
+			Phase:    "s",
+			Time:     from.Time,
+			Pid:      actionsPid,
+			Tid:      from.Tid,
+			ID:       id,
+		})
+		t.writeEventLocked(&viewerEvent{
+			Name:      "critical path",
+			Category:  criticalPathCategory,
+			Phase:     "f",
+			BindPoint: "e",
+			Time:      to.Time,
+			Pid:       actionsPid,
+			Tid:       to.Tid,
+			ID:        id,
+		})
+	}
+
+	s.finished = nil
 }
 
-func (s *statusOutput) Flush()                                        {}
 func (s *statusOutput) Message(level status.MsgLevel, message string) {}
 
 func (s *statusOutput) Write(p []byte) (int, error) {
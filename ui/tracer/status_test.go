@@ -0,0 +1,85 @@
+// Copyright 2020 Google Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"android/soong/ui/status"
+)
+
+func TestStatusTracer(t *testing.T) {
+	tracer := New(nil)
+	s := tracer.StatusTracer()
+
+	//  a   c
+	//  |
+	//  b
+	a := &status.Action{Description: "a", Outputs: []string{"a"}}
+	b := &status.Action{Description: "b", Outputs: []string{"b"}, Inputs: []string{"a"}}
+	c := &status.Action{Description: "c", Outputs: []string{"c"}}
+	s.StartAction(a, status.Counts{})
+	// Make sure a takes longer than c, so that the critical path is a, b.
+	time.Sleep(time.Millisecond)
+	s.StartAction(c, status.Counts{})
+	s.FinishAction(status.ActionResult{Action: c}, status.Counts{})
+	s.FinishAction(status.ActionResult{Action: a}, status.Counts{})
+	s.StartAction(b, status.Counts{})
+	s.FinishAction(status.ActionResult{Action: b}, status.Counts{})
+	s.Flush()
+
+	var events []viewerEvent
+	if err := json.Unmarshal(append(tracer.buf.Bytes(), ']'), &events); err != nil {
+		t.Fatalf("failed to parse trace: %s\n%s", err, tracer.buf.String())
+	}
+
+	type slice struct {
+		name, category string
+		tid            uint64
+	}
+	var slices []slice
+	var flows []string
+	threads := map[uint64]bool{}
+	for _, event := range events {
+		switch event.Phase {
+		case "X":
+			slices = append(slices, slice{event.Name, event.Category, event.Tid})
+		case "s", "f":
+			flows = append(flows, event.Phase+event.BindPoint)
+		case "M":
+			if event.Pid == actionsPid && event.Name == "thread_name" {
+				threads[event.Tid] = true
+			}
+		}
+	}
+
+	wantSlices := []slice{
+		{"c", actionCategory, 1},
+		{"a", criticalPathCategory, 0},
+		{"b", criticalPathCategory, 0},
+	}
+	if !reflect.DeepEqual(slices, wantSlices) {
+		t.Errorf("want slices %v, got %v", wantSlices, slices)
+	}
+	if want := []string{"s", "fe"}; !reflect.DeepEqual(flows, want) {
+		t.Errorf("want flow events %v, got %v", want, flows)
+	}
+	if want := map[uint64]bool{0: true, 1: true}; !reflect.DeepEqual(threads, want) {
+		t.Errorf("want action threads %v, got %v", want, threads)
+	}
+}
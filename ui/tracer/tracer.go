@@ -63,20 +63,23 @@ type tracerImpl struct {
 
 	firstEvent bool
 	nextTid    uint64
+	nextFlowID uint64
 }
 
 var _ Tracer = &tracerImpl{}
 
 type viewerEvent struct {
-	Name  string      `json:"name,omitempty"`
-	Phase string      `json:"ph"`
-	Scope string      `json:"s,omitempty"`
-	Time  uint64      `json:"ts"`
-	Dur   uint64      `json:"dur,omitempty"`
-	Pid   uint64      `json:"pid"`
-	Tid   uint64      `json:"tid"`
-	ID    uint64      `json:"id,omitempty"`
-	Arg   interface{} `json:"args,omitempty"`
+	Name      string      `json:"name,omitempty"`
+	Category  string      `json:"cat,omitempty"`
+	Phase     string      `json:"ph"`
+	Scope     string      `json:"s,omitempty"`
+	BindPoint string      `json:"bp,omitempty"`
+	Time      uint64      `json:"ts"`
+	Dur       uint64      `json:"dur,omitempty"`
+	Pid       uint64      `json:"pid"`
+	Tid       uint64      `json:"tid"`
+	ID        uint64      `json:"id,omitempty"`
+	Arg       interface{} `json:"args,omitempty"`
 }
 
 type nameArg struct {
@@ -95,6 +98,7 @@ func New(log logger.Logger) *tracerImpl {
 
 		firstEvent: true,
 		nextTid:    uint64(MaxInitThreads),
+		nextFlowID: 1,
 	}
 	ret.startBuffer()
 